@@ -0,0 +1,206 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+// all runs `bench all`.
+func all(args []string) {
+	fs := flag.NewFlagSet("all", flag.ExitOnError)
+	scenarioList := fs.String("scenario", "", "comma-separated scenarios to run (default all; see context-ticker -list)")
+	sweepSpec := fs.String("sweep", "", combined.SweepUsage)
+	seed := fs.Uint64("seed", combined.DefaultSeed, combined.SeedUsage)
+	cpu := fs.Int("cpu", -1, "pin the loop to this CPU (-1 = unpinned)")
+	r := harness.NewRunner(fs, scenarioBench, 1_000_000, 5, 100*time.Millisecond)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	selected, err := selectScenarios(*scenarioList)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	sweep, err := combined.ParseSweep(*sweepSpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	for _, a := range sweep {
+		if !combined.Takes(selected, a.Name) {
+			fmt.Fprintf(os.Stderr, "no scenario to run takes parameter %q (see context-ticker -list)\n", a.Name)
+			os.Exit(2)
+		}
+	}
+	if err := combined.SetSeed(*seed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	var scenarios []combined.Scenario
+	for _, s := range selected {
+		scenarios = append(scenarios, sweep.Expand(s)...)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rf := runFlags{n: r.N, benchtime: r.Benchtime, count: r.Count, cpu: *cpu, warm: r.Warm, outliers: r.Outliers}
+
+	if r.Format == harness.FormatText {
+		settings := r.Settings()
+		if *seed != combined.DefaultSeed {
+			settings = append([]string{fmt.Sprintf("Seed: %d", *seed)}, settings...)
+		}
+		r.Reporter().Header(fmt.Sprintf("Benchmarking %d scenarios (%s, %d runs each)", len(scenarios), harness.RunLength(r.N, r.Benchtime), r.Count), settings...)
+	}
+
+	// Progress goes to stderr, and only alongside the text report. With
+	// -shuffle it counts runs, since the scenarios' runs are interleaved.
+	var progress *harness.Progress
+	if r.Format == harness.FormatText {
+		total := len(scenarios)
+		if r.Shuffle.On {
+			total *= r.Count
+		}
+		progress = harness.NewProgress(os.Stderr, total)
+	}
+	var results []harness.Result
+	if r.Shuffle.On {
+		variants := make([]harness.Variant, len(scenarios))
+		for i, s := range scenarios {
+			v := combined.Variant(s, rf.n, rf.benchtime, rf.cpu, rf.warm, &err)
+			timed := v.Timed
+			v.Timed = func() time.Duration {
+				progress.Start(v.Name)
+				defer progress.Done()
+				return timed()
+			}
+			variants[i] = v
+		}
+		results = r.Prof.MeasureAll(variants, r.Count, r.Shuffle)
+		for i := range results {
+			results[i] = results[i].WithoutOutliers(r.Outliers)
+			results[i].Seed = combined.Seed()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	} else {
+		results = make([]harness.Result, len(scenarios))
+		for i, s := range scenarios {
+			if r.Interrupted() {
+				break
+			}
+			progress.Start(s.Name())
+			if results[i], err = rf.measure(s, r.Prof); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			progress.Done()
+		}
+	}
+	if err := r.Prof.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for i, s := range scenarios {
+		if len(results[i].Samples) == 0 {
+			continue // Skipped by an interrupt
+		}
+		if err := combined.Trace(r.Trace, s, results[i].N, rf.cpu); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	results = r.Collected(results)
+	_ = harness.CheckThrottling(r.Notes, results)
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Println()
+		if len(sweep) > 0 {
+			_ = harness.WriteSweep(os.Stdout, results)
+		} else {
+			_ = writeReport(os.Stdout, r.Color, results)
+		}
+		r.Reporter().Summary(r.Count, results)
+		if len(sweep) == 0 {
+			fmt.Printf("\nSpeedup is against each group's std variant, the standard-library baseline.\n")
+		}
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// writeReport writes results grouped by the part of the scenario name
+// before the slash, in name order, with each group's std variant first
+// and every variant's speedup over it, styled by c.
+func writeReport(w io.Writer, c harness.Color, results []harness.Result) error {
+	groups := make(map[string][]harness.Result)
+	var names []string
+	for _, r := range results {
+		group := combined.Group(r.Name)
+		if _, ok := groups[group]; !ok {
+			names = append(names, group)
+		}
+		groups[group] = append(groups[group], r)
+	}
+	slices.Sort(names)
+
+	for i, group := range names {
+		rs := groups[group]
+		std := 0.0
+		if j := slices.IndexFunc(rs, func(r harness.Result) bool { return variant(r) == "std" }); j >= 0 {
+			s := rs[j]
+			rs = slices.Insert(slices.Delete(rs, j, j+1), 0, s)
+			std = s.NsPerOp()
+		}
+
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s:\n", group); err != nil {
+			return err
+		}
+		best := harness.Fastest(rs)
+		for j, r := range rs {
+			name := fmt.Sprintf("%-12s", variant(r))
+			if j == best {
+				name = c.Winner(name)
+			}
+			speedup := "      -"
+			if std > 0 {
+				x := std / r.NsPerOp()
+				speedup = c.Speedup(fmt.Sprintf("%6.2fx", x), x)
+			}
+			if _, err := fmt.Fprintf(w, "  %s %10.2f ns/op%s  %s  %s\n", name, r.NsPerOp(), r.CyclesText(), speedup, r.MemPerOp()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// variant returns the part of r's scenario name after the group.
+func variant(r harness.Result) string {
+	_, v, _ := strings.Cut(r.Name, "/")
+	return v
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/alloc"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+// Sinks keep the compiler from eliminating the allocations bench alloc
+// times.
+var (
+	sinkAllocBytes []byte
+	sinkAllocPtrs  []*byte
+)
+
+// timeEscape calls f n times and returns how long that took.
+func timeEscape(n int, f func(int64) int64) time.Duration {
+	var sum int64
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		sum += f(int64(i))
+	}
+	d := time.Since(start)
+	sinkInt64 = sum
+	return d
+}
+
+// timeSlices builds and sums n Points in slices of length, with build and
+// sum, and returns how long that took. The last slice is shorter if
+// length doesn't divide n.
+func timeSlices[S any](n, length int, build func(int) S, sum func(S) int64) time.Duration {
+	var total int64
+	start := time.Now()
+	for left := n; left > 0; left -= length {
+		total += sum(build(min(length, left)))
+	}
+	d := time.Since(start)
+	sinkInt64 = total
+	return d
+}
+
+// allocRow is a row of a bench alloc table: a label and the variants to
+// compare on it, in column order.
+type allocRow struct {
+	label    string
+	variants []harness.Variant
+}
+
+// allocRun runs `bench alloc`.
+func allocRun(args []string) {
+	fs := flag.NewFlagSet("alloc", flag.ExitOnError)
+	sizeList := fs.String("sizes", "16,64,256,1024,32768", "small-object sizes in bytes to sweep (comma-separated)")
+	lengthList := fs.String("lengths", "16,1024,65536", "slice lengths to sweep (comma-separated)")
+	r := harness.NewRunner(fs, "Alloc", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if r.LatencyTracing() {
+		fmt.Fprintln(os.Stderr, "-latency-trace doesn't support bench alloc: a slice operation is one Point of a batch")
+		os.Exit(2)
+	}
+	sizes, err := harness.ParseCounts(*sizeList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -sizes: %v\n", err)
+		os.Exit(2)
+	}
+	lengths, err := harness.ParseCounts(*lengthList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -lengths: %v\n", err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Allocation and escape costs (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+	}
+
+	// The variants are made as each section comes up, so that -warmup
+	// and -time calibration run just before they are measured
+	sections := []struct {
+		title   string
+		label   string
+		columns []string
+		rows    func() []allocRow
+	}{
+		{"Escape (per call):", "Pattern", []string{"Stack", "Heap"}, func() []allocRow {
+			var rows []allocRow
+			for _, c := range alloc.Cases {
+				variant := func(side string, f func(int64) int64) harness.Variant {
+					return r.Variant(fmt.Sprintf("Escape/%s/%s", c.Name, side), func() { sinkInt64 += f(1) },
+						func(n int) time.Duration { return timeEscape(n, f) })
+				}
+				rows = append(rows, allocRow{c.Name, []harness.Variant{variant("Stack", c.Stack), variant("Heap", c.Heap)}})
+			}
+			return rows
+		}},
+		{"Small objects (per allocation):", "Size", []string{"NoScan", "Scan"}, func() []allocRow {
+			var rows []allocRow
+			for _, size := range sizes {
+				rows = append(rows, allocRow{fmt.Sprintf("%d B", size), []harness.Variant{
+					r.Variant(fmt.Sprintf("Small/NoScan/size=%d", size), func() { sinkAllocBytes = alloc.NewBytes(size) },
+						func(n int) time.Duration {
+							start := time.Now()
+							for i := 0; i < n; i++ {
+								sinkAllocBytes = alloc.NewBytes(size)
+							}
+							return time.Since(start)
+						}),
+					r.Variant(fmt.Sprintf("Small/Scan/size=%d", size), func() { sinkAllocPtrs = alloc.NewPointers(size) },
+						func(n int) time.Duration {
+							start := time.Now()
+							for i := 0; i < n; i++ {
+								sinkAllocPtrs = alloc.NewPointers(size)
+							}
+							return time.Since(start)
+						}),
+				}})
+			}
+			return rows
+		}},
+		{"Slices (build and sum, per Point):", "Length", []string{"Values", "Pointers"}, func() []allocRow {
+			var rows []allocRow
+			for _, length := range lengths {
+				// The op, for -warmup, is a slice of one
+				rows = append(rows, allocRow{fmt.Sprint(length), []harness.Variant{
+					r.Variant(fmt.Sprintf("Slice/Values/N=%d", length), func() { sinkInt64 += alloc.SumValues(alloc.Values(1)) },
+						func(n int) time.Duration { return timeSlices(n, length, alloc.Values, alloc.SumValues) }),
+					r.Variant(fmt.Sprintf("Slice/Pointers/N=%d", length), func() { sinkInt64 += alloc.SumPointers(alloc.Pointers(1)) },
+						func(n int) time.Duration { return timeSlices(n, length, alloc.Pointers, alloc.SumPointers) }),
+				}})
+			}
+			return rows
+		}},
+	}
+
+	var results []harness.Result
+	for _, section := range sections {
+		if r.Interrupted() {
+			break
+		}
+		if r.Format == harness.FormatText {
+			fmt.Printf("\n%s\n", section.title)
+			fmt.Printf("  %-10s", section.label)
+			for _, c := range section.columns {
+				fmt.Printf(" %30s", c)
+			}
+			fmt.Println()
+		}
+		for _, row := range section.rows() {
+			if r.Interrupted() {
+				break
+			}
+			rs, err := r.Measure(row.variants)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			results = append(results, rs...)
+
+			if r.Format == harness.FormatText && !r.Partial() {
+				cells := make([]string, len(rs))
+				for i, res := range rs {
+					cells[i] = fmt.Sprintf("%9.2f %4d allocs %4d GCs", res.NsPerOp(), res.AllocsPerOp, res.GCs)
+				}
+				fmt.Printf("  %-10s %s\n", row.label, strings.Join(rp.Winners(rs, cells...), " "))
+			}
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns and heap allocations per operation, and garbage collections during the samples;\n")
+		fmt.Printf("fastest in each row highlighted. Scan objects hold pointers the GC has to trace;\n")
+		fmt.Printf("allocs per Point rounds down, so Values' one allocation per slice shows as 0.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
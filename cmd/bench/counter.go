@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/counter"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+// counterStrategy is a shared counter bench counter can time.
+type counterStrategy struct {
+	name string
+
+	// open returns the Add each of writers goroutines calls, and the
+	// counter's Sum, for a counter with shards shards where it takes a
+	// count.
+	open func(writers, shards int) (adds []func(int64), sum func() int64, err error)
+}
+
+// sharedCounter returns a counterStrategy open for a Counter all writers
+// Add to.
+func sharedCounter(newCounter func(shards int) (counter.Counter, error)) func(int, int) ([]func(int64), func() int64, error) {
+	return func(writers, shards int) ([]func(int64), func() int64, error) {
+		c, err := newCounter(shards)
+		if err != nil {
+			return nil, nil, err
+		}
+		adds := make([]func(int64), writers)
+		for w := range adds {
+			adds[w] = c.Add
+		}
+		return adds, c.Sum, nil
+	}
+}
+
+// counterStrategies are the counters bench counter times, in report
+// order.
+var counterStrategies = []counterStrategy{
+	{name: "Atomic", open: sharedCounter(func(int) (counter.Counter, error) { return counter.NewAtomic(), nil })},
+	{name: "Mutex", open: sharedCounter(func(int) (counter.Counter, error) { return counter.NewMutex(), nil })},
+	{name: "PerP", open: sharedCounter(func(int) (counter.Counter, error) { return counter.NewPerP(), nil })},
+	{name: "Striped", open: sharedCounter(func(shards int) (counter.Counter, error) { return counter.NewStriped(shards) })},
+	{name: "Local", open: func(writers, _ int) ([]func(int64), func() int64, error) {
+		l := counter.NewLocal()
+		adds := make([]func(int64), writers)
+		for w := range adds {
+			adds[w] = l.NewCell().Add
+		}
+		return adds, l.Sum, nil
+	}},
+}
+
+// sinkInt64 keeps the compiler from eliminating the Sums.
+var sinkInt64 int64
+
+// timeCounter runs n Adds split across the writers, writer w calling
+// adds[w] and, if sumEvery is above 0, sum after every sumEvery Adds, and
+// returns how long they took.
+func timeCounter(n int, adds []func(int64), sum func() int64, sumEvery int) time.Duration {
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w, add := range adds {
+		count := n / len(adds)
+		if w < n%len(adds) {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var s int64
+			for i := range count {
+				add(1)
+				if sumEvery > 0 && i%sumEvery == sumEvery-1 {
+					s += sum()
+				}
+			}
+			sinkInt64 = s
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// counterRun runs `bench counter`.
+func counterRun(args []string) {
+	nameOf := func(s counterStrategy) string { return s.name }
+	fs := flag.NewFlagSet("counter", flag.ExitOnError)
+	counterList := fs.String("counters", "", "comma-separated counters to time (default all): "+strings.Join(namesOf(counterStrategies, nameOf), ", "))
+	writerList := fs.String("writers", "1,2,4,8", "writer goroutine counts to sweep (comma-separated)")
+	shards := fs.Int("shards", runtime.GOMAXPROCS(0)*4, "stripes in the Striped counter, rounded up to a power of two")
+	sumEvery := fs.Int("sum-every", 0, "have each writer also call Sum after every `n` Adds (0 = never)")
+	r := harness.NewRunner(fs, "Counter", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	writers, err := harness.ParseCounts(*writerList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -writers: %v\n", err)
+		os.Exit(2)
+	}
+	if _, err := counter.NewStriped(*shards); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -shards: %v\n", err)
+		os.Exit(2)
+	}
+	if *sumEvery < 0 {
+		fmt.Fprintf(os.Stderr, "invalid -sum-every: must be at least 0, got %d\n", *sumEvery)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("counters", *counterList, counterStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		title := fmt.Sprintf("Counters (%s, %d stripes", harness.RunLength(r.N, r.Benchtime), *shards)
+		if *sumEvery > 0 {
+			title += fmt.Sprintf(", Sum every %d Adds", *sumEvery)
+		}
+		rp.Header(title+")", r.Settings()...)
+		fmt.Printf("\n  %-10s", "Writers")
+		for _, s := range strategies {
+			fmt.Printf(" %10s", s.name)
+		}
+		fmt.Println()
+	}
+
+	var results []harness.Result
+	for _, w := range writers {
+		if r.Interrupted() {
+			break
+		}
+		variants := make([]harness.Variant, len(strategies))
+		for i, s := range strategies {
+			adds, sum, err := s.open(w, *shards)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", s.name, err)
+				os.Exit(1)
+			}
+			name := fmt.Sprintf("%s/W=%d", s.name, w)
+			if *sumEvery > 0 {
+				name += fmt.Sprintf("/sum=%d", *sumEvery)
+			}
+			variants[i] = r.Variant(name, func() { adds[0](1) },
+				func(n int) time.Duration { return timeCounter(n, adds, sum, *sumEvery) })
+		}
+		rs, err := r.Measure(variants)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, rs...)
+
+		if r.Format == harness.FormatText && !r.Partial() {
+			cells := make([]string, len(rs))
+			for i, res := range rs {
+				cells[i] = fmt.Sprintf("%10.2f", res.NsPerOp())
+			}
+			fmt.Printf("  %-10d %s\n", w, strings.Join(rp.Winners(rs, cells...), " "))
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns per Add, fastest in each row highlighted. PerP, Striped and Local spread\n")
+		fmt.Printf("the writes over cache lines and pay for it in Sum: see -sum-every.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
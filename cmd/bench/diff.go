@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+// diff runs `bench diff`.
+func diff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	alpha := fs.Float64("alpha", harness.DefaultAlpha, "p-value below which an ns/op change is significant")
+	noColor := fs.Bool("no-color", false, harness.NoColorUsage)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: bench diff [flags] old.json new.json\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *alpha <= 0 || *alpha >= 1 {
+		fmt.Fprintln(os.Stderr, "bench diff: -alpha must be between 0 and 1")
+		os.Exit(2)
+	}
+	oldPath, newPath := fs.Arg(0), fs.Arg(1)
+	old, err := harness.LoadBaseline(oldPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	cur, err := harness.LoadBaseline(newPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	fmt.Printf("old: %s\n     %s\n", oldPath, old.Machine)
+	fmt.Printf("new: %s\n     %s\n", newPath, cur.Machine)
+	harness.CheckFingerprint(os.Stdout, old.Machine, cur.Machine)
+	_ = harness.WriteDiff(os.Stdout, harness.NewColor(os.Stdout, *noColor), old, cur, *alpha)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/errbench"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+// sinkMatch keeps the compiler from eliminating the checks bench errors
+// times.
+var sinkMatch bool
+
+// errorsRun runs `bench errors`.
+func errorsRun(args []string) {
+	nameOf := func(c errbench.Case) string { return c.Name }
+	fs := flag.NewFlagSet("errors", flag.ExitOnError)
+	caseList := fs.String("cases", "", "comma-separated Cases to time (default all): "+strings.Join(namesOf(errbench.Cases, nameOf), ", "))
+	depthList := fs.String("depths", "0,1,4", "fmt.Errorf %w layers around the error to sweep (comma-separated; 0 = the error itself)")
+	r := harness.NewRunner(fs, "Errors", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	depths, err := parseZeroCounts(*depthList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -depths: %v\n", err)
+		os.Exit(2)
+	}
+	cases, err := selectNamed("cases", *caseList, errbench.Cases, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Error creation and checking (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+	}
+
+	// The variants are made as each row comes up, so that -warmup and
+	// -time calibration run just before they are measured
+	sections := []struct {
+		title   string
+		name    string
+		variant func(c errbench.Case, depth int) harness.Variant
+	}{
+		{"Recognize an error (per check):", "Match", func(c errbench.Case, depth int) harness.Variant {
+			err := errbench.Wrap(c.New(42), depth)
+			return r.Variant(fmt.Sprintf("Match/%s/depth=%d", c.Name, depth), func() { sinkMatch = c.Match(err) },
+				func(n int) time.Duration {
+					start := time.Now()
+					for i := 0; i < n; i++ {
+						sinkMatch = c.Match(err)
+					}
+					return time.Since(start)
+				})
+		}},
+		{"Fail: create, wrap and recognize (per failure):", "Fail", func(c errbench.Case, depth int) harness.Variant {
+			return r.Variant(fmt.Sprintf("Fail/%s/depth=%d", c.Name, depth), func() { sinkMatch = c.Match(errbench.Wrap(c.New(42), depth)) },
+				func(n int) time.Duration {
+					start := time.Now()
+					for i := 0; i < n; i++ {
+						sinkMatch = c.Match(errbench.Wrap(c.New(i), depth))
+					}
+					return time.Since(start)
+				})
+		}},
+	}
+
+	var results []harness.Result
+	for _, section := range sections {
+		if r.Interrupted() {
+			break
+		}
+		if r.Format == harness.FormatText {
+			fmt.Printf("\n%s\n", section.title)
+			fmt.Printf("  %-8s", "Depth")
+			for _, c := range cases {
+				fmt.Printf(" %14s", c.Name)
+			}
+			fmt.Println()
+		}
+		for _, depth := range depths {
+			if r.Interrupted() {
+				break
+			}
+			variants := make([]harness.Variant, len(cases))
+			for i, c := range cases {
+				variants[i] = section.variant(c, depth)
+			}
+			rs, err := r.Measure(variants)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			results = append(results, rs...)
+
+			if r.Format == harness.FormatText && !r.Partial() {
+				cells := make([]string, len(rs))
+				for i, res := range rs {
+					cells[i] = fmt.Sprintf("%10.2f %3d", res.NsPerOp(), res.AllocsPerOp)
+				}
+				fmt.Printf("  %-8d %s\n", depth, strings.Join(rp.Winners(rs, cells...), " "))
+			}
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns and heap allocations per operation; fastest in each row highlighted. Depth is\n")
+		fmt.Printf("how many callers wrapped the error with fmt.Errorf and %%w on its way up.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
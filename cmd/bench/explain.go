@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/google/pprof/profile"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+// explanation is one variant measured by bench explain.
+type explanation struct {
+	result  harness.Result
+	top     []harness.FuncShare
+	samples int64 // CPU samples in the profile
+}
+
+// explain runs `bench explain`.
+func explain(args []string) {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	benchtime := fs.Duration("time", time.Second, "how long to run each variant; the profile takes about 100 samples a second")
+	top := fs.Int("top", 5, "how many functions to list per variant")
+	seed := fs.Uint64("seed", combined.DefaultSeed, combined.SeedUsage)
+	cpu := fs.Int("cpu", -1, "pin the loop to this CPU (-1 = unpinned)")
+	cycles := fs.Bool("cycles", false, harness.CyclesUsage)
+	noColor := fs.Bool("no-color", false, harness.NoColorUsage)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: bench explain [flags] <scenario or group>\n\nGroups are the part of scenario names before the slash, such as cancel-tick.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *benchtime <= 0 || *top < 1 {
+		fmt.Fprintln(os.Stderr, "bench explain: -time and -top must be positive")
+		os.Exit(2)
+	}
+	if err := harness.CheckCycles(*cycles); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := combined.SetSeed(*seed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	name := fs.Arg(0)
+	scenarios := explained(name)
+	if len(scenarios) == 0 {
+		fmt.Fprintf(os.Stderr, "bench explain: no scenario or group %q (see context-ticker -list)\n", name)
+		os.Exit(2)
+	}
+
+	rf := runFlags{benchtime: *benchtime, count: 1, cpu: *cpu}
+	prof := &harness.Profiler{Cycles: *cycles}
+	exps := make([]explanation, len(scenarios))
+	results := make([]harness.Result, len(scenarios))
+	for i, s := range scenarios {
+		exp, err := rf.explain(s, *top, prof)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		exps[i], results[i] = exp, exp.result
+	}
+
+	rp := harness.Reporter{W: os.Stdout, Color: harness.NewColor(os.Stdout, *noColor)}
+	var settings []string
+	if *cycles {
+		settings = append(settings, harness.TSCRate())
+	}
+	rp.Header(fmt.Sprintf("Explaining %s (%s, CPU-profiled)", name, harness.RunLength(0, *benchtime)), settings...)
+	group := combined.Group(scenarios[0].Name())
+	if text := combined.Description(group); text != "" {
+		fmt.Printf("\n%s\n", indent(text, "  "))
+	}
+	labels := make([]string, len(exps))
+	for i, exp := range exps {
+		labels[i] = exp.result.Name
+	}
+	labels = rp.Winners(results, labels...)
+	for i, exp := range exps {
+		res := exp.result
+		fmt.Printf("\n%s: %.2f ns/op%s, %s\n", labels[i], res.NsPerOp(), res.CyclesText(), res.MemPerOp())
+		if text := combined.Description(res.Name); text != "" {
+			fmt.Println(indent(text, "  "))
+		}
+		if exp.samples == 0 {
+			fmt.Println("  (no CPU samples; raise -time)")
+			continue
+		}
+		fmt.Printf("  Where the time goes (%d CPU samples):\n", exp.samples)
+		for _, f := range exp.top {
+			fmt.Printf("    %5.1f%%  %s\n", f.Percent, shortFunc(f.Name))
+		}
+	}
+}
+
+// explained returns the scenarios bench explain covers for name: the
+// scenario of that name, or every scenario in group name.
+func explained(name string) []combined.Scenario {
+	if s, ok := combined.Lookup(name); ok {
+		return []combined.Scenario{s}
+	}
+	var out []combined.Scenario
+	for _, s := range combined.Scenarios() {
+		if combined.Group(s.Name()) == name {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// explain measures scenario s with prof for rf's benchtime under a CPU
+// profile of its own, and returns its result with the top functions the
+// profile found. prof must not profile the CPU itself.
+func (rf runFlags) explain(s combined.Scenario, top int, prof *harness.Profiler) (explanation, error) {
+	var err error
+	v := combined.Variant(s, rf.n, rf.benchtime, rf.cpu, rf.warm, &err)
+	var buf bytes.Buffer
+	if perr := pprof.StartCPUProfile(&buf); perr != nil {
+		return explanation{}, perr
+	}
+	res := prof.Measure(v.Name, v.N, rf.count, v.Timed)
+	pprof.StopCPUProfile()
+	if err != nil {
+		return explanation{}, err
+	}
+	p, err := profile.Parse(&buf)
+	if err != nil {
+		return explanation{}, err
+	}
+	exp := explanation{result: res}
+	exp.top, exp.samples = harness.TopFunctions(p, top)
+	return exp, nil
+}
+
+// shortFunc drops the package path from a function name:
+// "github.com/.../internal/tick.(*AtomicTicker).Tick" becomes
+// "tick.(*AtomicTicker).Tick".
+func shortFunc(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// indent prefixes every non-empty line of text with prefix.
+func indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/hash"
+)
+
+// hashStrategy is a hash function bench hash can time.
+type hashStrategy struct {
+	name string
+	sum  func(b []byte) uint64
+}
+
+// hashStrategies are the hashes bench hash times, in report order.
+var hashStrategies = []hashStrategy{
+	{"Maphash", hash.Maphash},
+	{"FNV", hash.FNV},
+	{"FNV1a", hash.FNV1a},
+	{"XX", hash.XX},
+}
+
+// hashRun runs `bench hash`.
+func hashRun(args []string) {
+	nameOf := func(s hashStrategy) string { return s.name }
+	fs := flag.NewFlagSet("hash", flag.ExitOnError)
+	hashList := fs.String("hashes", "", "comma-separated hashes to time (default all): "+strings.Join(namesOf(hashStrategies, nameOf), ", "))
+	sizeList := fs.String("sizes", "8,16,64,256,1024", "key sizes in bytes to sweep (comma-separated)")
+	r := harness.NewRunner(fs, "Hash", 10_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	sizes, err := harness.ParseCounts(*sizeList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -sizes: %v\n", err)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("hashes", *hashList, hashStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Non-cryptographic hashing (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+		fmt.Printf("\n  %-10s", "Key")
+		for _, s := range strategies {
+			fmt.Printf(" %16s", s.name)
+		}
+		fmt.Println()
+	}
+
+	var results []harness.Result
+	for _, size := range sizes {
+		if r.Interrupted() {
+			break
+		}
+		key := hash.Key(size)
+		variants := make([]harness.Variant, len(strategies))
+		for i, s := range strategies {
+			variants[i] = r.Variant(fmt.Sprintf("%s/size=%d", s.name, size), func() { sinkUint64 ^= s.sum(key) },
+				func(n int) time.Duration {
+					var sum uint64
+					start := time.Now()
+					for j := 0; j < n; j++ {
+						sum ^= s.sum(key)
+					}
+					d := time.Since(start)
+					sinkUint64 = sum
+					return d
+				})
+		}
+		rs, err := r.Measure(variants)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, rs...)
+
+		if r.Format == harness.FormatText && !r.Partial() {
+			cells := make([]string, len(rs))
+			for i, res := range rs {
+				cells[i] = fmt.Sprintf("%8.2f %7.2f", res.NsPerOp(), float64(size)/res.NsPerOp())
+			}
+			fmt.Printf("  %-10s %s\n", fmt.Sprintf("%d B", size), strings.Join(rp.Winners(rs, cells...), " "))
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns per hash and GB/s of key hashed; fastest in each row highlighted.\n")
+		fmt.Printf("Maphash is seeded per process, so its values differ from run to run.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
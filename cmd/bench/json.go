@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/jsonbench"
+)
+
+// jsonStrategy is a JSON encoding strategy bench json can time.
+type jsonStrategy struct {
+	name   string
+	encode func(b []byte, s *jsonbench.Sample) ([]byte, error)
+}
+
+// jsonStrategies are the strategies bench json times, in report order.
+var jsonStrategies = []jsonStrategy{
+	{"Marshal", jsonbench.Marshal},
+	{"Encode", jsonbench.Encode},
+	{"AppendJSON", jsonbench.AppendJSON},
+}
+
+// sinkJSON keeps the compiler from eliminating the encoding.
+var sinkJSON []byte
+
+// timeJSON encodes s n times split across the goroutines, each into a
+// buffer of its own, and returns how long that took.
+func timeJSON(n, goroutines int, s *jsonbench.Sample, encode func([]byte, *jsonbench.Sample) ([]byte, error)) time.Duration {
+	start := time.Now()
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		count := n / goroutines
+		if g < n%goroutines {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, 0, 512)
+			for range count {
+				buf, _ = encode(buf[:0], s)
+			}
+			sinkJSON = buf
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// jsonRun runs `bench json`.
+func jsonRun(args []string) {
+	nameOf := func(s jsonStrategy) string { return s.name }
+	fs := flag.NewFlagSet("json", flag.ExitOnError)
+	encoderList := fs.String("encoders", "", "comma-separated strategies to time (default all): "+strings.Join(namesOf(jsonStrategies, nameOf), ", "))
+	goroutineList := fs.String("goroutines", "1,4", "goroutine counts encoding at once to sweep (comma-separated)")
+	r := harness.NewRunner(fs, "JSON", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	goroutines, err := harness.ParseCounts(*goroutineList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -goroutines: %v\n", err)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("encoders", *encoderList, jsonStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	s := jsonbench.NewSample()
+	out, err := jsonbench.AppendJSON(nil, s)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("JSON encoding (%s, %d-byte record)", harness.RunLength(r.N, r.Benchtime), len(out)), r.Settings()...)
+		fmt.Printf("\n  %-12s", "Goroutines")
+		for _, st := range strategies {
+			fmt.Printf(" %14s", st.name)
+		}
+		fmt.Println()
+	}
+
+	var results []harness.Result
+	for _, g := range goroutines {
+		if r.Interrupted() {
+			break
+		}
+		variants := make([]harness.Variant, len(strategies))
+		for i, st := range strategies {
+			buf := make([]byte, 0, 512)
+			variants[i] = r.Variant(fmt.Sprintf("%s/G=%d", st.name, g), func() { buf, _ = st.encode(buf[:0], s) },
+				func(n int) time.Duration { return timeJSON(n, g, s, st.encode) })
+		}
+		rs, err := r.Measure(variants)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, rs...)
+
+		if r.Format == harness.FormatText && !r.Partial() {
+			cells := make([]string, len(rs))
+			for i, res := range rs {
+				cells[i] = fmt.Sprintf("%10.2f %3d", res.NsPerOp(), res.AllocsPerOp)
+			}
+			fmt.Printf("  %-12d %s\n", g, strings.Join(rp.Winners(rs, cells...), " "))
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns and heap allocations per record, wall time over all goroutines; fastest in each\n")
+		fmt.Printf("row highlighted. Each goroutine appends to a buffer of its own; Encode's encoders\n")
+		fmt.Printf("and their buffers come from a sync.Pool all goroutines share.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
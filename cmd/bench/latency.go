@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tdigest"
+)
+
+// latency runs `bench latency`.
+func latency(args []string) {
+	fs := flag.NewFlagSet("latency", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: bench latency results.json...\n")
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	// Merge each variant's digests across the files, in the order the
+	// variants first appear
+	var (
+		first   harness.Machine
+		results []harness.Result
+		index   = make(map[string]int)
+	)
+	for i, path := range fs.Args() {
+		b, err := harness.LoadBaseline(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		fmt.Printf("%s\n     %s\n", path, b.Machine)
+		if i == 0 {
+			first = b.Machine
+		} else {
+			harness.CheckFingerprint(os.Stdout, first, b.Machine)
+		}
+		for _, br := range b.Results {
+			if br.Latency == nil || br.Latency.Digest == nil {
+				continue
+			}
+			name := br.Benchmark + "/" + br.Variant
+			j, ok := index[name]
+			if !ok {
+				j = len(results)
+				index[name] = j
+				results = append(results, harness.Result{Name: name, Latency: tdigest.New()})
+			}
+			results[j].Latency.Merge(br.Latency.Digest)
+		}
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "bench latency: no latency digests in these files; record them with -latency-trace and -save or -format=json")
+		os.Exit(1)
+	}
+	fmt.Printf("\nOperation latency, merged over %d files:\n", fs.NArg())
+	_ = harness.WriteLatency(os.Stdout, results)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/lock"
+)
+
+// lockStrategy is a way of guarding shared state bench lock can time.
+type lockStrategy struct {
+	name string
+	open func() lock.Updater
+}
+
+// lockStrategies are the strategies bench lock times, in report order.
+var lockStrategies = []lockStrategy{
+	{"Mutex", func() lock.Updater { return lock.NewMutex() }},
+	{"RWMutex", func() lock.Updater { return lock.NewRWMutex() }},
+	{"Spin", func() lock.Updater { return lock.NewSpin() }},
+	{"Atomic", func() lock.Updater { return lock.NewAtomic() }},
+}
+
+// sinkUint64 keeps the compiler from eliminating the Updates.
+var sinkUint64 uint64
+
+// timeLock runs n Updates of f on u, split across the goroutines, and
+// returns how long they took.
+func timeLock(n, goroutines int, u lock.Updater, f func(uint64) uint64) time.Duration {
+	start := time.Now()
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		count := n / goroutines
+		if g < n%goroutines {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range count {
+				u.Update(f)
+			}
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// lockRun runs `bench lock`.
+func lockRun(args []string) {
+	nameOf := func(s lockStrategy) string { return s.name }
+	fs := flag.NewFlagSet("lock", flag.ExitOnError)
+	lockList := fs.String("locks", "", "comma-separated strategies to time (default all): "+strings.Join(namesOf(lockStrategies, nameOf), ", "))
+	goroutineList := fs.String("goroutines", "1,2,8", "goroutine counts contending for the state to sweep (comma-separated; 1 = uncontended)")
+	workList := fs.String("work", "0,100", "critical section lengths in multiply-add rounds to sweep (comma-separated; 0 = just an increment)")
+	r := harness.NewRunner(fs, "Lock", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	goroutines, err := harness.ParseCounts(*goroutineList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -goroutines: %v\n", err)
+		os.Exit(2)
+	}
+	work, err := parseZeroCounts(*workList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -work: %v\n", err)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("locks", *lockList, lockStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Lock strategies (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+	}
+
+	var results []harness.Result
+	for _, rounds := range work {
+		if r.Interrupted() {
+			break
+		}
+		if r.Format == harness.FormatText {
+			fmt.Printf("\nCritical section: %d rounds\n", rounds)
+			fmt.Printf("  %-18s", "Goroutines")
+			for _, s := range strategies {
+				fmt.Printf(" %10s", s.name)
+			}
+			fmt.Println()
+		}
+		f := func(v uint64) uint64 { return lock.Work(v, rounds) }
+		for _, g := range goroutines {
+			if r.Interrupted() {
+				break
+			}
+			variants := make([]harness.Variant, len(strategies))
+			for i, s := range strategies {
+				u := s.open()
+				variants[i] = r.Variant(fmt.Sprintf("%s/G=%d/work=%d", s.name, g, rounds),
+					func() { u.Update(f) },
+					func(n int) time.Duration {
+						d := timeLock(n, g, u, f)
+						sinkUint64 = u.Load()
+						return d
+					})
+			}
+			rs, err := r.Measure(variants)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			results = append(results, rs...)
+
+			if r.Format == harness.FormatText && !r.Partial() {
+				label := "1 (uncontended)"
+				if g > 1 {
+					label = fmt.Sprintf("%d (%d-way)", g, g)
+				}
+				cells := make([]string, len(rs))
+				for i, res := range rs {
+					cells[i] = fmt.Sprintf("%10.2f", res.NsPerOp())
+				}
+				fmt.Printf("  %-18s %s\n", label, strings.Join(rp.Winners(rs, cells...), " "))
+			}
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns per Update, fastest in each row highlighted. Atomic retries the critical\n")
+		fmt.Printf("section when another Update gets in first; the locks run it once.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
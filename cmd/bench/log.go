@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/logbench"
+)
+
+// logStrategy is a Logger bench log can time.
+type logStrategy struct {
+	name string
+	new  func(w io.Writer, level slog.Level) logbench.Logger
+}
+
+// logStrategies are the Loggers bench log times, in report order.
+var logStrategies = []logStrategy{
+	{"Printf", func(w io.Writer, l slog.Level) logbench.Logger { return logbench.NewPrintf(w, l) }},
+	{"Slog", func(w io.Writer, l slog.Level) logbench.Logger { return logbench.NewSlog(w, l) }},
+	{"SlogChecked", func(w io.Writer, l slog.Level) logbench.Logger { return logbench.NewSlogChecked(w, l) }},
+	{"Append", func(w io.Writer, l slog.Level) logbench.Logger { return logbench.NewAppend(w, l) }},
+}
+
+// logLines are the lines bench log times each Logger on, all from a
+// Logger at info level: one it writes and one it drops.
+var logLines = []struct {
+	name  string
+	label string
+	log   func(l logbench.Logger, i int)
+}{
+	{"Info", "Info", func(l logbench.Logger, i int) { l.Info("packet done", i, 1500) }},
+	{"DebugOff", "Debug (off)", func(l logbench.Logger, i int) { l.Debug("packet done", i, 1500) }},
+}
+
+// logRun runs `bench log`.
+func logRun(args []string) {
+	nameOf := func(s logStrategy) string { return s.name }
+	fs := flag.NewFlagSet("log", flag.ExitOnError)
+	loggerList := fs.String("loggers", "", "comma-separated Loggers to time (default all): "+strings.Join(namesOf(logStrategies, nameOf), ", "))
+	r := harness.NewRunner(fs, "Log", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("loggers", *loggerList, logStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Logging in a loop (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+		fmt.Printf("\n  %-12s", "Line")
+		for _, s := range strategies {
+			fmt.Printf(" %14s", s.name)
+		}
+		fmt.Println()
+	}
+
+	var results []harness.Result
+	for _, line := range logLines {
+		if r.Interrupted() {
+			break
+		}
+		variants := make([]harness.Variant, len(strategies))
+		for i, s := range strategies {
+			l := s.new(logbench.Discard, slog.LevelInfo)
+			variants[i] = r.Variant(fmt.Sprintf("%s/%s", s.name, line.name), func() { line.log(l, 0) },
+				func(n int) time.Duration {
+					start := time.Now()
+					for j := 0; j < n; j++ {
+						line.log(l, j)
+					}
+					return time.Since(start)
+				})
+		}
+		rs, err := r.Measure(variants)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, rs...)
+
+		if r.Format == harness.FormatText && !r.Partial() {
+			cells := make([]string, len(rs))
+			for i, res := range rs {
+				cells[i] = fmt.Sprintf("%10.2f %3d", res.NsPerOp(), res.AllocsPerOp)
+			}
+			fmt.Printf("  %-12s %s\n", line.label, strings.Join(rp.Winners(rs, cells...), " "))
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns and heap allocations per line, from a Logger at info level writing to a discarding\n")
+		fmt.Printf("io.Writer; fastest in each row highlighted. Debug (off) is a line the level drops.\n")
+		rp.Summary(r.Count, results)
+
+		// What a disabled slog.Debug in a loop costs for not checking
+		// Enabled first, at loop rates a busy service runs at
+		unchecked := slices.IndexFunc(results, func(res harness.Result) bool { return res.Name == "Slog/DebugOff" })
+		checked := slices.IndexFunc(results, func(res harness.Result) bool { return res.Name == "SlogChecked/DebugOff" })
+		if unchecked >= 0 && checked >= 0 {
+			fmt.Printf("\nA disabled slog.Debug per iteration, unchecked (Slog) vs checked (SlogChecked):\n")
+			rp.Impact(results[unchecked], results[checked], 100_000, 1_000_000, 10_000_000)
+		}
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,263 @@
+// Command bench works with the results the other cmd tools record.
+//
+// Usage:
+//
+//	go run ./cmd/bench all
+//	go run ./cmd/bench alloc -sizes 64,4096 -lengths 1024 -gc-off
+//	go run ./cmd/bench all -format=csv -count 10
+//	go run ./cmd/bench all -scenario queue/std,queue/ring -sweep "size=64,256,1024"
+//	go run ./cmd/bench counter -writers 1,4,16 -sum-every 1000
+//	go run ./cmd/bench diff old.json new.json
+//	go run ./cmd/bench errors -depths 0,8 -cases sentinel,typed
+//	go run ./cmd/bench explain cancel-tick
+//	go run ./cmd/bench hash -sizes 4,32,4096 -hashes maphash,xx
+//	go run ./cmd/bench json -goroutines 1,8 -count 5
+//	go run ./cmd/bench latency host1.json host2.json
+//	go run ./cmd/bench lock -goroutines 1,2,16 -work 0,1000
+//	go run ./cmd/bench log -loggers slog,slogchecked
+//	go run ./cmd/bench map -reads 90,99 -keys 1000 -goroutines 8
+//	go run ./cmd/bench mpsc -producers 1,4,16 -consumers 1 -burst 50%
+//	go run ./cmd/bench once -count 10
+//	go run ./cmd/bench pool -sizes 64,16384 -goroutines 1,8 -garbage 0,1000
+//	go run ./cmd/bench rand -goroutines 1,16 -sources locked,pcg
+//	go run ./cmd/bench sema -limits 1,8 -goroutines 1,8,64 -work 0
+//	go run ./cmd/bench serve -db results.db
+//	go run ./cmd/bench strbuild -parts 4,128 -lengths 16 -builders builder,grow
+//	go run ./cmd/bench timefmt -formats appendformat,manual -count 5
+//	go run ./cmd/bench wait -tasks 1,16,1024
+//	go run ./cmd/bench watch -interval 10m -scenario cancel-tick/std,cancel-tick/atomic
+//
+// all runs every scenario in the internal/combined registry, which holds
+// the single components (cancel, tick, queue) the other cmd tools compare
+// as well as the combined loops, and prints one report grouped by
+// component. It takes the cmd tools' flags, with defaults that keep a full
+// run under a second per scenario. -scenario picks scenarios, and -sweep
+// runs each at every combination of the parameter values given for it,
+// printing a long-format table with a column per parameter.
+//
+// alloc times what allocation costs: common patterns that make a value
+// escape to the heap against the same code keeping it on the stack,
+// small objects with and without pointers across sizes, and a slice of
+// structs against a slice of pointers, printing ns and allocations per
+// operation and the garbage collections during each.
+//
+// counter sweeps writer counts over shared event counters (an
+// atomic.Int64, a mutex-guarded int, and per-P, per-goroutine and striped
+// sharded counters) and prints ns per Add for each, optionally with each
+// writer also reading the sum.
+//
+// diff compares two files written by -save or -format=json, such as runs
+// on two machines or two commits: it aligns variants by name and prints
+// each metric's old and new values and change, benchstat-style, with a
+// Mann-Whitney U test deciding whether an ns/op change is significant.
+//
+// errors compares ways of failing (errors.New, fmt.Errorf wrapping a
+// sentinel with %w, the sentinel itself and a custom error type) and of
+// recognizing the failure (its text, errors.Is and errors.As), through
+// layers of wrapping, and prints ns and allocations per check and per
+// failure.
+//
+// explain runs a scenario, or every scenario in a group such as
+// cancel-tick, for a second each under a CPU profile, and prints what
+// each variant does, its ns/op, and the functions its time went to.
+//
+// hash times non-cryptographic hashes (hash/maphash, hash/fnv's FNV-1a,
+// an inlinable FNV-1a and xxHash64) across key sizes, and prints ns per
+// hash and GB/s for each.
+//
+// json compares ways of encoding a telemetry record as JSON
+// (json.Marshal, a pooled json.Encoder and a hand-written AppendJSON)
+// from one and from several goroutines, and prints ns and allocations
+// per record.
+//
+// latency merges the operation latency t-digests that -latency-trace
+// saves in files written by -save or -format=json, per variant across
+// all the files, such as repeated runs or runs on several machines, and
+// prints their percentiles.
+//
+// lock times ways of guarding shared state (sync.Mutex, sync.RWMutex, a
+// CAS spinlock with backoff and a lock-free CAS loop) as the number of
+// contending goroutines and the length of the critical section grow, and
+// prints ns per update for each.
+//
+// log compares loggers in a hot loop (log.Printf behind a level check,
+// slog with and without an Enabled check, and a logger appending to a
+// reused buffer) on a line they write and one their level drops, and
+// prints ns and allocations per line and what checking Enabled first
+// saves.
+//
+// map compares concurrent maps (a map behind a sync.Mutex or a
+// sync.RWMutex, sync.Map, and a sharded map) across read shares and key
+// counts, and prints ns per operation for each.
+//
+// mpsc sweeps producer counts over the multi-producer queues (channels,
+// MPSCRing, LinkedQueue, CombiningQueue and a sharded MPSCRing) and
+// prints ns per item for each, with flags for the consumer count, the
+// shard count and a bursty push pattern.
+//
+// once compares one-time initialization (sync.Once, sync.OnceValue, a
+// hand-written double-checked atomic flag, and an eagerly computed value
+// as package-level init gives) on the hot path, a Get after the first,
+// and on the first call, which creates the value and computes it.
+//
+// pool times object reuse strategies (plain allocation, sync.Pool, a
+// lock-free freelist and a per-goroutine stack) across object sizes,
+// goroutine counts and background garbage rates, and prints ns and bytes
+// allocated per Get and Put, showing where sync.Pool starts missing.
+//
+// rand times random number sources (math/rand's and math/rand/v2's
+// top-level functions, a math/rand generator behind a mutex as the
+// top-level functions once were, and a math/rand generator or a PCG per
+// goroutine) as the number of goroutines drawing numbers grows, and
+// prints ns per number for each.
+//
+// sema times counting semaphores (a buffered channel,
+// golang.org/x/sync/semaphore.Weighted, and an atomic counter that parks
+// waiters) with goroutines doing a little work holding each slot, across
+// limits and goroutine counts, and prints ns per acquire and release.
+//
+// serve starts a small web UI over a results database filled by the cmd
+// tools' -db flag: it lists the recorded runs, diffs any two, and charts
+// a variant's ns/op, B/op or allocs/op across runs.
+//
+// strbuild compares ways of building a string from parts (+= in a loop,
+// fmt.Sprintf, strings.Builder and bytes.Buffer, and a Builder or a
+// []byte sized up front) across part counts and lengths, and prints ns
+// and allocations per string.
+//
+// timefmt compares ways of writing a log-line timestamp (time.Format,
+// AppendFormat into a reused buffer, the layout formatted by hand, and
+// integer Unix nanoseconds) in UTC, a fixed offset and the local zone,
+// and prints ns and allocations per timestamp.
+//
+// wait compares completion signaling (sync.WaitGroup, errgroup.Group,
+// fan-in by channel close, and an atomic countdown latch), starting
+// batches of N empty goroutines and waiting for each, and prints ns and
+// bytes per task at each N.
+//
+// watch reruns internal/combined scenarios on an interval and serves the
+// latest results as Prometheus gauges on /metrics, so drift across kernel
+// and Go upgrades shows up in existing monitoring. It streams each result
+// to stdout as it comes, as text, CSV or JSON Lines, and after -rounds
+// rounds, or an interrupt, sums up how much each scenario drifted, for
+// soak-testing a machine before trusting its numbers.
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+const usage = `usage: bench <command> [flags]
+
+Commands:
+  all      run every scenario and print one consolidated report
+  alloc    compare stack and heap, object sizes and slices of structs or pointers
+  counter  compare an atomic counter with sharded ones across writer counts
+  diff     compare two saved result files with significance tests
+  errors   compare errors.New, fmt.Errorf %w, sentinels with errors.Is and types with errors.As
+  explain  describe a scenario's variants, measure them and show where the time goes
+  hash     compare maphash, FNV-1a and xxHash across key sizes
+  json     compare json.Marshal, a pooled Encoder and hand-written AppendJSON across callers
+  latency  merge saved latency digests across files and print percentiles
+  lock     compare mutexes, a spinlock and atomics across contention and critical sections
+  log      compare log.Printf, slog with and without level checks and an append logger
+  map      compare mutex-guarded, sync.Map and sharded maps across read shares and key counts
+  mpsc     compare the multi-producer queues across producer counts
+  once     compare sync.Once, sync.OnceValue and a double-checked flag, hot and first call
+  pool     compare sync.Pool, a freelist and allocation across sizes and GC pressure
+  rand     compare math/rand, math/rand/v2, a locked source and per-goroutine PCGs across callers
+  sema     compare channel, x/sync and atomic semaphores across limits and contention
+  serve    browse, diff and chart the runs in a results database
+  strbuild compare +=, Sprintf, strings.Builder, bytes.Buffer and preallocation across sizes
+  timefmt  compare time.Format, AppendFormat, hand-rolled RFC 3339 and Unix nanos
+  wait     compare WaitGroup, errgroup, channel fan-in and a latch across batch sizes
+  watch    rerun scenarios on an interval and export them to Prometheus
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "all":
+		all(args)
+	case "alloc":
+		allocRun(args)
+	case "counter":
+		counterRun(args)
+	case "diff":
+		diff(args)
+	case "errors":
+		errorsRun(args)
+	case "explain":
+		explain(args)
+	case "hash":
+		hashRun(args)
+	case "json":
+		jsonRun(args)
+	case "latency":
+		latency(args)
+	case "lock":
+		lockRun(args)
+	case "log":
+		logRun(args)
+	case "map":
+		mapRun(args)
+	case "mpsc":
+		mpsc(args)
+	case "once":
+		onceRun(args)
+	case "pool":
+		poolRun(args)
+	case "rand":
+		randRun(args)
+	case "sema":
+		semaRun(args)
+	case "serve":
+		serve(args)
+	case "strbuild":
+		strbuildRun(args)
+	case "timefmt":
+		timefmtRun(args)
+	case "wait":
+		waitRun(args)
+	case "watch":
+		watch(args)
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "bench: unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+}
+
+// namesOf returns the names of items, for a flag's usage string.
+func namesOf[T any](items []T, name func(T) string) []string {
+	names := make([]string, len(items))
+	for i, it := range items {
+		names[i] = name(it)
+	}
+	return names
+}
+
+// selectNamed returns the items named in list, a comma-separated value
+// of -flag matched case-insensitively, or all of them if it is empty.
+func selectNamed[T any](flag, list string, items []T, name func(T) string) ([]T, error) {
+	if list == "" {
+		return items, nil
+	}
+	var out []T
+	for _, n := range strings.Split(list, ",") {
+		n = strings.TrimSpace(n)
+		i := slices.IndexFunc(items, func(it T) bool { return strings.EqualFold(name(it), n) })
+		if i < 0 {
+			return nil, fmt.Errorf("invalid -%s: unknown name %q (have %s)", flag, n, strings.Join(namesOf(items, name), ", "))
+		}
+		out = append(out, items[i])
+	}
+	return out, nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/mapbench"
+)
+
+// mapStrategy is a concurrent map bench map can time.
+type mapStrategy struct {
+	name string
+	open func(shards int) (mapbench.Map[uint64, uint64], error)
+}
+
+// mapStrategies are the maps bench map times, in report order.
+var mapStrategies = []mapStrategy{
+	{"Mutex", func(int) (mapbench.Map[uint64, uint64], error) { return mapbench.NewMutex[uint64, uint64](), nil }},
+	{"RWMutex", func(int) (mapbench.Map[uint64, uint64], error) { return mapbench.NewRWMutex[uint64, uint64](), nil }},
+	{"SyncMap", func(int) (mapbench.Map[uint64, uint64], error) { return mapbench.NewSyncMap[uint64, uint64](), nil }},
+	{"Sharded", func(shards int) (mapbench.Map[uint64, uint64], error) {
+		return mapbench.NewSharded[uint64, uint64](shards)
+	}},
+}
+
+// mapStream is the combined.NewRand stream of goroutine 0's workload;
+// goroutine g draws from mapStream+g.
+const mapStream = 2 << 32
+
+// mapWorkloadOps is how many Ops each goroutine's workload holds before
+// it repeats.
+const mapWorkloadOps = 1 << 16
+
+// sinkMap keeps the compiler from eliminating the Loads.
+var sinkMap atomic.Uint64
+
+// timeMap runs n Ops on m split across the goroutines, goroutine g
+// running workloads[g], and returns how long they took.
+func timeMap(n int, m mapbench.Map[uint64, uint64], workloads [][]mapbench.Op) time.Duration {
+	start := time.Now()
+	var wg sync.WaitGroup
+	for g, ops := range workloads {
+		count := n / len(workloads)
+		if g < n%len(workloads) {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sinkMap.Add(mapbench.Run(m, ops, 0, count))
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// mapRun runs `bench map`.
+func mapRun(args []string) {
+	nameOf := func(s mapStrategy) string { return s.name }
+	fs := flag.NewFlagSet("map", flag.ExitOnError)
+	mapList := fs.String("maps", "", "comma-separated maps to time (default all): "+strings.Join(namesOf(mapStrategies, nameOf), ", "))
+	readList := fs.String("reads", "50,90,99", "percentages of operations that are reads to sweep (comma-separated)")
+	keyList := fs.String("keys", "100,10000,1000000", "key counts to sweep (comma-separated)")
+	goroutines := fs.Int("goroutines", 4, "goroutines using the map at once")
+	shards := fs.Int("shards", runtime.GOMAXPROCS(0)*4, "shards in the Sharded map, rounded up to a power of two")
+	seed := fs.Uint64("seed", combined.DefaultSeed, combined.SeedUsage)
+	r := harness.NewRunner(fs, "Map", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	reads, err := parseZeroCounts(*readList)
+	if err == nil && slices.Max(reads) > 100 {
+		err = fmt.Errorf("must be at most 100, got %d", slices.Max(reads))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -reads: %v\n", err)
+		os.Exit(2)
+	}
+	keys, err := harness.ParseCounts(*keyList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -keys: %v\n", err)
+		os.Exit(2)
+	}
+	if *goroutines < 1 {
+		fmt.Fprintf(os.Stderr, "invalid -goroutines: must be at least 1, got %d\n", *goroutines)
+		os.Exit(2)
+	}
+	if _, err := mapbench.NewSharded[uint64, uint64](*shards); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -shards: %v\n", err)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("maps", *mapList, mapStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := combined.SetSeed(*seed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		settings := []string{fmt.Sprintf("Goroutines: %d, Sharded shards: %d", *goroutines, *shards)}
+		if *seed != combined.DefaultSeed {
+			settings = append(settings, fmt.Sprintf("Seed: %d", *seed))
+		}
+		rp.Header(fmt.Sprintf("Concurrent maps (%s)", harness.RunLength(r.N, r.Benchtime)), append(settings, r.Settings()...)...)
+	}
+
+	var results []harness.Result
+	for _, k := range keys {
+		if r.Interrupted() {
+			break
+		}
+		if r.Format == harness.FormatText {
+			fmt.Printf("\nKeys: %d\n", k)
+			fmt.Printf("  %-10s", "Reads")
+			for _, s := range strategies {
+				fmt.Printf(" %10s", s.name)
+			}
+			fmt.Println()
+		}
+		for _, rd := range reads {
+			if r.Interrupted() {
+				break
+			}
+			workloads := make([][]mapbench.Op, *goroutines)
+			for g := range workloads {
+				workloads[g] = mapbench.NewWorkload(combined.NewRand(mapStream+uint64(g)), mapWorkloadOps, k, rd)
+			}
+			variants := make([]harness.Variant, len(strategies))
+			for i, s := range strategies {
+				m, err := s.open(*shards)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", s.name, err)
+					os.Exit(1)
+				}
+				mapbench.Fill(m, k)
+				next := 0
+				variants[i] = r.Variant(fmt.Sprintf("%s/keys=%d/reads=%d/G=%d", s.name, k, rd, *goroutines),
+					func() { sinkMap.Add(mapbench.Run(m, workloads[0], next, 1)); next++ },
+					func(n int) time.Duration { return timeMap(n, m, workloads) })
+			}
+			rs, err := r.Measure(variants)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			for i := range rs {
+				rs[i].Seed = combined.Seed()
+			}
+			results = append(results, rs...)
+
+			if r.Format == harness.FormatText && !r.Partial() {
+				cells := make([]string, len(rs))
+				for i, res := range rs {
+					cells[i] = fmt.Sprintf("%10.2f", res.NsPerOp())
+				}
+				fmt.Printf("  %-10s %s\n", fmt.Sprintf("%d%%", rd), strings.Join(rp.Winners(rs, cells...), " "))
+			}
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns per operation, keys drawn uniformly; fastest in each row highlighted.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
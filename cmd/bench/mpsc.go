@@ -0,0 +1,434 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// mpscEnds are the two ends of a queue under test: push(p, v) pushes v
+// for producer p, and pop(c) pops for consumer c. Both are non-blocking.
+type mpscEnds struct {
+	push func(p, v int) bool
+	pop  func(c int) (int, bool)
+}
+
+// mpscQueue is a queue bench mpsc can time.
+type mpscQueue struct {
+	name  string
+	multi bool // Takes more than one consumer
+
+	// open returns an empty queue of size for producers producers,
+	// consumers consumers and, for Sharded, shards shards.
+	open func(size, producers, consumers, shards int) (mpscEnds, error)
+}
+
+// mpscQueues are the queues bench mpsc times, in report order.
+var mpscQueues = []mpscQueue{
+	{name: "Channel", multi: true, open: func(size, _, _, _ int) (mpscEnds, error) {
+		q, err := queue.NewChannel[int](size)
+		if err != nil {
+			return mpscEnds{}, err
+		}
+		return mpscEnds{
+			push: func(_, v int) bool { return q.Push(v) },
+			pop:  func(int) (int, bool) { return q.Pop() },
+		}, nil
+	}},
+	{name: "BoundedChan", multi: true, open: func(size, _, _, _ int) (mpscEnds, error) {
+		q, err := queue.NewBoundedChan[int](size)
+		if err != nil {
+			return mpscEnds{}, err
+		}
+		return mpscEnds{
+			push: func(_, v int) bool { return q.Push(v) },
+			pop:  func(int) (int, bool) { return q.Pop() },
+		}, nil
+	}},
+	{name: "MPSCRing", open: func(size, _, _, _ int) (mpscEnds, error) {
+		q, err := queue.NewMPSCRing[int](size)
+		if err != nil {
+			return mpscEnds{}, err
+		}
+		return mpscEnds{
+			push: func(_, v int) bool { return q.Push(v) },
+			pop:  func(int) (int, bool) { return q.Pop() },
+		}, nil
+	}},
+	{name: "Linked", open: func(_, _, _, _ int) (mpscEnds, error) {
+		q := queue.NewLinkedQueue[int](queue.AllocPool)
+		return mpscEnds{
+			push: func(_, v int) bool { return q.Push(v) },
+			pop:  func(int) (int, bool) { return q.Pop() },
+		}, nil
+	}},
+	{name: "Combining", open: func(size, producers, _, _ int) (mpscEnds, error) {
+		q, err := queue.NewCombiningQueue[int](size, producers)
+		if err != nil {
+			return mpscEnds{}, err
+		}
+		handles := make([]*queue.CombiningProducer[int], producers)
+		for p := range handles {
+			if handles[p], err = q.NewProducer(); err != nil {
+				return mpscEnds{}, err
+			}
+		}
+		return mpscEnds{
+			push: func(p, v int) bool { return handles[p].Push(v) },
+			pop:  func(int) (int, bool) { return q.Pop() },
+		}, nil
+	}},
+	{name: "Sharded", multi: true, open: openSharded},
+}
+
+// openSharded returns shards MPSCRings of size each as one queue:
+// producer p pushes to shard p mod shards, and consumer c drains shards
+// c, c+consumers, c+2*consumers, ..., one item from each in turn, so
+// every shard keeps a single consumer.
+func openSharded(size, _, consumers, shards int) (mpscEnds, error) {
+	rings := make([]*queue.MPSCRing[int], shards)
+	owned := make([][]*queue.MPSCRing[int], consumers)
+	for i := range rings {
+		q, err := queue.NewMPSCRing[int](size)
+		if err != nil {
+			return mpscEnds{}, err
+		}
+		rings[i] = q
+		owned[i%consumers] = append(owned[i%consumers], q)
+	}
+	next := make([]int, consumers) // Each consumer's next shard in owned
+	return mpscEnds{
+		push: func(p, v int) bool { return rings[p%shards].Push(v) },
+		pop: func(c int) (int, bool) {
+			own := owned[c]
+			for range own {
+				i := next[c]
+				next[c] = (i + 1) % len(own)
+				if v, ok := own[i].Pop(); ok {
+					return v, true
+				}
+			}
+			return 0, false
+		},
+	}, nil
+}
+
+// burstStream is the combined.NewRand stream of producer 0's bursts;
+// producer p draws from burstStream+p.
+const burstStream = 1 << 32
+
+// burst is the pattern producers push in.
+type burst struct {
+	duty   float64       // Share of each period spent pushing, (0, 1]
+	period time.Duration // Length of a burst and the idle after it
+}
+
+// parseDuty parses a -burst duty cycle, "50%" or "50", as a share.
+func parseDuty(s string) (float64, error) {
+	pct, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid -burst %q: %w", s, err)
+	}
+	if pct <= 0 || pct > 100 {
+		return 0, fmt.Errorf("invalid -burst %q: want a duty cycle above 0%% and up to 100%%", s)
+	}
+	return pct / 100, nil
+}
+
+// steady reports whether b pushes without pauses.
+func (b burst) steady() bool {
+	return b.duty >= 1
+}
+
+func (b burst) String() string {
+	if b.steady() {
+		return "steady"
+	}
+	return fmt.Sprintf("%g%% of each %v", b.duty*100, b.period)
+}
+
+// produce pushes count items for producer p, retrying each while the
+// queue is full, yielding between tries so the consumer can run even
+// with more goroutines than CPUs. Unless b is steady, it pushes only for part of each
+// period and busy-waits out the rest, as BenchmarkPipeline_Bursty_* does:
+// each burst lasts between half and one and a half times the duty share
+// of its period, drawn from the seeded generator.
+func (b burst) produce(p, count int, push func(p, v int) bool) {
+	if b.steady() {
+		for i := 0; i < count; {
+			if push(p, i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+		return
+	}
+	rng := combined.NewRand(burstStream + uint64(p))
+	next := func() time.Duration {
+		return min(time.Duration(float64(b.period)*b.duty*(0.5+rng.Float64())), b.period)
+	}
+	on := next()
+	periodStart := time.Now()
+	for i := 0; i < count; {
+		if time.Since(periodStart) >= on {
+			// Idle until the next period
+			periodStart = periodStart.Add(b.period)
+			on = next()
+			for time.Now().Before(periodStart) {
+				runtime.Gosched()
+			}
+			continue
+		}
+		if push(p, i) {
+			i++
+		} else {
+			runtime.Gosched()
+		}
+	}
+}
+
+// timeQueue pushes n items split across producers goroutines, in the
+// pattern b says, pops them all on consumers goroutines, and returns how
+// long that took. With one consumer it pops on the calling goroutine.
+// A consumer that finds the queue empty yields, as produce does on a
+// full one.
+func timeQueue(n, producers, consumers int, b burst, q mpscEnds) time.Duration {
+	start := time.Now()
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		count := n / producers
+		if p < n%producers {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.produce(p, count, q.push)
+		}()
+	}
+	if consumers == 1 {
+		for received := 0; received < n; {
+			if _, ok := q.pop(0); ok {
+				received++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	} else {
+		var received atomic.Int64
+		for c := 0; c < consumers; c++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for received.Load() < int64(n) {
+					if _, ok := q.pop(c); ok {
+						received.Add(1)
+					} else {
+						runtime.Gosched()
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// mpsc runs `bench mpsc`.
+func mpsc(args []string) {
+	fs := flag.NewFlagSet("mpsc", flag.ExitOnError)
+	queueList := fs.String("queues", "", "comma-separated queues to time (default all): "+strings.Join(namesOf(mpscQueues, func(q mpscQueue) string { return q.name }), ", "))
+	producerList := fs.String("producers", "1,2,4,8,16", "producer counts to sweep (comma-separated)")
+	consumers := fs.Int("consumers", 1, "consumer goroutines; above 1, only the queues that take several consumers run")
+	shards := fs.Int("shards", 0, "shards in the Sharded queue (0 = one per producer)")
+	size := fs.Int("size", 1024, "queue size, per shard for Sharded")
+	duty := fs.String("burst", "100%", "share of each -burst-period producers push for, idling the rest (100% = steady)")
+	period := fs.Duration("burst-period", 100*time.Microsecond, "length of a burst plus the idle after it")
+	seed := fs.Uint64("seed", combined.DefaultSeed, combined.SeedUsage)
+	r := harness.NewRunner(fs, "MPSC", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if r.LatencyTracing() {
+		fmt.Fprintln(os.Stderr, "-latency-trace doesn't support bench mpsc: one item's trip spans goroutines")
+		os.Exit(2)
+	}
+	if *consumers < 1 || *shards < 0 || *period <= 0 {
+		fmt.Fprintln(os.Stderr, "bench mpsc: -consumers and -burst-period must be positive, and -shards at least 0")
+		os.Exit(2)
+	}
+	producers, err := harness.ParseCounts(*producerList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -producers: %v\n", err)
+		os.Exit(2)
+	}
+	queues, err := selectQueues(*queueList, *consumers)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	b := burst{period: *period}
+	if b.duty, err = parseDuty(*duty); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if _, err := queue.NewMPSCRing[int](*size); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -size: %v\n", err)
+		os.Exit(2)
+	}
+	if err := combined.SetSeed(*seed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if over := oversubscribed(producers, *consumers); len(over) > 0 {
+		fmt.Fprintf(r.Notes, "!!! WARNING: with -consumers %d, -producers %s need more goroutines than GOMAXPROCS=%d runs at once;\n", *consumers, strings.Join(over, ","), runtime.GOMAXPROCS(0))
+		fmt.Fprintf(r.Notes, "!!! their goroutines take turns on the CPUs, so ns/op there includes the scheduler's switching.\n")
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		sharding := "one per producer"
+		if *shards > 0 {
+			sharding = strconv.Itoa(*shards)
+		}
+		settings := []string{fmt.Sprintf("Consumers: %d, shards: %s, bursts: %s", *consumers, sharding, b)}
+		if !b.steady() && *seed != combined.DefaultSeed {
+			settings = append(settings, fmt.Sprintf("Seed: %d", *seed))
+		}
+		rp.Header(fmt.Sprintf("Multi-producer queues (%s, size=%d)", harness.RunLength(r.N, r.Benchtime), *size), append(settings, r.Settings()...)...)
+		fmt.Printf("  %-10s", "Producers")
+		for _, q := range queues {
+			fmt.Printf(" %12s", q.name)
+		}
+		fmt.Println()
+	}
+
+	var results []harness.Result
+	for _, p := range producers {
+		if r.Interrupted() {
+			break
+		}
+		s := *shards
+		if s == 0 {
+			s = p
+		}
+		variants := make([]harness.Variant, len(queues))
+		for i, q := range queues {
+			ends, err := q.open(*size, p, *consumers, s)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", q.name, err)
+				os.Exit(1)
+			}
+			name := fmt.Sprintf("%s/P=%d", q.name, p)
+			if *consumers > 1 {
+				name += fmt.Sprintf("/C=%d", *consumers)
+			}
+			if q.name == "Sharded" {
+				name += fmt.Sprintf("/S=%d", s)
+			}
+			variants[i] = r.Variant(name, func() { ends.push(0, 0); ends.pop(0) },
+				func(n int) time.Duration { return timeQueue(n, p, *consumers, b, ends) })
+		}
+		rs, err := r.Measure(variants)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, rs...)
+
+		if r.Format == harness.FormatText && !r.Partial() {
+			cells := make([]string, len(rs))
+			for i, res := range rs {
+				cells[i] = fmt.Sprintf("%12.2f", res.NsPerOp())
+			}
+			fmt.Printf("  %-10d %s\n", p, strings.Join(rp.Winners(rs, cells...), " "))
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns per item, from the first push to the last pop; fastest at each producer count highlighted.\n")
+		if !b.steady() {
+			fmt.Printf("Bursts include the idle time, so a queue that keeps up costs about the period over the items a burst pushes.\n")
+		}
+		if skipped := skippedQueues(*queueList, *consumers); len(skipped) > 0 {
+			last := len(skipped) - 1
+			fmt.Printf("%s and %s take only one consumer, so they are left out with -consumers %d.\n", strings.Join(skipped[:last], ", "), skipped[last], *consumers)
+		}
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// selectQueues returns the queues named in list, or all of them if it is
+// empty, that take consumers consumers. Naming a queue that doesn't is an
+// error; with list empty, such queues are left out.
+func selectQueues(list string, consumers int) ([]mpscQueue, error) {
+	if list == "" {
+		var out []mpscQueue
+		for _, q := range mpscQueues {
+			if q.multi || consumers == 1 {
+				out = append(out, q)
+			}
+		}
+		return out, nil
+	}
+	out, err := selectNamed("queues", list, mpscQueues, func(q mpscQueue) string { return q.name })
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range out {
+		if !q.multi && consumers > 1 {
+			return nil, fmt.Errorf("bench mpsc: %s takes only one consumer, not -consumers %d", q.name, consumers)
+		}
+	}
+	return out, nil
+}
+
+// oversubscribed returns the producer counts that, with consumers,
+// need more goroutines than GOMAXPROCS lets run at once.
+func oversubscribed(producers []int, consumers int) []string {
+	var over []string
+	for _, p := range producers {
+		if p+consumers > runtime.GOMAXPROCS(0) {
+			over = append(over, strconv.Itoa(p))
+		}
+	}
+	return over
+}
+
+// skippedQueues returns the names of the queues an empty -queues list
+// left out for taking only one consumer.
+func skippedQueues(list string, consumers int) []string {
+	if list != "" || consumers == 1 {
+		return nil
+	}
+	var names []string
+	for _, q := range mpscQueues {
+		if !q.multi {
+			names = append(names, q.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/once"
+)
+
+// onceImpls are the Value implementations bench once times, in report
+// order; the first is the baseline the speedups are against.
+var onceImpls = []struct {
+	name string
+	new  func(init func() int) once.Value[int]
+}{
+	{"SyncOnce", func(init func() int) once.Value[int] { return once.NewSyncOnce(init) }},
+	{"OnceValue", func(init func() int) once.Value[int] { return once.NewOnceValue(init) }},
+	{"DoubleChecked", func(init func() int) once.Value[int] { return once.NewDoubleChecked(init) }},
+	{"Eager", func(init func() int) once.Value[int] { return once.NewEager(init) }},
+}
+
+// onceConfig is what the Values compute: cheap, so bench once measures
+// the initialization mechanism rather than the work it guards.
+func onceConfig() int { return 42 }
+
+// sinkInt keeps the compiler from eliminating the Gets.
+var sinkInt int
+
+// onceRun runs `bench once`.
+func onceRun(args []string) {
+	fs := flag.NewFlagSet("once", flag.ExitOnError)
+	r := harness.NewRunner(fs, "Once", 10_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Benchmarking one-time initialization (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+	}
+
+	// Hot path: Get on a Value that has already computed
+	hot := make([]harness.Variant, len(onceImpls))
+	for i, impl := range onceImpls {
+		v := impl.new(onceConfig)
+		v.Get()
+		hot[i] = r.Variant(impl.name+"/Get", func() { sinkInt = v.Get() }, func(n int) time.Duration {
+			var result int
+			start := time.Now()
+			for j := 0; j < n; j++ {
+				result = v.Get()
+			}
+			d := time.Since(start)
+			sinkInt = result
+			return d
+		})
+	}
+	// First call: a new Value and the Get that computes it
+	first := make([]harness.Variant, len(onceImpls))
+	for i, impl := range onceImpls {
+		first[i] = r.Variant(impl.name+"/First", func() { sinkInt = impl.new(onceConfig).Get() }, func(n int) time.Duration {
+			var result int
+			start := time.Now()
+			for j := 0; j < n; j++ {
+				result = impl.new(onceConfig).Get()
+			}
+			d := time.Since(start)
+			sinkInt = result
+			return d
+		})
+	}
+
+	var results []harness.Result
+	for _, section := range []struct {
+		title    string
+		variants []harness.Variant
+	}{
+		{"Hot path (Get after the first):", hot},
+		{"First call (create, then Get):", first},
+	} {
+		if r.Interrupted() {
+			break
+		}
+		rs, err := r.Measure(section.variants)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, rs...)
+
+		if r.Format == harness.FormatText && !r.Partial() {
+			fmt.Printf("\n%s\n", section.title)
+			names := make([]string, len(rs))
+			for i, res := range rs {
+				names[i] = fmt.Sprintf("%-20s", res.Name)
+			}
+			names = rp.Winners(rs, names...)
+			for i, res := range rs {
+				fmt.Printf("  %s %12v  %8.2f ns/op%s  %s  %s\n",
+					names[i], res.Elapsed(), res.NsPerOp(), res.CyclesText(), rp.Speedup("%6.2fx", harness.Speedup(rs[0], res)), res.MemPerOp())
+			}
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		rp.Summary(r.Count, results)
+		fmt.Printf("\nNote: Eager computes its value when created, as package-level init does; its Get is the floor.\n")
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/pool"
+)
+
+// buffer is the object bench pool reuses.
+type buffer struct {
+	b []byte
+}
+
+// poolStrategy is an object reuse strategy bench pool can time.
+type poolStrategy struct {
+	name string
+
+	// open returns the Pool each of goroutines goroutines uses, for
+	// buffers newBuf allocates, holding up to capacity each where the
+	// strategy has a bound.
+	open func(goroutines, capacity int, newBuf func() *buffer) ([]pool.Pool[buffer], error)
+}
+
+// shared returns a poolStrategy open for one Pool all goroutines share.
+func shared(newPool func(capacity int, newBuf func() *buffer) (pool.Pool[buffer], error)) func(int, int, func() *buffer) ([]pool.Pool[buffer], error) {
+	return func(goroutines, capacity int, newBuf func() *buffer) ([]pool.Pool[buffer], error) {
+		p, err := newPool(capacity, newBuf)
+		if err != nil {
+			return nil, err
+		}
+		pools := make([]pool.Pool[buffer], goroutines)
+		for g := range pools {
+			pools[g] = p
+		}
+		return pools, nil
+	}
+}
+
+// poolStrategies are the strategies bench pool times, in report order.
+var poolStrategies = []poolStrategy{
+	{name: "Alloc", open: shared(func(_ int, newBuf func() *buffer) (pool.Pool[buffer], error) {
+		return pool.NewAlloc(newBuf), nil
+	})},
+	{name: "SyncPool", open: shared(func(_ int, newBuf func() *buffer) (pool.Pool[buffer], error) {
+		return pool.NewSyncPool(newBuf), nil
+	})},
+	{name: "Freelist", open: shared(func(capacity int, newBuf func() *buffer) (pool.Pool[buffer], error) {
+		return pool.NewFreelist(capacity, newBuf)
+	})},
+	{name: "Stack", open: func(goroutines, capacity int, newBuf func() *buffer) ([]pool.Pool[buffer], error) {
+		pools := make([]pool.Pool[buffer], goroutines)
+		for g := range pools {
+			s, err := pool.NewStack(capacity, newBuf)
+			if err != nil {
+				return nil, err
+			}
+			pools[g] = s
+		}
+		return pools, nil
+	}},
+}
+
+// sinkByte keeps the compiler from eliminating the buffer writes.
+var sinkByte byte
+
+// useBuffer gets a buffer from p, writes to each of its cache lines, as
+// filling it would bring them in, and puts it back.
+func useBuffer(p pool.Pool[buffer]) byte {
+	buf := p.Get()
+	for i := 0; i < len(buf.b); i += 64 {
+		buf.b[i]++
+	}
+	x := buf.b[0]
+	p.Put(buf)
+	return x
+}
+
+// timePool uses n buffers split across the goroutines, goroutine g from
+// pools[g], and returns how long that took.
+func timePool(n int, pools []pool.Pool[buffer]) time.Duration {
+	start := time.Now()
+	var wg sync.WaitGroup
+	for g, p := range pools {
+		count := n / len(pools)
+		if g < n%len(pools) {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var x byte
+			for range count {
+				x += useBuffer(p)
+			}
+			sinkByte = x
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// Background garbage, made as internal/combined's GC pressure benchmarks
+// make it
+const (
+	garbageChunk  = 16 << 10 // Bytes per allocation
+	garbageWindow = 1024     // Chunks kept live (16MB)
+)
+
+// startGarbage allocates mbPerSec MB/s in the background, keeping the
+// last garbageWindow chunks live so the collector has marking to do,
+// until the returned stop function is called. A rate of 0 does nothing.
+func startGarbage(mbPerSec int) (stop func()) {
+	if mbPerSec == 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		live := make([][]byte, garbageWindow)
+		perChunk := time.Duration(float64(time.Second) * garbageChunk / float64(mbPerSec<<20))
+		start := time.Now()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			live[i%garbageWindow] = make([]byte, garbageChunk)
+			if ahead := time.Duration(i+1)*perChunk - time.Since(start); ahead > 0 {
+				time.Sleep(ahead)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+// parseZeroCounts parses a comma-separated list of counts of 0 or more,
+// such as a -garbage flag's "0,100,1000".
+func parseZeroCounts(s string) ([]int, error) {
+	var out []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("must be at least 0, got %d", n)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// poolRun runs `bench pool`.
+func poolRun(args []string) {
+	nameOf := func(s poolStrategy) string { return s.name }
+	fs := flag.NewFlagSet("pool", flag.ExitOnError)
+	poolList := fs.String("pools", "", "comma-separated strategies to time (default all): "+strings.Join(namesOf(poolStrategies, nameOf), ", "))
+	sizeList := fs.String("sizes", "64,1024,16384", "buffer sizes in bytes to sweep (comma-separated)")
+	goroutineList := fs.String("goroutines", "1,4", "goroutine counts sharing the pool to sweep (comma-separated)")
+	garbageList := fs.String("garbage", "0,100", "background garbage rates in MB/s to sweep (comma-separated; 0 = none)")
+	capacity := fs.Int("capacity", 1024, "most buffers a Freelist, or each goroutine's Stack, holds")
+	r := harness.NewRunner(fs, "Pool", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	sizes, err := harness.ParseCounts(*sizeList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -sizes: %v\n", err)
+		os.Exit(2)
+	}
+	goroutines, err := harness.ParseCounts(*goroutineList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -goroutines: %v\n", err)
+		os.Exit(2)
+	}
+	garbage, err := parseZeroCounts(*garbageList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -garbage: %v\n", err)
+		os.Exit(2)
+	}
+	if _, err := pool.NewStack(*capacity, func() *buffer { return nil }); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -capacity: %v\n", err)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("pools", *poolList, poolStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Object pools (%s, capacity=%d)", harness.RunLength(r.N, r.Benchtime), *capacity), r.Settings()...)
+	}
+
+	var results []harness.Result
+	for _, mb := range garbage {
+		if r.Interrupted() {
+			break
+		}
+		if r.Format == harness.FormatText {
+			fmt.Printf("\nGarbage: %d MB/s\n", mb)
+			fmt.Printf("  %-16s", "Size x G")
+			for _, s := range strategies {
+				fmt.Printf(" %17s", s.name)
+			}
+			fmt.Println()
+		}
+		stop := startGarbage(mb)
+		for _, size := range sizes {
+			for _, g := range goroutines {
+				if r.Interrupted() {
+					break
+				}
+				newBuf := func() *buffer { return &buffer{b: make([]byte, size)} }
+				variants := make([]harness.Variant, len(strategies))
+				for i, s := range strategies {
+					pools, err := s.open(g, *capacity, newBuf)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "%s: %v\n", s.name, err)
+						os.Exit(1)
+					}
+					name := fmt.Sprintf("%s/size=%d/G=%d", s.name, size, g)
+					if mb > 0 {
+						name += fmt.Sprintf("/garbage=%dMB_s", mb)
+					}
+					variants[i] = r.Variant(name, func() { sinkByte += useBuffer(pools[0]) },
+						func(n int) time.Duration { return timePool(n, pools) })
+				}
+				rs, err := r.Measure(variants)
+				if err != nil {
+					stop()
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				results = append(results, rs...)
+
+				if r.Format == harness.FormatText && !r.Partial() {
+					cells := make([]string, len(rs))
+					for i, res := range rs {
+						cells[i] = fmt.Sprintf("%10.2f %5dB", res.NsPerOp(), res.BytesPerOp)
+					}
+					fmt.Printf("  %-16s %s\n", fmt.Sprintf("%d B x %d", size, g), strings.Join(rp.Winners(rs, cells...), " "))
+				}
+			}
+		}
+		stop()
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns and bytes allocated per Get, use and Put; fastest in each row highlighted.\n")
+		fmt.Printf("Stack is one per goroutine, the rest are shared. Under garbage, sync.Pool is emptied\n")
+		fmt.Printf("every two collections and allocates again: its B/op counts the misses, on top of\n")
+		fmt.Printf("the background garbage, which B/op includes.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
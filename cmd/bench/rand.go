@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/randbench"
+)
+
+// randStrategy is a random number source bench rand can time.
+type randStrategy struct {
+	name string
+
+	// open returns the Source each of goroutines goroutines calls.
+	open func(goroutines int) []randbench.Source
+}
+
+// sharedSource returns a randStrategy open for one Source all goroutines
+// share.
+func sharedSource(newSource func() randbench.Source) func(int) []randbench.Source {
+	return func(goroutines int) []randbench.Source {
+		src := newSource()
+		srcs := make([]randbench.Source, goroutines)
+		for g := range srcs {
+			srcs[g] = src
+		}
+		return srcs
+	}
+}
+
+// ownSource returns a randStrategy open for a Source per goroutine,
+// goroutine g's seeded with g.
+func ownSource(newSource func(seed int) randbench.Source) func(int) []randbench.Source {
+	return func(goroutines int) []randbench.Source {
+		srcs := make([]randbench.Source, goroutines)
+		for g := range srcs {
+			srcs[g] = newSource(g)
+		}
+		return srcs
+	}
+}
+
+// randStrategies are the Sources bench rand times, in report order.
+var randStrategies = []randStrategy{
+	{"Global", sharedSource(func() randbench.Source { return randbench.NewGlobal() })},
+	{"Locked", sharedSource(func() randbench.Source { return randbench.NewLocked(1) })},
+	{"V2", sharedSource(func() randbench.Source { return randbench.NewV2() })},
+	{"Rand", ownSource(func(seed int) randbench.Source { return randbench.NewRand(int64(seed)) })},
+	{"PCG", ownSource(func(seed int) randbench.Source { return randbench.NewPCG(uint64(seed)) })},
+}
+
+// timeRand draws n numbers split across the goroutines, goroutine g from
+// srcs[g], and returns how long that took.
+func timeRand(n int, srcs []randbench.Source) time.Duration {
+	start := time.Now()
+	var wg sync.WaitGroup
+	for g, src := range srcs {
+		count := n / len(srcs)
+		if g < n%len(srcs) {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var sum uint64
+			for range count {
+				sum ^= src.Uint64()
+			}
+			sinkUint64 = sum
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// randRun runs `bench rand`.
+func randRun(args []string) {
+	nameOf := func(s randStrategy) string { return s.name }
+	fs := flag.NewFlagSet("rand", flag.ExitOnError)
+	sourceList := fs.String("sources", "", "comma-separated Sources to time (default all): "+strings.Join(namesOf(randStrategies, nameOf), ", "))
+	goroutineList := fs.String("goroutines", "1,2,4,8", "goroutine counts drawing numbers at once to sweep (comma-separated)")
+	r := harness.NewRunner(fs, "Rand", 10_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	goroutines, err := harness.ParseCounts(*goroutineList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -goroutines: %v\n", err)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("sources", *sourceList, randStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Random number sources (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+		fmt.Printf("\n  %-12s", "Goroutines")
+		for _, s := range strategies {
+			fmt.Printf(" %10s", s.name)
+		}
+		fmt.Println()
+	}
+
+	var results []harness.Result
+	for _, g := range goroutines {
+		if r.Interrupted() {
+			break
+		}
+		variants := make([]harness.Variant, len(strategies))
+		for i, s := range strategies {
+			srcs := s.open(g)
+			variants[i] = r.Variant(fmt.Sprintf("%s/G=%d", s.name, g), func() { sinkUint64 ^= srcs[0].Uint64() },
+				func(n int) time.Duration { return timeRand(n, srcs) })
+		}
+		rs, err := r.Measure(variants)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, rs...)
+
+		if r.Format == harness.FormatText && !r.Partial() {
+			cells := make([]string, len(rs))
+			for i, res := range rs {
+				cells[i] = fmt.Sprintf("%10.2f", res.NsPerOp())
+			}
+			fmt.Printf("  %-12d %s\n", g, strings.Join(rp.Winners(rs, cells...), " "))
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns per number, wall time over all goroutines; fastest in each row highlighted.\n")
+		fmt.Printf("Global, Locked and V2 are shared by every goroutine, Rand and PCG one per goroutine.\n")
+		fmt.Printf("Locked is math/rand's top-level functions as they were before Go 1.20, and still\n")
+		fmt.Printf("are after rand.Seed with GODEBUG=randseednop=0.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
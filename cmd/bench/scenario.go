@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+// scenarioBench is the benchmark name of results from internal/combined
+// scenarios, whose variants are the scenario names. It matches
+// BenchmarkScenario's gobench lines.
+const scenarioBench = "Scenario"
+
+// runFlags are the measurement flags the bench subcommands share with
+// the cmd tools.
+type runFlags struct {
+	n         int
+	benchtime time.Duration
+	count     int
+	cpu       int // -1 = unpinned
+	warm      harness.Warmup
+	outliers  float64
+}
+
+// measure times scenario s count times, run as combined.Variant runs it
+// and set up only if prof measures it in this process (see -isolate),
+// and records the seed its data came from. prof may be nil.
+func (rf runFlags) measure(s combined.Scenario, prof *harness.Profiler) (harness.Result, error) {
+	var err error
+	v := harness.Variant{Name: s.Name()}
+	if prof.Runs(v.Name) {
+		v = combined.Variant(s, rf.n, rf.benchtime, rf.cpu, rf.warm, &err)
+	}
+	r := prof.Measure(v.Name, v.N, rf.count, v.Timed).WithoutOutliers(rf.outliers)
+	r.Seed = combined.Seed()
+	return r, err
+}
+
+// selectScenarios returns the scenarios named in list, a comma-separated
+// -scenario value, or every registered scenario if list is empty.
+func selectScenarios(list string) ([]combined.Scenario, error) {
+	if list == "" {
+		return combined.Scenarios(), nil
+	}
+	var scenarios []combined.Scenario
+	for _, name := range strings.Split(list, ",") {
+		s, ok := combined.Lookup(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown scenario %q (see context-ticker -list)", name)
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/lock"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/sema"
+)
+
+// semaStrategy is a semaphore bench sema can time.
+type semaStrategy struct {
+	name string
+	open func(limit int) (sema.Semaphore, error)
+}
+
+// semaStrategies are the semaphores bench sema times, in report order.
+var semaStrategies = []semaStrategy{
+	{"Chan", func(limit int) (sema.Semaphore, error) { return sema.NewChan(limit) }},
+	{"Weighted", func(limit int) (sema.Semaphore, error) { return sema.NewWeighted(limit) }},
+	{"Atomic", func(limit int) (sema.Semaphore, error) { return sema.NewAtomic(limit) }},
+}
+
+// holdSlot takes a slot of s, does rounds rounds of lock.Work holding
+// it, and gives it back.
+func holdSlot(s sema.Semaphore, rounds int) uint64 {
+	s.Acquire()
+	v := lock.Work(0, rounds)
+	s.Release()
+	return v
+}
+
+// timeSema runs n holdSlots on s split across goroutines goroutines, and
+// returns how long they took.
+func timeSema(n, goroutines int, s sema.Semaphore, rounds int) time.Duration {
+	start := time.Now()
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		count := n / goroutines
+		if g < n%goroutines {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var v uint64
+			for range count {
+				v += holdSlot(s, rounds)
+			}
+			sinkUint64 = v
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// semaRun runs `bench sema`.
+func semaRun(args []string) {
+	nameOf := func(s semaStrategy) string { return s.name }
+	fs := flag.NewFlagSet("sema", flag.ExitOnError)
+	semaList := fs.String("semas", "", "comma-separated semaphores to time (default all): "+strings.Join(namesOf(semaStrategies, nameOf), ", "))
+	goroutineList := fs.String("goroutines", "1,4,16", "goroutine counts acquiring at once to sweep (comma-separated)")
+	limitList := fs.String("limits", "1,4", "semaphore limits, the slots goroutines share, to sweep (comma-separated)")
+	work := fs.Int("work", 100, "rounds of multiply-add work done holding a slot (0 = none)")
+	r := harness.NewRunner(fs, "Sema", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	goroutines, err := harness.ParseCounts(*goroutineList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -goroutines: %v\n", err)
+		os.Exit(2)
+	}
+	limits, err := harness.ParseCounts(*limitList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -limits: %v\n", err)
+		os.Exit(2)
+	}
+	if *work < 0 {
+		fmt.Fprintf(os.Stderr, "invalid -work: must be at least 0, got %d\n", *work)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("semas", *semaList, semaStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Semaphores (%s, %d rounds of work per slot)", harness.RunLength(r.N, r.Benchtime), *work), r.Settings()...)
+	}
+
+	var results []harness.Result
+	for _, limit := range limits {
+		if r.Interrupted() {
+			break
+		}
+		if r.Format == harness.FormatText {
+			fmt.Printf("\nLimit: %d\n", limit)
+			fmt.Printf("  %-12s", "Goroutines")
+			for _, s := range strategies {
+				fmt.Printf(" %10s", s.name)
+			}
+			fmt.Println()
+		}
+		for _, g := range goroutines {
+			if r.Interrupted() {
+				break
+			}
+			variants := make([]harness.Variant, len(strategies))
+			for i, st := range strategies {
+				s, err := st.open(limit)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", st.name, err)
+					os.Exit(1)
+				}
+				variants[i] = r.Variant(fmt.Sprintf("%s/limit=%d/G=%d", st.name, limit, g),
+					func() { sinkUint64 = holdSlot(s, *work) },
+					func(n int) time.Duration { return timeSema(n, g, s, *work) })
+			}
+			rs, err := r.Measure(variants)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			results = append(results, rs...)
+
+			if r.Format == harness.FormatText && !r.Partial() {
+				cells := make([]string, len(rs))
+				for i, res := range rs {
+					cells[i] = fmt.Sprintf("%10.2f", res.NsPerOp())
+				}
+				fmt.Printf("  %-12d %s\n", g, strings.Join(rp.Winners(rs, cells...), " "))
+			}
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns per Acquire, work and Release; fastest in each row highlighted. Goroutines\n")
+		fmt.Printf("up to the limit never wait: above it, each Release hands a slot to a parked waiter.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"flag"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+// serve runs `bench serve`.
+func serve(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	dbPath := fs.String("db", "results.db", "results database written by the cmd tools' -db flag")
+	addr := fs.String("addr", "localhost:8080", "address to serve the UI on")
+	_ = fs.Parse(args)
+
+	// Fail now, not on the first request, if the database can't be read.
+	if _, err := harness.ReadRuns(*dbPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	s := &server{db: *dbPath}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.HandleFunc("GET /run/{id}", s.run)
+	mux.HandleFunc("GET /diff", s.diff)
+	mux.HandleFunc("GET /history", s.history)
+
+	fmt.Printf("Serving %s on http://%s/\n", *dbPath, *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// server serves the UI. It reads the database afresh for every request,
+// read-only, so the cmd tools can keep adding runs while it is up and
+// they show on the next reload.
+type server struct {
+	db string
+}
+
+// series is one benchmark variant, whose history can be charted.
+type series struct {
+	Benchmark string
+	Variant   string
+}
+
+// index lists the runs, newest first, with a form to diff two of them
+// and links to every variant's history.
+func (s *server) index(w http.ResponseWriter, r *http.Request) {
+	runs, ok := s.runs(w)
+	if !ok {
+		return
+	}
+	slices.Reverse(runs)
+
+	seen := make(map[series]bool)
+	var all []series
+	for _, run := range runs {
+		for _, br := range run.Results {
+			if sr := (series{br.Benchmark, br.Variant}); !seen[sr] {
+				seen[sr] = true
+				all = append(all, sr)
+			}
+		}
+	}
+	slices.SortFunc(all, func(a, b series) int {
+		return cmp.Or(cmp.Compare(a.Benchmark, b.Benchmark), cmp.Compare(a.Variant, b.Variant))
+	})
+
+	render(w, "index", struct {
+		DB     string
+		Runs   []harness.Run
+		Series []series
+	}{s.db, runs, all})
+}
+
+// run shows one run's machine and results.
+func (s *server) run(w http.ResponseWriter, r *http.Request) {
+	runs, ok := s.runs(w)
+	if !ok {
+		return
+	}
+	run, ok := find(w, runs, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	render(w, "run", run)
+}
+
+// diff compares run b with run a, as -compare would: each of b's
+// variants against the same variant in a.
+func (s *server) diff(w http.ResponseWriter, r *http.Request) {
+	runs, ok := s.runs(w)
+	if !ok {
+		return
+	}
+	a, ok := find(w, runs, r.FormValue("a"))
+	if !ok {
+		return
+	}
+	b, ok := find(w, runs, r.FormValue("b"))
+	if !ok {
+		return
+	}
+	render(w, "diff", struct {
+		A, B   harness.Run
+		Deltas []harness.Delta
+	}{a, b, a.Diff(b.Baseline)})
+}
+
+// metrics are the values history can chart, by their query name.
+var metrics = map[string]struct {
+	Unit     string
+	value    func(harness.BaselineResult) float64
+	optional bool // 0 means not measured, and the run is left out
+}{
+	"ns":     {"ns/op", func(br harness.BaselineResult) float64 { return br.NsPerOp }, false},
+	"bytes":  {"B/op", func(br harness.BaselineResult) float64 { return float64(br.BytesPerOp) }, false},
+	"allocs": {"allocs/op", func(br harness.BaselineResult) float64 { return float64(br.AllocsPerOp) }, false},
+	"cycles": {"cycles/op", func(br harness.BaselineResult) float64 { return br.CyclesPerOp }, true},
+}
+
+// point is one run's value of the charted metric.
+type point struct {
+	Run   harness.Run
+	Value float64
+}
+
+// history charts one variant's metric across every run that has it.
+func (s *server) history(w http.ResponseWriter, r *http.Request) {
+	runs, ok := s.runs(w)
+	if !ok {
+		return
+	}
+	sr := series{r.FormValue("bench"), r.FormValue("variant")}
+	metric := cmp.Or(r.FormValue("metric"), "ns")
+	m, ok := metrics[metric]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown metric %q: use ns, bytes, allocs or cycles", metric), http.StatusBadRequest)
+		return
+	}
+
+	var points []point
+	for _, run := range runs {
+		for _, br := range run.Results {
+			if br.Benchmark == sr.Benchmark && br.Variant == sr.Variant {
+				if v := m.value(br); v != 0 || !m.optional {
+					points = append(points, point{run, v})
+				}
+				break
+			}
+		}
+	}
+	if len(points) == 0 {
+		http.Error(w, fmt.Sprintf("no runs of %s/%s", sr.Benchmark, sr.Variant), http.StatusNotFound)
+		return
+	}
+
+	render(w, "history", struct {
+		series
+		Metric string
+		Unit   string
+		Points []point
+		Chart  chart
+	}{sr, metric, m.Unit, points, newChart(points)})
+}
+
+// runs reads every run, answering the request with an error if it can't.
+func (s *server) runs(w http.ResponseWriter) ([]harness.Run, bool) {
+	runs, err := harness.ReadRuns(s.db)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return runs, true
+}
+
+// find returns the run whose ID is id, answering the request with an
+// error if there is none.
+func find(w http.ResponseWriter, runs []harness.Run, id string) (harness.Run, bool) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("bad run ID %q", id), http.StatusBadRequest)
+		return harness.Run{}, false
+	}
+	for _, run := range runs {
+		if run.ID == n {
+			return run, true
+		}
+	}
+	http.Error(w, fmt.Sprintf("no run %d", n), http.StatusNotFound)
+	return harness.Run{}, false
+}
+
+// Chart size and the margin left around the plot for axis labels, in
+// SVG user units.
+const (
+	chartWidth  = 720
+	chartHeight = 240
+	chartMargin = 48
+)
+
+// chart is a line chart of points, drawn as an SVG polyline from zero up
+// to the largest value so small changes don't look like cliffs.
+type chart struct {
+	Width, Height int
+	Left, Right   int // Plot area's x range
+	Top, Bottom   int // Plot area's y range
+	Max           float64
+	Line          string // Polyline points, "x,y x,y ..."
+	Dots          []dot
+}
+
+// dot is one point's marker on a chart.
+type dot struct {
+	X, Y  float64
+	Label string
+}
+
+// newChart lays points out evenly along the x axis, in run order.
+func newChart(points []point) chart {
+	c := chart{
+		Width: chartWidth, Height: chartHeight,
+		Left: chartMargin, Right: chartWidth - chartMargin/2,
+		Top: chartMargin / 2, Bottom: chartHeight - chartMargin,
+	}
+	for _, p := range points {
+		c.Max = max(c.Max, p.Value)
+	}
+	top := c.Max
+	if top == 0 {
+		top = 1
+	}
+
+	var line strings.Builder
+	for i, p := range points {
+		x := float64(c.Left+c.Right) / 2
+		if len(points) > 1 {
+			x = float64(c.Left) + float64(i)*float64(c.Right-c.Left)/float64(len(points)-1)
+		}
+		y := float64(c.Bottom) - p.Value/top*float64(c.Bottom-c.Top)
+		fmt.Fprintf(&line, "%.1f,%.1f ", x, y)
+		c.Dots = append(c.Dots, dot{x, y, fmt.Sprintf("run %d, %s: %.2f", p.Run.ID, p.Run.Machine.Hostname, p.Value)})
+	}
+	c.Line = strings.TrimSpace(line.String())
+	return c
+}
+
+// render executes the named page template, buffering it so a failure
+// can still be reported as an error response.
+func render(w http.ResponseWriter, name string, data any) {
+	var buf bytes.Buffer
+	if err := pages.ExecuteTemplate(&buf, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = buf.WriteTo(w)
+}
+
+var pages = template.Must(template.New("").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05") },
+	"benchmarks": func(run harness.Run) string {
+		var names []string
+		for _, br := range run.Results {
+			if !slices.Contains(names, br.Benchmark) {
+				names = append(names, br.Benchmark)
+			}
+		}
+		return strings.Join(names, ", ")
+	},
+}).Parse(`
+{{define "head"}}<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.}} - bench</title>
+<style>
+body { font: 14px sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { padding: 0.25em 0.75em; text-align: left; border-bottom: 1px solid #ddd; }
+td.num { text-align: right; font-family: monospace; }
+.slower { color: #b00; }
+.faster { color: #070; }
+svg { border: 1px solid #ddd; }
+</style></head>
+<body><p><a href="/">All runs</a></p><h1>{{.}}</h1>
+{{end}}
+
+{{define "foot"}}</body></html>
+{{end}}
+
+{{define "index"}}{{template "head" "Runs"}}
+<p>{{len .Runs}} runs in {{.DB}}.</p>
+{{if .Runs}}
+<form action="/diff">
+Diff run <select name="b">{{range .Runs}}<option value="{{.ID}}">{{.ID}}</option>{{end}}</select>
+against run <select name="a">{{range $i, $_ := .Runs}}<option value="{{.ID}}"{{if eq $i 1}} selected{{end}}>{{.ID}}</option>{{end}}</select>
+<button>Diff</button>
+</form>
+<table>
+<tr><th>Run</th><th>Time</th><th>Host</th><th>Benchmarks</th><th>Variants</th></tr>
+{{range .Runs}}<tr><td><a href="/run/{{.ID}}">{{.ID}}</a></td><td>{{time .Time}}</td><td>{{.Machine.Hostname}}</td><td>{{benchmarks .}}</td><td class="num">{{len .Results}}</td></tr>
+{{end}}</table>
+<h2>History</h2>
+<ul>
+{{range .Series}}<li><a href="/history?bench={{.Benchmark}}&amp;variant={{.Variant}}">{{.Benchmark}}/{{.Variant}}</a></li>
+{{end}}</ul>
+{{else}}<p>Record runs with a cmd tool's -db flag, e.g. <code>go run ./cmd/ticker -db {{.DB}}</code>.</p>
+{{end}}{{template "foot"}}{{end}}
+
+{{define "run"}}{{template "head" (printf "Run %d" .ID)}}
+<p>{{time .Time}} on {{.Machine.Hostname}}: {{.Machine}}</p>
+<table>
+<tr><th>Benchmark</th><th>Variant</th><th>Iterations</th><th>Runs</th><th>ns/op</th><th>B/op</th><th>allocs/op</th></tr>
+{{range .Results}}<tr><td>{{.Benchmark}}</td><td><a href="/history?bench={{.Benchmark}}&amp;variant={{.Variant}}">{{.Variant}}</a></td>
+<td class="num">{{.Iterations}}</td><td class="num">{{len .Samples}}</td><td class="num">{{printf "%.2f" .NsPerOp}}</td>
+<td class="num">{{.BytesPerOp}}</td><td class="num">{{.AllocsPerOp}}</td></tr>
+{{end}}</table>
+{{template "foot"}}{{end}}
+
+{{define "diff"}}{{template "head" (printf "Run %d vs run %d" .B.ID .A.ID)}}
+<p>Old: <a href="/run/{{.A.ID}}">run {{.A.ID}}</a>, {{time .A.Time}} on {{.A.Machine}}<br>
+New: <a href="/run/{{.B.ID}}">run {{.B.ID}}</a>, {{time .B.Time}} on {{.B.Machine}}</p>
+<table>
+<tr><th>Benchmark</th><th>Variant</th><th>Old ns/op</th><th>New ns/op</th><th>Delta</th></tr>
+{{range .Deltas}}<tr><td>{{.Benchmark}}</td><td>{{.Variant}}</td>
+{{if .Found}}<td class="num">{{printf "%.2f" .Old}}</td><td class="num">{{printf "%.2f" .New}}</td>
+<td class="num {{if gt .Percent 0.0}}slower{{else if lt .Percent 0.0}}faster{{end}}">{{printf "%+.2f%%" .Percent}}</td>
+{{else}}<td class="num">-</td><td class="num">{{printf "%.2f" .New}}</td><td>not in run {{$.A.ID}}</td>{{end}}</tr>
+{{end}}</table>
+<p>Positive is slower.</p>
+{{template "foot"}}{{end}}
+
+{{define "history"}}{{template "head" (printf "%s/%s %s" .Benchmark .Variant .Unit)}}
+<p>Chart:
+<a href="?bench={{.Benchmark}}&amp;variant={{.Variant}}&amp;metric=ns">ns/op</a> |
+<a href="?bench={{.Benchmark}}&amp;variant={{.Variant}}&amp;metric=bytes">B/op</a> |
+<a href="?bench={{.Benchmark}}&amp;variant={{.Variant}}&amp;metric=allocs">allocs/op</a> |
+<a href="?bench={{.Benchmark}}&amp;variant={{.Variant}}&amp;metric=cycles">cycles/op</a></p>
+{{with .Chart}}<svg width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}">
+<line x1="{{.Left}}" y1="{{.Bottom}}" x2="{{.Right}}" y2="{{.Bottom}}" stroke="#999"/>
+<line x1="{{.Left}}" y1="{{.Top}}" x2="{{.Left}}" y2="{{.Bottom}}" stroke="#999"/>
+<text x="{{.Left}}" y="{{.Top}}" dx="-4" dy="4" text-anchor="end" font-size="11">{{printf "%.4g" .Max}}</text>
+<text x="{{.Left}}" y="{{.Bottom}}" dx="-4" dy="4" text-anchor="end" font-size="11">0</text>
+<polyline points="{{.Line}}" fill="none" stroke="#36c" stroke-width="2"/>
+{{range .Dots}}<circle cx="{{.X}}" cy="{{.Y}}" r="3" fill="#36c"><title>{{.Label}}</title></circle>
+{{end}}</svg>{{end}}
+<table>
+<tr><th>Run</th><th>Time</th><th>Host</th><th>{{.Unit}}</th></tr>
+{{range .Points}}<tr><td><a href="/run/{{.Run.ID}}">{{.Run.ID}}</a></td><td>{{time .Run.Time}}</td><td>{{.Run.Machine.Hostname}}</td><td class="num">{{printf "%.2f" .Value}}</td></tr>
+{{end}}</table>
+{{template "foot"}}{{end}}
+`))
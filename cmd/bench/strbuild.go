@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/strbuild"
+)
+
+// strbuildStrategy is a way of building a string bench strbuild can time.
+type strbuildStrategy struct {
+	name  string
+	build func(parts []string) string
+}
+
+// strbuildStrategies are the strategies bench strbuild times, in report
+// order.
+var strbuildStrategies = []strbuildStrategy{
+	{"Concat", strbuild.Concat},
+	{"Sprintf", strbuild.Sprintf},
+	{"Builder", strbuild.Builder},
+	{"Buffer", strbuild.Buffer},
+	{"Grow", strbuild.Grow},
+	{"Append", strbuild.Append},
+}
+
+// sinkString keeps the compiler from eliminating the strings built.
+var sinkString string
+
+// strbuildRun runs `bench strbuild`.
+func strbuildRun(args []string) {
+	nameOf := func(s strbuildStrategy) string { return s.name }
+	fs := flag.NewFlagSet("strbuild", flag.ExitOnError)
+	builderList := fs.String("builders", "", "comma-separated strategies to time (default all): "+strings.Join(namesOf(strbuildStrategies, nameOf), ", "))
+	partList := fs.String("parts", "2,8,64", "part counts to sweep (comma-separated)")
+	lengthList := fs.String("lengths", "8,256", "part lengths in bytes to sweep (comma-separated)")
+	r := harness.NewRunner(fs, "StrBuild", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	parts, err := harness.ParseCounts(*partList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -parts: %v\n", err)
+		os.Exit(2)
+	}
+	lengths, err := harness.ParseCounts(*lengthList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -lengths: %v\n", err)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("builders", *builderList, strbuildStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("String construction (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+		fmt.Printf("\n  %-14s", "Parts x Len")
+		for _, s := range strategies {
+			fmt.Printf(" %13s", s.name)
+		}
+		fmt.Println()
+	}
+
+	var results []harness.Result
+	for _, n := range parts {
+		for _, length := range lengths {
+			if r.Interrupted() {
+				break
+			}
+			p := strbuild.Parts(n, length)
+			variants := make([]harness.Variant, len(strategies))
+			for i, s := range strategies {
+				variants[i] = r.Variant(fmt.Sprintf("%s/parts=%d/len=%d", s.name, n, length), func() { sinkString = s.build(p) },
+					func(n int) time.Duration {
+						var out string
+						start := time.Now()
+						for j := 0; j < n; j++ {
+							out = s.build(p)
+						}
+						d := time.Since(start)
+						sinkString = out
+						return d
+					})
+			}
+			rs, err := r.Measure(variants)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			results = append(results, rs...)
+
+			if r.Format == harness.FormatText && !r.Partial() {
+				cells := make([]string, len(rs))
+				for i, res := range rs {
+					cells[i] = fmt.Sprintf("%9.2f %3d", res.NsPerOp(), res.AllocsPerOp)
+				}
+				fmt.Printf("  %-14s %s\n", fmt.Sprintf("%d x %d B", n, length), strings.Join(rp.Winners(rs, cells...), " "))
+			}
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns and heap allocations per string built; fastest in each row highlighted.\n")
+		fmt.Printf("Grow and Append size their buffer from the parts' total length first.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/timefmt"
+)
+
+// timefmtStrategy is a way of writing a timestamp bench timefmt can time.
+type timefmtStrategy struct {
+	name   string
+	append func(b []byte, t time.Time) []byte
+}
+
+// timefmtStrategies are the strategies bench timefmt times, in report
+// order.
+var timefmtStrategies = []timefmtStrategy{
+	{"Format", timefmt.Format},
+	{"AppendFormat", timefmt.AppendFormat},
+	{"Manual", timefmt.Manual},
+	{"UnixNano", timefmt.UnixNano},
+}
+
+// timefmtZones are the locations bench timefmt formats timestamps in:
+// UTC, which needs no offset, a fixed offset, and time.Local, which has
+// to look up the offset in effect.
+var timefmtZones = []struct {
+	name string
+	loc  *time.Location
+}{
+	{"UTC", time.UTC},
+	{"Fixed", time.FixedZone("IST", 5*3600+30*60)},
+	{"Local", time.Local},
+}
+
+// sinkTimestamp keeps the compiler from eliminating the formatting.
+var sinkTimestamp []byte
+
+// timefmtRun runs `bench timefmt`.
+func timefmtRun(args []string) {
+	nameOf := func(s timefmtStrategy) string { return s.name }
+	fs := flag.NewFlagSet("timefmt", flag.ExitOnError)
+	formatList := fs.String("formats", "", "comma-separated strategies to time (default all): "+strings.Join(namesOf(timefmtStrategies, nameOf), ", "))
+	r := harness.NewRunner(fs, "TimeFmt", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("formats", *formatList, timefmtStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Timestamp formatting (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+		fmt.Printf("\n  %-8s", "Zone")
+		for _, s := range strategies {
+			fmt.Printf(" %14s", s.name)
+		}
+		fmt.Println()
+	}
+
+	var results []harness.Result
+	for _, z := range timefmtZones {
+		if r.Interrupted() {
+			break
+		}
+		t := time.Date(2025, 3, 9, 14, 5, 7, 123456789, time.UTC).In(z.loc)
+		variants := make([]harness.Variant, len(strategies))
+		for i, s := range strategies {
+			buf := make([]byte, 0, 64)
+			variants[i] = r.Variant(fmt.Sprintf("%s/zone=%s", s.name, z.name), func() { buf = s.append(buf[:0], t) },
+				func(n int) time.Duration {
+					start := time.Now()
+					for j := 0; j < n; j++ {
+						buf = s.append(buf[:0], t)
+					}
+					d := time.Since(start)
+					sinkTimestamp = buf
+					return d
+				})
+		}
+		rs, err := r.Measure(variants)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, rs...)
+
+		if r.Format == harness.FormatText && !r.Partial() {
+			cells := make([]string, len(rs))
+			for i, res := range rs {
+				cells[i] = fmt.Sprintf("%10.2f %3d", res.NsPerOp(), res.AllocsPerOp)
+			}
+			fmt.Printf("  %-8s %s\n", z.name, strings.Join(rp.Winners(rs, cells...), " "))
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns and heap allocations per timestamp appended to a reused buffer; fastest in each\n")
+		fmt.Printf("row highlighted. All but UnixNano write %s.\n", timefmt.Layout)
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
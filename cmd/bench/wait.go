@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/wait"
+)
+
+// waitStrategy is a completion signaling approach bench wait can time.
+type waitStrategy struct {
+	name string
+	new  func() wait.Waiter
+}
+
+// waitStrategies are the Waiters bench wait times, in report order.
+var waitStrategies = []waitStrategy{
+	{"WaitGroup", func() wait.Waiter { return wait.NewWaitGroup() }},
+	{"ErrGroup", func() wait.Waiter { return wait.NewErrGroup() }},
+	{"Chan", func() wait.Waiter { return wait.NewChan() }},
+	{"Latch", func() wait.Waiter { return wait.NewLatch() }},
+}
+
+// noopTask is the task bench wait starts: empty, so it measures starting
+// and signaling rather than work.
+func noopTask() {}
+
+// timeWait starts n tasks in batches of batch, each on a new Waiter that
+// it waits on before starting the next, and returns how long that took.
+// The last batch is smaller if batch doesn't divide n.
+func timeWait(n, batch int, newWaiter func() wait.Waiter) time.Duration {
+	start := time.Now()
+	for left := n; left > 0; left -= batch {
+		w := newWaiter()
+		for range min(batch, left) {
+			w.Go(noopTask)
+		}
+		w.Wait()
+	}
+	return time.Since(start)
+}
+
+// waitRun runs `bench wait`.
+func waitRun(args []string) {
+	nameOf := func(s waitStrategy) string { return s.name }
+	fs := flag.NewFlagSet("wait", flag.ExitOnError)
+	waiterList := fs.String("waiters", "", "comma-separated Waiters to time (default all): "+strings.Join(namesOf(waitStrategies, nameOf), ", "))
+	taskList := fs.String("tasks", "1,8,64,512", "tasks per batch, started together and waited for, to sweep (comma-separated)")
+	r := harness.NewRunner(fs, "Wait", 1_000_000, 1, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if r.LatencyTracing() {
+		fmt.Fprintln(os.Stderr, "-latency-trace doesn't support bench wait: one task's completion spans goroutines")
+		os.Exit(2)
+	}
+	tasks, err := harness.ParseCounts(*taskList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -tasks: %v\n", err)
+		os.Exit(2)
+	}
+	strategies, err := selectNamed("waiters", *waiterList, waitStrategies, nameOf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Completion signaling (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+		fmt.Printf("\n  %-10s", "Tasks")
+		for _, s := range strategies {
+			fmt.Printf(" %15s", s.name)
+		}
+		fmt.Println()
+	}
+
+	var results []harness.Result
+	for _, batch := range tasks {
+		if r.Interrupted() {
+			break
+		}
+		variants := make([]harness.Variant, len(strategies))
+		for i, s := range strategies {
+			// The op, for -warmup, is a batch of one
+			variants[i] = r.Variant(fmt.Sprintf("%s/N=%d", s.name, batch), func() { timeWait(1, 1, s.new) },
+				func(n int) time.Duration { return timeWait(n, batch, s.new) })
+		}
+		rs, err := r.Measure(variants)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, rs...)
+
+		if r.Format == harness.FormatText && !r.Partial() {
+			cells := make([]string, len(rs))
+			for i, res := range rs {
+				cells[i] = fmt.Sprintf("%9.2f %4dB", res.NsPerOp(), res.BytesPerOp)
+			}
+			fmt.Printf("  %-10d %s\n", batch, strings.Join(rp.Winners(rs, cells...), " "))
+		}
+	}
+
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nns and bytes allocated per task, from its Go to Wait returning on its batch;\n")
+		fmt.Printf("fastest in each row highlighted. Starting the goroutine is most of it.\n")
+		rp.Summary(r.Count, results)
+	}
+
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+// watchUnsupported are the shared run flags bench watch has no use for:
+// it measures each scenario on its own every round and streams the
+// results rather than saving or tracing them.
+var watchUnsupported = []string{
+	"shuffle", "isolate", "save", "compare", "fail-on-regression", "latency-trace", "latency-sample",
+	"cpuprofile-dir", "memprofile-dir", "blockprofile-dir", "mutexprofile-dir", "folded-dir",
+}
+
+// watch runs `bench watch`.
+func watch(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	scenarioList := fs.String("scenario", "", "comma-separated scenarios to run each round (default all; see context-ticker -list)")
+	interval := fs.Duration("interval", 5*time.Minute, "time from the start of one round to the start of the next")
+	rounds := fs.Int("rounds", 0, "stop after this many rounds and summarize them (0 = run until interrupted)")
+	cpu := fs.Int("cpu", -1, "pin the loop to this CPU (-1 = unpinned)")
+	seed := fs.Uint64("seed", combined.DefaultSeed, combined.SeedUsage)
+	addr := fs.String("addr", "localhost:9477", "address to serve /metrics on (empty = don't serve)")
+	r := harness.NewRunner(fs, scenarioBench, 1_000_000, 5, 0)
+	_ = fs.Parse(args)
+
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if slices.Contains(watchUnsupported, f.Name) {
+			fmt.Fprintf(os.Stderr, "-%s doesn't support bench watch: it measures each scenario on its own every round and streams the results\n", f.Name)
+			os.Exit(2)
+		}
+	})
+	stream, err := harness.NewStream(os.Stdout, r.Format, scenarioBench)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	// Only the stream goes to stdout in the machine-readable formats
+	notes := io.Writer(os.Stdout)
+	if r.Format != harness.FormatText {
+		notes = r.Notes
+	}
+
+	if *interval <= 0 {
+		fmt.Fprintln(os.Stderr, "bench watch: -interval must be positive")
+		os.Exit(2)
+	}
+	if *rounds < 0 {
+		fmt.Fprintln(os.Stderr, "bench watch: -rounds must be 0 or more")
+		os.Exit(2)
+	}
+	scenarios, err := selectScenarios(*scenarioList)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := combined.SetSeed(*seed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	wr := &watcher{
+		runFlags:  runFlags{n: r.N, benchtime: r.Benchtime, count: r.Count, cpu: *cpu, warm: r.Warm, outliers: r.Outliers},
+		scenarios: scenarios,
+		prof:      r.Prof,
+		db:        r.Baselines.DB,
+		stream:    stream,
+		history:   make(map[string]*drift),
+	}
+	if *addr != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /metrics", wr.metrics)
+		go func() {
+			if err := http.ListenAndServe(*addr, mux); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}()
+		fmt.Fprintf(notes, "Serving metrics on http://%s/metrics\n", *addr)
+	}
+	fmt.Fprintf(notes, "Running %d scenarios every %v\n", len(scenarios), *interval)
+
+	start := time.Now()
+	tick := time.NewTicker(*interval)
+	defer tick.Stop()
+	for round := 1; ; round++ {
+		wr.round(round)
+		if round == *rounds || !nextTick(tick.C) {
+			break
+		}
+	}
+	wr.summary(notes, start)
+}
+
+// nextTick waits for the next tick of c and reports whether it came before
+// an interrupt (see harness.CatchInterrupts).
+func nextTick(c <-chan time.Time) bool {
+	poll := time.NewTicker(100 * time.Millisecond)
+	defer poll.Stop()
+	for !harness.Interrupted() {
+		select {
+		case <-c:
+			return true
+		case <-poll.C:
+		}
+	}
+	return false
+}
+
+// watcher reruns its scenarios each round and keeps the latest results
+// for /metrics.
+type watcher struct {
+	runFlags
+	scenarios []combined.Scenario
+	prof      *harness.Profiler // For -cycles and -gc-off; profiles nothing
+	db        string
+	stream    *harness.Stream
+	history   map[string]*drift // By scenario, across rounds
+	rounds    int               // Rounds run
+
+	mu      sync.Mutex
+	results []harness.Result // Latest round's, in scenario order
+	machine harness.Machine
+	last    time.Time // When the latest round finished
+}
+
+// drift is one scenario's results across rounds, for the summary.
+type drift struct {
+	nsPerOp []float64 // Each round's median ns/op
+	within  []float64 // Each round's range of ns/op over its median
+}
+
+// round runs every scenario once, as context-ticker -scenario would,
+// streams each result as it comes, and publishes them. A scenario that
+// fails is logged and left out of this round; the others still run.
+// Throttled runs are logged but kept. An interrupt ends the round after
+// the current scenario.
+func (wr *watcher) round(round int) {
+	var results []harness.Result
+	m := harness.CurrentMachine()
+	for _, s := range wr.scenarios {
+		if harness.Interrupted() {
+			break
+		}
+		r, err := wr.measure(s, wr.prof)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", time.Now().Format(time.DateTime), err)
+			continue
+		}
+		if err := wr.stream.Write(round, time.Now(), m, []harness.Result{r}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, r)
+
+		d := wr.history[r.Name]
+		if d == nil {
+			d = &drift{}
+			wr.history[r.Name] = d
+		}
+		st := r.Stats()
+		d.nsPerOp = append(d.nsPerOp, st.Median)
+		d.within = append(d.within, (st.Max-st.Min)/st.Median)
+	}
+	wr.rounds = round
+	_ = harness.CheckThrottling(os.Stderr, results)
+
+	wr.mu.Lock()
+	wr.results, wr.machine, wr.last = results, m, time.Now()
+	wr.mu.Unlock()
+
+	if wr.db != "" && len(results) > 0 {
+		if _, err := harness.AddRun(wr.db, harness.NewBaseline(scenarioBench, m, results)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// metrics serves the latest round's results as Prometheus gauges, with
+// bench_watch_last_round_timestamp_seconds so stale numbers can be
+// alerted on.
+func (wr *watcher) metrics(w http.ResponseWriter, r *http.Request) {
+	wr.mu.Lock()
+	results, m, last := wr.results, wr.machine, wr.last
+	wr.mu.Unlock()
+
+	var buf bytes.Buffer
+	if !last.IsZero() {
+		if err := harness.WritePrometheus(&buf, scenarioBench, m, results); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		buf.WriteString("# HELP bench_watch_last_round_timestamp_seconds When the latest round of runs finished.\n")
+		buf.WriteString("# TYPE bench_watch_last_round_timestamp_seconds gauge\n")
+		fmt.Fprintf(&buf, "bench_watch_last_round_timestamp_seconds %d\n", last.Unix())
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = buf.WriteTo(w)
+}
+
+// summary writes to w how much each scenario's ns/op moved across the
+// rounds since start. Spread is the range of the rounds' medians over
+// their median, Within the median of each round's own range over its
+// -count runs: a Spread well beyond Within is drift between rounds,
+// which no -count smooths over.
+func (wr *watcher) summary(w io.Writer, start time.Time) {
+	if wr.rounds < 2 {
+		return
+	}
+	fmt.Fprintf(w, "\nAcross %d rounds, %s to %s:\n", wr.rounds, start.Format(time.DateTime), time.Now().Format(time.DateTime))
+	fmt.Fprintf(w, "  %-32s %6s %10s %10s %10s %8s %8s  (ns/op)\n", "Scenario", "Rounds", "Min", "Median", "Max", "Spread", "Within")
+	for _, s := range wr.scenarios {
+		d := wr.history[s.Name()]
+		if d == nil {
+			continue
+		}
+		st := harness.Summarize(d.nsPerOp)
+		fmt.Fprintf(w, "  %-32s %6d %10.2f %10.2f %10.2f %7.2f%% %7.2f%%\n", s.Name(), st.Count, st.Min, st.Median, st.Max,
+			(st.Max-st.Min)/st.Median*100, harness.Summarize(d.within).Median*100)
+	}
+	fmt.Fprintf(w, "\nSpread is the range of the rounds' medians, Within the typical range inside one\n")
+	fmt.Fprintf(w, "round's -count runs. A Spread well beyond Within means the machine drifted between\n")
+	fmt.Fprintf(w, "rounds: check its cooling, governor and background load before trusting it.\n")
+}
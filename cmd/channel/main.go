@@ -3,58 +3,147 @@
 // Usage:
 //
 //	go run ./cmd/channel -n 10000000 -size 1024
+//	go run ./cmd/channel -count 10
+//	go run ./cmd/channel -time 2s
+//	go run ./cmd/channel -count 10 -shuffle on
+//
+// The multi-producer sweep that was -mpsc is bench mpsc, which runs the
+// same channel and MPSCRing variants among others:
+//
+//	go run ./cmd/bench mpsc -queues channel,mpscring -producers 1,2,4,8,16
+//
+// -format=gobench or -format=csv prints the results machine-readably, and
+// -save and -compare record and diff them against a JSON baseline:
+//
+//	go run ./cmd/channel -save before.json
+//	go run ./cmd/channel -compare before.json
+//
+// -db adds the results as a run to a database for bench serve:
+//
+//	go run ./cmd/channel -db results.db
+//
+// -cpuprofile-dir writes a CPU profile per variant. -shuffle interleaves
+// the variants' runs.
 package main
 
 import (
 	"flag"
 	"fmt"
+	"os"
 	"time"
 
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
 	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
 )
 
 func main() {
-	iterations := flag.Int("n", 10_000_000, "number of iterations")
 	size := flag.Int("size", 1024, "queue size")
+	mpsc := flag.Bool("mpsc", false, "moved to bench mpsc: go run ./cmd/bench mpsc -queues channel,mpscring")
+	producerList := flag.String("producers", "", "moved to bench mpsc, with -mpsc")
+	r := harness.NewRunner(flag.CommandLine, "Channel", 10_000_000, 1, 0)
 	flag.Parse()
+	if *mpsc || *producerList != "" {
+		cmd := "go run ./cmd/bench mpsc -queues channel,mpscring"
+		if *producerList != "" {
+			cmd += " -producers " + *producerList
+		}
+		fmt.Fprintf(os.Stderr, "channel: the -mpsc producer sweep is now bench mpsc, with the same variant names:\n  %s\n", cmd)
+		os.Exit(2)
+	}
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	fmt.Printf("Benchmarking SPSC queue (%d iterations, size=%d)\n", *iterations, *size)
-	fmt.Println("─────────────────────────────────────────────────")
+	ch, err := queue.NewChannel[int](*size)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -size: %v\n", err)
+		os.Exit(2)
+	}
+	ring, err := queue.NewRingBuffer[int](*size)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -size: %v\n", err)
+		os.Exit(2)
+	}
+	floor, err := queue.NewUnsyncRing[int](*size)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -size: %v\n", err)
+		os.Exit(2)
+	}
+
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Benchmarking SPSC queue (%s, size=%d)", harness.RunLength(r.N, r.Benchtime), *size), r.Settings()...)
+	}
 
 	// Benchmark channel queue
-	ch := queue.NewChannel[int](*size)
-	start := time.Now()
-	for i := 0; i < *iterations; i++ {
-		ch.Push(i)
-		ch.Pop()
+	chLoop := func(n int) time.Duration {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			ch.Push(i)
+			ch.Pop()
+		}
+		return time.Since(start)
 	}
-	chDur := time.Since(start)
 
 	// Benchmark ring buffer
-	ring := queue.NewRingBuffer[int](*size)
-	start = time.Now()
-	for i := 0; i < *iterations; i++ {
-		ring.Push(i)
-		ring.Pop()
+	ringLoop := func(n int) time.Duration {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			ring.Push(i)
+			ring.Pop()
+		}
+		return time.Since(start)
+	}
+
+	// Benchmark the unsynchronized floor
+	floorLoop := func(n int) time.Duration {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			floor.Push(i)
+			floor.Pop()
+		}
+		return time.Since(start)
+	}
+
+	results, err := r.Measure([]harness.Variant{
+		r.Variant("Channel", func() { ch.Push(0); ch.Pop() }, chLoop),
+		r.Variant("RingBuffer", func() { ring.Push(0); ring.Pop() }, ringLoop),
+		r.Variant("UnsyncRing", func() { floor.Push(0); floor.Pop() }, floorLoop),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-	ringDur := time.Since(start)
 
-	// Results
-	chPerOp := float64(chDur.Nanoseconds()) / float64(*iterations)
-	ringPerOp := float64(ringDur.Nanoseconds()) / float64(*iterations)
+	if r.Format == harness.FormatText && !r.Partial() {
+		chRes, ringRes, floorRes := results[0], results[1], results[2]
 
-	fmt.Printf("\nResults (push + pop per iteration):\n")
-	fmt.Printf("  Channel:     %v (%.2f ns/op)\n", chDur, chPerOp)
-	fmt.Printf("  RingBuffer:  %v (%.2f ns/op)\n", ringDur, ringPerOp)
+		// The floor isn't a contender, so only the first two can win
+		labels := append(rp.Winners(results[:2], "Channel:   ", "RingBuffer:"), "UnsyncRing:")
+		fmt.Printf("\nResults (push + pop per iteration):\n")
+		fmt.Printf("  %s  %v (%.2f ns/op%s, %s)\n", labels[0], chRes.Elapsed(), chRes.NsPerOp(), chRes.CyclesText(), chRes.MemPerOp())
+		fmt.Printf("  %s  %v (%.2f ns/op%s, %s)\n", labels[1], ringRes.Elapsed(), ringRes.NsPerOp(), ringRes.CyclesText(), ringRes.MemPerOp())
+		fmt.Printf("  %s  %v (%.2f ns/op%s, %s)  <- floor: no atomics, no guards\n", labels[2], floorRes.Elapsed(), floorRes.NsPerOp(), floorRes.CyclesText(), floorRes.MemPerOp())
 
-	if ringPerOp < chPerOp {
-		fmt.Printf("\n  Speedup:  %.2fx (RingBuffer faster)\n", chPerOp/ringPerOp)
-	} else {
-		fmt.Printf("\n  Speedup:  %.2fx (Channel faster)\n", ringPerOp/chPerOp)
+		x := harness.Speedup(chRes, ringRes)
+		if x > 1 {
+			fmt.Printf("\n  Speedup:  %s (RingBuffer faster)\n", rp.Speedup("%.2fx", x))
+		} else {
+			fmt.Printf("\n  Speedup:  %s (Channel faster)\n", rp.Color.Speedup(fmt.Sprintf("%.2fx", 1/x), x))
+		}
+		fmt.Printf("  Sync cost: %.2f ns/op (RingBuffer - UnsyncRing)\n", ringRes.NsPerOp()-floorRes.NsPerOp())
+
+		rp.Summary(r.Count, results)
+		rp.Throughput([]string{"Channel:    ", "RingBuffer: ", "UnsyncRing: "}, results)
 	}
 
-	// Extrapolate to ops/second
-	fmt.Printf("\nThroughput (theoretical max):\n")
-	fmt.Printf("  Channel:     %.2f M ops/sec\n", 1000/chPerOp)
-	fmt.Printf("  RingBuffer:  %.2f M ops/sec\n", 1000/ringPerOp)
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
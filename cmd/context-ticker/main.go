@@ -3,105 +3,182 @@
 // This represents a realistic hot-loop pattern where you check both
 // context cancellation and periodic timing on every iteration.
 //
+// The loops are scenarios from the internal/combined registry. -list
+// prints every registered scenario, with the parameters it takes and
+// their defaults, and -scenario runs one by name. -cpu
+// pins the loop's thread to one CPU so runs don't migrate between cores.
+//
 // Usage:
 //
 //	go run ./cmd/context-ticker -n 10000000
+//	go run ./cmd/context-ticker -list
+//	go run ./cmd/context-ticker -scenario full-loop/optimized
+//	go run ./cmd/context-ticker -cpu 2
+//	go run ./cmd/context-ticker -count 10
+//	go run ./cmd/context-ticker -time 2s
+//	go run ./cmd/context-ticker -count 10 -shuffle on
+//	go run ./cmd/context-ticker -seed 42
+//	go run ./cmd/context-ticker -format=gobench
+//	go run ./cmd/context-ticker -save before.json
+//	go run ./cmd/context-ticker -compare before.json
+//	go run ./cmd/context-ticker -db results.db
+//	go run ./cmd/context-ticker -cpuprofile-dir prof
 package main
 
 import (
-	"context"
 	"flag"
 	"fmt"
-	"time"
+	"os"
 
-	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
-	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
 )
 
-func main() {
-	iterations := flag.Int("n", 10_000_000, "number of iterations")
-	flag.Parse()
-
-	interval := time.Hour // Long so we measure check overhead, not actual ticks
-
-	fmt.Printf("Benchmarking combined cancel+tick check (%d iterations)\n", *iterations)
-	fmt.Println("─────────────────────────────────────────────────────────")
-	fmt.Println()
-	fmt.Println("This simulates a hot loop that checks for cancellation")
-	fmt.Println("and periodic timing on every iteration:")
-	fmt.Println()
-	fmt.Println("  for {")
-	fmt.Println("      if cancel.Done() { return }")
-	fmt.Println("      if ticker.Tick() { doPeriodicWork() }")
-	fmt.Println("      processItem()")
-	fmt.Println("  }")
-	fmt.Println()
+// variant returns the named scenario ready to measure on cpu, as
+// combined.Variant runs it, storing the first error from its runs in
+// *err. If r doesn't measure it in this process (see -isolate), only its
+// name is set. It exits on an unknown name.
+func variant(r *harness.Runner, name string, cpu int, err *error) harness.Variant {
+	s, ok := combined.Lookup(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown scenario %q (see -list)\n", name)
+		os.Exit(2)
+	}
+	if !r.Prof.Runs(name) {
+		return harness.Variant{Name: name}
+	}
+	return combined.Variant(s, r.N, r.Benchtime, cpu, r.Warm, err)
+}
 
-	// Standard: context + time.Ticker
-	ctxCancel := cancel.NewContext(context.Background())
-	stdTicker := tick.NewTicker(interval)
+// measure times variants as r says, records their latencies for
+// -latency-trace with combined.Trace, unless the run was interrupted,
+// and notes the seed of the synthetic data they ran on. runErr is where
+// the variants store the first error from their runs. It exits on
+// failure.
+func measure(r *harness.Runner, variants []harness.Variant, cpu int, runErr *error) []harness.Result {
+	results, err := r.Measure(variants)
+	if err == nil {
+		err = *runErr
+	}
+	if err == nil && !r.Partial() {
+		for _, v := range variants {
+			s, _ := combined.Lookup(v.Name)
+			if err = combined.Trace(r.Trace, s, v.N, cpu); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for i := range results {
+		results[i].Seed = combined.Seed()
+	}
+	return results
+}
 
-	start := time.Now()
-	for i := 0; i < *iterations; i++ {
-		_ = ctxCancel.Done()
-		_ = stdTicker.Tick()
+// finish writes results machine-readably or applies -save and -compare
+// after the text report, as r says, exiting on failure or regression.
+func finish(r *harness.Runner, results []harness.Result) {
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-	stdDur := time.Since(start)
-	stdTicker.Stop()
+}
 
-	// Optimized: atomic cancel + atomic ticker
-	atomicCancel := cancel.NewAtomic()
-	atomicTicker := tick.NewAtomicTicker(interval)
+func main() {
+	list := flag.Bool("list", false, "list registered scenarios and exit")
+	scenario := flag.String("scenario", "", "run only the named scenario")
+	seed := flag.Uint64("seed", combined.DefaultSeed, combined.SeedUsage)
+	cpu := flag.Int("cpu", -1, "pin the loop to this CPU (-1 = unpinned)")
+	r := harness.NewRunner(flag.CommandLine, "ContextTicker", 10_000_000, 1, 0)
+	flag.Parse()
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := combined.SetSeed(*seed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	start = time.Now()
-	for i := 0; i < *iterations; i++ {
-		_ = atomicCancel.Done()
-		_ = atomicTicker.Tick()
+	if *list {
+		for _, s := range combined.Scenarios() {
+			if ps, ok := s.(combined.Parameterized); ok {
+				fmt.Printf("%-30s %s\n", s.Name(), ps.Params())
+			} else {
+				fmt.Println(s.Name())
+			}
+		}
+		return
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *scenario != "" {
+		var runErr error
+		v := variant(r, *scenario, *cpu, &runErr)
+		results := measure(r, []harness.Variant{v}, *cpu, &runErr)
+		if r.Format == harness.FormatText && !r.Partial() {
+			res := results[0]
+			fmt.Printf("%s: %v (%.2f ns/op%s, %s)\n", *scenario, res.Elapsed(), res.NsPerOp(), res.CyclesText(), res.MemPerOp())
+			if r.Count > 1 {
+				_ = harness.WriteSummary(os.Stdout, results)
+			}
+		}
+		finish(r, results)
+		return
 	}
-	optDur := time.Since(start)
 
-	// Ultra-optimized: atomic cancel + batch ticker
-	atomicCancel2 := cancel.NewAtomic()
-	batchTicker := tick.NewBatch(interval, 1000)
+	var runErr error
+	var variants []harness.Variant
+	for _, name := range []string{"cancel-tick/std", "cancel-tick/atomic", "cancel-tick/batch"} {
+		variants = append(variants, variant(r, name, *cpu, &runErr))
+	}
+	if r.Format != harness.FormatText {
+		finish(r, measure(r, variants, *cpu, &runErr))
+		return
+	}
 
-	start = time.Now()
-	for i := 0; i < *iterations; i++ {
-		_ = atomicCancel2.Done()
-		_ = batchTicker.Tick()
+	rp := r.Reporter()
+	settings := r.Settings()
+	if *seed != combined.DefaultSeed {
+		settings = append([]string{fmt.Sprintf("Seed: %d", *seed)}, settings...)
 	}
-	batchDur := time.Since(start)
+	rp.Header(fmt.Sprintf("Benchmarking combined cancel+tick check (%s)", harness.RunLength(r.N, r.Benchtime)), settings...)
+	fmt.Printf("\n%s\n\n", combined.Description("cancel-tick"))
+	fmt.Println("For what each variant does and where its time goes:")
+	fmt.Println("  go run ./cmd/bench explain cancel-tick")
+	fmt.Println()
 
-	// Results
-	stdPerOp := float64(stdDur.Nanoseconds()) / float64(*iterations)
-	optPerOp := float64(optDur.Nanoseconds()) / float64(*iterations)
-	batchPerOp := float64(batchDur.Nanoseconds()) / float64(*iterations)
+	// Standard: context + time.Ticker; optimized: atomic cancel + atomic
+	// ticker; ultra-optimized: atomic cancel + batch ticker
+	results := measure(r, variants, *cpu, &runErr)
+	if r.Partial() {
+		finish(r, results)
+		return
+	}
+	std, opt, batch := results[0], results[1], results[2]
+	labels := rp.Winners(results, "Standard (ctx + time.Ticker):", "Optimized (atomic + AtomicTicker):", "Ultra (atomic + BatchTicker):")
 
 	fmt.Println("Results:")
-	fmt.Println("─────────────────────────────────────────────────────────")
-	fmt.Printf("  Standard (ctx + time.Ticker):\n")
-	fmt.Printf("    Total: %v, Per-op: %.2f ns\n", stdDur, stdPerOp)
+	fmt.Println("─────────────────────────────────────────────────")
+	fmt.Printf("  %s\n", labels[0])
+	fmt.Printf("    Total: %v, Per-op: %.2f ns%s, %s\n", std.Elapsed(), std.NsPerOp(), std.CyclesText(), std.MemPerOp())
 	fmt.Println()
-	fmt.Printf("  Optimized (atomic + AtomicTicker):\n")
-	fmt.Printf("    Total: %v, Per-op: %.2f ns\n", optDur, optPerOp)
-	fmt.Printf("    Speedup: %.2fx\n", stdPerOp/optPerOp)
+	fmt.Printf("  %s\n", labels[1])
+	fmt.Printf("    Total: %v, Per-op: %.2f ns%s, %s\n", opt.Elapsed(), opt.NsPerOp(), opt.CyclesText(), opt.MemPerOp())
+	fmt.Printf("    Speedup: %s\n", rp.Speedup("%.2fx", harness.Speedup(std, opt)))
 	fmt.Println()
-	fmt.Printf("  Ultra (atomic + BatchTicker):\n")
-	fmt.Printf("    Total: %v, Per-op: %.2f ns\n", batchDur, batchPerOp)
-	fmt.Printf("    Speedup: %.2fx\n", stdPerOp/batchPerOp)
-	fmt.Println()
-
-	// Impact analysis
-	fmt.Println("Impact Analysis:")
-	fmt.Println("─────────────────────────────────────────────────────────")
-	savedNs := stdPerOp - optPerOp
+	fmt.Printf("  %s\n", labels[2])
+	fmt.Printf("    Total: %v, Per-op: %.2f ns%s, %s\n", batch.Elapsed(), batch.NsPerOp(), batch.CyclesText(), batch.MemPerOp())
+	fmt.Printf("    Speedup: %s\n", rp.Speedup("%.2fx", harness.Speedup(std, batch)))
 
-	fmt.Printf("  Savings per iteration: %.2f ns\n", savedNs)
+	rp.Summary(r.Count, results)
 	fmt.Println()
+	rp.Impact(std, opt, 100_000, 1_000_000, 10_000_000)
 
-	rates := []int{100_000, 1_000_000, 10_000_000}
-	for _, rate := range rates {
-		savedPerSec := savedNs * float64(rate) / 1e9
-		fmt.Printf("  At %dK ops/sec: save %.2f ms/sec (%.2f%% of 1 core)\n",
-			rate/1000, savedPerSec*1000, savedPerSec*100)
-	}
+	finish(r, results)
 }
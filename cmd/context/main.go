@@ -3,51 +3,87 @@
 // Usage:
 //
 //	go run ./cmd/context -n 10000000
+//	go run ./cmd/context -count 10
+//	go run ./cmd/context -time 2s
+//	go run ./cmd/context -count 10 -shuffle on
+//	go run ./cmd/context -isolate
+//	go run ./cmd/context -format=gobench
+//	go run ./cmd/context -save before.json
+//	go run ./cmd/context -compare before.json
+//	go run ./cmd/context -db results.db
+//	go run ./cmd/context -cpuprofile-dir prof
 package main
 
 import (
 	"context"
 	"flag"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
 )
 
 func main() {
-	iterations := flag.Int("n", 10_000_000, "number of iterations")
+	r := harness.NewRunner(flag.CommandLine, "Context", 10_000_000, 1, 0)
 	flag.Parse()
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	fmt.Printf("Benchmarking cancellation check (%d iterations)\n", *iterations)
-	fmt.Println("─────────────────────────────────────────────────")
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Benchmarking cancellation check (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+	}
 
 	// Benchmark context-based cancellation
 	ctx := cancel.NewContext(context.Background())
-	start := time.Now()
-	for i := 0; i < *iterations; i++ {
-		_ = ctx.Done()
+	ctxLoop := func(n int) time.Duration {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			_ = ctx.Done()
+		}
+		return time.Since(start)
 	}
-	ctxDur := time.Since(start)
 
 	// Benchmark atomic-based cancellation
 	atomic := cancel.NewAtomic()
-	start = time.Now()
-	for i := 0; i < *iterations; i++ {
-		_ = atomic.Done()
+	atomicLoop := func(n int) time.Duration {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			_ = atomic.Done()
+		}
+		return time.Since(start)
 	}
-	atomicDur := time.Since(start)
 
-	// Results
-	ctxPerOp := float64(ctxDur.Nanoseconds()) / float64(*iterations)
-	atomicPerOp := float64(atomicDur.Nanoseconds()) / float64(*iterations)
+	results, err := r.Measure([]harness.Variant{
+		r.Variant("Context", func() { _ = ctx.Done() }, ctxLoop),
+		r.Variant("Atomic", func() { _ = atomic.Done() }, atomicLoop),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	fmt.Printf("\nResults:\n")
-	fmt.Printf("  Context:  %v (%.2f ns/op)\n", ctxDur, ctxPerOp)
-	fmt.Printf("  Atomic:   %v (%.2f ns/op)\n", atomicDur, atomicPerOp)
-	fmt.Printf("\n  Speedup:  %.2fx\n", ctxPerOp/atomicPerOp)
+	if r.Format == harness.FormatText && !r.Partial() {
+		ctxRes, atomicRes := results[0], results[1]
+		labels := rp.Winners(results, "Context: ", "Atomic:  ")
+		fmt.Printf("\nResults:\n")
+		fmt.Printf("  %s %v (%.2f ns/op%s, %s)\n", labels[0], ctxRes.Elapsed(), ctxRes.NsPerOp(), ctxRes.CyclesText(), ctxRes.MemPerOp())
+		fmt.Printf("  %s %v (%.2f ns/op%s, %s)\n", labels[1], atomicRes.Elapsed(), atomicRes.NsPerOp(), atomicRes.CyclesText(), atomicRes.MemPerOp())
+		fmt.Printf("\n  Speedup:  %s\n", rp.Speedup("%.2fx", harness.Speedup(ctxRes, atomicRes)))
+		rp.Summary(r.Count, results)
+		rp.Throughput([]string{"Context: ", "Atomic:  "}, results)
+	}
 
-	// Extrapolate to ops/second
-	fmt.Printf("\nThroughput (theoretical max):\n")
-	fmt.Printf("  Context:  %.2f M ops/sec\n", 1000/ctxPerOp)
-	fmt.Printf("  Atomic:   %.2f M ops/sec\n", 1000/atomicPerOp)
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
@@ -3,14 +3,24 @@
 // Usage:
 //
 //	go run ./cmd/ticker -n 10000000
+//	go run ./cmd/ticker -count 10
+//	go run ./cmd/ticker -time 2s
+//	go run ./cmd/ticker -count 10 -shuffle on
+//	go run ./cmd/ticker -isolate
+//	go run ./cmd/ticker -format=gobench
+//	go run ./cmd/ticker -save before.json
+//	go run ./cmd/ticker -compare before.json
+//	go run ./cmd/ticker -db results.db
+//	go run ./cmd/ticker -cpuprofile-dir prof
 package main
 
 import (
 	"flag"
 	"fmt"
-	"runtime"
+	"os"
 	"time"
 
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
 	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
 )
 
@@ -20,14 +30,23 @@ type tickerInfo struct {
 }
 
 func main() {
-	iterations := flag.Int("n", 10_000_000, "number of iterations")
+	r := harness.NewRunner(flag.CommandLine, "Ticker", 10_000_000, 1, 0)
 	flag.Parse()
+	if err := r.Parse(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := r.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
 	interval := time.Hour // Long so we measure check overhead, not actual ticks
 
-	fmt.Printf("Benchmarking tick check (%d iterations)\n", *iterations)
-	fmt.Printf("Architecture: %s/%s\n", runtime.GOOS, runtime.GOARCH)
-	fmt.Println("─────────────────────────────────────────────────")
+	rp := r.Reporter()
+	if r.Format == harness.FormatText {
+		rp.Header(fmt.Sprintf("Benchmarking tick check (%s)", harness.RunLength(r.N, r.Benchtime)), r.Settings()...)
+	}
 
 	// Build list of tickers to test
 	tickers := []tickerInfo{
@@ -36,38 +55,60 @@ func main() {
 		{"AtomicTicker", func() tick.Ticker { return tick.NewAtomicTicker(interval) }},
 	}
 
-	// Add TSC ticker only on amd64
-	if runtime.GOARCH == "amd64" {
-		tickers = append(tickers, tickerInfo{
-			"TSCTicker",
-			func() tick.Ticker { return tick.NewTSCCalibrated(interval) },
-		})
-	}
-
-	results := make([]time.Duration, len(tickers))
+	// Add architecture-specific tickers (TSC on amd64)
+	tickers = append(tickers, platformTickers(interval)...)
 
+	// Set up every ticker before timing any, so -shuffle can interleave
+	// them, but only those this process measures (see -isolate): a ticker's
+	// timer runs from its creation
+	variants := make([]harness.Variant, len(tickers))
+	created := make([]tick.Ticker, len(tickers))
 	for i, info := range tickers {
+		variants[i].Name = info.name
+		if !r.Prof.Runs(info.name) {
+			continue
+		}
 		t := info.create()
-		start := time.Now()
-		for j := 0; j < *iterations; j++ {
-			_ = t.Tick()
+		created[i] = t
+		loop := func(n int) time.Duration {
+			start := time.Now()
+			for j := 0; j < n; j++ {
+				_ = t.Tick()
+			}
+			return time.Since(start)
 		}
-		results[i] = time.Since(start)
-		t.Stop()
+		variants[i] = r.Variant(info.name, func() { _ = t.Tick() }, loop)
+	}
+	results, err := r.Measure(variants)
+	for _, t := range created {
+		if t != nil {
+			t.Stop()
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
-	// Print results
-	fmt.Printf("\nResults:\n")
-	baseline := float64(results[0].Nanoseconds()) / float64(*iterations)
-
-	for i, info := range tickers {
-		perOp := float64(results[i].Nanoseconds()) / float64(*iterations)
-		speedup := baseline / perOp
-		throughput := 1000 / perOp // M ops/sec
-
-		fmt.Printf("  %-20s %12v  %8.2f ns/op  %6.2fx  %8.2f M/s\n",
-			info.name, results[i], perOp, speedup, throughput)
+	if r.Format == harness.FormatText && !r.Partial() {
+		fmt.Printf("\nResults:\n")
+		names := make([]string, len(results))
+		for i, res := range results {
+			names[i] = fmt.Sprintf("%-20s", res.Name)
+		}
+		names = rp.Winners(results, names...)
+		for i, res := range results {
+			perOp := res.NsPerOp()
+			throughput := 1000 / perOp // M ops/sec
+			fmt.Printf("  %s %12v  %8.2f ns/op%s  %s  %8.2f M/s  %s\n",
+				names[i], res.Elapsed(), perOp, res.CyclesText(), rp.Speedup("%6.2fx", harness.Speedup(results[0], res)), throughput, res.MemPerOp())
+		}
+		rp.Summary(r.Count, results)
+		fmt.Printf("\nNote: BatchTicker only checks time every N calls, so overhead is amortized.\n")
 	}
 
-	fmt.Printf("\nNote: BatchTicker only checks time every N calls, so overhead is amortized.\n")
+	if err := r.Finish(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,16 @@
+//go:build amd64
+
+package main
+
+import (
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+// platformTickers returns the tickers only available on amd64.
+func platformTickers(interval time.Duration) []tickerInfo {
+	return []tickerInfo{
+		{"TSCTicker", func() tick.Ticker { return tick.NewTSCCalibrated(interval) }},
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !amd64
+
+package main
+
+import "time"
+
+// platformTickers returns nothing: the TSC ticker requires amd64.
+func platformTickers(interval time.Duration) []tickerInfo {
+	return nil
+}
@@ -2,4 +2,14 @@ module github.com/randomizedcoder/some-go-benchmarks
 
 go 1.25.4
 
-require github.com/randomizedcoder/go-lock-free-ring v1.0.4 // indirect
+require github.com/randomizedcoder/go-lock-free-ring v1.0.4
+
+require (
+	github.com/Workiva/go-datastructures v1.1.0
+	github.com/gammazero/deque v1.2.1
+	github.com/google/pprof v0.0.0-20260115054156-294ebfa9ad83
+	go.etcd.io/bbolt v1.4.3
+	golang.org/x/sync v0.10.0
+	golang.org/x/sys v0.47.0
+	golang.org/x/time v0.15.0
+)
@@ -0,0 +1,106 @@
+// Package affinity pins goroutines to CPUs and describes the CPU topology,
+// so benchmarks can place a producer and consumer on the same core, on
+// SMT siblings, or on different physical cores.
+//
+// A pinned goroutine is locked to its OS thread and never unlocked: when
+// the goroutine exits the runtime terminates the thread instead of
+// returning a pinned thread to the scheduler's pool.
+//
+// Pinning is implemented on Linux only. Elsewhere PinCurrent and Topology
+// return ErrUnsupported.
+package affinity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrUnsupported is returned on platforms without CPU pinning support.
+var ErrUnsupported = errors.New("affinity: not supported on this platform")
+
+// CPU is one logical CPU the process may run on.
+type CPU struct {
+	ID      int // Logical CPU number
+	Core    int // Physical core ID, unique within Package
+	Package int // Socket
+	Node    int // NUMA node (0 on non-NUMA systems)
+}
+
+// Placement describes where two pinned goroutines run relative to each
+// other.
+type Placement int
+
+const (
+	// SameCore pins both goroutines to one logical CPU. They only make
+	// progress when the OS time-slices between them.
+	SameCore Placement = iota
+
+	// SMTSibling pins them to two hardware threads of one physical core,
+	// sharing L1/L2.
+	SMTSibling
+
+	// CrossCore pins them to different physical cores in the same
+	// package, communicating through the shared L3.
+	CrossCore
+)
+
+// Placements lists every Placement in benchmark order.
+var Placements = []Placement{SameCore, SMTSibling, CrossCore}
+
+// String returns the placement name used in benchmark output.
+func (p Placement) String() string {
+	switch p {
+	case SameCore:
+		return "SameCore"
+	case SMTSibling:
+		return "SMTSibling"
+	case CrossCore:
+		return "CrossCore"
+	default:
+		return fmt.Sprintf("Placement(%d)", int(p))
+	}
+}
+
+// Pick returns two logical CPUs from cpus with placement p.
+// ok is false if the topology has no such pair.
+func Pick(cpus []CPU, p Placement) (a, b int, ok bool) {
+	for i, x := range cpus {
+		if p == SameCore {
+			return x.ID, x.ID, true
+		}
+		for _, y := range cpus[i+1:] {
+			if x.Package != y.Package {
+				continue
+			}
+			sameCore := x.Core == y.Core
+			if (p == SMTSibling && sameCore) || (p == CrossCore && !sameCore) {
+				return x.ID, y.ID, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Nodes returns the distinct NUMA nodes of cpus in ascending order.
+func Nodes(cpus []CPU) []int {
+	var nodes []int
+	for _, c := range cpus {
+		if !slices.Contains(nodes, c.Node) {
+			nodes = append(nodes, c.Node)
+		}
+	}
+	slices.Sort(nodes)
+	return nodes
+}
+
+// OnNode returns the CPUs in cpus that belong to NUMA node node.
+func OnNode(cpus []CPU, node int) []CPU {
+	var out []CPU
+	for _, c := range cpus {
+		if c.Node == node {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+//go:build linux
+
+package affinity
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const sysCPU = "/sys/devices/system/cpu"
+
+// PinCurrent locks the calling goroutine to its OS thread and restricts
+// that thread to cpu. The goroutine stays locked for the rest of its life.
+func PinCurrent(cpu int) error {
+	runtime.LockOSThread()
+
+	var set unix.CPUSet
+	set.Set(cpu)
+	if err := unix.SchedSetaffinity(0, &set); err != nil {
+		return fmt.Errorf("affinity: pin to CPU %d: %w", cpu, err)
+	}
+	return nil
+}
+
+// Topology returns the CPUs this process is allowed to run on, in CPU
+// number order, with their core and package IDs from sysfs.
+func Topology() ([]CPU, error) {
+	var allowed unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &allowed); err != nil {
+		return nil, fmt.Errorf("affinity: get allowed CPUs: %w", err)
+	}
+
+	var cpus []CPU
+	for id := 0; id < len(allowed)*64; id++ {
+		if !allowed.IsSet(id) {
+			continue
+		}
+		core, err := readTopologyInt(id, "core_id")
+		if err != nil {
+			return nil, err
+		}
+		pkg, err := readTopologyInt(id, "physical_package_id")
+		if err != nil {
+			return nil, err
+		}
+		node, err := readNode(id)
+		if err != nil {
+			return nil, err
+		}
+		cpus = append(cpus, CPU{ID: id, Core: core, Package: pkg, Node: node})
+	}
+	return cpus, nil
+}
+
+// readNode finds the nodeN link in the CPU's sysfs directory. Kernels
+// built without NUMA have none, and everything is node 0.
+func readNode(cpu int) (int, error) {
+	entries, err := os.ReadDir(fmt.Sprintf("%s/cpu%d", sysCPU, cpu))
+	if err != nil {
+		return 0, fmt.Errorf("affinity: %w", err)
+	}
+	for _, e := range entries {
+		if rest, ok := strings.CutPrefix(e.Name(), "node"); ok {
+			if n, err := strconv.Atoi(rest); err == nil {
+				return n, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+func readTopologyInt(cpu int, name string) (int, error) {
+	path := fmt.Sprintf("%s/cpu%d/topology/%s", sysCPU, cpu, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("affinity: %w", err)
+	}
+	v, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("affinity: parse %s: %w", path, err)
+	}
+	return v, nil
+}
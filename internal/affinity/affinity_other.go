@@ -0,0 +1,13 @@
+//go:build !linux
+
+package affinity
+
+// PinCurrent is not supported on this platform.
+func PinCurrent(cpu int) error {
+	return ErrUnsupported
+}
+
+// Topology is not supported on this platform.
+func Topology() ([]CPU, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,100 @@
+package affinity_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/affinity"
+)
+
+// Two packages, two cores each, two threads per core:
+//
+//	pkg 0: core 0 = CPUs 0,4   core 1 = CPUs 1,5
+//	pkg 1: core 0 = CPUs 2,6   core 1 = CPUs 3,7
+var testTopology = []affinity.CPU{
+	{ID: 0, Core: 0, Package: 0, Node: 0},
+	{ID: 1, Core: 1, Package: 0, Node: 0},
+	{ID: 2, Core: 0, Package: 1, Node: 1},
+	{ID: 3, Core: 1, Package: 1, Node: 1},
+	{ID: 4, Core: 0, Package: 0, Node: 0},
+	{ID: 5, Core: 1, Package: 0, Node: 0},
+	{ID: 6, Core: 0, Package: 1, Node: 1},
+	{ID: 7, Core: 1, Package: 1, Node: 1},
+}
+
+func TestPick(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cpus   []affinity.CPU
+		p      affinity.Placement
+		a, b   int
+		wantOK bool
+	}{
+		{"same core", testTopology, affinity.SameCore, 0, 0, true},
+		{"SMT sibling", testTopology, affinity.SMTSibling, 0, 4, true},
+		{"cross core", testTopology, affinity.CrossCore, 0, 1, true},
+		{"no SMT", testTopology[:4], affinity.SMTSibling, 0, 0, false},
+		{"one CPU cross core", testTopology[:1], affinity.CrossCore, 0, 0, false},
+		{"empty", nil, affinity.SameCore, 0, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b, ok := affinity.Pick(tc.cpus, tc.p)
+			if ok != tc.wantOK {
+				t.Fatalf("Pick() ok = %v, want %v", ok, tc.wantOK)
+			}
+			if ok && (a != tc.a || b != tc.b) {
+				t.Errorf("Pick() = (%d, %d), want (%d, %d)", a, b, tc.a, tc.b)
+			}
+		})
+	}
+}
+
+func TestNodes(t *testing.T) {
+	if got := affinity.Nodes(testTopology); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("Nodes() = %v, want [0 1]", got)
+	}
+	if got := affinity.Nodes(testTopology[:2]); !slices.Equal(got, []int{0}) {
+		t.Errorf("Nodes() = %v, want [0]", got)
+	}
+
+	var ids []int
+	for _, c := range affinity.OnNode(testTopology, 1) {
+		ids = append(ids, c.ID)
+	}
+	if !slices.Equal(ids, []int{2, 3, 6, 7}) {
+		t.Errorf("OnNode(1) CPUs = %v, want [2 3 6 7]", ids)
+	}
+}
+
+func TestPlacement_String(t *testing.T) {
+	for _, p := range affinity.Placements {
+		if s := p.String(); s == "" || s[0] == 'P' {
+			t.Errorf("Placement(%d).String() = %q, want a name", int(p), s)
+		}
+	}
+}
+
+func TestPinCurrent(t *testing.T) {
+	cpus, err := affinity.Topology()
+	if errors.Is(err, affinity.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("Topology() error: %v", err)
+	}
+	if len(cpus) == 0 {
+		t.Fatal("Topology() returned no CPUs")
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		// Exiting while locked discards the pinned thread
+		errc <- affinity.PinCurrent(cpus[0].ID)
+	}()
+	if err := <-errc; err != nil {
+		t.Errorf("PinCurrent(%d) error: %v", cpus[0].ID, err)
+	}
+}
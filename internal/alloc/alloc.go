@@ -0,0 +1,36 @@
+// Package alloc provides code shaped to allocate on the stack or on the
+// heap, for benchmarking what an allocation costs and what makes a value
+// escape.
+//
+// The compiler keeps a value on the stack when it can prove nothing
+// refers to it after the function returns; otherwise the value escapes
+// and is allocated on the heap, which costs a call into the allocator
+// and, later, the garbage collector's time to find it dead. This package
+// offers three sets of comparisons:
+//   - Cases: pairs of functions doing the same work, one written so its
+//     value stays on the stack and one with a common pattern that makes it
+//     escape: returning a pointer, boxing in an interface, capturing in a
+//     returned closure, and sizing a slice at run time
+//   - NewBytes and NewPointers: small objects of a given size, without
+//     and with pointers the collector has to scan
+//   - Values and Pointers: a slice of structs against a slice of
+//     pointers to separately allocated structs
+//
+// Run `go build -gcflags=-m ./internal/alloc` to see the compiler's
+// escape decisions for each.
+package alloc
+
+// Point is the value the comparisons allocate: 32 bytes, no pointers.
+type Point struct {
+	X, Y, Z, W int64
+}
+
+// Sum returns the sum of p's fields.
+func (p *Point) Sum() int64 {
+	return p.X + p.Y + p.Z + p.W
+}
+
+// point returns the Point the comparisons build from i.
+func point(i int64) Point {
+	return Point{X: i, Y: i + 1, Z: i + 2, W: i + 3}
+}
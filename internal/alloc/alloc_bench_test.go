@@ -0,0 +1,104 @@
+package alloc_test
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/alloc"
+)
+
+// Sink variables to prevent compiler from eliminating benchmark loops
+var (
+	sinkInt64 int64
+	sinkBytes []byte
+	sinkPtrs  []*byte
+)
+
+// objectSizes are the small-object sizes the benchmarks allocate, in
+// bytes.
+var objectSizes = []int{16, 64, 256, 1024, 32 << 10}
+
+// sliceLens are the slice lengths the benchmarks build and sum.
+var sliceLens = []int{16, 1024, 64 << 10}
+
+// trackGC reports the GC cycles and stop-the-world pause time a benchmark
+// run caused, per op, as internal/combined's benchmarks do: the cost of
+// the heap side that ns/op only partly charges for. Call it first thing
+// in each b.Run closure.
+func trackGC(b *testing.B) {
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	b.Cleanup(func() {
+		var after runtime.MemStats
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gcs/op")
+		b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "gc-pause-ns/op")
+	})
+}
+
+func BenchmarkEscape(b *testing.B) {
+	for _, c := range alloc.Cases {
+		for _, side := range []struct {
+			name string
+			f    func(int64) int64
+		}{{"Stack", c.Stack}, {"Heap", c.Heap}} {
+			b.Run(c.Name+"/"+side.name, func(b *testing.B) {
+				trackGC(b)
+				b.ReportAllocs()
+				var sum int64
+				for i := 0; i < b.N; i++ {
+					sum += side.f(int64(i))
+				}
+				sinkInt64 = sum
+			})
+		}
+	}
+}
+
+func BenchmarkSmall(b *testing.B) {
+	for _, size := range objectSizes {
+		b.Run(fmt.Sprintf("NoScan/size=%d", size), func(b *testing.B) {
+			trackGC(b)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sinkBytes = alloc.NewBytes(size)
+			}
+		})
+		b.Run(fmt.Sprintf("Scan/size=%d", size), func(b *testing.B) {
+			trackGC(b)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sinkPtrs = alloc.NewPointers(size)
+			}
+		})
+	}
+}
+
+// BenchmarkSlice builds a slice of N Points and sums it, reporting ns
+// per Point.
+func BenchmarkSlice(b *testing.B) {
+	for _, n := range sliceLens {
+		b.Run(fmt.Sprintf("Values/N=%d", n), func(b *testing.B) {
+			trackGC(b)
+			b.ReportAllocs()
+			var sum int64
+			for i := 0; i < b.N; i++ {
+				sum += alloc.SumValues(alloc.Values(n))
+			}
+			sinkInt64 = sum
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/elem")
+		})
+		b.Run(fmt.Sprintf("Pointers/N=%d", n), func(b *testing.B) {
+			trackGC(b)
+			b.ReportAllocs()
+			var sum int64
+			for i := 0; i < b.N; i++ {
+				sum += alloc.SumPointers(alloc.Pointers(n))
+			}
+			sinkInt64 = sum
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/elem")
+		})
+	}
+}
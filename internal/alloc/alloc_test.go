@@ -0,0 +1,62 @@
+package alloc_test
+
+import (
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/alloc"
+)
+
+func TestCases_SameResult(t *testing.T) {
+	for _, c := range alloc.Cases {
+		for _, i := range []int64{0, 1, -7, 1 << 40} {
+			if s, h := c.Stack(i), c.Heap(i); s != h {
+				t.Errorf("%s(%d): Stack = %d, Heap = %d", c.Name, i, s, h)
+			}
+		}
+	}
+}
+
+// TestCases_Escape checks the compiler still decides as each Case says:
+// nothing allocated on the Stack side, and an allocation on the Heap side.
+func TestCases_Escape(t *testing.T) {
+	for _, c := range alloc.Cases {
+		var sink int64
+		if n := testing.AllocsPerRun(100, func() { sink += c.Stack(3) }); n != 0 {
+			t.Errorf("%s: Stack allocated %v times per call, want 0", c.Name, n)
+		}
+		if n := testing.AllocsPerRun(100, func() { sink += c.Heap(3) }); n < 1 {
+			t.Errorf("%s: Heap allocated %v times per call, want at least 1", c.Name, n)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, size := range []int{1, 8, 64, 1000} {
+		if got := len(alloc.NewBytes(size)); got != size {
+			t.Errorf("NewBytes(%d) has length %d", size, got)
+		}
+		if got := len(alloc.NewPointers(size)); got != max(size/8, 1) && got != max(size/4, 1) {
+			t.Errorf("NewPointers(%d) has length %d", size, got)
+		}
+	}
+}
+
+func TestSlices(t *testing.T) {
+	for _, n := range []int{0, 1, 100} {
+		values, pointers := alloc.Values(n), alloc.Pointers(n)
+		if len(values) != n || len(pointers) != n {
+			t.Fatalf("n=%d: lengths %d and %d", n, len(values), len(pointers))
+		}
+		// Each Point i sums to 4i+6
+		want := int64(0)
+		for i := range n {
+			want += 4*int64(i) + 6
+		}
+		if got := alloc.SumValues(values); got != want {
+			t.Errorf("n=%d: SumValues = %d, want %d", n, got, want)
+		}
+		if got := alloc.SumPointers(pointers); got != want {
+			t.Errorf("n=%d: SumPointers = %d, want %d", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package alloc
+
+// Case is one escape-causing pattern: Stack and Heap compute the same
+// result from i, Stack keeping its value on the stack and Heap making it
+// escape to the heap.
+type Case struct {
+	Name  string
+	Stack func(i int64) int64
+	Heap  func(i int64) int64
+}
+
+// SliceLen is the length of the slices the Slice case makes.
+const SliceLen = 64
+
+// Cases are the patterns, in report order.
+var Cases = []Case{
+	{"Pointer", pointerStack, pointerHeap},
+	{"Interface", interfaceStack, interfaceHeap},
+	{"Closure", closureStack, closureHeap},
+	{"Slice", sliceStack, sliceHeap},
+}
+
+// pointerStack passes a pointer down: sumPoint doesn't keep it, so p
+// stays in pointerStack's frame.
+func pointerStack(i int64) int64 {
+	p := point(i)
+	return sumPoint(&p)
+}
+
+// pointerHeap gets a pointer back from newPoint, so the Point outlives
+// newPoint's frame and is allocated on the heap.
+func pointerHeap(i int64) int64 {
+	return newPoint(i).Sum()
+}
+
+//go:noinline
+func sumPoint(p *Point) int64 {
+	return p.Sum()
+}
+
+//go:noinline
+func newPoint(i int64) *Point {
+	p := point(i)
+	return &p
+}
+
+// interfaceStack returns the Point by value.
+func interfaceStack(i int64) int64 {
+	p := valuePoint(i)
+	return p.Sum()
+}
+
+// interfaceHeap returns the Point in an any, which holds a pointer to a
+// heap copy of any value bigger than a word.
+func interfaceHeap(i int64) int64 {
+	p := boxPoint(i).(Point)
+	return p.Sum()
+}
+
+//go:noinline
+func valuePoint(i int64) Point {
+	return point(i)
+}
+
+//go:noinline
+func boxPoint(i int64) any {
+	return point(i)
+}
+
+// closureStack calls a closure where it is made, so what it captures
+// stays on the stack.
+func closureStack(i int64) int64 {
+	p := point(i)
+	sum := func() int64 { return p.Sum() }
+	return sum()
+}
+
+// closureHeap gets a closure back from sumLater, which moves the closure
+// and the Point it captures to the heap.
+func closureHeap(i int64) int64 {
+	return sumLater(i)()
+}
+
+//go:noinline
+func sumLater(i int64) func() int64 {
+	p := point(i)
+	return func() int64 { return p.Sum() }
+}
+
+// sliceStack makes a slice of a constant length the compiler can reserve
+// stack space for.
+func sliceStack(i int64) int64 {
+	s := make([]int64, SliceLen)
+	return fillSum(s, i)
+}
+
+// sliceHeap makes a slice of the same length known only at run time, so
+// the compiler can't reserve space for it and allocates it on the heap.
+// (Since Go 1.25 it reserves 32 bytes for such slices, too few for this
+// one.)
+func sliceHeap(i int64) int64 {
+	s := make([]int64, sliceLen)
+	return fillSum(s, i)
+}
+
+// sliceLen is SliceLen as a variable, which the compiler can't assume
+// is unchanged.
+var sliceLen = SliceLen
+
+//go:noinline
+func fillSum(s []int64, i int64) int64 {
+	var sum int64
+	for j := range s {
+		s[j] = i + int64(j)
+		sum += s[j]
+	}
+	return sum
+}
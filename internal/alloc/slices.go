@@ -0,0 +1,41 @@
+package alloc
+
+// Values returns n Points in one slice: a single allocation, the Points
+// contiguous in memory.
+func Values(n int) []Point {
+	s := make([]Point, n)
+	for i := range s {
+		s[i] = point(int64(i))
+	}
+	return s
+}
+
+// Pointers returns pointers to n Points, each allocated on its own: n+1
+// allocations, and a pointer per Point for the collector to follow.
+func Pointers(n int) []*Point {
+	s := make([]*Point, n)
+	for i := range s {
+		p := point(int64(i))
+		s[i] = &p
+	}
+	return s
+}
+
+// SumValues returns the sum of every Point in s, read in order.
+func SumValues(s []Point) int64 {
+	var sum int64
+	for i := range s {
+		sum += s[i].Sum()
+	}
+	return sum
+}
+
+// SumPointers returns the sum of every Point s points to, each a load
+// through its pointer.
+func SumPointers(s []*Point) int64 {
+	var sum int64
+	for _, p := range s {
+		sum += p.Sum()
+	}
+	return sum
+}
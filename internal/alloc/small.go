@@ -0,0 +1,20 @@
+package alloc
+
+import "unsafe"
+
+// NewBytes allocates a pointer-free object of size bytes. The allocator
+// marks its span noscan, so the collector never looks inside it.
+//
+//go:noinline
+func NewBytes(size int) []byte {
+	return make([]byte, size)
+}
+
+// NewPointers allocates an object of size bytes, rounded down to whole
+// pointers, and at least one. The collector has to scan it for pointers
+// while it is live, and the allocator zeroes it with that in mind.
+//
+//go:noinline
+func NewPointers(size int) []*byte {
+	return make([]*byte, max(size/int(unsafe.Sizeof(uintptr(0))), 1))
+}
@@ -0,0 +1,91 @@
+package combined_test
+
+import (
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/affinity"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// CPU placement benchmarks (SPSC with pinned threads)
+// ============================================================================
+// The unpinned pipeline benchmarks let the scheduler place the producer
+// and consumer anywhere, so a run mixes same-core, SMT-sibling and
+// cross-core handoffs. These pin both sides so each placement is measured
+// on its own. Placements the machine (or the current taskset) can't
+// provide are skipped.
+//
+// SameCore is mostly a measure of the OS time slice: with both threads
+// spinning on one CPU, each only runs while the other is descheduled.
+
+// benchPinned runs the SPSC pipeline through q with the producer on CPU
+// prodCPU and the consumer on CPU consCPU.
+func benchPinned(b *testing.B, q queue.Queue[int], prodCPU, consCPU int) {
+	done := make(chan struct{})
+	consumerDone := make(chan struct{})
+	pinned := make(chan error, 1)
+
+	go func() {
+		defer close(consumerDone)
+		pinned <- affinity.PinCurrent(consCPU)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				q.Pop()
+			}
+		}
+	}()
+	if err := <-pinned; err != nil {
+		b.Fatal(err)
+	}
+
+	// Pin the producer in its own goroutine so the benchmark goroutine's
+	// thread is not left pinned for later benchmarks.
+	producerDone := make(chan error, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	go func() {
+		if err := affinity.PinCurrent(prodCPU); err != nil {
+			producerDone <- err
+			return
+		}
+		for i := 0; i < b.N; i++ {
+			for !q.Push(i) {
+				// Spin until push succeeds
+			}
+		}
+		producerDone <- nil
+	}()
+
+	err := <-producerDone
+	b.StopTimer()
+	close(done)
+	<-consumerDone
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkPipeline_Pinned_RingBuffer runs the SPSC ring pipeline once
+// per CPU placement.
+func BenchmarkPipeline_Pinned_RingBuffer(b *testing.B) {
+	cpus, err := affinity.Topology()
+	if err != nil {
+		b.Skip(err)
+	}
+
+	for _, p := range affinity.Placements {
+		b.Run(p.String(), func(b *testing.B) {
+			trackGC(b)
+			prodCPU, consCPU, ok := affinity.Pick(cpus, p)
+			if !ok {
+				b.Skipf("no %s CPU pair available", p)
+			}
+			benchPinned(b, queue.Must(queue.NewRingBuffer[int](1024)), prodCPU, consCPU)
+		})
+	}
+}
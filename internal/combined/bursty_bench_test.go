@@ -0,0 +1,107 @@
+package combined_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/histogram"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// Bursty traffic benchmarks
+// ============================================================================
+// Real ingest is rarely a steady stream: packets and requests arrive in
+// bursts with idle gaps between them. Here the producer pushes flat out
+// for the "on" part of each period and idles for the rest, and the
+// consumer samples queue depth at every Pop. Each burst's length is drawn
+// from 50% to 150% of the duty cycle's (capped at the whole period), from
+// -combined.seed, so bursts vary the way real ones do but every run sees
+// the same sequence. A queue that keeps up with
+// the average rate can still run near full during bursts, which is what
+// the depth percentiles show.
+
+// burstPeriod is one on+off cycle of the producer.
+const burstPeriod = 100 * time.Microsecond
+
+// burstDuties are the fractions of each period spent pushing.
+var burstDuties = []float64{0.1, 0.5, 0.9}
+
+// benchBursty pushes b.N items in bursts of the given duty cycle and
+// reports throughput and the distribution of queue depth seen by the
+// consumer.
+func benchBursty(b *testing.B, q queue.Sized[int], duty float64) {
+	depth := histogram.New()
+	rng := combined.NewRand(burstStream)
+	burst := func() time.Duration {
+		return min(time.Duration(float64(burstPeriod)*duty*(0.5+rng.Float64())), burstPeriod)
+	}
+	on := burst()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for n := 0; n < b.N; {
+			d := q.Len()
+			if _, ok := q.Pop(); !ok {
+				continue
+			}
+			depth.Record(int64(d))
+			n++
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	start := time.Now()
+	periodStart := start
+	for i := 0; i < b.N; {
+		if time.Since(periodStart) >= on {
+			// Idle until the next period
+			periodStart = periodStart.Add(burstPeriod)
+			on = burst()
+			for time.Now().Before(periodStart) {
+			}
+			continue
+		}
+		if q.Push(i) {
+			i++
+		}
+	}
+
+	wg.Wait()
+	b.StopTimer()
+	elapsed := time.Since(start)
+
+	b.ReportMetric(float64(b.N)/elapsed.Seconds(), "items/s")
+	b.ReportMetric(float64(depth.Quantile(0.50)), "p50-depth")
+	b.ReportMetric(float64(depth.Quantile(0.99)), "p99-depth")
+	b.ReportMetric(float64(depth.Max()), "max-depth")
+}
+
+// BenchmarkPipeline_Bursty_Channel runs the bursty producer against a
+// buffered channel.
+func BenchmarkPipeline_Bursty_Channel(b *testing.B) {
+	for _, duty := range burstDuties {
+		b.Run(fmt.Sprintf("duty=%.0f%%", duty*100), func(b *testing.B) {
+			trackGC(b)
+			benchBursty(b, queue.Must(queue.NewChannel[int](1024)), duty)
+		})
+	}
+}
+
+// BenchmarkPipeline_Bursty_RingBuffer runs the bursty producer against
+// the SPSC ring buffer.
+func BenchmarkPipeline_Bursty_RingBuffer(b *testing.B) {
+	for _, duty := range burstDuties {
+		b.Run(fmt.Sprintf("duty=%.0f%%", duty*100), func(b *testing.B) {
+			trackGC(b)
+			benchBursty(b, queue.Must(queue.NewRingBuffer[int](1024)), duty)
+		})
+	}
+}
@@ -1,13 +1,11 @@
 package combined_test
 
 import (
-	"context"
 	"testing"
 	"time"
 
-	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
 	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
-	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
 )
 
 // Sink variables
@@ -19,38 +17,49 @@ const benchInterval = time.Hour
 // ============================================================================
 // Combined Cancel + Tick benchmarks
 // ============================================================================
+// These run scenarios from the combined registry (see scenarios.go), the
+// same loops cmd/context-ticker runs by name.
+
+// benchScenario runs the registered scenario name for b.N iterations.
+func benchScenario(b *testing.B, name string) {
+	s, ok := combined.Lookup(name)
+	if !ok {
+		b.Fatalf("scenario %q not registered", name)
+	}
+	if err := s.Setup(); err != nil {
+		b.Fatal(err)
+	}
+	defer s.Teardown()
 
-// BenchmarkCombined_CancelTick_Standard measures the combined overhead
-// of checking context cancellation and ticker using standard library.
-func BenchmarkCombined_CancelTick_Standard(b *testing.B) {
-	ctx := cancel.NewContext(context.Background())
-	ticker := tick.NewTicker(benchInterval)
-	defer ticker.Stop()
 	b.ReportAllocs()
 	b.ResetTimer()
+	s.Run(b.N)
+	b.StopTimer()
+}
 
-	var cancelled, ticked bool
-	for i := 0; i < b.N; i++ {
-		cancelled = ctx.Done()
-		ticked = ticker.Tick()
+// BenchmarkScenario runs every registered scenario, so new ones are
+// benchmarked without writing a wrapper.
+func BenchmarkScenario(b *testing.B) {
+	for _, s := range combined.Scenarios() {
+		b.Run(s.Name(), func(b *testing.B) {
+			trackGC(b)
+			benchScenario(b, s.Name())
+		})
 	}
-	sinkBool = cancelled || ticked
+}
+
+// BenchmarkCombined_CancelTick_Standard measures the combined overhead
+// of checking context cancellation and ticker using standard library.
+func BenchmarkCombined_CancelTick_Standard(b *testing.B) {
+	trackGC(b)
+	benchScenario(b, "cancel-tick/std")
 }
 
 // BenchmarkCombined_CancelTick_Optimized measures the same operations
 // using atomic-based implementations.
 func BenchmarkCombined_CancelTick_Optimized(b *testing.B) {
-	ctx := cancel.NewAtomic()
-	ticker := tick.NewAtomicTicker(benchInterval)
-	b.ReportAllocs()
-	b.ResetTimer()
-
-	var cancelled, ticked bool
-	for i := 0; i < b.N; i++ {
-		cancelled = ctx.Done()
-		ticked = ticker.Tick()
-	}
-	sinkBool = cancelled || ticked
+	trackGC(b)
+	benchScenario(b, "cancel-tick/atomic")
 }
 
 // ============================================================================
@@ -60,55 +69,28 @@ func BenchmarkCombined_CancelTick_Optimized(b *testing.B) {
 // BenchmarkCombined_FullLoop_Standard simulates a realistic hot loop:
 // check cancellation, check tick, process message from queue.
 func BenchmarkCombined_FullLoop_Standard(b *testing.B) {
-	ctx := cancel.NewContext(context.Background())
-	ticker := tick.NewTicker(benchInterval)
-	q := queue.NewChannel[int](1024)
-	defer ticker.Stop()
-
-	// Pre-fill queue
-	for i := 0; i < 1024; i++ {
-		q.Push(i)
-	}
-
-	b.ReportAllocs()
-	b.ResetTimer()
-
-	var val int
-	var ok, cancelled, ticked bool
-	for i := 0; i < b.N; i++ {
-		cancelled = ctx.Done()
-		ticked = ticker.Tick()
-		val, ok = q.Pop()
-		q.Push(val) // Recycle
-	}
-	sinkInt = val
-	sinkBool = ok || cancelled || ticked
+	trackGC(b)
+	benchScenario(b, "full-loop/std")
 }
 
 // BenchmarkCombined_FullLoop_Optimized uses all optimized implementations.
 func BenchmarkCombined_FullLoop_Optimized(b *testing.B) {
-	ctx := cancel.NewAtomic()
-	ticker := tick.NewAtomicTicker(benchInterval)
-	q := queue.NewRingBuffer[int](1024)
-
-	// Pre-fill queue
-	for i := 0; i < 1024; i++ {
-		q.Push(i)
-	}
+	trackGC(b)
+	benchScenario(b, "full-loop/optimized")
+}
 
-	b.ReportAllocs()
-	b.ResetTimer()
+// BenchmarkCombined_FullLoopObserved_Standard is the standard full loop
+// plus per-item counters and a JSON log line per millisecond tick.
+func BenchmarkCombined_FullLoopObserved_Standard(b *testing.B) {
+	trackGC(b)
+	benchScenario(b, "full-loop-observed/std")
+}
 
-	var val int
-	var ok, cancelled, ticked bool
-	for i := 0; i < b.N; i++ {
-		cancelled = ctx.Done()
-		ticked = ticker.Tick()
-		val, ok = q.Pop()
-		q.Push(val) // Recycle
-	}
-	sinkInt = val
-	sinkBool = ok || cancelled || ticked
+// BenchmarkCombined_FullLoopObserved_Optimized is the optimized full loop
+// with the same observability added.
+func BenchmarkCombined_FullLoopObserved_Optimized(b *testing.B) {
+	trackGC(b)
+	benchScenario(b, "full-loop-observed/optimized")
 }
 
 // ============================================================================
@@ -118,7 +100,8 @@ func BenchmarkCombined_FullLoop_Optimized(b *testing.B) {
 // BenchmarkPipeline_Channel benchmarks a 2-goroutine SPSC pipeline
 // using buffered channels.
 func BenchmarkPipeline_Channel(b *testing.B) {
-	q := queue.NewChannel[int](1024)
+	trackGC(b)
+	q := queue.Must(queue.NewChannel[int](1024))
 	done := make(chan struct{})
 
 	// Consumer goroutine
@@ -149,7 +132,8 @@ func BenchmarkPipeline_Channel(b *testing.B) {
 // BenchmarkPipeline_RingBuffer benchmarks a 2-goroutine SPSC pipeline
 // using the lock-free ring buffer.
 func BenchmarkPipeline_RingBuffer(b *testing.B) {
-	q := queue.NewRingBuffer[int](1024)
+	trackGC(b)
+	q := queue.Must(queue.NewRingBuffer[int](1024))
 	done := make(chan struct{})
 
 	// Consumer goroutine (single consumer - SPSC contract)
@@ -178,6 +162,177 @@ func BenchmarkPipeline_RingBuffer(b *testing.B) {
 	close(done)
 }
 
+// ============================================================================
+// Pipeline benchmarks with batch consumption
+// ============================================================================
+// Same topology as above, but the consumer takes everything available in
+// one call instead of one Pop per item. For the ring buffer this also
+// means one tail publish per batch instead of per item.
+
+const drainBatch = 64
+
+// BenchmarkPipeline_Channel_Drain consumes with ChannelQueue.Drain.
+func BenchmarkPipeline_Channel_Drain(b *testing.B) {
+	trackGC(b)
+	q := queue.Must(queue.NewChannel[int](1024))
+	done := make(chan struct{})
+
+	go func() {
+		buf := make([]int, drainBatch)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				q.Drain(buf)
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for !q.Push(i) {
+			// Spin until push succeeds
+		}
+	}
+
+	b.StopTimer()
+	close(done)
+}
+
+// BenchmarkPipeline_RingBuffer_Drain consumes with RingBuffer.Drain.
+func BenchmarkPipeline_RingBuffer_Drain(b *testing.B) {
+	trackGC(b)
+	q := queue.Must(queue.NewRingBuffer[int](1024))
+	done := make(chan struct{})
+
+	go func() {
+		buf := make([]int, drainBatch)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				q.Drain(buf)
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for !q.Push(i) {
+			// Spin until push succeeds
+		}
+	}
+
+	b.StopTimer()
+	close(done)
+}
+
+// BenchmarkPipeline_RingBuffer_Range consumes with RingBuffer.Range,
+// processing items in place without copying them out.
+func BenchmarkPipeline_RingBuffer_Range(b *testing.B) {
+	trackGC(b)
+	q := queue.Must(queue.NewRingBuffer[int](1024))
+	done := make(chan struct{})
+
+	go func() {
+		sum := 0
+		fn := func(v int) bool {
+			sum += v
+			return true
+		}
+		for {
+			select {
+			case <-done:
+				sinkInt = sum
+				return
+			default:
+				q.Range(fn)
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for !q.Push(i) {
+			// Spin until push succeeds
+		}
+	}
+
+	b.StopTimer()
+	close(done)
+}
+
+// ============================================================================
+// Pipeline benchmarks with Len-based backpressure
+// ============================================================================
+// The producer goes through the queue.Sized interface: above a high-water
+// mark it stops pushing until the consumer has drained the queue below a
+// low-water mark, instead of spinning on a failed Push. This is how a
+// producer that also has other work (reading a socket, say) would use
+// Len, and the interface calls show what dynamic dispatch costs on the
+// hot path.
+
+// benchBackpressure runs the pipeline with hysteresis at 3/4 and 1/4 of
+// Cap, and reports how often the producer stalled.
+func benchBackpressure(b *testing.B, q queue.Sized[int]) {
+	highWater := q.Cap() * 3 / 4
+	lowWater := q.Cap() / 4
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				q.Pop()
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	stalls := 0
+	for i := 0; i < b.N; i++ {
+		if q.Len() >= highWater {
+			stalls++
+			for q.Len() > lowWater {
+				// Wait for the consumer to catch up
+			}
+		}
+		for !q.Push(i) {
+			// Spin until push succeeds
+		}
+	}
+
+	b.StopTimer()
+	close(done)
+	b.ReportMetric(float64(stalls)/float64(b.N), "stalls/op")
+}
+
+// BenchmarkPipeline_Backpressure_Channel applies backpressure to a
+// buffered channel through queue.Sized.
+func BenchmarkPipeline_Backpressure_Channel(b *testing.B) {
+	trackGC(b)
+	benchBackpressure(b, queue.Must(queue.NewChannel[int](1024)))
+}
+
+// BenchmarkPipeline_Backpressure_RingBuffer applies backpressure to the
+// SPSC ring buffer through queue.Sized.
+func BenchmarkPipeline_Backpressure_RingBuffer(b *testing.B) {
+	trackGC(b)
+	benchBackpressure(b, queue.Must(queue.NewRingBuffer[int](1024)))
+}
+
 // ============================================================================
 // MPSC benchmarks (Multiple Producer, Single Consumer)
 // ============================================================================
@@ -187,6 +342,7 @@ func BenchmarkPipeline_RingBuffer(b *testing.B) {
 
 // BenchmarkMPSC_Channel_2Producers benchmarks 2 producers -> 1 consumer.
 func BenchmarkMPSC_Channel_2Producers(b *testing.B) {
+	trackGC(b)
 	ch := make(chan int, 1024)
 	done := make(chan struct{})
 	var consumerDone chan struct{}
@@ -238,6 +394,7 @@ func BenchmarkMPSC_Channel_2Producers(b *testing.B) {
 
 // BenchmarkMPSC_Channel_4Producers benchmarks 4 producers -> 1 consumer.
 func BenchmarkMPSC_Channel_4Producers(b *testing.B) {
+	trackGC(b)
 	ch := make(chan int, 1024)
 	done := make(chan struct{})
 	var consumerDone chan struct{}
@@ -286,6 +443,7 @@ func BenchmarkMPSC_Channel_4Producers(b *testing.B) {
 // BenchmarkMPSC_Channel_8Producers benchmarks 8 producers -> 1 consumer.
 // This stresses channel lock contention heavily.
 func BenchmarkMPSC_Channel_8Producers(b *testing.B) {
+	trackGC(b)
 	ch := make(chan int, 1024)
 	done := make(chan struct{})
 	var consumerDone chan struct{}
@@ -0,0 +1,98 @@
+package combined_test
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// MPSC: flat combining vs CAS at high producer counts
+// ============================================================================
+// MPSCRing is the CAS design: every producer races on one head index, so
+// the cache line holding it bounces between cores on every Push.
+// CombiningQueue has producers publish into their own slots and lets
+// whichever one holds the combiner lock apply the whole batch, so at
+// high producer counts most pushes touch only their own cache line.
+//
+// SetParallelism(p) runs p*GOMAXPROCS producer goroutines.
+
+var combiningProducers = []int{4, 8, 16, 32}
+
+// benchMPSC runs producers against a single polling consumer. newPush is
+// called once per producer goroutine and returns its push function.
+func benchMPSC(b *testing.B, producers int, newPush func() func(int) bool, pop func() (int, bool)) {
+	done := make(chan struct{})
+	consumerDone := make(chan struct{})
+
+	go func() {
+		defer close(consumerDone)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				pop()
+			}
+		}
+	}()
+
+	b.SetParallelism(producers)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		push := newPush()
+		i := 0
+		for pb.Next() {
+			for !push(i) {
+				// Spin until push succeeds
+			}
+			i++
+		}
+	})
+
+	b.StopTimer()
+	close(done)
+	<-consumerDone
+}
+
+// BenchmarkMPSC_MPSCRing pushes through the CAS-based MPSC ring.
+func BenchmarkMPSC_MPSCRing(b *testing.B) {
+	for _, p := range combiningProducers {
+		b.Run(fmt.Sprintf("P=%d", p), func(b *testing.B) {
+			trackGC(b)
+			q := queue.Must(queue.NewMPSCRing[int](1024))
+			benchMPSC(b, p, func() func(int) bool { return q.Push }, q.Pop)
+		})
+	}
+}
+
+// BenchmarkMPSC_Combining pushes through the flat-combining queue, one
+// producer handle per goroutine.
+func BenchmarkMPSC_Combining(b *testing.B) {
+	for _, p := range combiningProducers {
+		b.Run(fmt.Sprintf("P=%d", p), func(b *testing.B) {
+			trackGC(b)
+			maxProducers := p * runtime.GOMAXPROCS(0)
+			q := queue.Must(queue.NewCombiningQueue[int](1024, maxProducers))
+			benchMPSC(b, p, func() func(int) bool {
+				return queue.Must(q.NewProducer()).Push
+			}, q.Pop)
+		})
+	}
+}
+
+// BenchmarkMPSC_ChannelSweep is the channel baseline for the same
+// producer counts.
+func BenchmarkMPSC_ChannelSweep(b *testing.B) {
+	for _, p := range combiningProducers {
+		b.Run(fmt.Sprintf("P=%d", p), func(b *testing.B) {
+			trackGC(b)
+			q := queue.Must(queue.NewChannel[int](1024))
+			benchMPSC(b, p, func() func(int) bool { return q.Push }, q.Pop)
+		})
+	}
+}
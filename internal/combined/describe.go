@@ -0,0 +1,119 @@
+package combined
+
+import "strings"
+
+// descriptions holds what Describe has set, guarded by registryMu.
+var descriptions = map[string]string{}
+
+// Describe sets the description of name, a scenario or a group of them
+// (the part of scenario names before the first slash), for bench explain
+// and the cmd tools' reports to print. text is plain prose, already
+// wrapped; a scenario's says what its loop body does, and a group's what
+// its variants have in common.
+func Describe(name, text string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	descriptions[name] = strings.TrimSpace(text)
+}
+
+// Description returns the description Describe set for name, or "".
+func Description(name string) string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return descriptions[name]
+}
+
+// Group returns the group scenario name belongs to: "cancel-tick" for
+// "cancel-tick/std".
+func Group(name string) string {
+	group, _, _ := strings.Cut(name, "/")
+	return group
+}
+
+func init() {
+	Describe("cancel", `
+Checks once per iteration whether the loop has been cancelled, as a
+worker does between items. Nothing cancels it, so every check is the
+"keep going" answer the loop sees almost every time.`)
+	Describe("cancel/std", `
+context.Context: a select on ctx.Done() with a default case, a
+non-blocking receive on the context's channel.`)
+	Describe("cancel/atomic", `
+cancel.AtomicCanceler: a single atomic load of a bool.`)
+
+	Describe("tick", `
+Asks a ticker once per iteration whether its interval has elapsed. The
+interval (1h unless swept) never elapses during a run, so this times the
+check, not the periodic work.`)
+	Describe("tick/std", `
+time.Ticker: a select on the ticker's channel with a default case, a
+non-blocking receive that the runtime timer would fill.`)
+	Describe("tick/atomic", `
+tick.AtomicTicker: reads the monotonic clock (runtime.nanotime) and
+compares it with the last tick, an atomic load, swapping in the new time
+with a CAS only when the interval has passed.`)
+	Describe("tick/batch", `
+tick.BatchTicker: counts calls and reads the clock (time.Now) only on
+every batch'th one, so most checks are an increment and a modulo. Ticks
+can fire up to batch-1 calls late.`)
+	Describe("tick/tsc", `
+tick.TSCTicker (amd64): reads the CPU's time stamp counter (RDTSC)
+instead of the clock, compared in cycles calibrated at startup.`)
+
+	Describe("queue", `
+Pushes one item onto an empty queue and pops it straight back, on one
+goroutine: the bare cost of a queue operation, without contention, as
+cmd/channel's SPSC loop times it.`)
+	Describe("queue/std", `
+A buffered channel, with a non-blocking send and receive (select with
+default), each taking the channel's lock.`)
+	Describe("queue/ring", `
+queue.RingBuffer, the lock-free SPSC ring: atomic head and tail indexes
+publish each slot, plus guards that panic if two goroutines push or pop
+at once.`)
+	Describe("queue/unsync", `
+queue.UnsyncRing, a plain ring with no atomics and no guards: the floor.
+queue/ring minus this is what its synchronization costs.`)
+
+	Describe("cancel-tick", `
+Simulates a hot loop that checks for cancellation and periodic timing
+on every iteration:
+
+  for {
+      if cancel.Done() { return }
+      if ticker.Tick() { doPeriodicWork() }
+      processItem()
+  }`)
+	Describe("cancel-tick/std", `
+Standard: a context.Context and a time.Ticker, two non-blocking channel
+receives per iteration.`)
+	Describe("cancel-tick/atomic", `
+Optimized: an atomic cancel flag and tick.AtomicTicker, an atomic load
+and a clock read per iteration.`)
+	Describe("cancel-tick/batch", `
+Ultra: an atomic cancel flag and tick.BatchTicker, which reads the clock
+only every batch'th iteration.`)
+
+	Describe("full-loop", `
+The cancel + tick loop around a queue: each iteration checks for
+cancellation, checks the ticker, pops an item off a full queue and pushes
+it back.`)
+	Describe("full-loop/std", `
+context.Context, time.Ticker and a buffered channel: four non-blocking
+channel operations per iteration.`)
+	Describe("full-loop/optimized", `
+An atomic cancel flag, tick.AtomicTicker and queue.RingBuffer: atomics
+only, no channel operations.`)
+
+	Describe("full-loop-observed", `
+full-loop plus the observability a service adds: two atomic counter
+increments per item, and a structured (slog JSON) log line each time the
+1ms ticker fires. The log goes to io.Discard, so it times formatting,
+not I/O, amortized over the items between ticks.`)
+	Describe("full-loop-observed/std", `
+The observed loop on context.Context, time.Ticker and a buffered
+channel.`)
+	Describe("full-loop-observed/optimized", `
+The observed loop on an atomic cancel flag, tick.AtomicTicker and
+queue.RingBuffer.`)
+}
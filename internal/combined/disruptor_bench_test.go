@@ -0,0 +1,154 @@
+package combined_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// 1 Producer -> 3 dependent consumers (journal -> replicate -> apply)
+// ============================================================================
+//
+// The Disruptor lets all three stages read the same slot in place, gated by
+// sequence barriers. The channel version is the idiomatic alternative: three
+// channels chained through two forwarding goroutines.
+//
+// Both measure time until the last stage has processed all b.N items.
+
+// BenchmarkPipeline_Disruptor_3Stage uses per-item Poll on every stage.
+func BenchmarkPipeline_Disruptor_3Stage(b *testing.B) {
+	trackGC(b)
+	benchmarkDisruptor3Stage(b, false)
+}
+
+// BenchmarkPipeline_Disruptor_3Stage_Batch uses Consume, which processes
+// everything available and publishes progress once per batch.
+func BenchmarkPipeline_Disruptor_3Stage_Batch(b *testing.B) {
+	trackGC(b)
+	benchmarkDisruptor3Stage(b, true)
+}
+
+func benchmarkDisruptor3Stage(b *testing.B, batch bool) {
+	d := queue.Must(queue.NewDisruptor[int](1024))
+	journal := d.NewConsumer()
+	replicate := d.NewConsumer(journal)
+	apply := d.NewConsumer(replicate)
+
+	var applied atomic.Int64
+	done := make(chan struct{})
+
+	// Each stage keeps its sum in its own slot, sunk once all have exited
+	var sums [3]int
+	var wg sync.WaitGroup
+	stage := func(c *queue.Consumer[int], sum *int, last bool) {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if batch {
+				n := c.Consume(func(v int) { *sum += v })
+				if last && n > 0 {
+					applied.Add(int64(n))
+				}
+				continue
+			}
+			if v, ok := c.Poll(); ok {
+				*sum += v
+				if last {
+					applied.Add(1)
+				}
+			}
+		}
+	}
+	wg.Add(3)
+	go stage(journal, &sums[0], false)
+	go stage(replicate, &sums[1], false)
+	go stage(apply, &sums[2], true)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for !d.Publish(i) {
+			// Spin until the slowest stage frees a slot
+		}
+	}
+	for applied.Load() < int64(b.N) {
+	}
+
+	b.StopTimer()
+	close(done)
+	wg.Wait()
+	sinkInt = sums[0] + sums[1] + sums[2]
+}
+
+// BenchmarkPipeline_ChannelChain_3Stage chains three buffered channels.
+func BenchmarkPipeline_ChannelChain_3Stage(b *testing.B) {
+	trackGC(b)
+	q1 := queue.Must(queue.NewChannel[int](1024))
+	q2 := queue.Must(queue.NewChannel[int](1024))
+	q3 := queue.Must(queue.NewChannel[int](1024))
+
+	var applied atomic.Int64
+	done := make(chan struct{})
+
+	forward := func(in, out *queue.ChannelQueue[int]) {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if v, ok := in.Pop(); ok {
+				for !out.Push(v) {
+					select {
+					case <-done:
+						return
+					default:
+					}
+				}
+			}
+		}
+	}
+	var sum int
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go forward(q1, q2) // journal
+	go forward(q2, q3) // replicate
+	go func() {        // apply
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if v, ok := q3.Pop(); ok {
+				sum += v
+				applied.Add(1)
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for !q1.Push(i) {
+			// Spin until push succeeds
+		}
+	}
+	for applied.Load() < int64(b.N) {
+	}
+
+	b.StopTimer()
+	close(done)
+	wg.Wait()
+	sinkInt = sum
+}
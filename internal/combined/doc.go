@@ -4,4 +4,20 @@
 // These benchmarks are more representative of real-world performance
 // than isolated micro-benchmarks, as they capture the cumulative cost
 // and any interactions between components.
+//
+// Single-goroutine loops are defined as Scenarios and registered by name
+// (scenarios.go), so the benchmarks and the cmd tools share one
+// implementation: BenchmarkScenario runs every registered scenario, and
+// cmd/context-ticker can list them and run any one with -scenario. The
+// single components (cancel/, tick/, queue/) are registered as well, so
+// bench all can run the full comparison from the registry alone.
+// Scenarios built with DefineParams take parameters such as the queue
+// size, and a Sweep (sweep.go) expands one into a run per combination of
+// parameter values.
+// Multi-goroutine pipelines stay as plain benchmarks in the _test files.
+//
+// Pinning (pin.go) places the producer, consumer and periodic-work
+// goroutines on chosen CPUs, and RunOn runs a Scenario pinned, so
+// cross-core effects are fixed by the benchmark rather than left to the
+// scheduler.
 package combined
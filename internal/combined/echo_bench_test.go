@@ -0,0 +1,138 @@
+package combined_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/histogram"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+// ============================================================================
+// Loopback echo (real syscalls)
+// ============================================================================
+// The client hot loop checks cancel and tick, pops a message buffer from
+// a queue, writes it to a TCP echo server on 127.0.0.1, reads the echo
+// back and recycles the buffer. One message is in flight at a time, so
+// each op is a full round trip through the kernel: a write, a read, and
+// usually a netpoller wakeup on each side.
+//
+// Against microseconds of syscall and scheduler work, the nanoseconds
+// saved by the optimized primitives should barely show. That is the
+// point: it tells you whether they matter for an I/O-bound loop.
+
+const (
+	echoSize     = 64 // Bytes per message
+	echoBuffers  = 16 // Message buffers cycling through the queue
+	echoInterval = time.Millisecond
+)
+
+// startEchoServer listens on a loopback port and echoes every connection
+// until stop is called.
+func startEchoServer(b *testing.B) (addr string, stop func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Skip(err)
+	}
+	conns := make(chan net.Conn, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns <- c
+			go func() {
+				_, _ = io.Copy(c, c)
+			}()
+		}
+	}()
+
+	return ln.Addr().String(), func() {
+		ln.Close()
+		<-done
+		close(conns)
+		for c := range conns {
+			c.Close()
+		}
+	}
+}
+
+// benchEcho runs b.N round trips through an echo server using c, t and
+// q in the client loop, and reports msgs/s and round-trip percentiles.
+func benchEcho(b *testing.B, c cancel.Canceler, t tick.Ticker, q queue.Queue[[]byte]) {
+	defer t.Stop()
+	addr, stop := startEchoServer(b)
+	defer stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+
+	for i := 0; i < echoBuffers; i++ {
+		q.Push(make([]byte, echoSize))
+	}
+	resp := make([]byte, echoSize)
+	rtt := histogram.New()
+	ticks := 0
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	t.Reset()
+
+	for i := 0; i < b.N; i++ {
+		if c.Done() {
+			b.Fatal("cancelled")
+		}
+		if t.Tick() {
+			ticks++
+		}
+		msg, ok := q.Pop()
+		if !ok {
+			b.Fatal("message queue empty")
+		}
+		sent := time.Now()
+		if _, err := conn.Write(msg); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			b.Fatal(err)
+		}
+		rtt.Record(int64(time.Since(sent)))
+		q.Push(msg) // Recycle
+	}
+
+	b.StopTimer()
+	sinkInt = ticks
+	reportLatency(b, rtt)
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "msgs/s")
+}
+
+// BenchmarkPipeline_Echo_Std runs the echo client loop with a context,
+// time.Ticker and buffered channel.
+func BenchmarkPipeline_Echo_Std(b *testing.B) {
+	trackGC(b)
+	benchEcho(b,
+		cancel.NewContext(context.Background()),
+		tick.NewTicker(echoInterval),
+		queue.Must(queue.NewChannel[[]byte](echoBuffers)))
+}
+
+// BenchmarkPipeline_Echo_Optimized runs the echo client loop with an
+// AtomicCanceler, AtomicTicker and ring buffer.
+func BenchmarkPipeline_Echo_Optimized(b *testing.B) {
+	trackGC(b)
+	benchEcho(b,
+		cancel.NewAtomic(),
+		tick.NewAtomicTicker(echoInterval),
+		queue.Must(queue.NewRingBuffer[[]byte](echoBuffers)))
+}
@@ -0,0 +1,172 @@
+//go:build extqueues
+
+package combined_test
+
+import (
+	"sync"
+	"testing"
+
+	workiva "github.com/Workiva/go-datastructures/queue"
+	"github.com/gammazero/deque"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// Third-party queue comparison (opt-in)
+// ============================================================================
+// Built only with -tags extqueues, so the default build and test runs do
+// not pull these modules in:
+//
+//	go test -tags extqueues -bench=BenchmarkExt -benchmem ./internal/combined
+//
+// Compared against our RingBuffer:
+//
+//   - gammazero/deque: a growable ring-buffer deque, not goroutine-safe.
+//     Used as a plain FIFO list, and behind a mutex for the pipeline.
+//   - Workiva/go-datastructures queue.RingBuffer: a port of Vyukov's
+//     bounded MPMC queue (per-slot sequence numbers, CAS on both ends).
+//     It stores interface{} values and its Get blocks by yielding.
+
+// ----------------------------------------------------------------------------
+// Single goroutine push+pop
+// ----------------------------------------------------------------------------
+
+// BenchmarkExt_PushPop_RingBuffer is the in-repo baseline.
+func BenchmarkExt_PushPop_RingBuffer(b *testing.B) {
+	trackGC(b)
+	q := queue.Must(queue.NewRingBuffer[int](1024))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		val, _ = q.Pop()
+	}
+	sinkInt = val
+}
+
+// BenchmarkExt_PushPop_Deque uses gammazero/deque as a FIFO list.
+func BenchmarkExt_PushPop_Deque(b *testing.B) {
+	trackGC(b)
+	var q deque.Deque[int]
+	q.Grow(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	for i := 0; i < b.N; i++ {
+		q.PushBack(i)
+		val = q.PopFront()
+	}
+	sinkInt = val
+}
+
+// BenchmarkExt_PushPop_WorkivaRing uses the Workiva Vyukov MPMC ring.
+func BenchmarkExt_PushPop_WorkivaRing(b *testing.B) {
+	trackGC(b)
+	q := workiva.NewRingBuffer(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val any
+	for i := 0; i < b.N; i++ {
+		q.Offer(i)
+		val, _ = q.Get()
+	}
+	sinkInt, _ = val.(int)
+}
+
+// ----------------------------------------------------------------------------
+// SPSC pipeline (2 goroutines)
+// ----------------------------------------------------------------------------
+
+// BenchmarkExt_Pipeline_RingBuffer is the in-repo baseline.
+func BenchmarkExt_Pipeline_RingBuffer(b *testing.B) {
+	trackGC(b)
+	q := queue.Must(queue.NewRingBuffer[int](1024))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for n := 0; n < b.N; {
+			if _, ok := q.Pop(); ok {
+				n++
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for !q.Push(i) {
+			// Spin until push succeeds
+		}
+	}
+	wg.Wait()
+}
+
+// BenchmarkExt_Pipeline_DequeMutex guards a gammazero/deque with a mutex,
+// the usual way to share it between goroutines.
+func BenchmarkExt_Pipeline_DequeMutex(b *testing.B) {
+	trackGC(b)
+	var mu sync.Mutex
+	var q deque.Deque[int]
+	q.Grow(1024)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for n := 0; n < b.N; {
+			mu.Lock()
+			if q.Len() > 0 {
+				q.PopFront()
+				n++
+			}
+			mu.Unlock()
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; {
+		mu.Lock()
+		if q.Len() < 1024 {
+			q.PushBack(i)
+			i++
+		}
+		mu.Unlock()
+	}
+	wg.Wait()
+}
+
+// BenchmarkExt_Pipeline_WorkivaRing uses the Workiva ring with a blocking
+// consumer.
+func BenchmarkExt_Pipeline_WorkivaRing(b *testing.B) {
+	trackGC(b)
+	q := workiva.NewRingBuffer(1024)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for n := 0; n < b.N; n++ {
+			if _, err := q.Get(); err != nil {
+				return
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := q.Put(i); err != nil {
+			b.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,137 @@
+package combined_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// Fan-out/fan-in: 1 producer -> N workers -> 1 collector
+// ============================================================================
+// The channel version is the textbook pattern: workers range over a
+// shared jobs channel, send into a shared results channel, and watch
+// ctx.Done() to exit. The queue version replaces the jobs channel with an
+// SPMCRing, the results channel with an MPSCRing, and ctx.Done() with an
+// AtomicCanceler.
+//
+// ns/op is per item, end to end. Queue workers yield when the jobs queue
+// is empty; without that, N spinning workers starve the producer as soon
+// as N exceeds GOMAXPROCS.
+
+var fanOutWorkers = []int{2, 4, 8, 16, 32}
+
+// fanOutWork stands in for the per-item processing a worker does.
+func fanOutWork(v int) int {
+	return v*2 + 1
+}
+
+// BenchmarkPipeline_FanOut_Channel fans out and back in over channels.
+func BenchmarkPipeline_FanOut_Channel(b *testing.B) {
+	for _, n := range fanOutWorkers {
+		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
+			trackGC(b)
+			ctx, cancelFn := context.WithCancel(context.Background())
+			jobs := make(chan int, 1024)
+			results := make(chan int, 1024)
+
+			var wg sync.WaitGroup
+			for w := 0; w < n; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						select {
+						case <-ctx.Done():
+							return
+						case v := <-jobs:
+							results <- fanOutWork(v)
+						}
+					}
+				}()
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			go func() {
+				for i := 0; i < b.N; i++ {
+					jobs <- i
+				}
+			}()
+
+			var sum int
+			for i := 0; i < b.N; i++ {
+				sum += <-results
+			}
+
+			b.StopTimer()
+			cancelFn()
+			wg.Wait()
+			sinkInt = sum
+		})
+	}
+}
+
+// BenchmarkPipeline_FanOut_Queue fans out over an SPMCRing and back in
+// over an MPSCRing, stopping workers with an AtomicCanceler.
+func BenchmarkPipeline_FanOut_Queue(b *testing.B) {
+	for _, n := range fanOutWorkers {
+		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
+			trackGC(b)
+			stop := cancel.NewAtomic()
+			jobs := queue.Must(queue.NewSPMCRing[int](1024))
+			results := queue.Must(queue.NewMPSCRing[int](1024))
+
+			var wg sync.WaitGroup
+			for w := 0; w < n; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for !stop.Done() {
+						v, ok := jobs.Pop()
+						if !ok {
+							runtime.Gosched()
+							continue
+						}
+						r := fanOutWork(v)
+						for !results.Push(r) {
+							// Spin until push succeeds
+						}
+					}
+				}()
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			go func() {
+				for i := 0; i < b.N; i++ {
+					for !jobs.Push(i) {
+						// Spin until push succeeds
+					}
+				}
+			}()
+
+			var sum int
+			for i := 0; i < b.N; {
+				v, ok := results.Pop()
+				if !ok {
+					continue
+				}
+				sum += v
+				i++
+			}
+
+			b.StopTimer()
+			stop.Cancel()
+			wg.Wait()
+			sinkInt = sum
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package combined_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/histogram"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+// ============================================================================
+// Batch drain on tick (periodic flush)
+// ============================================================================
+// The producer pushes flat out; the consumer does nothing until its
+// ticker fires, then empties the queue in one go, the way a writer
+// batches rows into one insert or a metrics exporter flushes a buffer.
+//
+// Pop empties the queue one item at a time; Drain takes it in slices of
+// flushBatch, reading head and publishing tail once per slice. Each is run
+// with each ticker kind. ns/op is per item; items/flush is the mean batch
+// size and flush-*-ns is how long emptying the queue took.
+
+const (
+	flushInterval = 100 * time.Microsecond
+	flushQueue    = 16384
+	flushBatch    = 256
+)
+
+var flushModes = []struct {
+	name  string
+	flush func(q *queue.RingBuffer[int], buf []int) int
+}{
+	{"Pop", func(q *queue.RingBuffer[int], _ []int) int {
+		n := 0
+		for {
+			if _, ok := q.Pop(); !ok {
+				return n
+			}
+			n++
+		}
+	}},
+	{"Drain", func(q *queue.RingBuffer[int], buf []int) int {
+		n := 0
+		for {
+			k := q.Drain(buf)
+			n += k
+			if k < len(buf) {
+				return n
+			}
+		}
+	}},
+}
+
+var flushTickers = []struct {
+	name string
+	new  func() tick.Ticker
+}{
+	{"Std", func() tick.Ticker { return tick.NewTicker(flushInterval) }},
+	{"Atomic", func() tick.Ticker { return tick.NewAtomicTicker(flushInterval) }},
+	{"Batch", func() tick.Ticker { return tick.NewBatch(flushInterval, 100) }},
+}
+
+// benchFlushOnTick consumes b.N items, emptying q with flush whenever t
+// fires.
+func benchFlushOnTick(b *testing.B, t tick.Ticker, flush func(*queue.RingBuffer[int], []int) int) {
+	defer t.Stop()
+	q := queue.Must(queue.NewRingBuffer[int](flushQueue))
+	buf := make([]int, flushBatch)
+	lat := histogram.New()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	t.Reset()
+
+	go func() {
+		for i := 0; i < b.N; i++ {
+			for !q.Push(i) {
+				// Spin until push succeeds
+			}
+		}
+	}()
+
+	flushes := 0
+	for n := 0; n < b.N; {
+		if !t.Tick() {
+			continue
+		}
+		start := time.Now()
+		n += flush(q, buf)
+		lat.Record(int64(time.Since(start)))
+		flushes++
+	}
+
+	b.StopTimer()
+	b.ReportMetric(float64(b.N)/float64(flushes), "items/flush")
+	b.ReportMetric(float64(lat.Quantile(0.50)), "flush-p50-ns")
+	b.ReportMetric(float64(lat.Quantile(0.99)), "flush-p99-ns")
+	b.ReportMetric(float64(lat.Max()), "flush-max-ns")
+}
+
+// BenchmarkPipeline_FlushOnTick drains a ring buffer on every tick, per
+// item and in slices, for each ticker.
+func BenchmarkPipeline_FlushOnTick(b *testing.B) {
+	for _, mode := range flushModes {
+		for _, tk := range flushTickers {
+			b.Run(mode.name+"/"+tk.name, func(b *testing.B) {
+				trackGC(b)
+				benchFlushOnTick(b, tk.new(), mode.flush)
+			})
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package combined_test
+
+import (
+	"runtime"
+	"testing"
+)
+
+// trackGC reports the GC cycles and stop-the-world pause time a benchmark
+// run caused, per op, next to ns/op and allocs/op. An "optimized" variant
+// that only wins by allocating more shows up here as GC work that the
+// ns/op column does not charge for.
+//
+// Call it first thing in the benchmark (or in each b.Run closure). The
+// window covers the whole run, setup included; the testing package runs a
+// GC before every run, so earlier benchmarks' garbage is not counted.
+func trackGC(b *testing.B) {
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	b.Cleanup(func() {
+		var after runtime.MemStats
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gcs/op")
+		b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "gc-pause-ns/op")
+	})
+}
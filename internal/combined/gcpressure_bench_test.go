@@ -0,0 +1,91 @@
+package combined_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// Tail latency under background GC pressure
+// ============================================================================
+// The latency benchmarks run on an otherwise idle heap, where the GC
+// never runs. Here a background goroutine allocates garbage at a fixed
+// rate while keeping a rolling window of it live, so the collector has
+// real marking work and runs concurrently with the pipeline. Compare
+// p99-ns/p999-ns across garbage rates: GC assists and stop-the-world
+// phases land on whichever goroutine is allocating or waiting, and a
+// channel's parked consumer wakes through the same scheduler the GC is
+// using.
+
+const (
+	garbageChunk  = 16 << 10 // Bytes per allocation
+	garbageWindow = 1024     // Chunks kept live (16MB)
+)
+
+// garbageRates are background allocation rates in MB/s.
+var garbageRates = []int{0, 100, 1000}
+
+// gcPressureLoad is the offered load the pipeline runs at.
+var gcPressureLoad = latencyLoad{"100K_per_sec", 10 * time.Microsecond}
+
+// startGarbage allocates mbPerSec MB/s in the background until the
+// returned stop function is called. A rate of 0 does nothing.
+func startGarbage(mbPerSec int) (stop func()) {
+	if mbPerSec == 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		live := make([][]byte, garbageWindow)
+		perChunk := time.Duration(float64(time.Second) * garbageChunk / float64(mbPerSec<<20))
+		start := time.Now()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			live[i%garbageWindow] = make([]byte, garbageChunk)
+			if ahead := time.Duration(i+1)*perChunk - time.Since(start); ahead > 0 {
+				time.Sleep(ahead)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+// BenchmarkPipeline_GCPressure_Channel reports channel pipeline latency
+// at each background garbage rate.
+func BenchmarkPipeline_GCPressure_Channel(b *testing.B) {
+	for _, mb := range garbageRates {
+		b.Run(fmt.Sprintf("garbage=%dMB_s", mb), func(b *testing.B) {
+			trackGC(b)
+			stop := startGarbage(mb)
+			defer stop()
+			benchLatency(b, queue.Must(queue.NewChannel[int64](1024)), gcPressureLoad, false)
+		})
+	}
+}
+
+// BenchmarkPipeline_GCPressure_RingBuffer reports ring buffer pipeline
+// latency at each background garbage rate.
+func BenchmarkPipeline_GCPressure_RingBuffer(b *testing.B) {
+	for _, mb := range garbageRates {
+		b.Run(fmt.Sprintf("garbage=%dMB_s", mb), func(b *testing.B) {
+			trackGC(b)
+			stop := startGarbage(mb)
+			defer stop()
+			benchLatency(b, queue.Must(queue.NewRingBuffer[int64](1024)), gcPressureLoad, false)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package combined_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/histogram"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+// ============================================================================
+// Tick jitter under load
+// ============================================================================
+// A hot loop processes items flat out (pop, checksum a 256-byte payload,
+// recycle) and polls a 10ms ticker for its periodic work. Each op is one
+// tick; the benchmark records how far each interval between consecutive
+// ticks was from 10ms, and how many items the loop got through.
+//
+// BatchTicker only reads the clock every N calls, so its ticks land up to
+// N items late: the larger N, the cheaper the loop and the worse the
+// jitter. StdTicker and AtomicTicker check on every call.
+
+// jitterInterval is how often the periodic work should run.
+const jitterInterval = 10 * time.Millisecond
+
+var jitterTickers = []struct {
+	name string
+	new  func() tick.Ticker
+}{
+	{"Std", func() tick.Ticker { return tick.NewTicker(jitterInterval) }},
+	{"Atomic", func() tick.Ticker { return tick.NewAtomicTicker(jitterInterval) }},
+	{"Batch/every=1000", func() tick.Ticker { return tick.NewBatch(jitterInterval, 1000) }},
+	{"Batch/every=10000", func() tick.Ticker { return tick.NewBatch(jitterInterval, 10000) }},
+}
+
+// benchTickJitter runs the loaded loop until t has fired b.N times and
+// reports interval error percentiles and loop throughput.
+func benchTickJitter(b *testing.B, t tick.Ticker) {
+	defer t.Stop()
+	q := queue.Must(queue.NewRingBuffer[int](1024))
+	for i := 0; i < 1024; i++ {
+		q.Push(i)
+	}
+	jitter := histogram.New()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	t.Reset()
+
+	start := time.Now()
+	last := start
+	items, r := 0, 0
+	for fired := 0; fired < b.N; {
+		val, _ := q.Pop()
+		r += workChecksum(workPayload, val)
+		q.Push(val) // Recycle
+		items++
+
+		if t.Tick() {
+			now := time.Now()
+			jitter.Record(int64((now.Sub(last) - jitterInterval).Abs()))
+			last = now
+			fired++
+		}
+	}
+
+	b.StopTimer()
+	elapsed := time.Since(start)
+	sinkInt = r
+	b.ReportMetric(float64(jitter.Quantile(0.50)), "jitter-p50-ns")
+	b.ReportMetric(float64(jitter.Quantile(0.99)), "jitter-p99-ns")
+	b.ReportMetric(float64(jitter.Max()), "jitter-max-ns")
+	b.ReportMetric(float64(items)/elapsed.Seconds(), "items/s")
+}
+
+// BenchmarkCombined_TickJitter measures 10ms tick accuracy for each
+// ticker while the loop runs at full throughput.
+func BenchmarkCombined_TickJitter(b *testing.B) {
+	for _, tc := range jitterTickers {
+		b.Run(tc.name, func(b *testing.B) {
+			trackGC(b)
+			benchTickJitter(b, tc.new())
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package combined_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/histogram"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// Per-element latency benchmarks
+// ============================================================================
+// ns/op is the producer's cost per item and says nothing about how long an
+// item waits in the queue. Here every element carries its Push timestamp
+// and the consumer records Pop time minus Push time into an HDR-style
+// histogram, so the benchmark reports the latency distribution alongside
+// throughput. Recording is a few atomic adds with no allocation, so it
+// barely perturbs the consumer and memory stays fixed however large b.N
+// grows.
+//
+// Offered load is set by spacing pushes: "saturated" pushes back to back,
+// the other levels pace the producer to a fixed rate. Under saturation the
+// queue fills and latency is dominated by queueing delay; at low load it
+// approaches the cross-goroutine handoff cost.
+//
+// Stamping at Push time hides stalls: if the queue is full, the producer
+// spins, and the items it should have sent meanwhile are never measured
+// waiting (coordinated omission). The Corrected benchmarks instead stamp
+// each item with its intended send time on the fixed schedule, so a stall
+// shows up as latency on every item it delayed, as it would for a real
+// client that kept sending.
+
+// latencyLoad is one offered-load level.
+type latencyLoad struct {
+	name string
+	gap  time.Duration // Time between pushes; 0 = saturated
+}
+
+var latencyLoads = []latencyLoad{
+	{"saturated", 0},
+	{"1M_per_sec", time.Microsecond},
+	{"100K_per_sec", 10 * time.Microsecond},
+}
+
+// reportLatency reports p50/p99/p999/max of h in nanoseconds.
+func reportLatency(b *testing.B, h *histogram.Histogram) {
+	b.ReportMetric(float64(h.Quantile(0.50)), "p50-ns")
+	b.ReportMetric(float64(h.Quantile(0.99)), "p99-ns")
+	b.ReportMetric(float64(h.Quantile(0.999)), "p999-ns")
+	b.ReportMetric(float64(h.Max()), "max-ns")
+}
+
+// benchLatency runs a paced producer against a single consumer that
+// records per-element latency. Timestamps are monotonic offsets from a
+// shared base, so elements are plain int64s.
+//
+// With corrected set, paced items carry their scheduled send time instead
+// of the time Push was actually called.
+func benchLatency(b *testing.B, q queue.Queue[int64], load latencyLoad, corrected bool) {
+	base := time.Now()
+	lat := histogram.New()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for n := 0; n < b.N; {
+			sent, ok := q.Pop()
+			if !ok {
+				continue
+			}
+			lat.Record(int64(time.Since(base)) - sent)
+			n++
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	next := time.Duration(0)
+	for i := 0; i < b.N; i++ {
+		if load.gap > 0 {
+			next += load.gap
+			for time.Since(base) < next {
+				// Spin to hold the offered rate; if behind, send at once
+			}
+		}
+		stamp := int64(time.Since(base))
+		if corrected && load.gap > 0 {
+			stamp = int64(next)
+		}
+		for !q.Push(stamp) {
+			// Spin until push succeeds
+		}
+	}
+
+	wg.Wait()
+	b.StopTimer()
+	reportLatency(b, lat)
+}
+
+// BenchmarkPipeline_Latency_Channel reports per-element latency through
+// a buffered channel.
+func BenchmarkPipeline_Latency_Channel(b *testing.B) {
+	for _, load := range latencyLoads {
+		b.Run(load.name, func(b *testing.B) {
+			trackGC(b)
+			benchLatency(b, queue.Must(queue.NewChannel[int64](1024)), load, false)
+		})
+	}
+}
+
+// BenchmarkPipeline_Latency_RingBuffer reports per-element latency
+// through the SPSC ring buffer.
+func BenchmarkPipeline_Latency_RingBuffer(b *testing.B) {
+	for _, load := range latencyLoads {
+		b.Run(load.name, func(b *testing.B) {
+			trackGC(b)
+			benchLatency(b, queue.Must(queue.NewRingBuffer[int64](1024)), load, false)
+		})
+	}
+}
+
+// BenchmarkPipeline_Latency_Corrected_Channel reports coordinated-omission
+// corrected latency through a buffered channel at each paced load.
+func BenchmarkPipeline_Latency_Corrected_Channel(b *testing.B) {
+	for _, load := range latencyLoads {
+		if load.gap == 0 {
+			continue // No schedule to correct against
+		}
+		b.Run(load.name, func(b *testing.B) {
+			trackGC(b)
+			benchLatency(b, queue.Must(queue.NewChannel[int64](1024)), load, true)
+		})
+	}
+}
+
+// BenchmarkPipeline_Latency_Corrected_RingBuffer reports coordinated-
+// omission corrected latency through the SPSC ring buffer at each paced
+// load.
+func BenchmarkPipeline_Latency_Corrected_RingBuffer(b *testing.B) {
+	for _, load := range latencyLoads {
+		if load.gap == 0 {
+			continue // No schedule to correct against
+		}
+		b.Run(load.name, func(b *testing.B) {
+			trackGC(b)
+			benchLatency(b, queue.Must(queue.NewRingBuffer[int64](1024)), load, true)
+		})
+	}
+}
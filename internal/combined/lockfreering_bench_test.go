@@ -54,7 +54,7 @@ func (r *spscRing) Push(v int) bool {
 func (r *spscRing) Pop() (int, bool) {
 	tail := r.tail.Load()
 	head := r.head.Load()
-	if tail >= head {
+	if tail == head {
 		return 0, false
 	}
 	v := r.buf[tail&r.mask]
@@ -64,6 +64,7 @@ func (r *spscRing) Pop() (int, bool) {
 
 // BenchmarkLFR_SPSC_Channel - baseline channel
 func BenchmarkLFR_SPSC_Channel(b *testing.B) {
+	trackGC(b)
 	ch := make(chan int, 1024)
 	done := make(chan struct{})
 
@@ -95,6 +96,7 @@ func BenchmarkLFR_SPSC_Channel(b *testing.B) {
 
 // BenchmarkLFR_SPSC_OurRing - our unguarded SPSC
 func BenchmarkLFR_SPSC_OurRing(b *testing.B) {
+	trackGC(b)
 	q := newSPSCRing(1024)
 	done := make(chan struct{})
 
@@ -120,6 +122,7 @@ func BenchmarkLFR_SPSC_OurRing(b *testing.B) {
 
 // BenchmarkLFR_SPSC_ShardedRing1 - go-lock-free-ring with 1 shard (SPSC-like)
 func BenchmarkLFR_SPSC_ShardedRing1(b *testing.B) {
+	trackGC(b)
 	r, _ := ring.NewShardedRing(1024, 1)
 	done := make(chan struct{})
 
@@ -149,6 +152,7 @@ func BenchmarkLFR_SPSC_ShardedRing1(b *testing.B) {
 
 // BenchmarkLFR_MPSC_Channel_4P - 4 producers using channel
 func BenchmarkLFR_MPSC_Channel_4P(b *testing.B) {
+	trackGC(b)
 	ch := make(chan int, 1024)
 	done := make(chan struct{})
 	consumerDone := make(chan struct{})
@@ -190,6 +194,7 @@ func BenchmarkLFR_MPSC_Channel_4P(b *testing.B) {
 
 // BenchmarkLFR_MPSC_ShardedRing_4P_4S - 4 producers, 4 shards
 func BenchmarkLFR_MPSC_ShardedRing_4P_4S(b *testing.B) {
+	trackGC(b)
 	r, _ := ring.NewShardedRing(1024, 4)
 	done := make(chan struct{})
 	consumerDone := make(chan struct{})
@@ -227,6 +232,7 @@ func BenchmarkLFR_MPSC_ShardedRing_4P_4S(b *testing.B) {
 
 // BenchmarkLFR_MPSC_Channel_8P - 8 producers using channel
 func BenchmarkLFR_MPSC_Channel_8P(b *testing.B) {
+	trackGC(b)
 	ch := make(chan int, 1024)
 	done := make(chan struct{})
 	consumerDone := make(chan struct{})
@@ -268,6 +274,7 @@ func BenchmarkLFR_MPSC_Channel_8P(b *testing.B) {
 
 // BenchmarkLFR_MPSC_ShardedRing_8P_8S - 8 producers, 8 shards
 func BenchmarkLFR_MPSC_ShardedRing_8P_8S(b *testing.B) {
+	trackGC(b)
 	r, _ := ring.NewShardedRing(2048, 8) // Larger capacity for 8 producers
 	done := make(chan struct{})
 	consumerDone := make(chan struct{})
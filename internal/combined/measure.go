@@ -0,0 +1,39 @@
+package combined
+
+import (
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+// Variant returns scenario s ready to measure on cpu (unpinned if
+// negative), the way the cmd tools run scenarios: each timed run sets s
+// up afresh and warms it up as warm says first, and its n iterations are
+// calibrated on unwarmed runs when benchtime is set (see
+// harness.Iterations). The first error from any of its runs is stored in
+// *err, after which they return at once.
+//
+// The Variant has no Op: a scenario can't run one op outside RunWarm, so
+// Trace records its latencies instead.
+func Variant(s Scenario, n int, benchtime time.Duration, cpu int, warm harness.Warmup, err *error) harness.Variant {
+	loop := func(n int, warm func(op func())) time.Duration {
+		if *err != nil {
+			return 0
+		}
+		var d time.Duration
+		d, *err = RunWarm(s, n, cpu, warm)
+		return d
+	}
+	n = harness.Iterations(n, benchtime, func(n int) time.Duration { return loop(n, nil) })
+	return harness.Variant{Name: s.Name(), N: n, Timed: func() time.Duration { return loop(n, warm.Run) }}
+}
+
+// Trace writes the latencies of n ops of scenario s to lt, timed in a
+// pass of their own on a fresh Setup on cpu. lt may be nil.
+func Trace(lt *harness.LatencyTrace, s Scenario, n, cpu int) error {
+	if lt == nil {
+		return nil
+	}
+	_, err := RunWarm(s, 0, cpu, func(op func()) { lt.Record(s.Name(), n, op) })
+	return err
+}
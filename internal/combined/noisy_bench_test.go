@@ -0,0 +1,129 @@
+package combined_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// Memory-bandwidth contention (noisy neighbors)
+// ============================================================================
+// Co-located workloads share the last-level cache and memory controllers
+// even when they have cores to themselves. Here N neighbor goroutines
+// stream through buffers much larger than any LLC, touching every cache
+// line, while the loop or pipeline under test runs. The queue slots,
+// ticker state and cancel flag then get evicted and refetched under a
+// saturated memory bus, and each variant's ns/op (and, for pipelines,
+// latency) shows how much that costs it.
+//
+// neighbor-GB/s is the bandwidth the neighbors actually achieved, to
+// confirm the pressure was there.
+
+const (
+	noisyBuffer = 64 << 20 // Bytes streamed per neighbor
+	noisyChunk  = 1 << 20  // Bytes between stop checks
+	noisyLine   = 64       // Touch one byte per cache line
+)
+
+// noisyNeighbors are the neighbor counts run for each variant.
+var noisyNeighbors = []int{0, 1, 2, 4}
+
+// noisyBufs are the neighbors' buffers, kept across b.N rounds and
+// sub-benchmarks and grown to the largest neighbor count run so far.
+var noisyBufs [][]byte
+
+// noisyBuffers returns n buffers of noisyBuffer bytes, allocating any
+// not made yet and writing every page of them, so the neighbors stream
+// at full rate from the first iteration instead of taking page faults.
+func noisyBuffers(n int) [][]byte {
+	for len(noisyBufs) < n {
+		buf := make([]byte, noisyBuffer)
+		for j := 0; j < len(buf); j += noisyLine {
+			buf[j] = 1
+		}
+		noisyBufs = append(noisyBufs, buf)
+	}
+	return noisyBufs[:n]
+}
+
+// startNoisyNeighbors starts n goroutines streaming through their own
+// buffers. stop ends them and returns their combined bandwidth in GB/s;
+// it is also registered with b.Cleanup, so the neighbors end if the
+// benchmark fails or skips before calling it.
+func startNoisyNeighbors(b *testing.B, n int) (stop func() float64) {
+	var (
+		quit  atomic.Bool
+		bytes atomic.Int64
+		wg    sync.WaitGroup
+	)
+	bufs := noisyBuffers(n)
+	start := time.Now()
+	for _, buf := range bufs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for off := 0; !quit.Load(); off = (off + noisyChunk) % noisyBuffer {
+				chunk := buf[off : off+noisyChunk]
+				for j := 0; j < len(chunk); j += noisyLine {
+					chunk[j]++
+				}
+				bytes.Add(noisyChunk)
+			}
+		}()
+	}
+
+	stop = sync.OnceValue(func() float64 {
+		quit.Store(true)
+		wg.Wait()
+		return float64(bytes.Load()) / time.Since(start).Seconds() / 1e9
+	})
+	b.Cleanup(func() { stop() })
+	return stop
+}
+
+// BenchmarkCombined_Noisy runs every registered scenario against each
+// number of noisy neighbors.
+func BenchmarkCombined_Noisy(b *testing.B) {
+	for _, s := range combined.Scenarios() {
+		for _, n := range noisyNeighbors {
+			b.Run(fmt.Sprintf("%s/neighbors=%d", s.Name(), n), func(b *testing.B) {
+				trackGC(b)
+				stop := startNoisyNeighbors(b, n)
+				benchScenario(b, s.Name())
+				b.ReportMetric(stop(), "neighbor-GB/s")
+			})
+		}
+	}
+}
+
+// BenchmarkPipeline_Noisy_Channel runs the saturated channel pipeline
+// against each number of noisy neighbors.
+func BenchmarkPipeline_Noisy_Channel(b *testing.B) {
+	for _, n := range noisyNeighbors {
+		b.Run(fmt.Sprintf("neighbors=%d", n), func(b *testing.B) {
+			trackGC(b)
+			stop := startNoisyNeighbors(b, n)
+			benchLatency(b, queue.Must(queue.NewChannel[int64](1024)), latencyLoads[0], false)
+			b.ReportMetric(stop(), "neighbor-GB/s")
+		})
+	}
+}
+
+// BenchmarkPipeline_Noisy_RingBuffer runs the saturated ring buffer
+// pipeline against each number of noisy neighbors.
+func BenchmarkPipeline_Noisy_RingBuffer(b *testing.B) {
+	for _, n := range noisyNeighbors {
+		b.Run(fmt.Sprintf("neighbors=%d", n), func(b *testing.B) {
+			trackGC(b)
+			stop := startNoisyNeighbors(b, n)
+			benchLatency(b, queue.Must(queue.NewRingBuffer[int64](1024)), latencyLoads[0], false)
+			b.ReportMetric(stop(), "neighbor-GB/s")
+		})
+	}
+}
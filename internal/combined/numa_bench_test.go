@@ -0,0 +1,100 @@
+package combined_test
+
+import (
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/affinity"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// NUMA placement benchmarks
+// ============================================================================
+// Producer and consumer are pinned to two cores of the first NUMA node;
+// the ring's backing array is bound either to that node (Local) or to
+// another node (Remote), so every slot access crosses the interconnect.
+// Skipped on machines (or tasksets) with a single node.
+
+// numaRingSize is large enough that the backing array spans many pages;
+// WithNUMANode only binds whole pages.
+const numaRingSize = 1 << 16
+
+func BenchmarkPipeline_NUMA_RingBuffer(b *testing.B) {
+	cpus, err := affinity.Topology()
+	if err != nil {
+		b.Skip(err)
+	}
+	nodes := affinity.Nodes(cpus)
+	if len(nodes) < 2 {
+		b.Skipf("need 2 NUMA nodes, have %d", len(nodes))
+	}
+	local, remote := nodes[0], nodes[1]
+
+	prodCPU, consCPU, ok := affinity.Pick(affinity.OnNode(cpus, local), affinity.CrossCore)
+	if !ok {
+		b.Skipf("need 2 cores on NUMA node %d", local)
+	}
+
+	for _, tc := range []struct {
+		name string
+		node int
+	}{
+		{"Local", local},
+		{"Remote", remote},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			trackGC(b)
+			q, err := queue.NewRingBuffer[int](numaRingSize, queue.WithNUMANode(tc.node))
+			if err != nil {
+				b.Skip(err)
+			}
+			benchPinned(b, q, prodCPU, consCPU)
+		})
+	}
+}
+
+// BenchmarkPipeline_CrossSocket runs the pinned SPSC pipeline for each
+// queue type with producer and consumer on one node (SameSocket) or on
+// two (CrossSocket), so every handoff's cache lines cross the
+// interconnect. Queue memory is left wherever the allocator puts it;
+// BenchmarkPipeline_NUMA_RingBuffer isolates that effect. Skipped with a
+// single node.
+func BenchmarkPipeline_CrossSocket(b *testing.B) {
+	cpus, err := affinity.Topology()
+	if err != nil {
+		b.Skip(err)
+	}
+	nodes := affinity.Nodes(cpus)
+	if len(nodes) < 2 {
+		b.Skipf("need 2 NUMA nodes, have %d", len(nodes))
+	}
+	near := affinity.OnNode(cpus, nodes[0])
+	far := affinity.OnNode(cpus, nodes[1])
+
+	type placement struct {
+		name             string
+		prodCPU, consCPU int
+	}
+	placements := []placement{{"CrossSocket", near[0].ID, far[0].ID}}
+	if prodCPU, consCPU, ok := affinity.Pick(near, affinity.CrossCore); ok {
+		placements = append([]placement{{"SameSocket", prodCPU, consCPU}}, placements...)
+	}
+
+	queues := []struct {
+		name string
+		new  func() queue.Queue[int]
+	}{
+		{"Channel", func() queue.Queue[int] { return queue.Must(queue.NewChannel[int](1024)) }},
+		{"RingBuffer", func() queue.Queue[int] { return queue.Must(queue.NewRingBuffer[int](1024)) }},
+		{"MPSCRing", func() queue.Queue[int] { return queue.Must(queue.NewMPSCRing[int](1024)) }},
+	}
+
+	for _, qt := range queues {
+		for _, p := range placements {
+			b.Run(qt.name+"/"+p.name, func(b *testing.B) {
+				trackGC(b)
+				benchPinned(b, qt.new(), p.prodCPU, p.consCPU)
+			})
+		}
+	}
+}
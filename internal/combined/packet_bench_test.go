@@ -0,0 +1,252 @@
+package combined_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+// ============================================================================
+// Packet pipeline: generate -> parse/route -> N aggregators
+// ============================================================================
+// Modelled on a telemetry collector. The benchmark goroutine generates
+// fixed-size frames (as indices into a pre-built frame table, the way a
+// NIC ring hands out buffers). A router goroutine parses each frame's
+// IPv4 header and routes it by flow key to one of N aggregator
+// goroutines, which count frames and bytes per flow and flush their
+// counters to the shared totals on every tick.
+//
+// Std is built from channels, close() for shutdown and time.Ticker in the
+// aggregators' select. Optimized uses SPSC RingBuffers for every hop,
+// AtomicCancelers as end-of-input flags and AtomicTicker. ns/op is per
+// frame, end to end, including the final flush. The Optimized pipeline
+// spins in every stage, so give it N+2 CPUs.
+
+const (
+	packetFrames   = 4096 // Frame table size (power of 2)
+	packetFlows    = 256  // Distinct flow keys
+	packetFlushInt = time.Millisecond
+)
+
+var packetWorkers = []int{1, 2, 4}
+
+// packetTable holds pre-built frames with flow keys drawn at random
+// from packetFlows destinations, set by TestMain from -combined.seed.
+var packetTable [][]byte
+
+// newPacketTable builds packetTable from the current seed and
+// workPayload.
+func newPacketTable() [][]byte {
+	rng := combined.NewRand(packetStream)
+	t := make([][]byte, packetFrames)
+	for i := range t {
+		p := make([]byte, payloadSize)
+		copy(p, workPayload)
+		p[19] = byte(rng.IntN(packetFlows)) // Destination address low byte
+		t[i] = p
+	}
+	return t
+}
+
+// parseFrame validates the IPv4 header and returns the frame's flow key
+// and length.
+func parseFrame(p []byte) (key, length int, ok bool) {
+	if len(p) < 20 || p[0]>>4 != 4 || int(p[0]&0x0f)*4 < 20 {
+		return 0, 0, false
+	}
+	length = int(binary.BigEndian.Uint16(p[2:]))
+	if length > len(p) || p[8] == 0 {
+		return 0, 0, false
+	}
+	dst := binary.BigEndian.Uint32(p[16:])
+	return int(dst % packetFlows), length, true
+}
+
+// flowCounters is one aggregator's per-flow state between flushes.
+type flowCounters struct {
+	frames [packetFlows]uint64
+	bytes  [packetFlows]uint64
+}
+
+// packetTotals is the flushed, shared view of all aggregators.
+type packetTotals struct {
+	mu      sync.Mutex
+	frames  [packetFlows]uint64
+	bytes   [packetFlows]uint64
+	flushes atomic.Int64
+}
+
+// flush adds c into t and clears c.
+func (t *packetTotals) flush(c *flowCounters) {
+	t.mu.Lock()
+	for k := range c.frames {
+		t.frames[k] += c.frames[k]
+		t.bytes[k] += c.bytes[k]
+	}
+	t.mu.Unlock()
+	*c = flowCounters{}
+	t.flushes.Add(1)
+}
+
+// check fails b unless exactly n frames were counted.
+func (t *packetTotals) check(b *testing.B, n int) {
+	var sum uint64
+	for _, f := range t.frames {
+		sum += f
+	}
+	if sum != uint64(n) {
+		b.Fatalf("counted %d frames, want %d", sum, n)
+	}
+	b.ReportMetric(float64(t.flushes.Load())/float64(n), "flushes/op")
+}
+
+// BenchmarkPipeline_Packet_Std runs the packet pipeline on channels.
+func BenchmarkPipeline_Packet_Std(b *testing.B) {
+	for _, workers := range packetWorkers {
+		b.Run(fmt.Sprintf("N=%d", workers), func(b *testing.B) {
+			trackGC(b)
+			var totals packetTotals
+			ingress := make(chan int, 1024)
+			out := make([]chan int, workers)
+			for i := range out {
+				out[i] = make(chan int, 1024)
+			}
+
+			var wg sync.WaitGroup
+			wg.Add(1 + workers)
+			go func() {
+				defer wg.Done()
+				for idx := range ingress {
+					if key, _, ok := parseFrame(packetTable[idx]); ok {
+						out[key%workers] <- idx
+					}
+				}
+				for _, ch := range out {
+					close(ch)
+				}
+			}()
+			for w := 0; w < workers; w++ {
+				go func(in chan int) {
+					defer wg.Done()
+					ticker := time.NewTicker(packetFlushInt)
+					defer ticker.Stop()
+					var c flowCounters
+					for {
+						select {
+						case idx, ok := <-in:
+							if !ok {
+								totals.flush(&c)
+								return
+							}
+							key, length, _ := parseFrame(packetTable[idx])
+							c.frames[key]++
+							c.bytes[key] += uint64(length)
+						case <-ticker.C:
+							totals.flush(&c)
+						}
+					}
+				}(out[w])
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				ingress <- i & (packetFrames - 1)
+			}
+			close(ingress)
+			wg.Wait()
+
+			b.StopTimer()
+			totals.check(b, b.N)
+		})
+	}
+}
+
+// BenchmarkPipeline_Packet_Optimized runs the packet pipeline on SPSC
+// ring buffers with atomic flags and tickers.
+func BenchmarkPipeline_Packet_Optimized(b *testing.B) {
+	for _, workers := range packetWorkers {
+		b.Run(fmt.Sprintf("N=%d", workers), func(b *testing.B) {
+			trackGC(b)
+			var totals packetTotals
+			ingress := queue.Must(queue.NewRingBuffer[int](1024))
+			out := make([]*queue.RingBuffer[int], workers)
+			for i := range out {
+				out[i] = queue.Must(queue.NewRingBuffer[int](1024))
+			}
+			inputDone := cancel.NewAtomic()
+			routerDone := cancel.NewAtomic()
+
+			var wg sync.WaitGroup
+			wg.Add(1 + workers)
+			go func() {
+				defer wg.Done()
+				defer routerDone.Cancel()
+				for {
+					// Read the flag before popping so the last frames
+					// pushed before it was set are not missed
+					finished := inputDone.Done()
+					idx, ok := ingress.Pop()
+					if !ok {
+						if finished {
+							return
+						}
+						continue
+					}
+					if key, _, ok := parseFrame(packetTable[idx]); ok {
+						for !out[key%workers].Push(idx) {
+							// Spin until push succeeds
+						}
+					}
+				}
+			}()
+			for w := 0; w < workers; w++ {
+				go func(in *queue.RingBuffer[int]) {
+					defer wg.Done()
+					ticker := tick.NewAtomicTicker(packetFlushInt)
+					var c flowCounters
+					for {
+						if ticker.Tick() {
+							totals.flush(&c)
+						}
+						finished := routerDone.Done()
+						idx, ok := in.Pop()
+						if !ok {
+							if finished {
+								totals.flush(&c)
+								return
+							}
+							continue
+						}
+						key, length, _ := parseFrame(packetTable[idx])
+						c.frames[key]++
+						c.bytes[key] += uint64(length)
+					}
+				}(out[w])
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				for !ingress.Push(i & (packetFrames - 1)) {
+					// Spin until push succeeds
+				}
+			}
+			inputDone.Cancel()
+			wg.Wait()
+
+			b.StopTimer()
+			totals.check(b, b.N)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package combined
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/affinity"
+)
+
+// ErrInvalidPinning is returned by ParsePinning for a malformed spec.
+var ErrInvalidPinning = errors.New("combined: pinning must be producer,consumer,periodic CPUs")
+
+// Pinning assigns a CPU to each goroutine role of an interaction
+// benchmark. A negative CPU leaves that role to the scheduler.
+//
+// Unpinned runs let the scheduler move goroutines between cores, so one
+// run can mix same-core, SMT-sibling and cross-core handoffs and the
+// numbers drift from run to run. Pinning each role holds that placement
+// fixed.
+type Pinning struct {
+	Producer int // Goroutine that feeds the queue (or runs a Scenario)
+	Consumer int // Goroutine that drains it
+	Periodic int // Goroutine that does the ticker-driven work
+}
+
+// Unpinned leaves every role to the scheduler.
+var Unpinned = Pinning{Producer: -1, Consumer: -1, Periodic: -1}
+
+// ParsePinning parses "P,C,T", the producer, consumer and periodic CPUs.
+// A field of "-" or "" leaves that role unpinned, and an empty spec is
+// Unpinned.
+func ParsePinning(spec string) (Pinning, error) {
+	if spec == "" {
+		return Unpinned, nil
+	}
+	fields := strings.Split(spec, ",")
+	if len(fields) != 3 {
+		return Pinning{}, fmt.Errorf("%w: got %q", ErrInvalidPinning, spec)
+	}
+	cpus := make([]int, 3)
+	for i, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" || f == "-" {
+			cpus[i] = -1
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return Pinning{}, fmt.Errorf("%w: got %q", ErrInvalidPinning, spec)
+		}
+		cpus[i] = n
+	}
+	return Pinning{Producer: cpus[0], Consumer: cpus[1], Periodic: cpus[2]}, nil
+}
+
+// Spread picks three logical CPUs on distinct physical cores of one
+// package, one per role, so no two roles share a core's L1/L2 or compete
+// for its time slice. ok is false if cpus has fewer than three cores in
+// any package.
+func Spread(cpus []affinity.CPU) (p Pinning, ok bool) {
+	for i, x := range cpus {
+		picked := []affinity.CPU{x}
+		for _, y := range cpus[i+1:] {
+			if y.Package != x.Package || sameCoreAsAny(y, picked) {
+				continue
+			}
+			picked = append(picked, y)
+			if len(picked) == 3 {
+				return Pinning{Producer: picked[0].ID, Consumer: picked[1].ID, Periodic: picked[2].ID}, true
+			}
+		}
+	}
+	return Unpinned, false
+}
+
+func sameCoreAsAny(c affinity.CPU, cpus []affinity.CPU) bool {
+	for _, x := range cpus {
+		if x.Core == c.Core {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the spec form accepted by ParsePinning.
+func (p Pinning) String() string {
+	field := func(cpu int) string {
+		if cpu < 0 {
+			return "-"
+		}
+		return strconv.Itoa(cpu)
+	}
+	return field(p.Producer) + "," + field(p.Consumer) + "," + field(p.Periodic)
+}
+
+// Check pins a throwaway goroutine to each role's CPU, so a bad CPU
+// number fails before a benchmark starts goroutines that would wait on
+// each other forever.
+func (p Pinning) Check() error {
+	for _, cpu := range []int{p.Producer, p.Consumer, p.Periodic} {
+		if err := <-Go(cpu, func() {}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Go runs fn in a new goroutine pinned to cpu, or unpinned if cpu is
+// negative, and returns a channel that receives nil when fn returns or
+// the pinning error instead of running fn.
+//
+// Pinned goroutines keep their thread locked until they exit (see
+// affinity.PinCurrent), so each role gets a fresh goroutine rather than
+// pinning the caller.
+func Go(cpu int, fn func()) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		if cpu >= 0 {
+			if err := affinity.PinCurrent(cpu); err != nil {
+				done <- err
+				return
+			}
+		}
+		fn()
+		done <- nil
+	}()
+	return done
+}
+
+// RunOn is Run with the iteration loop on a goroutine pinned to cpu.
+// Setup and Teardown run on the caller.
+func RunOn(s Scenario, n, cpu int) (time.Duration, error) {
+	return RunWarm(s, n, cpu, nil)
+}
+
+// RunWarm is RunOn with an untimed warmup: after Setup, and on the same
+// goroutine as the timed loop, it calls warm with a function that runs
+// one op. warm decides how long to run it; nil skips the warmup.
+func RunWarm(s Scenario, n, cpu int, warm func(iter func())) (time.Duration, error) {
+	if err := s.Setup(); err != nil {
+		return 0, fmt.Errorf("combined: setup %s: %w", s.Name(), err)
+	}
+	defer s.Teardown()
+
+	var d time.Duration
+	err := <-Go(cpu, func() {
+		if warm != nil {
+			warm(func() { s.Run(1) })
+		}
+		start := time.Now()
+		s.Run(n)
+		d = time.Since(start)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("combined: run %s: %w", s.Name(), err)
+	}
+	return d, nil
+}
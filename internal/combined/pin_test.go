@@ -0,0 +1,97 @@
+package combined_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/affinity"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+)
+
+func TestParsePinning(t *testing.T) {
+	tests := []struct {
+		spec string
+		want combined.Pinning
+	}{
+		{"", combined.Unpinned},
+		{"0,1,2", combined.Pinning{Producer: 0, Consumer: 1, Periodic: 2}},
+		{"3,-,", combined.Pinning{Producer: 3, Consumer: -1, Periodic: -1}},
+		{" 4, 5 ,6", combined.Pinning{Producer: 4, Consumer: 5, Periodic: 6}},
+	}
+	for _, tt := range tests {
+		got, err := combined.ParsePinning(tt.spec)
+		if err != nil {
+			t.Errorf("ParsePinning(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePinning(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+		if again, _ := combined.ParsePinning(got.String()); again != got {
+			t.Errorf("ParsePinning(%q.String()) = %+v, want %+v", tt.spec, again, got)
+		}
+	}
+}
+
+func TestParsePinning_Invalid(t *testing.T) {
+	for _, spec := range []string{"0", "0,1", "0,1,2,3", "a,1,2", "-1,0,0"} {
+		if _, err := combined.ParsePinning(spec); !errors.Is(err, combined.ErrInvalidPinning) {
+			t.Errorf("ParsePinning(%q) error = %v, want ErrInvalidPinning", spec, err)
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+	// Two packages; package 0 has only two cores (four SMT threads),
+	// package 1 has three
+	cpus := []affinity.CPU{
+		{ID: 0, Core: 0, Package: 0}, {ID: 1, Core: 0, Package: 0},
+		{ID: 2, Core: 1, Package: 0}, {ID: 3, Core: 1, Package: 0},
+		{ID: 4, Core: 0, Package: 1}, {ID: 5, Core: 0, Package: 1},
+		{ID: 6, Core: 1, Package: 1}, {ID: 7, Core: 2, Package: 1},
+	}
+	p, ok := combined.Spread(cpus)
+	want := combined.Pinning{Producer: 4, Consumer: 6, Periodic: 7}
+	if !ok || p != want {
+		t.Errorf("Spread = %+v, %v; want %+v, true", p, ok, want)
+	}
+
+	if _, ok := combined.Spread(cpus[:4]); ok {
+		t.Error("Spread found three cores in a two-core package")
+	}
+}
+
+func TestRunOn_Unpinned(t *testing.T) {
+	s, _ := combined.Lookup("full-loop/optimized")
+	if _, err := combined.RunOn(s, 1000, -1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunOn_Pinned(t *testing.T) {
+	cpus, err := affinity.Topology()
+	if err != nil {
+		t.Skip(err)
+	}
+	s, _ := combined.Lookup("full-loop/optimized")
+	if _, err := combined.RunOn(s, 1000, cpus[0].ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunWarm(t *testing.T) {
+	s, _ := combined.Lookup("cancel-tick/atomic")
+	warmed := 0
+	_, err := combined.RunWarm(s, 1000, -1, func(iter func()) {
+		for i := 0; i < 10; i++ {
+			iter()
+			warmed++
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warmed != 10 {
+		t.Errorf("warm ran %d iterations, want 10", warmed)
+	}
+}
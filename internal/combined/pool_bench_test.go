@@ -0,0 +1,156 @@
+package combined_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// Worker pool with graceful shutdown
+// ============================================================================
+// A fixed pool of W workers takes jobs from one shared queue. The
+// benchmark goroutine submits b.N jobs, waits until all are processed,
+// then shuts the pool down and times how long the workers take to exit.
+//
+// Channel is the usual pool: workers select on ctx.Done() and a jobs
+// channel. Queue replaces the channel with an SPMCRing (one submitter,
+// many workers) and ctx with an AtomicCanceler; idle workers yield
+// rather than spin, so W may exceed GOMAXPROCS.
+//
+// ns/op is per job. items/s counts submission through completion;
+// shutdown-ns is Cancel() to the last worker's exit, per run.
+
+var poolWorkers = []int{1, 2, 4, 8, 16}
+
+// poolCounter is one worker's state, on its own cache line: the
+// completed-job count the submitter polls, and a result sum that only the
+// worker touches until it exits.
+type poolCounter struct {
+	n   atomic.Int64
+	sum int
+
+	_pad [48]byte //nolint:unused
+}
+
+// poolWait spins until the counters add up to n.
+func poolWait(counters []poolCounter, n int) {
+	for {
+		var sum int64
+		for i := range counters {
+			sum += counters[i].n.Load()
+		}
+		if sum >= int64(n) {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+// reportPool reports throughput and shutdown time.
+func reportPool(b *testing.B, elapsed, shutdown time.Duration) {
+	b.ReportMetric(float64(b.N)/elapsed.Seconds(), "items/s")
+	b.ReportMetric(float64(shutdown.Nanoseconds()), "shutdown-ns")
+}
+
+// BenchmarkPipeline_WorkerPool_Channel runs a channel + context pool.
+func BenchmarkPipeline_WorkerPool_Channel(b *testing.B) {
+	for _, workers := range poolWorkers {
+		b.Run(fmt.Sprintf("W=%d", workers), func(b *testing.B) {
+			trackGC(b)
+			ctx, cancelFn := context.WithCancel(context.Background())
+			jobs := make(chan int, 1024)
+			counters := make([]poolCounter, workers)
+
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(c *poolCounter) {
+					defer wg.Done()
+					for {
+						select {
+						case <-ctx.Done():
+							return
+						case j := <-jobs:
+							c.sum += workParseHeader(workPayload, j)
+							c.n.Add(1)
+						}
+					}
+				}(&counters[w])
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				jobs <- i
+			}
+			poolWait(counters, b.N)
+			elapsed := time.Since(start)
+
+			b.StopTimer()
+			stopAt := time.Now()
+			cancelFn()
+			wg.Wait()
+			reportPool(b, elapsed, time.Since(stopAt))
+			sinkInt = counters[0].sum
+		})
+	}
+}
+
+// BenchmarkPipeline_WorkerPool_Queue runs an SPMCRing + AtomicCanceler
+// pool.
+func BenchmarkPipeline_WorkerPool_Queue(b *testing.B) {
+	for _, workers := range poolWorkers {
+		b.Run(fmt.Sprintf("W=%d", workers), func(b *testing.B) {
+			trackGC(b)
+			stop := cancel.NewAtomic()
+			jobs := queue.Must(queue.NewSPMCRing[int](1024))
+			counters := make([]poolCounter, workers)
+
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(c *poolCounter) {
+					defer wg.Done()
+					for !stop.Done() {
+						j, ok := jobs.Pop()
+						if !ok {
+							runtime.Gosched()
+							continue
+						}
+						c.sum += workParseHeader(workPayload, j)
+						c.n.Add(1)
+					}
+				}(&counters[w])
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				for !jobs.Push(i) {
+					runtime.Gosched() // Let the workers catch up
+				}
+			}
+			poolWait(counters, b.N)
+			elapsed := time.Since(start)
+
+			b.StopTimer()
+			stopAt := time.Now()
+			stop.Cancel()
+			wg.Wait()
+			reportPool(b, elapsed, time.Since(stopAt))
+			sinkInt = counters[0].sum
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package combined_test
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/time/rate"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/ratelimit"
+)
+
+// ============================================================================
+// Rate limiting: x/time/rate vs AtomicTicker + atomic token counter
+// ============================================================================
+// Open: the rate is far above what the loop can reach, so every call is
+// allowed and the benchmark measures the limiter's fast path.
+// Limited: 1000/s with the bucket empty, so nearly every Allow is denied,
+// which is the path a shedding hot loop takes under overload.
+// Parallel runs Open from GOMAXPROCS goroutines sharing one limiter.
+//
+// Wait is benchmarked only Open: once limited, ns/op is just 1/rate.
+
+const (
+	rateOpen    = 1_000_000_000
+	rateLimited = 1000
+)
+
+func BenchmarkCombined_RateLimit_XRate_Allow_Open(b *testing.B) {
+	trackGC(b)
+	l := rate.NewLimiter(rateOpen, rateOpen)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var ok bool
+	for i := 0; i < b.N; i++ {
+		ok = l.Allow()
+	}
+	sinkBool = ok
+}
+
+func BenchmarkCombined_RateLimit_Atomic_Allow_Open(b *testing.B) {
+	trackGC(b)
+	l, err := ratelimit.New(rateOpen, rateOpen)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var ok bool
+	for i := 0; i < b.N; i++ {
+		ok = l.Allow()
+	}
+	sinkBool = ok
+}
+
+func BenchmarkCombined_RateLimit_XRate_Allow_Limited(b *testing.B) {
+	trackGC(b)
+	l := rate.NewLimiter(rateLimited, 1)
+	l.Allow()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var ok bool
+	for i := 0; i < b.N; i++ {
+		ok = l.Allow()
+	}
+	sinkBool = ok
+}
+
+func BenchmarkCombined_RateLimit_Atomic_Allow_Limited(b *testing.B) {
+	trackGC(b)
+	l, err := ratelimit.New(rateLimited, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	l.Allow()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var ok bool
+	for i := 0; i < b.N; i++ {
+		ok = l.Allow()
+	}
+	sinkBool = ok
+}
+
+func BenchmarkCombined_RateLimit_XRate_Allow_Parallel(b *testing.B) {
+	trackGC(b)
+	l := rate.NewLimiter(rateOpen, rateOpen)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Allow()
+		}
+	})
+}
+
+func BenchmarkCombined_RateLimit_Atomic_Allow_Parallel(b *testing.B) {
+	trackGC(b)
+	l, err := ratelimit.New(rateOpen, rateOpen)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Allow()
+		}
+	})
+}
+
+func BenchmarkCombined_RateLimit_XRate_Wait_Open(b *testing.B) {
+	trackGC(b)
+	l := rate.NewLimiter(rateOpen, rateOpen)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var err error
+	for i := 0; i < b.N; i++ {
+		err = l.Wait(ctx)
+	}
+	sinkBool = err == nil
+}
+
+func BenchmarkCombined_RateLimit_Atomic_Wait_Open(b *testing.B) {
+	trackGC(b)
+	l, err := ratelimit.New(rateOpen, rateOpen)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		err = l.Wait(ctx)
+	}
+	sinkBool = err == nil
+}
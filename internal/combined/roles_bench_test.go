@@ -0,0 +1,182 @@
+package combined_test
+
+import (
+	"context"
+	"flag"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/affinity"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+// ============================================================================
+// Producer / consumer / periodic pipeline with pinned roles
+// ============================================================================
+// Three goroutines: a producer feeding the queue, a consumer counting what
+// it pops, and a periodic goroutine that snapshots the consumer's counter
+// every rolesInterval (a stats exporter, say). The periodic reads pull the
+// counter's cache line away from the consumer, so where the three run
+// relative to each other shows up in ns/op.
+//
+// Each variant runs unpinned, spread over three physical cores, and, if
+// -combined.pin=P,C,T is given, on exactly those CPUs:
+//
+//	go test -bench=Roles ./internal/combined -combined.pin=2,4,6
+
+var pinFlag = flag.String("combined.pin", "", "producer,consumer,periodic CPUs for the Roles benchmarks")
+
+// rolesInterval is how often the periodic goroutine runs.
+const rolesInterval = 100 * time.Microsecond
+
+// rolePinning is one placement of the three roles.
+type rolePinning struct {
+	name string
+	pin  combined.Pinning
+}
+
+// rolesPinnings returns the placements to run, named for sub-benchmarks.
+func rolesPinnings(b *testing.B) []rolePinning {
+	pins := []rolePinning{{"unpinned", combined.Unpinned}}
+
+	if cpus, err := affinity.Topology(); err == nil {
+		if p, ok := combined.Spread(cpus); ok {
+			pins = append(pins, rolePinning{"spread", p})
+		}
+	}
+	if *pinFlag != "" {
+		p, err := combined.ParsePinning(*pinFlag)
+		if err != nil {
+			b.Fatal(err)
+		}
+		pins = append(pins, rolePinning{"pin=" + p.String(), p})
+	}
+	return pins
+}
+
+// runRoles starts producer, consumer and periodic on their CPUs, waits
+// for producer and consumer, then stops periodic with stop. It reports
+// how often the periodic work ran.
+func runRoles(b *testing.B, p combined.Pinning, producer, consumer, periodic func() int, stop func()) {
+	if err := p.Check(); err != nil {
+		b.Skip(err)
+	}
+
+	var ticks int
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	perDone := combined.Go(p.Periodic, func() { ticks = periodic() })
+	consDone := combined.Go(p.Consumer, func() { sinkInt = consumer() })
+	prodDone := combined.Go(p.Producer, func() { producer() })
+	errs := []error{<-prodDone, <-consDone}
+
+	b.StopTimer()
+	stop()
+	errs = append(errs, <-perDone)
+	for _, err := range errs {
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(ticks)/b.Elapsed().Seconds(), "ticks/s")
+}
+
+// BenchmarkPipeline_Roles_Std uses a channel, ctx.Done() and a
+// time.Ticker in the periodic goroutine's select.
+func BenchmarkPipeline_Roles_Std(b *testing.B) {
+	for _, pin := range rolesPinnings(b) {
+		b.Run(pin.name, func(b *testing.B) {
+			trackGC(b)
+			ch := make(chan int, 1024)
+			ctx, cancelFn := context.WithCancel(context.Background())
+			defer cancelFn()
+			var processed, seen atomic.Int64
+
+			runRoles(b, pin.pin,
+				func() int {
+					for i := 0; i < b.N; i++ {
+						ch <- i
+					}
+					return b.N
+				},
+				func() int {
+					sum := 0
+					for n := 0; n < b.N; n++ {
+						sum += <-ch
+						processed.Add(1)
+					}
+					return sum
+				},
+				func() int {
+					t := time.NewTicker(rolesInterval)
+					defer t.Stop()
+					ticks := 0
+					for {
+						select {
+						case <-ctx.Done():
+							return ticks
+						case <-t.C:
+							seen.Add(processed.Load())
+							ticks++
+						}
+					}
+				},
+				cancelFn)
+			sinkInt += int(seen.Load())
+		})
+	}
+}
+
+// BenchmarkPipeline_Roles_Optimized uses the SPSC ring, an AtomicCanceler
+// and an AtomicTicker polled by the periodic goroutine.
+func BenchmarkPipeline_Roles_Optimized(b *testing.B) {
+	for _, pin := range rolesPinnings(b) {
+		b.Run(pin.name, func(b *testing.B) {
+			trackGC(b)
+			q := queue.Must(queue.NewRingBuffer[int](1024))
+			c := cancel.NewAtomic()
+			var processed, seen atomic.Int64
+
+			runRoles(b, pin.pin,
+				func() int {
+					for i := 0; i < b.N; i++ {
+						for !q.Push(i) {
+							// Spin until push succeeds
+						}
+					}
+					return b.N
+				},
+				func() int {
+					sum := 0
+					for n := 0; n < b.N; {
+						v, ok := q.Pop()
+						if !ok {
+							continue
+						}
+						sum += v
+						processed.Add(1)
+						n++
+					}
+					return sum
+				},
+				func() int {
+					t := tick.NewAtomicTicker(rolesInterval)
+					ticks := 0
+					for !c.Done() {
+						if t.Tick() {
+							seen.Add(processed.Load())
+							ticks++
+						}
+					}
+					return ticks
+				},
+				c.Cancel)
+			sinkInt += int(seen.Load())
+		})
+	}
+}
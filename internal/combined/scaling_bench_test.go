@@ -0,0 +1,84 @@
+package combined_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// Producer scaling: 1..16 producers over one shared queue
+// ============================================================================
+// Unlike the RunParallel-based MPSC benchmarks, which start
+// p*GOMAXPROCS producers, these start exactly P producer goroutines and
+// split b.N items between them, so P=1 is the SPSC case and the sweep
+// shows where the channel's lock starts to dominate. ns/op is per item
+// delivered to the consumer.
+//
+// benchstat prints the sweep as a table:
+//
+//	go test -bench 'MPSC_Scaling' -count 10 ./internal/combined > s.txt
+//	benchstat -col /P s.txt
+//
+// or run `go run ./cmd/bench mpsc -queues channel,mpscring` for a quick one.
+
+var scalingProducers = []int{1, 2, 4, 8, 16}
+
+// benchScaling starts producers goroutines that together push b.N items,
+// and pops all of them on the benchmark goroutine.
+func benchScaling(b *testing.B, producers int, push func(int) bool, pop func() (int, bool)) {
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		n := b.N / producers
+		if p < b.N%producers {
+			n++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				for !push(i) {
+					// Spin until push succeeds
+				}
+			}
+		}()
+	}
+
+	for received := 0; received < b.N; {
+		if _, ok := pop(); ok {
+			received++
+		}
+	}
+
+	b.StopTimer()
+	wg.Wait()
+}
+
+// BenchmarkMPSC_Scaling_Channel sweeps producer count over a buffered
+// channel.
+func BenchmarkMPSC_Scaling_Channel(b *testing.B) {
+	for _, p := range scalingProducers {
+		b.Run(fmt.Sprintf("P=%d", p), func(b *testing.B) {
+			trackGC(b)
+			q := queue.Must(queue.NewChannel[int](1024))
+			benchScaling(b, p, q.Push, q.Pop)
+		})
+	}
+}
+
+// BenchmarkMPSC_Scaling_MPSCRing sweeps producer count over the CAS-based
+// MPSC ring.
+func BenchmarkMPSC_Scaling_MPSCRing(b *testing.B) {
+	for _, p := range scalingProducers {
+		b.Run(fmt.Sprintf("P=%d", p), func(b *testing.B) {
+			trackGC(b)
+			q := queue.Must(queue.NewMPSCRing[int](1024))
+			benchScaling(b, p, q.Push, q.Pop)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package combined
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Scenario is one interaction benchmark that both `go test -bench` and the
+// cmd tools can run by name.
+//
+// Setup builds the components, Run performs n ops of the hot loop, and
+// Teardown releases whatever Setup allocated. Run holds the loop itself,
+// so a timed run pays for one call rather than one per op. A Scenario is
+// run by one goroutine at a time; Setup is called again before every run.
+type Scenario interface {
+	Name() string
+	Setup() error
+	Run(n int)
+	Teardown()
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]Scenario{}
+)
+
+// Register adds s to the registry. It panics if the name is empty or
+// already taken, since both are programming errors in an init function.
+func Register(s Scenario) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := s.Name()
+	if name == "" {
+		panic("combined: Register with empty scenario name")
+	}
+	if _, dup := registry[name]; dup {
+		panic("combined: Register called twice for scenario " + name)
+	}
+	registry[name] = s
+}
+
+// Lookup returns the registered scenario with the given name.
+func Lookup(name string) (Scenario, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	s, ok := registry[name]
+	return s, ok
+}
+
+// Scenarios returns every registered scenario, sorted by name.
+func Scenarios() []Scenario {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	out := make([]Scenario, 0, len(registry))
+	for _, s := range registry {
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(a, b Scenario) int {
+		return cmp.Compare(a.Name(), b.Name())
+	})
+	return out
+}
+
+// Run sets s up, times n iterations, and tears it down. Setup and
+// Teardown are not included in the returned duration.
+func Run(s Scenario, n int) (time.Duration, error) {
+	if err := s.Setup(); err != nil {
+		return 0, fmt.Errorf("combined: setup %s: %w", s.Name(), err)
+	}
+	defer s.Teardown()
+
+	start := time.Now()
+	s.Run(n)
+	return time.Since(start), nil
+}
+
+// funcScenario is a Scenario assembled by Define.
+type funcScenario struct {
+	name     string
+	setup    func() (run func(n int), teardown func(), err error)
+	run      func(n int)
+	teardown func()
+}
+
+// Define builds a Scenario from a setup function that returns the loop,
+// which runs n ops, and its teardown (which may be nil). This keeps each
+// scenario's state in one closure instead of a struct per scenario. The
+// loop keeps its results in locals and stores them to a sink once, after
+// the loop, so it makes no call or store the op doesn't make itself:
+//
+//	combined.Register(combined.Define("cancel/atomic", func() (func(int), func(), error) {
+//		c := cancel.NewAtomic()
+//		return func(n int) {
+//			var done bool
+//			for range n {
+//				done = c.Done()
+//			}
+//			sinkBool = done
+//		}, nil, nil
+//	}))
+func Define(name string, setup func() (run func(n int), teardown func(), err error)) Scenario {
+	return &funcScenario{name: name, setup: setup}
+}
+
+func (f *funcScenario) Name() string { return f.name }
+
+func (f *funcScenario) Setup() error {
+	run, teardown, err := f.setup()
+	if err != nil {
+		return err
+	}
+	f.run, f.teardown = run, teardown
+	return nil
+}
+
+func (f *funcScenario) Run(n int) { f.run(n) }
+
+func (f *funcScenario) Teardown() {
+	if f.teardown != nil {
+		f.teardown()
+	}
+	f.run, f.teardown = nil, nil
+}
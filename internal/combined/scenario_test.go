@@ -0,0 +1,102 @@
+package combined_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+)
+
+func TestScenarios_BuiltinsRegistered(t *testing.T) {
+	for _, name := range []string{
+		"cancel/std", "cancel/atomic",
+		"tick/std", "tick/batch", "tick/atomic",
+		"queue/std", "queue/ring", "queue/unsync",
+		"cancel-tick/std", "cancel-tick/atomic", "cancel-tick/batch",
+		"full-loop/std", "full-loop/optimized",
+		"full-loop-observed/std", "full-loop-observed/optimized",
+	} {
+		if _, ok := combined.Lookup(name); !ok {
+			t.Errorf("scenario %q not registered", name)
+		}
+	}
+	if _, ok := combined.Lookup("no-such-scenario"); ok {
+		t.Error("Lookup of unknown name returned ok")
+	}
+}
+
+func TestScenarios_Described(t *testing.T) {
+	for _, s := range combined.Scenarios() {
+		if combined.Description(s.Name()) == "" {
+			t.Errorf("scenario %q has no Description", s.Name())
+		}
+		if group := combined.Group(s.Name()); combined.Description(group) == "" {
+			t.Errorf("group %q has no Description", group)
+		}
+	}
+}
+
+func TestScenarios_Sorted(t *testing.T) {
+	var names []string
+	for _, s := range combined.Scenarios() {
+		names = append(names, s.Name())
+	}
+	if !slices.IsSorted(names) {
+		t.Errorf("Scenarios() not sorted by name: %v", names)
+	}
+}
+
+func TestRun_AllScenarios(t *testing.T) {
+	for _, s := range combined.Scenarios() {
+		t.Run(s.Name(), func(t *testing.T) {
+			// Run twice: Setup must rebuild state after Teardown
+			for i := 0; i < 2; i++ {
+				if _, err := combined.Run(s, 1000); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_SetupErrorAndTeardown(t *testing.T) {
+	errBoom := errors.New("boom")
+	failing := combined.Define("test/failing", func() (func(int), func(), error) {
+		return nil, nil, errBoom
+	})
+	if _, err := combined.Run(failing, 1); !errors.Is(err, errBoom) {
+		t.Errorf("Run() error = %v, want wrapping %v", err, errBoom)
+	}
+
+	iters, teardowns := 0, 0
+	counting := combined.Define("test/counting", func() (func(int), func(), error) {
+		return func(n int) { iters += n }, func() { teardowns++ }, nil
+	})
+	if _, err := combined.Run(counting, 5); err != nil {
+		t.Fatal(err)
+	}
+	if iters != 5 || teardowns != 1 {
+		t.Errorf("iterations = %d, teardowns = %d, want 5 and 1", iters, teardowns)
+	}
+}
+
+func TestRegister_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		s    combined.Scenario
+	}{
+		{"empty name", combined.Define("", nil)},
+		{"duplicate", combined.Define("full-loop/std", nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected Register to panic")
+				}
+			}()
+			combined.Register(tt.s)
+		})
+	}
+}
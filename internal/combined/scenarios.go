@@ -0,0 +1,312 @@
+package combined
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+// Sink variables keep scenario results alive so the loops are not
+// optimized away.
+var (
+	sinkInt  int
+	sinkBool bool
+)
+
+// Parameter defaults, overridden per run by a sweep (see Sweep). The
+// check interval is long enough that tickers never fire, so scenarios
+// measure the cost of checking rather than of periodic work. The observed
+// loops' interval does fire, so the log line's cost is amortized over the
+// items between ticks the way it would be in a service. The queue size
+// matches cmd/channel's default.
+var (
+	tickParams     = Params{{"interval", "1h"}}
+	batchParams    = Params{{"interval", "1h"}, {"batch", "1000"}}
+	queueParams    = Params{{"size", "1024"}}
+	loopParams     = Params{{"size", "1024"}, {"interval", "1h"}}
+	observedParams = Params{{"size", "1024"}, {"interval", "1ms"}}
+)
+
+func init() {
+	// Single components, as cmd/context, cmd/ticker and cmd/channel time
+	// them, so bench all can run the whole comparison from the registry.
+	// Each group's std variant is the standard-library baseline.
+	Register(Define("cancel/std", func() (func(int), func(), error) {
+		c := cancel.NewContext(context.Background())
+		return func(n int) {
+			var done bool
+			for range n {
+				done = c.Done()
+			}
+			sinkBool = done
+		}, nil, nil
+	}))
+	Register(Define("cancel/atomic", func() (func(int), func(), error) {
+		c := cancel.NewAtomic()
+		return func(n int) {
+			var done bool
+			for range n {
+				done = c.Done()
+			}
+			sinkBool = done
+		}, nil, nil
+	}))
+
+	Register(DefineParams("tick/std", tickParams, func(p Params) (func(int), func(), error) {
+		t, err := newTicker(p, tick.NewTicker)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tickLoop(t), t.Stop, nil
+	}))
+	Register(DefineParams("tick/batch", batchParams, func(p Params) (func(int), func(), error) {
+		t, err := newBatch(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tickLoop(t), t.Stop, nil
+	}))
+	Register(DefineParams("tick/atomic", tickParams, func(p Params) (func(int), func(), error) {
+		t, err := newTicker(p, tick.NewAtomicTicker)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tickLoop(t), t.Stop, nil
+	}))
+
+	Register(DefineParams("queue/std", queueParams, func(p Params) (func(int), func(), error) {
+		q, err := newQueue(p, queue.NewChannel[int])
+		if err != nil {
+			return nil, nil, err
+		}
+		return pushPop(q), nil, nil
+	}))
+	Register(DefineParams("queue/ring", queueParams, func(p Params) (func(int), func(), error) {
+		q, err := newQueue(p, newRing)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pushPop(q), nil, nil
+	}))
+	Register(DefineParams("queue/unsync", queueParams, func(p Params) (func(int), func(), error) {
+		q, err := newQueue(p, queue.NewUnsyncRing[int])
+		if err != nil {
+			return nil, nil, err
+		}
+		return pushPop(q), nil, nil
+	}))
+
+	Register(DefineParams("cancel-tick/std", tickParams, func(p Params) (func(int), func(), error) {
+		c := cancel.NewContext(context.Background())
+		t, err := newTicker(p, tick.NewTicker)
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(n int) {
+			var stop bool
+			for range n {
+				stop = c.Done() || t.Tick()
+			}
+			sinkBool = stop
+		}, t.Stop, nil
+	}))
+	Register(DefineParams("cancel-tick/atomic", tickParams, func(p Params) (func(int), func(), error) {
+		c := cancel.NewAtomic()
+		t, err := newTicker(p, tick.NewAtomicTicker)
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(n int) {
+			var stop bool
+			for range n {
+				stop = c.Done() || t.Tick()
+			}
+			sinkBool = stop
+		}, nil, nil
+	}))
+	Register(DefineParams("cancel-tick/batch", batchParams, func(p Params) (func(int), func(), error) {
+		c := cancel.NewAtomic()
+		t, err := newBatch(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(n int) {
+			var stop bool
+			for range n {
+				stop = c.Done() || t.Tick()
+			}
+			sinkBool = stop
+		}, nil, nil
+	}))
+
+	Register(DefineParams("full-loop/std", loopParams, func(p Params) (func(int), func(), error) {
+		q, err := newQueue(p, queue.NewChannel[int])
+		if err != nil {
+			return nil, nil, err
+		}
+		t, err := newTicker(p, tick.NewTicker)
+		if err != nil {
+			return nil, nil, err
+		}
+		return fullLoop(cancel.NewContext(context.Background()), t, q)
+	}))
+	Register(DefineParams("full-loop/optimized", loopParams, func(p Params) (func(int), func(), error) {
+		q, err := newQueue(p, newRing)
+		if err != nil {
+			return nil, nil, err
+		}
+		t, err := newTicker(p, tick.NewAtomicTicker)
+		if err != nil {
+			return nil, nil, err
+		}
+		return fullLoop(cancel.NewAtomic(), t, q)
+	}))
+
+	Register(DefineParams("full-loop-observed/std", observedParams, func(p Params) (func(int), func(), error) {
+		q, err := newQueue(p, queue.NewChannel[int])
+		if err != nil {
+			return nil, nil, err
+		}
+		t, err := newTicker(p, tick.NewTicker)
+		if err != nil {
+			return nil, nil, err
+		}
+		return observedLoop(cancel.NewContext(context.Background()), t, q)
+	}))
+	Register(DefineParams("full-loop-observed/optimized", observedParams, func(p Params) (func(int), func(), error) {
+		q, err := newQueue(p, newRing)
+		if err != nil {
+			return nil, nil, err
+		}
+		t, err := newTicker(p, tick.NewAtomicTicker)
+		if err != nil {
+			return nil, nil, err
+		}
+		return observedLoop(cancel.NewAtomic(), t, q)
+	}))
+}
+
+// newTicker builds a ticker with p's interval.
+func newTicker[T tick.Ticker](p Params, newT func(time.Duration) T) (tick.Ticker, error) {
+	interval, err := p.Duration("interval")
+	if err != nil {
+		return nil, err
+	}
+	return newT(interval), nil
+}
+
+// newBatch builds a BatchTicker with p's interval and batch.
+func newBatch(p Params) (tick.Ticker, error) {
+	interval, err := p.Duration("interval")
+	if err != nil {
+		return nil, err
+	}
+	every, err := p.Int("batch")
+	if err != nil {
+		return nil, err
+	}
+	return tick.NewBatch(interval, every), nil
+}
+
+// newQueue builds a queue of p's size.
+func newQueue[Q queue.Queue[int]](p Params, newQ func(size int) (Q, error)) (queue.Queue[int], error) {
+	size, err := p.Int("size")
+	if err != nil {
+		return nil, err
+	}
+	return newQ(size)
+}
+
+// newRing is queue.NewRingBuffer without options.
+func newRing(size int) (*queue.RingBuffer[int], error) {
+	return queue.NewRingBuffer[int](size)
+}
+
+// tickLoop returns a loop of t.Tick checks.
+func tickLoop(t tick.Ticker) func(n int) {
+	return func(n int) {
+		var ticked bool
+		for range n {
+			ticked = t.Tick()
+		}
+		sinkBool = ticked
+	}
+}
+
+// pushPop returns a loop that pushes one item onto the empty q and pops
+// it straight back, on one goroutine.
+func pushPop(q queue.Queue[int]) func(n int) {
+	return func(n int) {
+		var val int
+		var ok bool
+		for i := range n {
+			q.Push(i)
+			val, ok = q.Pop()
+		}
+		sinkInt, sinkBool = val, ok
+	}
+}
+
+// fullLoop pre-fills q and returns the cancel + tick + recycle loop.
+func fullLoop(c cancel.Canceler, t tick.Ticker, q queue.Queue[int]) (func(int), func(), error) {
+	for i := 0; q.Push(i); i++ {
+	}
+	return func(n int) {
+		var val int
+		var busy bool
+		for range n {
+			cancelled := c.Done()
+			ticked := t.Tick()
+			var ok bool
+			val, ok = q.Pop()
+			q.Push(val) // Recycle
+			busy = ok || cancelled || ticked
+		}
+		sinkInt, sinkBool = val, busy
+	}, t.Stop, nil
+}
+
+// loopMetrics are Prometheus-style counters: monotonic, updated with one
+// atomic add each, and read by whatever exports them.
+type loopMetrics struct {
+	items atomic.Uint64
+	bytes atomic.Uint64
+	ticks atomic.Uint64
+}
+
+// observedLoop is fullLoop plus typical in-loop observability: two
+// counter increments per item and a structured (JSON) log line per tick.
+// The log goes to io.Discard, so this measures formatting, not I/O.
+func observedLoop(c cancel.Canceler, t tick.Ticker, q queue.Queue[int]) (func(int), func(), error) {
+	for i := 0; q.Push(i); i++ {
+	}
+	var m loopMetrics
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	return func(n int) {
+		var val int
+		var busy bool
+		for range n {
+			cancelled := c.Done()
+			ticked := t.Tick()
+			var ok bool
+			val, ok = q.Pop()
+			q.Push(val) // Recycle
+			m.items.Add(1)
+			m.bytes.Add(8)
+			if ticked {
+				log.Info("tick",
+					"ticks", m.ticks.Add(1),
+					"items", m.items.Load(),
+					"bytes", m.bytes.Load())
+			}
+			busy = ok || cancelled
+		}
+		sinkInt, sinkBool = val, busy
+	}, t.Stop, nil
+}
@@ -0,0 +1,24 @@
+//go:build amd64
+
+package combined
+
+import (
+	"sync"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+// tscCyclesPerNs calibrates the TSC once, rather than for ~10ms in every
+// Setup.
+var tscCyclesPerNs = sync.OnceValue(tick.CalibrateTSC)
+
+func init() {
+	Register(DefineParams("tick/tsc", tickParams, func(p Params) (func(int), func(), error) {
+		interval, err := p.Duration("interval")
+		if err != nil {
+			return nil, nil, err
+		}
+		t := tick.NewTSC(interval, tscCyclesPerNs())
+		return tickLoop(t), t.Stop, nil
+	}))
+}
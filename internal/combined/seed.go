@@ -0,0 +1,46 @@
+package combined
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+// ErrInvalidSeed is returned by SetSeed for seed 0, which results use to
+// mean "no seed".
+var ErrInvalidSeed = errors.New("combined: seed must be at least 1")
+
+// DefaultSeed is the seed synthetic data is generated from unless SetSeed
+// says otherwise.
+const DefaultSeed uint64 = 1
+
+// SeedUsage is the help text for a -seed flag.
+const SeedUsage = "seed for the synthetic data scenarios generate (payloads, flow keys, burst lengths); the same seed gives the same data every run"
+
+// seed is the current seed, less one, so the zero value is DefaultSeed.
+var seed atomic.Uint64
+
+// SetSeed sets the seed NewRand uses from now on. Set it before any
+// scenario's Setup, so every run processes the same data.
+func SetSeed(s uint64) error {
+	if s == 0 {
+		return ErrInvalidSeed
+	}
+	seed.Store(s - 1)
+	return nil
+}
+
+// Seed returns the seed NewRand uses.
+func Seed() uint64 {
+	return seed.Load() + 1
+}
+
+// NewRand returns a generator for one stream of synthetic data. The same
+// Seed and stream give the same numbers on every run and every platform,
+// and distinct streams are independent, so a workload that draws more
+// numbers doesn't change the data of another. Scenarios should make
+// their data with it in Setup, never from the clock or a global source,
+// so that runs with the same seed can be compared item for item.
+func NewRand(stream uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(Seed(), stream))
+}
@@ -0,0 +1,71 @@
+package combined_test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+)
+
+// The synthetic data in these benchmarks is generated from one seed, so
+// two runs process identical payloads, flow keys and bursts:
+//
+//	go test -bench=Packet ./internal/combined -combined.seed=42
+var seedFlag = flag.Uint64("combined.seed", combined.DefaultSeed, combined.SeedUsage)
+
+// Streams of combined.NewRand, one per kind of synthetic data.
+const (
+	payloadStream = iota + 1
+	packetStream
+	burstStream
+	shutdownStream
+)
+
+// TestMain sets the seed before any data is generated and, when running
+// benchmarks, prints it as a configuration line that benchstat keeps with
+// the results, as it does goos and cpu.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if err := combined.SetSeed(*seedFlag); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	workPayload = newWorkPayload()
+	packetTable = newPacketTable()
+	if f := flag.Lookup("test.bench"); f != nil && f.Value.String() != "" {
+		fmt.Printf("seed: %d\n", combined.Seed())
+	}
+	os.Exit(m.Run())
+}
+
+func TestNewRand(t *testing.T) {
+	defer combined.SetSeed(combined.Seed())
+
+	draw := func(stream uint64) [4]uint64 {
+		r := combined.NewRand(stream)
+		return [4]uint64{r.Uint64(), r.Uint64(), r.Uint64(), r.Uint64()}
+	}
+	if err := combined.SetSeed(42); err != nil {
+		t.Fatal(err)
+	}
+	a := draw(1)
+	if b := draw(1); a != b {
+		t.Errorf("seed 42 stream 1 drew %v, then %v", a, b)
+	}
+	if b := draw(2); a == b {
+		t.Error("streams 1 and 2 drew the same numbers")
+	}
+	_ = combined.SetSeed(43)
+	if b := draw(1); a == b {
+		t.Error("seeds 42 and 43 drew the same numbers")
+	}
+	if err := combined.SetSeed(0); !errors.Is(err, combined.ErrInvalidSeed) {
+		t.Errorf("SetSeed(0) error = %v, want ErrInvalidSeed", err)
+	}
+	if s := combined.Seed(); s != 43 {
+		t.Errorf("Seed() after SetSeed(0) = %d, want 43 kept", s)
+	}
+}
@@ -0,0 +1,166 @@
+package combined_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+// ============================================================================
+// Canonical select loop vs optimized poll loop
+// ============================================================================
+// The loop most Go code actually contains:
+//
+//	select {
+//	case <-ctx.Done():
+//	    return
+//	case <-ticker.C:
+//	    flush()
+//	case v := <-ch:
+//	    process(v)
+//	}
+//
+// against the poll loop this repo recommends in its place: AtomicCanceler,
+// AtomicTicker and RingBuffer, checked in turn. The ticker interval is
+// long enough that it never fires, so both measure loop overhead per item.
+//
+// Combined_SelectLoop keeps one goroutine busy by recycling a pre-filled
+// queue, as the FullLoop benchmarks do; Pipeline_SelectLoop feeds the loop
+// from a separate producer goroutine, where the select version can park.
+
+// BenchmarkCombined_SelectLoop_Stdlib runs the three-case select with a
+// recycled item in the channel every time round.
+func BenchmarkCombined_SelectLoop_Stdlib(b *testing.B) {
+	trackGC(b)
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	ticker := time.NewTicker(benchInterval)
+	defer ticker.Stop()
+	ch := make(chan int, 1024)
+	for i := 0; i < 1024; i++ {
+		ch <- i
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var sum, ticks int
+	for i := 0; i < b.N; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ticks++
+		case v := <-ch:
+			sum += v
+			ch <- v // Recycle
+		}
+	}
+	sinkInt = sum + ticks
+}
+
+// BenchmarkCombined_SelectLoop_Optimized runs the equivalent poll loop.
+func BenchmarkCombined_SelectLoop_Optimized(b *testing.B) {
+	trackGC(b)
+	stop := cancel.NewAtomic()
+	ticker := tick.NewAtomicTicker(benchInterval)
+	q := queue.Must(queue.NewRingBuffer[int](1024))
+	for i := 0; i < 1024; i++ {
+		q.Push(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var sum, ticks int
+	for i := 0; i < b.N; i++ {
+		if stop.Done() {
+			return
+		}
+		if ticker.Tick() {
+			ticks++
+		}
+		if v, ok := q.Pop(); ok {
+			sum += v
+			q.Push(v) // Recycle
+		}
+	}
+	sinkInt = sum + ticks
+}
+
+// BenchmarkPipeline_SelectLoop_Stdlib consumes b.N items from a producer
+// goroutine with the three-case select.
+func BenchmarkPipeline_SelectLoop_Stdlib(b *testing.B) {
+	trackGC(b)
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	ticker := time.NewTicker(benchInterval)
+	defer ticker.Stop()
+	ch := make(chan int, 1024)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	go func() {
+		for i := 0; i < b.N; i++ {
+			ch <- i
+		}
+	}()
+
+	var sum, ticks int
+	for n := 0; n < b.N; {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ticks++
+		case v := <-ch:
+			sum += v
+			n++
+		}
+	}
+
+	b.StopTimer()
+	sinkInt = sum + ticks
+}
+
+// BenchmarkPipeline_SelectLoop_Optimized consumes b.N items from a
+// producer goroutine with the poll loop.
+func BenchmarkPipeline_SelectLoop_Optimized(b *testing.B) {
+	trackGC(b)
+	stop := cancel.NewAtomic()
+	ticker := tick.NewAtomicTicker(benchInterval)
+	q := queue.Must(queue.NewRingBuffer[int](1024))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	go func() {
+		for i := 0; i < b.N; i++ {
+			for !q.Push(i) {
+				// Spin until push succeeds
+			}
+		}
+	}()
+
+	var sum, ticks int
+	for n := 0; n < b.N; {
+		if stop.Done() {
+			return
+		}
+		if ticker.Tick() {
+			ticks++
+		}
+		if v, ok := q.Pop(); ok {
+			sum += v
+			n++
+		}
+	}
+
+	b.StopTimer()
+	sinkInt = sum + ticks
+}
@@ -0,0 +1,164 @@
+package combined_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/histogram"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// Shutdown latency: time from Cancel() until every goroutine has exited
+// ============================================================================
+// Each op starts a producer and a consumer, lets them run for a random
+// time, cancels, and waits for both to return. The time from Cancel() to
+// the last exit is recorded; that is what a graceful shutdown waits on.
+//
+// ContextSelect is the usual blocking loop: every send and receive sits
+// in a select with <-ctx.Done(), so cancellation wakes it immediately.
+// AtomicPoll spins on the ring buffer and checks an AtomicCanceler only
+// once every N loop iterations: larger N is cheaper per item but slower
+// to notice the cancel.
+//
+// ns/op is a whole start/run/cancel/exit cycle and is not the interesting
+// column; read shutdown-p50-ns, shutdown-p99-ns and shutdown-max-ns.
+
+// shutdownMaxRun bounds the random run time before Cancel(), drawn from
+// combined.NewRand(shutdownStream) so every run waits the same times.
+const shutdownMaxRun = 100 * time.Microsecond
+
+var shutdownPollEvery = []int{1, 64, 1024}
+
+// reportShutdown reports the shutdown latency distribution.
+func reportShutdown(b *testing.B, h *histogram.Histogram) {
+	b.ReportMetric(float64(h.Quantile(0.50)), "shutdown-p50-ns")
+	b.ReportMetric(float64(h.Quantile(0.99)), "shutdown-p99-ns")
+	b.ReportMetric(float64(h.Max()), "shutdown-max-ns")
+}
+
+// BenchmarkPipeline_Shutdown_ContextSelect cancels a channel pipeline
+// whose loops select on ctx.Done().
+func BenchmarkPipeline_Shutdown_ContextSelect(b *testing.B) {
+	trackGC(b)
+	ch := make(chan int, 1024)
+	lat := histogram.New()
+	rng := combined.NewRand(shutdownStream)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ctx, cancelFn := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			for n := 0; ; n++ {
+				select {
+				case <-ctx.Done():
+					return
+				case ch <- n:
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			sum := 0
+			for {
+				select {
+				case <-ctx.Done():
+					sinkInt = sum
+					return
+				case v := <-ch:
+					sum += v
+				}
+			}
+		}()
+
+		time.Sleep(time.Duration(rng.Int64N(int64(shutdownMaxRun))))
+		start := time.Now()
+		cancelFn()
+		wg.Wait()
+		lat.Record(int64(time.Since(start)))
+
+		for len(ch) > 0 {
+			<-ch
+		}
+	}
+
+	b.StopTimer()
+	reportShutdown(b, lat)
+}
+
+// BenchmarkPipeline_Shutdown_AtomicPoll cancels a ring buffer pipeline
+// whose loops check an AtomicCanceler every N iterations.
+func BenchmarkPipeline_Shutdown_AtomicPoll(b *testing.B) {
+	for _, every := range shutdownPollEvery {
+		b.Run(fmt.Sprintf("every=%d", every), func(b *testing.B) {
+			trackGC(b)
+			q := queue.Must(queue.NewRingBuffer[int](1024))
+			lat := histogram.New()
+			rng := combined.NewRand(shutdownStream)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				stop := cancel.NewAtomic()
+				var wg sync.WaitGroup
+				wg.Add(2)
+
+				go func() {
+					defer wg.Done()
+					for n, check := 0, every; ; check-- {
+						if check == 0 {
+							if stop.Done() {
+								return
+							}
+							check = every
+						}
+						if q.Push(n) {
+							n++
+						}
+					}
+				}()
+				go func() {
+					defer wg.Done()
+					sum := 0
+					for check := every; ; check-- {
+						if check == 0 {
+							if stop.Done() {
+								sinkInt = sum
+								return
+							}
+							check = every
+						}
+						if v, ok := q.Pop(); ok {
+							sum += v
+						}
+					}
+				}()
+
+				time.Sleep(time.Duration(rng.Int64N(int64(shutdownMaxRun))))
+				start := time.Now()
+				stop.Cancel()
+				wg.Wait()
+				lat.Record(int64(time.Since(start)))
+
+				for {
+					if _, ok := q.Pop(); !ok {
+						break
+					}
+				}
+			}
+
+			b.StopTimer()
+			reportShutdown(b, lat)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package combined_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ============================================================================
+// Queue capacity sweep
+// ============================================================================
+// Every other pipeline benchmark uses a capacity of 1024. This runs the
+// saturated channel and ring pipelines at each size in sweepSizes and
+// reports throughput (items/s) and latency (p99-ns) side by side. A
+// larger queue absorbs more scheduling hiccups, so throughput rises, but
+// under saturation it also stays fuller, and every item waits behind
+// more of them, so latency rises too. Pick the smallest size past which
+// items/s stops improving.
+
+var sweepSizes = []int{64, 256, 1024, 8192, 65536}
+
+// benchSizeSweep runs benchLatency saturated over a queue from newQ at
+// each sweep size.
+func benchSizeSweep(b *testing.B, newQ func(size int) queue.Queue[int64]) {
+	for _, size := range sweepSizes {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			trackGC(b)
+			benchLatency(b, newQ(size), latencyLoads[0], false)
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "items/s")
+		})
+	}
+}
+
+// BenchmarkPipeline_SizeSweep_Channel sweeps buffered channel capacity.
+func BenchmarkPipeline_SizeSweep_Channel(b *testing.B) {
+	benchSizeSweep(b, func(size int) queue.Queue[int64] {
+		return queue.Must(queue.NewChannel[int64](size))
+	})
+}
+
+// BenchmarkPipeline_SizeSweep_RingBuffer sweeps SPSC ring buffer capacity.
+func BenchmarkPipeline_SizeSweep_RingBuffer(b *testing.B) {
+	benchSizeSweep(b, func(size int) queue.Queue[int64] {
+		return queue.Must(queue.NewRingBuffer[int64](size))
+	})
+}
@@ -0,0 +1,207 @@
+package combined
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSweep is returned by ParseSweep for a malformed spec.
+var ErrInvalidSweep = errors.New("combined: invalid sweep")
+
+// SweepUsage is the help text for a -sweep flag.
+const SweepUsage = `run each scenario at every combination of these parameter values, e.g. "size=64,256,1024;batch=100,1000"`
+
+// Param is one scenario parameter setting, such as size=1024.
+type Param struct {
+	Name  string
+	Value string
+}
+
+// Params are parameter settings, in the order they were given.
+type Params []Param
+
+// Lookup returns the value of parameter name.
+func (p Params) Lookup(name string) (string, bool) {
+	for _, kv := range p {
+		if kv.Name == name {
+			return kv.Value, true
+		}
+	}
+	return "", false
+}
+
+// Int returns parameter name as a positive integer.
+func (p Params) Int(name string) (int, error) {
+	v, _ := p.Lookup(name)
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("combined: parameter %s=%q is not a positive integer", name, v)
+	}
+	return n, nil
+}
+
+// Duration returns parameter name as a positive time.Duration.
+func (p Params) Duration(name string) (time.Duration, error) {
+	v, _ := p.Lookup(name)
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("combined: parameter %s=%q is not a positive duration", name, v)
+	}
+	return d, nil
+}
+
+// String formats p the way go test names sub-benchmarks, which benchstat
+// reads back as configuration: "size=64/batch=100".
+func (p Params) String() string {
+	parts := make([]string, len(p))
+	for i, kv := range p {
+		parts[i] = kv.Name + "=" + kv.Value
+	}
+	return strings.Join(parts, "/")
+}
+
+// Parameterized is a Scenario whose Setup takes Params, built by
+// DefineParams. Its own Name and Setup use the default values.
+type Parameterized interface {
+	Scenario
+	// Params returns every parameter the scenario takes, with its default.
+	Params() Params
+	// With returns the scenario with the parameters it takes from p set,
+	// named like a go test sub-benchmark: "queue/ring/size=64". Settings
+	// for parameters it doesn't take are ignored; if none are left, With
+	// returns the scenario itself.
+	With(p Params) Scenario
+}
+
+// paramScenario is a Parameterized assembled by DefineParams.
+type paramScenario struct {
+	funcScenario
+	base   string // Name without settings
+	params Params // Defaults overridden by With
+	psetup func(Params) (run func(n int), teardown func(), err error)
+}
+
+// DefineParams is Define for a scenario that takes parameters: setup is
+// called with defaults, or with the values a sweep sets through With.
+//
+//	combined.Register(combined.DefineParams("queue/ring", combined.Params{{Name: "size", Value: "1024"}},
+//		func(p combined.Params) (func(int), func(), error) {
+//			size, err := p.Int("size")
+//			...
+//		}))
+func DefineParams(name string, defaults Params, setup func(p Params) (run func(n int), teardown func(), err error)) Scenario {
+	return newParamScenario(name, name, slices.Clone(defaults), setup)
+}
+
+func newParamScenario(name, base string, params Params, setup func(Params) (func(int), func(), error)) *paramScenario {
+	return &paramScenario{
+		funcScenario: funcScenario{name: name, setup: func() (func(int), func(), error) { return setup(params) }},
+		base:         base,
+		params:       params,
+		psetup:       setup,
+	}
+}
+
+func (ps *paramScenario) Params() Params { return slices.Clone(ps.params) }
+
+func (ps *paramScenario) With(p Params) Scenario {
+	params := slices.Clone(ps.params)
+	var set Params
+	for _, kv := range p {
+		i := slices.IndexFunc(params, func(d Param) bool { return d.Name == kv.Name })
+		if i < 0 {
+			continue
+		}
+		params[i].Value = kv.Value
+		set = append(set, kv)
+	}
+	if len(set) == 0 {
+		return ps
+	}
+	return newParamScenario(ps.base+"/"+set.String(), ps.base, params, ps.psetup)
+}
+
+// Axis is one parameter of a sweep and the values to try for it.
+type Axis struct {
+	Name   string
+	Values []string
+}
+
+// Sweep is a set of parameter axes whose cartesian product is run.
+type Sweep []Axis
+
+// ParseSweep parses a -sweep spec: axes separated by semicolons, each a
+// parameter name, "=", and comma-separated values, such as
+// "size=64,256,1024;batch=100,1000". An empty spec is an empty Sweep.
+// Values are checked by the scenarios that take them, in Setup.
+func ParseSweep(spec string) (Sweep, error) {
+	var sw Sweep
+	if strings.TrimSpace(spec) == "" {
+		return sw, nil
+	}
+	for _, part := range strings.Split(spec, ";") {
+		name, list, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, "/ ") {
+			return nil, fmt.Errorf("%w: %q: want name=v1,v2,...", ErrInvalidSweep, part)
+		}
+		if slices.ContainsFunc(sw, func(a Axis) bool { return a.Name == name }) {
+			return nil, fmt.Errorf("%w: %q given twice", ErrInvalidSweep, name)
+		}
+		a := Axis{Name: name}
+		for _, v := range strings.Split(list, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" || strings.Contains(v, "/") {
+				return nil, fmt.Errorf("%w: %q: bad value %q", ErrInvalidSweep, part, v)
+			}
+			a.Values = append(a.Values, v)
+		}
+		sw = append(sw, a)
+	}
+	return sw, nil
+}
+
+// Takes reports whether any of scenarios takes parameter name.
+func Takes(scenarios []Scenario, name string) bool {
+	for _, s := range scenarios {
+		if ps, ok := s.(Parameterized); ok {
+			if _, ok := ps.Params().Lookup(name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Expand returns s at every combination of the sweep's values for the
+// parameters s takes, the first axis varying slowest. A scenario that
+// takes none of them is returned alone, unchanged.
+func (sw Sweep) Expand(s Scenario) []Scenario {
+	ps, ok := s.(Parameterized)
+	if !ok {
+		return []Scenario{s}
+	}
+	defaults := ps.Params()
+	combos := []Params{nil}
+	for _, a := range sw {
+		if _, ok := defaults.Lookup(a.Name); !ok {
+			continue
+		}
+		next := make([]Params, 0, len(combos)*len(a.Values))
+		for _, c := range combos {
+			for _, v := range a.Values {
+				next = append(next, append(slices.Clone(c), Param{a.Name, v}))
+			}
+		}
+		combos = next
+	}
+	out := make([]Scenario, len(combos))
+	for i, c := range combos {
+		out[i] = ps.With(c)
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package combined_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+)
+
+func TestParseSweep(t *testing.T) {
+	sw, err := combined.ParseSweep(" size=64, 256 ;batch=100,1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := combined.Sweep{
+		{Name: "size", Values: []string{"64", "256"}},
+		{Name: "batch", Values: []string{"100", "1000"}},
+	}
+	if !slices.EqualFunc(sw, want, func(a, b combined.Axis) bool {
+		return a.Name == b.Name && slices.Equal(a.Values, b.Values)
+	}) {
+		t.Errorf("ParseSweep = %+v, want %+v", sw, want)
+	}
+	if sw, err := combined.ParseSweep(""); err != nil || len(sw) != 0 {
+		t.Errorf("ParseSweep(\"\") = %+v, %v; want empty", sw, err)
+	}
+	for _, spec := range []string{"size", "=64", "size=", "size=64,,256", "size=64;size=128", "a/b=1", "size=1/2"} {
+		if _, err := combined.ParseSweep(spec); !errors.Is(err, combined.ErrInvalidSweep) {
+			t.Errorf("ParseSweep(%q) error = %v, want ErrInvalidSweep", spec, err)
+		}
+	}
+}
+
+func TestSweep_Expand(t *testing.T) {
+	sw, err := combined.ParseSweep("size=64,256;batch=100,1000;interval=1ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := func(ss []combined.Scenario) []string {
+		var out []string
+		for _, s := range ss {
+			out = append(out, s.Name())
+		}
+		return out
+	}
+
+	ring, _ := combined.Lookup("queue/ring")
+	if got, want := names(sw.Expand(ring)), []string{"queue/ring/size=64", "queue/ring/size=256"}; !slices.Equal(got, want) {
+		t.Errorf("Expand(queue/ring) = %v, want %v", got, want)
+	}
+	batch, _ := combined.Lookup("tick/batch")
+	if got, want := names(sw.Expand(batch)), []string{
+		"tick/batch/batch=100/interval=1ms", "tick/batch/batch=1000/interval=1ms",
+	}; !slices.Equal(got, want) {
+		t.Errorf("Expand(tick/batch) = %v, want %v", got, want)
+	}
+	std, _ := combined.Lookup("cancel/std")
+	if got := sw.Expand(std); len(got) != 1 || got[0] != std {
+		t.Errorf("Expand(cancel/std) = %v, want the scenario itself", names(got))
+	}
+
+	// Swept scenarios run with their settings, and bad values fail Setup
+	for _, s := range sw.Expand(ring) {
+		if _, err := combined.Run(s, 1000); err != nil {
+			t.Errorf("Run(%s): %v", s.Name(), err)
+		}
+	}
+	bad := ring.(combined.Parameterized).With(combined.Params{{Name: "size", Value: "big"}})
+	if _, err := combined.Run(bad, 1); err == nil {
+		t.Error("Run with size=big succeeded")
+	}
+}
+
+func TestTakes(t *testing.T) {
+	all := combined.Scenarios()
+	for name, want := range map[string]bool{"size": true, "batch": true, "interval": true, "producers": false} {
+		if got := combined.Takes(all, name); got != want {
+			t.Errorf("Takes(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
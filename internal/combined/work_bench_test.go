@@ -0,0 +1,152 @@
+package combined_test
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/cancel"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/combined"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+// ============================================================================
+// Full loop benchmarks with per-item work
+// ============================================================================
+// The FullLoop benchmarks above do nothing with the item they pop, so the
+// cancel/tick/queue overhead is the whole cost and the optimized variant
+// looks several times faster. Real loops do something with each item.
+// These run the same loop with a synthetic workload per item; the Work
+// benchmarks measure the workload alone, so the loop overhead can be read
+// as a fraction of per-item cost:
+//
+//	overhead = FullLoopWork_X/work=W - Work/W
+
+// payloadSize is the size of the synthetic packet each workload reads.
+const payloadSize = 256
+
+// workload is one kind of per-item processing.
+type workload struct {
+	name string
+	fn   func(p []byte, v int) int
+}
+
+var workloads = []workload{
+	{"none", func(_ []byte, v int) int { return v }},
+	{"checksum256", workChecksum},
+	{"parseHeader", workParseHeader},
+}
+
+// workPayload is an IPv4 header followed by seeded random payload
+// bytes, set by TestMain from -combined.seed.
+var workPayload []byte
+
+// newWorkPayload builds workPayload from the current seed.
+func newWorkPayload() []byte {
+	p := make([]byte, payloadSize)
+	p[0] = 0x45                                    // Version 4, IHL 5
+	binary.BigEndian.PutUint16(p[2:], payloadSize) // Total length
+	p[8] = 64                                      // TTL
+	p[9] = 17                                      // UDP
+	copy(p[12:], []byte{10, 0, 0, 1, 10, 0, 0, 2})
+	rng := combined.NewRand(payloadStream)
+	for i := 20; i < len(p); i++ {
+		p[i] = byte(rng.Uint32())
+	}
+	return p
+}
+
+// workChecksum computes the Internet checksum (RFC 1071) over the whole
+// payload, as a receive path verifying a packet would.
+func workChecksum(p []byte, v int) int {
+	var sum uint32
+	for i := 0; i+1 < len(p); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(p[i:]))
+	}
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+	return int(^uint16(sum)) + v
+}
+
+// workParseHeader decodes and sanity-checks the IPv4 header fields a
+// router or load balancer would look at.
+func workParseHeader(p []byte, v int) int {
+	if p[0]>>4 != 4 {
+		return -1
+	}
+	ihl := int(p[0]&0x0f) * 4
+	total := int(binary.BigEndian.Uint16(p[2:]))
+	if ihl < 20 || total > len(p) || p[8] == 0 {
+		return -1
+	}
+	src := binary.BigEndian.Uint32(p[12:])
+	dst := binary.BigEndian.Uint32(p[16:])
+	return int(src^dst) + int(p[9]) + ihl + total + v
+}
+
+// BenchmarkCombined_Work measures each workload on its own.
+func BenchmarkCombined_Work(b *testing.B) {
+	for _, w := range workloads {
+		b.Run(w.name, func(b *testing.B) {
+			trackGC(b)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var r int
+			for i := 0; i < b.N; i++ {
+				r = w.fn(workPayload, i)
+			}
+			sinkInt = r
+		})
+	}
+}
+
+// benchFullLoopWork runs the FullLoop body plus w on every popped item.
+func benchFullLoopWork(b *testing.B, c cancel.Canceler, t tick.Ticker, q queue.Queue[int], w workload) {
+	for i := 0; i < 1024; i++ {
+		q.Push(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val, r int
+	var ok, cancelled, ticked bool
+	for i := 0; i < b.N; i++ {
+		cancelled = c.Done()
+		ticked = t.Tick()
+		val, ok = q.Pop()
+		r += w.fn(workPayload, val)
+		q.Push(val) // Recycle
+	}
+	sinkInt = r
+	sinkBool = ok || cancelled || ticked
+}
+
+// BenchmarkCombined_FullLoopWork_Standard is FullLoop_Standard with
+// per-item work.
+func BenchmarkCombined_FullLoopWork_Standard(b *testing.B) {
+	for _, w := range workloads {
+		b.Run("work="+w.name, func(b *testing.B) {
+			trackGC(b)
+			ticker := tick.NewTicker(benchInterval)
+			defer ticker.Stop()
+			benchFullLoopWork(b, cancel.NewContext(context.Background()), ticker,
+				queue.Must(queue.NewChannel[int](1024)), w)
+		})
+	}
+}
+
+// BenchmarkCombined_FullLoopWork_Optimized is FullLoop_Optimized with
+// per-item work.
+func BenchmarkCombined_FullLoopWork_Optimized(b *testing.B) {
+	for _, w := range workloads {
+		b.Run("work="+w.name, func(b *testing.B) {
+			trackGC(b)
+			benchFullLoopWork(b, cancel.NewAtomic(), tick.NewAtomicTicker(benchInterval),
+				queue.Must(queue.NewRingBuffer[int](1024)), w)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Package counter provides shared event counters to compare with each
+// other: a single atomic.Int64, a mutex-guarded int64, and sharded
+// counters that spread the writes over several cache lines and add them
+// up in Sum.
+//
+// A counter every goroutine increments is one cache line every core
+// writes, so each Add waits for the line to come over from the last
+// writer: the more writers, the slower. Sharding gives writers their own
+// lines, per P (PerP), per goroutine (Local), or picked at random once
+// the single line is contended (Striped), which makes Add scale and
+// Sum cost a walk over every shard. The cancel package's AtomicCanceler
+// is the read-mostly side of the same trade; these are the write-mostly
+// side.
+package counter
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrInvalidShards is returned by constructors given a shard count that
+// is zero, negative, or above MaxShards.
+var ErrInvalidShards = errors.New("counter: shards must be between 1 and MaxShards")
+
+// MaxShards is the most shards a Striped counter has.
+const MaxShards = 1 << 16
+
+// Counter counts events from many goroutines.
+//
+// Add adds delta to the count. Sum returns the count: every Add that
+// returned before Sum was called, and any number of those still running.
+//
+// All Counters are safe for concurrent use, and start at 0.
+type Counter interface {
+	Add(delta int64)
+	Sum() int64
+}
+
+// shard is one cache line of a sharded counter.
+type shard struct {
+	v atomic.Int64
+
+	_pad0 [56]byte //nolint:unused
+}
+
+// Atomic is a Counter on a single atomic.Int64.
+//
+// This is the standard approach: Add is one atomic add and Sum one load,
+// but every writer contends for the same cache line.
+type Atomic struct {
+	v atomic.Int64
+}
+
+// NewAtomic creates an Atomic.
+func NewAtomic() *Atomic {
+	return &Atomic{}
+}
+
+// Add adds delta atomically.
+func (a *Atomic) Add(delta int64) {
+	a.v.Add(delta)
+}
+
+// Sum returns the count.
+func (a *Atomic) Sum() int64 {
+	return a.v.Load()
+}
+
+// Mutex is a Counter on an int64 guarded by a sync.Mutex.
+//
+// The contended line is the mutex's rather than the count's, and a
+// contended Add can park, so it is the floor the others should beat.
+type Mutex struct {
+	mu sync.Mutex
+	v  int64
+}
+
+// NewMutex creates a Mutex.
+func NewMutex() *Mutex {
+	return &Mutex{}
+}
+
+// Add adds delta under the lock.
+func (m *Mutex) Add(delta int64) {
+	m.mu.Lock()
+	m.v += delta
+	m.mu.Unlock()
+}
+
+// Sum returns the count under the lock.
+func (m *Mutex) Sum() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.v
+}
+
+// Must wraps a constructor call and panics if it returned an error.
+//
+// It is intended for tests, benchmarks and package-level variables with
+// constant shard counts:
+//
+//	s := counter.Must(counter.NewStriped(64))
+func Must[C any](c C, err error) C {
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// checkShards returns ErrInvalidShards unless 1 <= shards <= MaxShards.
+func checkShards(shards int) error {
+	if shards < 1 || shards > MaxShards {
+		return fmt.Errorf("%w: got %d", ErrInvalidShards, shards)
+	}
+	return nil
+}
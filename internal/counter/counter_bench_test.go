@@ -0,0 +1,55 @@
+package counter_test
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+var sinkInt64 int64
+
+// BenchmarkAdd times Adds from 1, 2 and GOMAXPROCS*4 writers at once,
+// splitting b.N between them.
+func BenchmarkAdd(b *testing.B) {
+	for _, writers := range []int{1, 2, runtime.GOMAXPROCS(0) * 4} {
+		for _, c := range counters() {
+			b.Run(fmt.Sprintf("%s/W=%d", c.name, writers), func(b *testing.B) {
+				var wg sync.WaitGroup
+				for w := range writers {
+					count := b.N / writers
+					if w < b.N%writers {
+						count++
+					}
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						add := c.newWriter()
+						for range count {
+							add(1)
+						}
+					}()
+				}
+				wg.Wait()
+				sinkInt64 = c.sum()
+			})
+		}
+	}
+}
+
+// BenchmarkSum times Sum, which walks every shard of the sharded
+// counters.
+func BenchmarkSum(b *testing.B) {
+	for _, c := range counters() {
+		for range runtime.GOMAXPROCS(0) * 4 {
+			c.newWriter()(1)
+		}
+		b.Run(c.name, func(b *testing.B) {
+			var sum int64
+			for range b.N {
+				sum += c.sum()
+			}
+			sinkInt64 = sum
+		})
+	}
+}
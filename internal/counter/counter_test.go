@@ -0,0 +1,87 @@
+package counter_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/counter"
+)
+
+// namedCounter is a counter, Local included, as the tests and
+// benchmarks drive it: each writing goroutine calls newWriter once and
+// adds through what it returns.
+type namedCounter struct {
+	name      string
+	newWriter func() func(delta int64)
+	sum       func() int64
+}
+
+// shared returns a namedCounter whose writers all Add to c.
+func shared(name string, c counter.Counter) namedCounter {
+	return namedCounter{name, func() func(int64) { return c.Add }, c.Sum}
+}
+
+// counters returns a fresh counter of each kind.
+func counters() []namedCounter {
+	local := counter.NewLocal()
+	return []namedCounter{
+		shared("Atomic", counter.NewAtomic()),
+		shared("Mutex", counter.NewMutex()),
+		shared("PerP", counter.NewPerP()),
+		shared("Striped", counter.Must(counter.NewStriped(8))),
+		{"Local", func() func(int64) { return local.NewCell().Add }, local.Sum},
+	}
+}
+
+func TestNewStriped_InvalidShards(t *testing.T) {
+	for _, shards := range []int{0, -1, counter.MaxShards + 1} {
+		if s, err := counter.NewStriped(shards); !errors.Is(err, counter.ErrInvalidShards) || s != nil {
+			t.Errorf("NewStriped(%d) = %v, %v; want nil, ErrInvalidShards", shards, s, err)
+		}
+	}
+	if s := counter.Must(counter.NewStriped(5)); s.Shards() != 8 {
+		t.Errorf("Shards() = %d, want 5 rounded up to 8", s.Shards())
+	}
+}
+
+// TestCounter_NoLostAdds checks that concurrent Adds all count, and that
+// Sum sees them once the writers are done.
+func TestCounter_NoLostAdds(t *testing.T) {
+	const writers, perWriter = 8, 5000
+	for _, c := range counters() {
+		t.Run(c.name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			for w := range writers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					add := c.newWriter()
+					for i := range perWriter {
+						add(int64(w + 1))
+						if i%1000 == 0 {
+							_ = c.sum()
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			// Writer w adds w+1 each time
+			want := int64(perWriter * writers * (writers + 1) / 2)
+			if got := c.sum(); got != want {
+				t.Errorf("Sum() = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestCounter_Negative(t *testing.T) {
+	for _, c := range counters() {
+		add := c.newWriter()
+		add(5)
+		add(-7)
+		if got := c.sum(); got != -2 {
+			t.Errorf("%s: Sum() = %d after Add(5), Add(-7); want -2", c.name, got)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package counter
+
+import (
+	"runtime"
+	_ "unsafe" // Required for go:linkname
+)
+
+// procPin disables preemption and returns the current P's id; procUnpin
+// undoes it. sync.Pool uses them to find its per-P caches.
+//
+// Note: This uses go:linkname to access internal runtime functions. The
+// runtime keeps them for packages that already do (go.dev/issue/67401).
+//
+//go:linkname procPin runtime.procPin
+func procPin() int
+
+//go:linkname procUnpin runtime.procUnpin
+func procUnpin()
+
+// PerP is a Counter with a shard per P, as sync.Pool has its caches: Add
+// adds to the shard of the P it runs on, so writers on different Ps
+// never share a cache line, and Sum adds up all the shards.
+//
+// Add stays atomic, as the goroutine may move to another P right after
+// looking its shard up and another goroutine then write the same shard,
+// but the atomic add is on a line that is almost always already in the
+// writer's cache. It has GOMAXPROCS shards as of NewPerP; if GOMAXPROCS
+// grows later, Ps share shards.
+type PerP struct {
+	shards []shard
+}
+
+// NewPerP creates a PerP with a shard for each of the GOMAXPROCS Ps.
+func NewPerP() *PerP {
+	return &PerP{shards: make([]shard, runtime.GOMAXPROCS(0))}
+}
+
+// Add adds delta to the current P's shard.
+func (p *PerP) Add(delta int64) {
+	pid := procPin()
+	procUnpin()
+	p.shards[pid%len(p.shards)].v.Add(delta)
+}
+
+// Sum returns the sum of the shards.
+func (p *PerP) Sum() int64 {
+	var sum int64
+	for i := range p.shards {
+		sum += p.shards[i].v.Load()
+	}
+	return sum
+}
@@ -0,0 +1,64 @@
+package counter
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+// Striped is a Counter in the design of Java's LongAdder: Add tries a
+// compare-and-swap on a single base count first, and only when that fails,
+// because another writer got there first, adds to one of a set of
+// stripes picked at random. Sum adds up the base and the stripes.
+//
+// Uncontended it costs about what Atomic does; contended, the writers
+// spread over the stripes instead of queueing for one line. Unlike PerP it
+// needs no runtime internals, but a random stripe is a line the writer
+// last touched long ago, and two writers can still pick the same one.
+type Striped struct {
+	stripes []shard
+	mask    uint32
+
+	_pad0 [56]byte //nolint:unused
+
+	base atomic.Int64
+
+	_pad1 [56]byte //nolint:unused
+}
+
+// NewStriped creates a Striped counter with shards stripes, rounded up
+// to a power of two.
+//
+// Returns ErrInvalidShards if shards is not between 1 and MaxShards.
+func NewStriped(shards int) (*Striped, error) {
+	if err := checkShards(shards); err != nil {
+		return nil, err
+	}
+	n := uint32(1)
+	for n < uint32(shards) {
+		n <<= 1
+	}
+	return &Striped{stripes: make([]shard, n), mask: n - 1}, nil
+}
+
+// Add adds delta to the base count, or to a random stripe if another Add
+// is updating the base.
+func (s *Striped) Add(delta int64) {
+	if b := s.base.Load(); s.base.CompareAndSwap(b, b+delta) {
+		return
+	}
+	s.stripes[rand.Uint32()&s.mask].v.Add(delta)
+}
+
+// Sum returns the sum of the base and the stripes.
+func (s *Striped) Sum() int64 {
+	sum := s.base.Load()
+	for i := range s.stripes {
+		sum += s.stripes[i].v.Load()
+	}
+	return sum
+}
+
+// Shards returns the number of stripes.
+func (s *Striped) Shards() int {
+	return len(s.stripes)
+}
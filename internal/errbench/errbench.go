@@ -0,0 +1,117 @@
+// Package errbench provides ways of returning an error from a failed
+// operation and of telling that error apart from others, for
+// benchmarking what error handling costs in a hot loop.
+//
+// This package offers four Cases, each a way to fail and the check a
+// caller makes to recognize the failure:
+//   - New: Standard library errors.New on every failure, recognized by
+//     its text, as it has no identity to compare
+//   - Errorf: Standard library fmt.Errorf wrapping ErrNotFound with %w
+//     and the failing ID, recognized by errors.Is
+//   - Sentinel: Optimized ErrNotFound itself, created once, recognized
+//     by errors.Is
+//   - Typed: a *NotFoundError carrying the ID, recognized by errors.As
+//     into a variable of its type
+//
+// A sentinel costs nothing to return and one comparison to recognize, so
+// a loop that fails often, such as lookups that miss, pays for little
+// but the branch. errors.New and fmt.Errorf allocate on every failure,
+// fmt.Errorf formatting its message besides, and a custom type allocates
+// for the fields it carries; errors.As then matches types by reflection,
+// and the target it is passed escapes, an allocation per check. Each
+// caller that adds context with fmt.Errorf and %w, as Wrap does, adds
+// two allocations to every failure and a link for errors.Is and
+// errors.As to walk.
+package errbench
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound is the sentinel error Errorf wraps and Sentinel returns.
+var ErrNotFound = errors.New("errbench: not found")
+
+// NotFoundError is the error Typed returns: the failure with the ID that
+// wasn't found.
+type NotFoundError struct {
+	ID int
+}
+
+// Error returns the message, with the ID.
+func (e *NotFoundError) Error() string {
+	return "errbench: " + strconv.Itoa(e.ID) + " not found"
+}
+
+// Case is a way of failing: New returns the error for an operation on id
+// that failed, and Match reports whether err, or an error it wraps, is an
+// error New returns.
+type Case struct {
+	Name  string
+	New   func(id int) error
+	Match func(err error) bool
+}
+
+// Cases are the ways of failing, in report order.
+var Cases = []Case{
+	{"New", newError, matchText},
+	{"Errorf", errorf, isNotFound},
+	{"Sentinel", sentinel, isNotFound},
+	{"Typed", typed, asNotFound},
+}
+
+// newError returns a new error from errors.New.
+//
+//go:noinline
+func newError(int) error {
+	return errors.New("errbench: not found")
+}
+
+// errorf returns ErrNotFound wrapped by fmt.Errorf with id.
+//
+//go:noinline
+func errorf(id int) error {
+	return fmt.Errorf("errbench: lookup %d: %w", id, ErrNotFound)
+}
+
+// sentinel returns ErrNotFound.
+//
+//go:noinline
+func sentinel(int) error {
+	return ErrNotFound
+}
+
+// typed returns a *NotFoundError for id.
+//
+//go:noinline
+func typed(id int) error {
+	return &NotFoundError{ID: id}
+}
+
+// matchText reports whether err's message ends in "not found", the check
+// left for an error with no identity.
+func matchText(err error) bool {
+	return strings.HasSuffix(err.Error(), "not found")
+}
+
+// isNotFound reports whether err is or wraps ErrNotFound.
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// asNotFound reports whether err is or wraps a *NotFoundError.
+func asNotFound(err error) bool {
+	var nf *NotFoundError
+	return errors.As(err, &nf)
+}
+
+// Wrap returns err wrapped depth times by fmt.Errorf with %w, as each
+// caller it is returned through adds its context.
+func Wrap(err error, depth int) error {
+	for range depth {
+		err = fmt.Errorf("errbench: call: %w", err)
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package errbench_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/errbench"
+)
+
+// Sink variables to prevent compiler from eliminating benchmark loops
+var (
+	sinkErr  error
+	sinkBool bool
+)
+
+// BenchmarkNew creates each Case's error, the cost of a failure.
+func BenchmarkNew(b *testing.B) {
+	for _, c := range errbench.Cases {
+		b.Run(c.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sinkErr = c.New(i)
+			}
+		})
+	}
+}
+
+// BenchmarkMatch recognizes an error from each Case, wrapped 0, 1 and 4
+// times.
+func BenchmarkMatch(b *testing.B) {
+	for _, depth := range []int{0, 1, 4} {
+		for _, c := range errbench.Cases {
+			b.Run(fmt.Sprintf("%s/depth=%d", c.Name, depth), func(b *testing.B) {
+				b.ReportAllocs()
+				err := errbench.Wrap(c.New(42), depth)
+				for i := 0; i < b.N; i++ {
+					sinkBool = c.Match(err)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkFail creates, wraps and recognizes an error, a failed call
+// returned up through depth callers, each adding context.
+func BenchmarkFail(b *testing.B) {
+	for _, depth := range []int{0, 1, 4} {
+		for _, c := range errbench.Cases {
+			b.Run(fmt.Sprintf("%s/depth=%d", c.Name, depth), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					sinkBool = c.Match(errbench.Wrap(c.New(i), depth))
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package errbench_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/errbench"
+)
+
+func TestCases_Match(t *testing.T) {
+	for _, c := range errbench.Cases {
+		for depth := range 4 {
+			err := errbench.Wrap(c.New(42), depth)
+			if !c.Match(err) {
+				t.Errorf("%s: Match(%v) = false at depth %d, want true", c.Name, err, depth)
+			}
+		}
+		for _, other := range []error{io.EOF, fmt.Errorf("wrapped: %w", io.ErrUnexpectedEOF)} {
+			if c.Match(other) {
+				t.Errorf("%s: Match(%v) = true, want false", c.Name, other)
+			}
+		}
+	}
+}
+
+func TestCases_Identity(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		want bool
+	}{
+		{"New", false},
+		{"Errorf", true},
+		{"Sentinel", true},
+		{"Typed", false},
+	} {
+		c := errbench.Cases[caseIndex(t, tt.name)]
+		if got := errors.Is(c.New(1), errbench.ErrNotFound); got != tt.want {
+			t.Errorf("errors.Is(%s, ErrNotFound) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTyped_ID(t *testing.T) {
+	err := errbench.Wrap(errbench.Cases[caseIndex(t, "Typed")].New(7), 2)
+	var nf *errbench.NotFoundError
+	if !errors.As(err, &nf) || nf.ID != 7 {
+		t.Fatalf("errors.As(%v) = %v, want a *NotFoundError with ID 7", err, nf)
+	}
+	if want := "errbench: call: errbench: call: errbench: 7 not found"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if err := errbench.Wrap(io.EOF, 0); err != io.EOF {
+		t.Errorf("Wrap(EOF, 0) = %v, want EOF itself", err)
+	}
+	err := errbench.Wrap(io.EOF, 3)
+	for range 3 {
+		if err = errors.Unwrap(err); err == nil {
+			t.Fatal("Wrap(EOF, 3) has fewer than 3 layers")
+		}
+	}
+	if err != io.EOF {
+		t.Errorf("Wrap(EOF, 3) unwrapped 3 times = %v, want EOF", err)
+	}
+}
+
+func TestSentinel_NoAlloc(t *testing.T) {
+	c := errbench.Cases[caseIndex(t, "Sentinel")]
+	var sink bool
+	if n := testing.AllocsPerRun(100, func() { sink = c.Match(c.New(1)) }); n != 0 {
+		t.Errorf("Sentinel allocated %v times, want 0", n)
+	}
+	_ = sink
+}
+
+// caseIndex returns the index of the Case named name.
+func caseIndex(t *testing.T, name string) int {
+	t.Helper()
+	for i, c := range errbench.Cases {
+		if c.Name == name {
+			return i
+		}
+	}
+	t.Fatalf("no Case %q", name)
+	return -1
+}
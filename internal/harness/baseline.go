@@ -0,0 +1,359 @@
+package harness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidThreshold is returned by OpenBaselines for a
+	// -fail-on-regression value that isn't a non-negative percentage, that
+	// comes without -compare, or with too few runs on either side to tell
+	// a slowdown from noise (see MinGateRuns).
+	ErrInvalidThreshold = errors.New("harness: invalid regression threshold")
+
+	// ErrRegression is returned by Baselines.Apply when a variant is
+	// slower than its baseline by more than the threshold.
+	ErrRegression = errors.New("harness: regression")
+
+	// ErrSchemaVersion is returned when reading results written with a
+	// newer SchemaVersion than this build knows.
+	ErrSchemaVersion = errors.New("harness: unsupported schema version")
+)
+
+// SchemaVersion is the version of the JSON baseline and CSV formats this
+// build writes, in their schema_version field and column.
+//
+// New metrics don't change it: they are added as new JSON fields and new
+// trailing CSV columns. Readers ignore fields they don't know, and a
+// field an older file lacks reads as zero, which every metric treats as
+// "not measured", so stored baselines keep working in both directions.
+// The version goes up only when an existing field changes its name, unit
+// or meaning; LoadBaseline then upgrades files of each older version it
+// knows, and refuses newer ones rather than misread them.
+//
+// Files from before versioning have no schema_version, and are version 1.
+const SchemaVersion = 1
+
+// SaveUsage, CompareUsage and FailOnRegressionUsage are the help text for
+// -save, -compare and -fail-on-regression.
+const (
+	SaveUsage             = "save results as a JSON baseline to this file"
+	CompareUsage          = "compare results against the JSON baseline in this file"
+	FailOnRegressionUsage = "with -compare and -count 5 or more, exit non-zero if any variant's ns/op (significantly, p < 0.05), B/op or allocs/op is this much worse (e.g. 5%)"
+)
+
+// MinGateRuns is the fewest runs of a variant, in the -compare baseline
+// and in the new results, that -fail-on-regression takes: below it,
+// MannWhitneyU can't find any ns/op change significant, so the gate
+// could never fail.
+const MinGateRuns = 5
+
+// Baseline is a saved set of results, written by -save and read back by
+// -compare to report what changed in between.
+type Baseline struct {
+	SchemaVersion int              `json:"schema_version"`
+	Machine       Machine          `json:"machine"`
+	Fingerprint   string           `json:"fingerprint,omitempty"` // Machine.Fingerprint, for tools that read the file
+	Results       []BaselineResult `json:"results"`
+}
+
+// BaselineResult is one variant's saved measurement.
+type BaselineResult struct {
+	Benchmark   string    `json:"benchmark"`
+	Variant     string    `json:"variant"`
+	Iterations  int       `json:"iterations"`
+	NsPerOp     float64   `json:"ns_per_op"` // Median over Samples
+	Samples     []float64 `json:"samples_ns_per_op"`
+	BytesPerOp  int64     `json:"bytes_per_op"`
+	AllocsPerOp int64     `json:"allocs_per_op"`
+	MinMHz      int       `json:"min_mhz,omitempty"` // CPU frequency range while measured
+	MaxMHz      int       `json:"max_mhz,omitempty"`
+	Seed        uint64    `json:"seed,omitempty"`          // Synthetic data seed, for scenarios
+	Partial     bool      `json:"partial,omitempty"`       // From an interrupted run
+	CyclesPerOp float64   `json:"cycles_per_op,omitempty"` // Median TSC cycles per iteration, with -cycles
+
+	Latency *LatencySummary `json:"latency,omitempty"` // Operation latencies, with -latency-trace
+}
+
+// NewBaseline records results for benchmark bench, measured on m.
+func NewBaseline(bench string, m Machine, results []Result) Baseline {
+	b := Baseline{SchemaVersion: SchemaVersion, Machine: m, Fingerprint: m.Fingerprint()}
+	for _, r := range results {
+		b.Results = append(b.Results, BaselineResult{
+			Benchmark:   bench,
+			Variant:     r.Name,
+			Iterations:  r.N,
+			NsPerOp:     r.NsPerOp(),
+			Samples:     r.nsPerOp(),
+			BytesPerOp:  r.BytesPerOp,
+			AllocsPerOp: r.AllocsPerOp,
+			MinMHz:      r.MinMHz,
+			MaxMHz:      r.MaxMHz,
+			Seed:        r.Seed,
+			Partial:     r.Partial,
+			CyclesPerOp: r.CyclesPerOp(),
+			Latency:     NewLatencySummary(r.Latency),
+		})
+	}
+	return b
+}
+
+// SaveBaseline writes b to path as indented JSON.
+func SaveBaseline(path string, b Baseline) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadBaseline reads a Baseline written by SaveBaseline or -format=json,
+// by this build or an earlier one, upgraded to SchemaVersion.
+func LoadBaseline(path string) (Baseline, error) {
+	var b Baseline
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return b, err
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return b, fmt.Errorf("harness: baseline %s: %w", path, err)
+	}
+	if err := b.upgrade(); err != nil {
+		return b, fmt.Errorf("harness: baseline %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// upgrade brings b, as decoded, to SchemaVersion. Version 1 is the
+// first, so the only upgrade so far is from the unversioned files that
+// preceded it, which have the same fields.
+func (b *Baseline) upgrade() error {
+	switch {
+	case b.SchemaVersion == 0:
+		b.SchemaVersion = 1
+	case b.SchemaVersion > SchemaVersion:
+		return fmt.Errorf("%w %d: this build reads up to %d; rebuild from a newer checkout", ErrSchemaVersion, b.SchemaVersion, SchemaVersion)
+	}
+	return nil
+}
+
+// Delta compares one of a variant's metrics with its baseline: ns/op, as
+// Diff returns, or B/op or allocs/op, as Regressions may.
+type Delta struct {
+	Benchmark string
+	Variant   string
+	Metric    string  // "ns/op", "B/op" or "allocs/op"
+	Old       float64 // Baseline value; meaningless unless Found
+	New       float64
+	Found     bool    // Whether the baseline has this variant
+	P         float64 // MannWhitneyU p-value of the ns/op samples; 1 for the other metrics
+}
+
+// String returns the variant, metric and change, such as
+// "Context ns/op +6.00%", or the old and new values where the baseline's
+// is 0 and a percentage would be infinite.
+func (d Delta) String() string {
+	return d.Variant + " " + d.change()
+}
+
+// change returns the metric and its change, without the variant.
+func (d Delta) change() string {
+	if d.Old == 0 {
+		return fmt.Sprintf("%s %g -> %g", d.Metric, d.Old, d.New)
+	}
+	return fmt.Sprintf("%s %+.2f%%", d.Metric, d.Percent())
+}
+
+// Percent returns the change from Old to New as a percentage; positive
+// is slower. It is NaN without a baseline.
+func (d Delta) Percent() float64 {
+	if !d.Found || d.Old == 0 {
+		return math.NaN()
+	}
+	return (d.New - d.Old) / d.Old * 100
+}
+
+// Compare returns a Delta for each of results, matched to the baseline by
+// benchmark bench and variant name.
+func (b Baseline) Compare(bench string, results []Result) []Delta {
+	return b.Diff(NewBaseline(bench, Machine{}, results))
+}
+
+// Diff returns a Delta for each result in newer, matched to b's by
+// benchmark and variant name.
+func (b Baseline) Diff(newer Baseline) []Delta {
+	deltas := make([]Delta, len(newer.Results))
+	for i, nr := range newer.Results {
+		deltas[i] = Delta{Benchmark: nr.Benchmark, Variant: nr.Variant, Metric: "ns/op", New: nr.NsPerOp, P: 1}
+		for _, br := range b.Results {
+			if br.Benchmark == nr.Benchmark && br.Variant == nr.Variant {
+				deltas[i].Old, deltas[i].Found = br.NsPerOp, true
+				deltas[i].P = MannWhitneyU(br.Samples, nr.Samples)
+				break
+			}
+		}
+	}
+	return deltas
+}
+
+// ParseThreshold parses a -fail-on-regression percentage such as "5%",
+// "2.5%" or "5".
+func ParseThreshold(s string) (float64, error) {
+	pct, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || pct < 0 || math.IsNaN(pct) || math.IsInf(pct, 0) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidThreshold, s)
+	}
+	return pct, nil
+}
+
+// Baselines is a command's -save, -compare, -fail-on-regression and -db
+// settings, with the -compare baseline already loaded.
+type Baselines struct {
+	Save        string
+	ComparePath string
+	DB          string // Results database to append each run to
+	NoColor     bool   // Never color the comparison (-no-color)
+	compare     *Baseline
+	gate        bool    // Whether -fail-on-regression was given
+	threshold   float64 // Percent slowdown allowed when gate is set
+}
+
+// OpenBaselines loads the baseline at compare, if set, and parses failOn,
+// if set, so bad flags fail before any benchmark runs rather than after
+// all of them. count is the -count the new results will have; with
+// failOn, it and every variant in the baseline must have MinGateRuns.
+func OpenBaselines(save, compare, failOn string, count int) (Baselines, error) {
+	bs := Baselines{Save: save, ComparePath: compare}
+	if failOn != "" {
+		if compare == "" {
+			return bs, fmt.Errorf("%w: -fail-on-regression needs -compare", ErrInvalidThreshold)
+		}
+		if count < MinGateRuns {
+			return bs, fmt.Errorf("%w: -fail-on-regression needs -count %d or more, got %d", ErrInvalidThreshold, MinGateRuns, count)
+		}
+		pct, err := ParseThreshold(failOn)
+		if err != nil {
+			return bs, err
+		}
+		bs.gate, bs.threshold = true, pct
+	}
+	if compare != "" {
+		b, err := LoadBaseline(compare)
+		if err != nil {
+			return bs, err
+		}
+		bs.compare = &b
+	}
+	if bs.gate {
+		for _, br := range bs.compare.Results {
+			if len(br.Samples) < MinGateRuns {
+				return bs, fmt.Errorf("%w: -fail-on-regression needs %d or more runs per variant in %s, but %s has %d; save it with a higher -count",
+					ErrInvalidThreshold, MinGateRuns, compare, br.Variant, len(br.Samples))
+			}
+		}
+	}
+	return bs, nil
+}
+
+// Regressions returns a Delta for each metric of each of results for
+// benchmark bench that is worse than its -compare baseline by more than
+// the -fail-on-regression threshold, in results' order, ns/op first.
+// Without a threshold there are none.
+//
+// An ns/op change only counts if it is also significant, with a
+// MannWhitneyU p-value below DefaultAlpha, so noise in a few runs can't
+// fail the gate. OpenBaselines makes sure of MinGateRuns on both sides;
+// if dropped outliers still leave a side short, the threshold alone
+// decides, so the gate fails rather than never failing. B/op and
+// allocs/op are single numbers, so any rise past the threshold counts,
+// and so does any rise from 0.
+func (bs Baselines) Regressions(bench string, results []Result) []Delta {
+	if bs.compare == nil || !bs.gate {
+		return nil
+	}
+	var regressed []Delta
+	for _, nr := range NewBaseline(bench, Machine{}, results).Results {
+		i := slices.IndexFunc(bs.compare.Results, func(br BaselineResult) bool {
+			return br.Benchmark == nr.Benchmark && br.Variant == nr.Variant
+		})
+		if i < 0 {
+			continue
+		}
+		br := bs.compare.Results[i]
+		d := Delta{Benchmark: bench, Variant: nr.Variant, Metric: "ns/op", Old: br.NsPerOp, New: nr.NsPerOp, Found: true,
+			P: MannWhitneyU(br.Samples, nr.Samples)}
+		testable := min(len(br.Samples), len(nr.Samples)) >= MinGateRuns
+		if d.Percent() > bs.threshold && (d.P < DefaultAlpha || !testable) {
+			regressed = append(regressed, d)
+		}
+		for _, m := range []struct {
+			metric   string
+			old, new int64
+		}{
+			{"B/op", br.BytesPerOp, nr.BytesPerOp},
+			{"allocs/op", br.AllocsPerOp, nr.AllocsPerOp},
+		} {
+			d := Delta{Benchmark: bench, Variant: nr.Variant, Metric: m.metric, Old: float64(m.old), New: float64(m.new), Found: true, P: 1}
+			if m.new > m.old && (m.old == 0 || d.Percent() > bs.threshold) {
+				regressed = append(regressed, d)
+			}
+		}
+	}
+	return regressed
+}
+
+// Apply writes a comparison of results with the -compare baseline to w,
+// as WriteDiff does, colored if w is a terminal and NoColor is unset, and
+// headed by the host it was saved on and, if the environment has changed
+// since, a CheckFingerprint warning; then saves results to the -save
+// path and adds them as a run to the DB. Each step is skipped if its flag
+// was empty. With a -fail-on-regression threshold, Apply then returns
+// ErrRegression naming each of the Regressions; variants missing from
+// the baseline never fail.
+func (bs Baselines) Apply(w io.Writer, bench string, results []Result) error {
+	var regressed []string
+	if bs.compare != nil {
+		if _, err := fmt.Fprintf(w, "\nCompared with %s (saved on %s):\n", bs.ComparePath, bs.compare.Machine.Hostname); err != nil {
+			return err
+		}
+		CheckFingerprint(w, bs.compare.Machine, CurrentMachine())
+		// Only this benchmark's variants, or the rest would show as gone
+		old := Baseline{Machine: bs.compare.Machine}
+		for _, br := range bs.compare.Results {
+			if br.Benchmark == bench {
+				old.Results = append(old.Results, br)
+			}
+		}
+		if err := WriteDiff(w, NewColor(w, bs.NoColor), old, NewBaseline(bench, Machine{}, results), DefaultAlpha); err != nil {
+			return err
+		}
+		for _, d := range bs.Regressions(bench, results) {
+			regressed = append(regressed, d.String())
+		}
+	}
+	if bs.Save != "" || bs.DB != "" {
+		b := NewBaseline(bench, CurrentMachine(), results)
+		if bs.Save != "" {
+			if err := SaveBaseline(bs.Save, b); err != nil {
+				return err
+			}
+		}
+		if bs.DB != "" {
+			if _, err := AddRun(bs.DB, b); err != nil {
+				return err
+			}
+		}
+	}
+	if len(regressed) > 0 {
+		return fmt.Errorf("%w over %g%%: %s", ErrRegression, bs.threshold, strings.Join(regressed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package harness
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// benchActionEntry is one metric in github-action-benchmark's custom
+// JSON format.
+type benchActionEntry struct {
+	Name  string  `json:"name"`
+	Unit  string  `json:"unit"`
+	Value float64 `json:"value"`
+	Range string  `json:"range,omitempty"`
+	Extra string  `json:"extra,omitempty"`
+}
+
+// WriteBenchmarkAction writes results as the JSON array the
+// github-action-benchmark action reads with tool customSmallerIsBetter:
+// one entry per metric of each result, named "<bench>/<variant> - <unit>"
+// as the action's own Go parser names them, so every metric gets a chart
+// of its own:
+//
+//	[{"name": "Ticker/StdTicker - ns/op", "unit": "ns/op", "value": 122.58, "range": "± 5.44", "extra": "..."}]
+//
+// ns/op is the median, with the samples' standard deviation as its range
+// when there are several, followed by B/op, allocs/op and, when counted,
+// cycles/op. extra holds -n, the runs kept and m's CPU and Go version,
+// which the action shows beside each point.
+func WriteBenchmarkAction(w io.Writer, bench string, m Machine, results []Result) error {
+	entries := []benchActionEntry{}
+	for _, r := range results {
+		st := r.Stats()
+		name := bench + "/" + r.Name
+		extra := fmt.Sprintf("n=%d count=%d", r.N, st.Count)
+		if r.Partial {
+			extra += " (partial)"
+		}
+		if m.CPUModel != "" {
+			extra += "\ncpu: " + m.CPUModel
+		}
+		if m.GoVersion != "" {
+			extra += "\ngo: " + m.GoVersion
+		}
+		var spread string
+		if st.Count > 1 {
+			spread = fmt.Sprintf("± %.2f", st.StdDev)
+		}
+		entries = append(entries,
+			benchActionEntry{name + " - ns/op", "ns/op", st.Median, spread, extra},
+			benchActionEntry{name + " - B/op", "B/op", float64(r.BytesPerOp), "", extra},
+			benchActionEntry{name + " - allocs/op", "allocs/op", float64(r.AllocsPerOp), "", extra},
+		)
+		if len(r.Cycles) > 0 {
+			entries = append(entries, benchActionEntry{name + " - cycles/op", "cycles/op", r.CyclesPerOp(), "", extra})
+		}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,46 @@
+package harness
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeUsage is the help text for a -time flag.
+const TimeUsage = "run each variant for about this long, calibrating iterations like go test -benchtime (overrides -n)"
+
+// maxIterations caps a calibrated iteration count, as testing.B does.
+const maxIterations = 1_000_000_000
+
+// Iterations returns how many iterations of loop to time. With d of 0 it
+// is simply n. Otherwise it calibrates: starting from one iteration it
+// grows the count the way testing.B does, at most 100x per round, until a
+// run takes at least a tenth of d, then predicts the count that takes d.
+// Stopping short of d keeps calibration from costing as much as a sample.
+//
+// loop runs its argument's worth of iterations and returns the time taken.
+func Iterations(n int, d time.Duration, loop func(n int) time.Duration) int {
+	if d <= 0 {
+		return n
+	}
+	n = 1
+	for {
+		took := max(loop(n), 1)
+		predicted := int(min(float64(n)*float64(d)/float64(took), maxIterations))
+		if took >= d/10 || n >= maxIterations {
+			return max(predicted, 1)
+		}
+		next := predicted + predicted/5
+		next = min(next, 100*n)
+		next = max(next, n+1)
+		n = min(next, maxIterations)
+	}
+}
+
+// RunLength describes how long each variant runs, for text headers:
+// "10000000 iterations", or "about 2s per variant" with d set.
+func RunLength(n int, d time.Duration) string {
+	if d > 0 {
+		return fmt.Sprintf("about %v per variant", d)
+	}
+	return fmt.Sprintf("%d iterations", n)
+}
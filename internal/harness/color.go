@@ -0,0 +1,101 @@
+package harness
+
+import (
+	"io"
+	"os"
+)
+
+// NoColorUsage is the help text for a -no-color flag.
+const NoColorUsage = "don't color the text report; it is colored only on a terminal, and never when NO_COLOR is set"
+
+// ANSI escape sequences for the styles Color uses.
+const (
+	ansiReset = "\033[0m"
+	ansiBold  = "\033[1m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+)
+
+// Color styles parts of a text report: improvements and speedups in
+// green, regressions and slowdowns in red, and the fastest variant in
+// bold. When c is off, as the zero Color is, every method returns its
+// text unchanged, so reports call them unconditionally. Escape codes take
+// no room on screen but count in fmt widths, so pad text before styling
+// it.
+type Color struct {
+	on bool
+}
+
+// NewColor returns the Color for a report written to w: on if w is a
+// terminal, unless noColor (-no-color) is set, NO_COLOR is set to
+// anything (see https://no-color.org) or TERM is "dumb".
+func NewColor(w io.Writer, noColor bool) Color {
+	return Color{on: !noColor && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && isTerminal(w)}
+}
+
+// Change styles s, a formatted change pct percent in a lower-is-better
+// metric such as ns/op: green if it fell, red if it rose.
+func (c Color) Change(s string, pct float64) string {
+	switch {
+	case pct < 0:
+		return c.style(ansiGreen, s)
+	case pct > 0:
+		return c.style(ansiRed, s)
+	}
+	return s
+}
+
+// Speedup styles s, a formatted speedup x over a baseline: green above
+// 1x, red below.
+func (c Color) Speedup(s string, x float64) string {
+	switch {
+	case x > 1:
+		return c.style(ansiGreen, s)
+	case x < 1:
+		return c.style(ansiRed, s)
+	}
+	return s
+}
+
+// Winner highlights s, the name of the fastest variant compared.
+func (c Color) Winner(s string) string {
+	return c.style(ansiBold, s)
+}
+
+// style wraps s in the escape sequence code if c is on.
+func (c Color) style(code, s string) string {
+	if !c.on {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// Fastest returns the index of the result in results with the lowest
+// ns/op, for Winner. Results without samples don't count, and with fewer
+// than two that do there is no contest, and Fastest returns -1.
+func Fastest(results []Result) int {
+	best, entrants := -1, 0
+	for i, r := range results {
+		if len(r.Samples) == 0 {
+			continue
+		}
+		entrants++
+		if best < 0 || r.NsPerOp() < results[best].NsPerOp() {
+			best = i
+		}
+	}
+	if entrants < 2 {
+		return -1
+	}
+	return best
+}
+
+// isTerminal reports whether w is a terminal, rather than a file or pipe.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,64 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrNoTSC is returned by CheckCycles where there is no time stamp
+// counter to read.
+var ErrNoTSC = errors.New("harness: -cycles needs the amd64 time stamp counter")
+
+// CyclesUsage is the help text for a -cycles flag.
+const CyclesUsage = "also report TSC cycles per op next to ns/op (amd64)"
+
+// CheckCycles validates a -cycles flag.
+func CheckCycles(on bool) error {
+	if on && !haveTSC {
+		return ErrNoTSC
+	}
+	return nil
+}
+
+// TSCRate returns the -cycles setting line for text report headers: the
+// calibrated rate the time stamp counter runs at. It calibrates once, for
+// about 10ms.
+func TSCRate() string {
+	return fmt.Sprintf("Cycles: TSC at %.2f GHz", tscPerNs())
+}
+
+// countCycles wraps timed to append the TSC cycles each call takes to
+// *cycles, read just outside it.
+func countCycles(timed func() time.Duration, cycles *[]uint64) func() time.Duration {
+	return func() time.Duration {
+		start := readTSC()
+		d := timed()
+		*cycles = append(*cycles, readTSC()-start)
+		return d
+	}
+}
+
+// CyclesPerOp returns the median TSC cycles per iteration over the
+// samples, or 0 if they weren't counted (see Profiler.Cycles).
+func (r Result) CyclesPerOp() float64 {
+	if r.N == 0 || len(r.Cycles) == 0 {
+		return 0
+	}
+	xs := make([]float64, len(r.Cycles))
+	for i, c := range r.Cycles {
+		xs[i] = float64(c) / float64(r.N)
+	}
+	slices.Sort(xs)
+	return median(xs)
+}
+
+// CyclesText formats CyclesPerOp for text reports to follow ns/op with:
+// ", 12.34 cycles/op", or "" if cycles weren't counted.
+func (r Result) CyclesText() string {
+	if len(r.Cycles) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %.2f cycles/op", r.CyclesPerOp())
+}
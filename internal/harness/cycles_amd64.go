@@ -0,0 +1,17 @@
+//go:build amd64
+
+package harness
+
+import (
+	"sync"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+const haveTSC = true
+
+func readTSC() uint64 {
+	return tick.ReadTSC()
+}
+
+var tscPerNs = sync.OnceValue(tick.CalibrateTSC)
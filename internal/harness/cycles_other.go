@@ -0,0 +1,13 @@
+//go:build !amd64
+
+package harness
+
+const haveTSC = false
+
+func readTSC() uint64 {
+	return 0
+}
+
+func tscPerNs() float64 {
+	return 0
+}
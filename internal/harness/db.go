@@ -0,0 +1,91 @@
+package harness
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DBUsage is the help text for a -db flag.
+const DBUsage = "append results as a run to this results database (browse with bench serve)"
+
+// runsBucket holds one JSON-encoded Run per key, keyed by big-endian ID so
+// iteration is in the order runs were added.
+var runsBucket = []byte("runs")
+
+// dbTimeout bounds how long opening a results database waits for another
+// process's lock: a run being recorded, or bench serve reading.
+const dbTimeout = 5 * time.Second
+
+// Run is one recorded invocation of a cmd tool in a results database.
+type Run struct {
+	ID   uint64    `json:"id"`
+	Time time.Time `json:"time"`
+	Baseline
+}
+
+// AddRun appends b to the results database at path, creating it if
+// needed, and returns the new run's ID.
+func AddRun(path string, b Baseline) (uint64, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: dbTimeout})
+	if err != nil {
+		return 0, fmt.Errorf("harness: results db %s: %w", path, err)
+	}
+	defer db.Close()
+
+	var id uint64
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(runsBucket)
+		if err != nil {
+			return err
+		}
+		if id, err = bucket.NextSequence(); err != nil {
+			return err
+		}
+		data, err := json.Marshal(Run{ID: id, Time: time.Now().UTC(), Baseline: b})
+		if err != nil {
+			return err
+		}
+		return bucket.Put(binary.BigEndian.AppendUint64(nil, id), data)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("harness: results db %s: %w", path, err)
+	}
+	return id, nil
+}
+
+// ReadRuns returns every run in the results database at path, oldest
+// first, each upgraded to SchemaVersion as LoadBaseline does. It opens the database read-only, so it only waits for writers.
+func ReadRuns(path string) ([]Run, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: dbTimeout, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("harness: results db %s: %w", path, err)
+	}
+	defer db.Close()
+
+	var runs []Run
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(runsBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var r Run
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			if err := r.upgrade(); err != nil {
+				return fmt.Errorf("run %d: %w", r.ID, err)
+			}
+			runs = append(runs, r)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("harness: results db %s: %w", path, err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,160 @@
+package harness
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+)
+
+// DefaultAlpha is the p-value below which WriteDiff calls a change in
+// ns/op significant, unless told otherwise (bench diff -alpha).
+const DefaultAlpha = 0.05
+
+// diffPair is one variant in the old and new baselines; either may be nil.
+type diffPair struct {
+	name     string
+	old, new *BaselineResult
+}
+
+// alignResults pairs the results of old and cur by benchmark and variant,
+// in old's order and then with cur's additions. Pairs are named by
+// variant, or by benchmark/variant when the two hold more than one
+// benchmark between them.
+func alignResults(old, cur Baseline) []diffPair {
+	benchmarks := make(map[string]bool)
+	for _, b := range []Baseline{old, cur} {
+		for _, br := range b.Results {
+			benchmarks[br.Benchmark] = true
+		}
+	}
+	name := func(br *BaselineResult) string {
+		if len(benchmarks) > 1 {
+			return br.Benchmark + "/" + br.Variant
+		}
+		return br.Variant
+	}
+
+	var pairs []diffPair
+	index := make(map[string]int)
+	for i := range old.Results {
+		br := &old.Results[i]
+		index[name(br)] = len(pairs)
+		pairs = append(pairs, diffPair{name: name(br), old: br})
+	}
+	for i := range cur.Results {
+		br := &cur.Results[i]
+		if j, ok := index[name(br)]; ok {
+			pairs[j].new = br
+		} else {
+			pairs = append(pairs, diffPair{name: name(br), new: br})
+		}
+	}
+	return pairs
+}
+
+// WriteDiff writes old against cur, matched by benchmark and variant, as
+// benchstat does: a table per metric of the old and new values, the
+// change as a percentage (positive is worse), and "~" where the change
+// isn't significant. ns/op changes are tested with MannWhitneyU over the
+// saved samples, and are significant when p < alpha; the p-value and
+// sample counts follow each one. B/op and allocs/op are single numbers,
+// so any change counts, and their tables are left out when every value
+// is zero. ns/op ends with the geometric mean of the variants in both.
+// c colors the significant changes.
+func WriteDiff(w io.Writer, c Color, old, cur Baseline, alpha float64) error {
+	pairs := alignResults(old, cur)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	// The change goes after the last tab, padded before it is colored, as
+	// escape codes would throw off the tabwriter's column widths
+	fmt.Fprintf(tw, "\n\tns/op\t\t\n")
+	fmt.Fprintf(tw, "  Variant\told\tnew\t%9s\n", "Delta")
+	var logOld, logNew float64
+	var matched int
+	for _, p := range pairs {
+		switch {
+		case p.old == nil:
+			fmt.Fprintf(tw, "  %s\t-\t%.2f\t%9s\n", p.name, p.new.NsPerOp, "(new)")
+		case p.new == nil:
+			fmt.Fprintf(tw, "  %s\t%.2f\t-\t%9s\n", p.name, p.old.NsPerOp, "(gone)")
+		default:
+			pv := MannWhitneyU(p.old.Samples, p.new.Samples)
+			significant := pv < alpha
+			fmt.Fprintf(tw, "  %s\t%.2f\t%.2f\t%s %s (p=%.3f n=%d+%d)\n", p.name, p.old.NsPerOp, p.new.NsPerOp,
+				change(c, p.old.NsPerOp, p.new.NsPerOp, significant), marker(significant), pv, len(p.old.Samples), len(p.new.Samples))
+			if p.old.NsPerOp > 0 && p.new.NsPerOp > 0 {
+				logOld += math.Log(p.old.NsPerOp)
+				logNew += math.Log(p.new.NsPerOp)
+				matched++
+			}
+		}
+	}
+	if matched > 1 {
+		gOld, gNew := math.Exp(logOld/float64(matched)), math.Exp(logNew/float64(matched))
+		fmt.Fprintf(tw, "  geomean\t%.2f\t%.2f\t%s\n", gOld, gNew, change(c, gOld, gNew, true))
+	}
+
+	for _, m := range []struct {
+		unit  string
+		value func(*BaselineResult) int64
+	}{
+		{"B/op", func(br *BaselineResult) int64 { return br.BytesPerOp }},
+		{"allocs/op", func(br *BaselineResult) int64 { return br.AllocsPerOp }},
+	} {
+		var rows []string
+		nonzero := false
+		for _, p := range pairs {
+			if p.old == nil || p.new == nil {
+				continue
+			}
+			o, n := m.value(p.old), m.value(p.new)
+			nonzero = nonzero || o != 0 || n != 0
+			var delta string
+			switch {
+			case o == n:
+				delta = change(c, float64(o), float64(n), false) + " " + marker(false)
+			case o == 0:
+				delta = c.Change(fmt.Sprintf("%+9d", n), float64(n))
+			default:
+				delta = change(c, float64(o), float64(n), true)
+			}
+			rows = append(rows, fmt.Sprintf("  %s\t%d\t%d\t%s\n", p.name, o, n, delta))
+		}
+		if !nonzero {
+			continue
+		}
+		fmt.Fprintf(tw, "\n\t%s\t\t\n", m.unit)
+		fmt.Fprintf(tw, "  Variant\told\tnew\t%9s\n", "Delta")
+		for _, row := range rows {
+			fmt.Fprint(tw, row)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n~: no significant change: p >= %g for ns/op, or no change at all in B/op and allocs/op; more runs (-count) let smaller ns/op changes show.\n", alpha)
+	return err
+}
+
+// change formats the change from o to n as a percentage 9 wide, styled by
+// c if it is significant.
+func change(c Color, o, n float64, significant bool) string {
+	pct := 0.0
+	if o != 0 {
+		pct = (n - o) / o * 100
+	}
+	s := fmt.Sprintf("%+8.2f%%", pct)
+	if !significant {
+		return s
+	}
+	return c.Change(s, pct)
+}
+
+// marker returns the mark after a change: "~" if it isn't significant.
+func marker(significant bool) string {
+	if significant {
+		return " "
+	}
+	return "~"
+}
@@ -0,0 +1,56 @@
+package harness
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/pprof/profile"
+)
+
+// WriteFolded writes the CPU samples in p as folded stacks, the input
+// format of flamegraph.pl, inferno and speedscope: one line per distinct
+// stack, root first, frames separated by semicolons, then the number of
+// samples taken in it:
+//
+//	runtime.main;main.main;...;tick.(*StdTicker).Tick;runtime.selectnbrecv 812
+//
+// Inlined calls get frames of their own, as pprof shows them. Lines are
+// sorted, so folded files of two runs can be diffed.
+func WriteFolded(w io.Writer, p *profile.Profile) error {
+	counts := make(map[string]int64)
+	var frames []string
+	for _, s := range p.Sample {
+		if len(s.Value) == 0 {
+			continue
+		}
+		frames = frames[:0]
+		// Locations run leaf first, and each location's Lines run
+		// innermost inlined call first; walk both backwards for root first.
+		for i := len(s.Location) - 1; i >= 0; i-- {
+			loc := s.Location[i]
+			for j := len(loc.Line) - 1; j >= 0; j-- {
+				if fn := loc.Line[j].Function; fn != nil {
+					frames = append(frames, fn.Name)
+				}
+			}
+			if len(loc.Line) == 0 {
+				frames = append(frames, fmt.Sprintf("0x%x", loc.Address))
+			}
+		}
+		counts[strings.Join(frames, ";")] += s.Value[0]
+	}
+
+	stacks := make([]string, 0, len(counts))
+	for stack := range counts {
+		stacks = append(stacks, stack)
+	}
+	slices.Sort(stacks)
+	for _, stack := range stacks {
+		if _, err := fmt.Fprintf(w, "%s %d\n", stack, counts[stack]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
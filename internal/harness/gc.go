@@ -0,0 +1,158 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidGC is returned by ParseGC for a malformed -gogc or -ballast.
+var ErrInvalidGC = errors.New("harness: invalid GC setting")
+
+// GOGCUsage, BallastUsage and GCOffUsage are the help text for -gogc,
+// -ballast and -gc-off.
+const (
+	GOGCUsage    = "set GOGC for the run: a percentage or off (default: the GOGC environment variable)"
+	BallastUsage = "allocate a heap ballast of this size, e.g. 256MB, so the GC runs less often"
+	GCOffUsage   = "force a GC before each timed run and turn the GC off during it, for CPU-only numbers"
+)
+
+// GC is how a run sets up the garbage collector. The zero GC leaves it as
+// the environment configured it.
+//
+// Benchmarks that allocate pay for the GC in their ns/op, at whatever
+// moment it happens to run; that is realistic, but makes them noisy and
+// hard to compare with ones that don't allocate. Percent and Ballast
+// make collections rarer across the whole run. Off goes further: each
+// timed run starts from a fresh collection and none happens during it.
+type GC struct {
+	Percent *int  // GOGC; -1 is off. nil leaves it unchanged
+	Ballast int64 // Bytes of heap ballast; 0 for none
+	Off     bool  // GC off during each timed run
+}
+
+// ParseGC parses -gogc, -ballast and -gc-off. gogc is "" to leave GOGC
+// alone, "off", or a non-negative percentage; ballast is "" or a size in
+// bytes with an optional KB, MB or GB suffix (powers of 1024).
+func ParseGC(gogc, ballast string, off bool) (GC, error) {
+	g := GC{Off: off}
+	switch gogc {
+	case "":
+	case "off":
+		pct := -1
+		g.Percent = &pct
+	default:
+		pct, err := strconv.Atoi(gogc)
+		if err != nil || pct < 0 {
+			return GC{}, fmt.Errorf("%w: -gogc %q: want a percentage or off", ErrInvalidGC, gogc)
+		}
+		g.Percent = &pct
+	}
+	if ballast != "" {
+		n, err := parseBytes(ballast)
+		if err != nil {
+			return GC{}, fmt.Errorf("%w: -ballast %q: want a size such as 256MB", ErrInvalidGC, ballast)
+		}
+		g.Ballast = n
+	}
+	return g, nil
+}
+
+// parseBytes parses a non-negative byte count with an optional KB, MB or
+// GB suffix.
+func parseBytes(s string) (int64, error) {
+	mult := int64(1)
+	upper := strings.ToUpper(s)
+	for _, u := range []struct {
+		suffix string
+		mult   int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if strings.HasSuffix(upper, u.suffix) {
+			s, mult = s[:len(s)-len(u.suffix)], u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("bad size %q", s)
+	}
+	return n * mult, nil
+}
+
+// ballast keeps the heap ballast reachable for the life of the process.
+// It is never written, so its pages are never touched and cost no RSS,
+// but the GC counts it as live heap and paces itself accordingly.
+var ballast []byte
+
+// Apply sets GOGC and allocates the ballast, once, before any variant
+// runs. Off is applied by Profiler.Measure around each timed run.
+func (g GC) Apply() {
+	if g.Percent != nil {
+		debug.SetGCPercent(*g.Percent)
+	}
+	if g.Ballast > 0 {
+		ballast = make([]byte, g.Ballast)
+	}
+}
+
+// IsZero reports whether g leaves the GC as the environment set it.
+func (g GC) IsZero() bool {
+	return g.Percent == nil && g.Ballast == 0 && !g.Off
+}
+
+// String describes g for text reports, such as
+// "GOGC=400, 256MB ballast, off during timed runs".
+func (g GC) String() string {
+	var parts []string
+	if g.Percent != nil {
+		if *g.Percent < 0 {
+			parts = append(parts, "GOGC=off")
+		} else {
+			parts = append(parts, fmt.Sprintf("GOGC=%d", *g.Percent))
+		}
+	}
+	if g.Ballast > 0 {
+		parts = append(parts, formatBytes(g.Ballast)+" ballast")
+	}
+	if g.Off {
+		parts = append(parts, "off during timed runs")
+	}
+	if len(parts) == 0 {
+		return "default"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatBytes formats n in the largest unit parseBytes takes that
+// divides it.
+func formatBytes(n int64) string {
+	for _, u := range []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}} {
+		if n%u.mult == 0 {
+			return fmt.Sprintf("%d%s", n/u.mult, u.suffix)
+		}
+	}
+	return fmt.Sprintf("%dB", n)
+}
+
+// withoutGC wraps timed so each call starts from a forced collection and
+// runs with the GC off, restoring GOGC after.
+func withoutGC(timed func() time.Duration) func() time.Duration {
+	return func() time.Duration {
+		runtime.GC()
+		defer debug.SetGCPercent(debug.SetGCPercent(-1))
+		return timed()
+	}
+}
+
+// gcCycles returns the garbage collections that finished between two
+// MemStats readings, leaving out runtime.GC calls such as withoutGC's.
+func gcCycles(before, after *runtime.MemStats) int64 {
+	return int64(after.NumGC-before.NumGC) - int64(after.NumForcedGC-before.NumForcedGC)
+}
@@ -0,0 +1,363 @@
+// Package harness holds what the cmd tools share: the Result of timing
+// one variant, and writers for each output format they support.
+//
+// The default text format is each command's own prose. The other formats
+// are for tools: gobench prints the same lines `go test -bench` does, so
+// benchstat can compare two runs of a command exactly as it compares two
+// test runs:
+//
+//	go run ./cmd/ticker -format=gobench -n 10000000 > old.txt
+//	# change something
+//	go run ./cmd/ticker -format=gobench -n 10000000 > new.txt
+//	benchstat old.txt new.txt
+//
+// csv prints one row per variant with the Machine it ran on, so rows from
+// many machines can be concatenated into one spreadsheet. json prints the
+// same document -save writes, for jq and scripts. junit prints a JUnit XML
+// report, with a test case per variant, for CI dashboards, and
+// benchmark-action the JSON the github-action-benchmark action charts.
+package harness
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tdigest"
+)
+
+// ErrUnknownFormat is returned by ParseFormat for an unsupported name.
+var ErrUnknownFormat = errors.New("harness: unknown format")
+
+// Format selects how a command prints its results.
+type Format string
+
+const (
+	// FormatText is the command's human-readable report.
+	FormatText Format = "text"
+
+	// FormatGoBench is Go benchmark text format, readable by benchstat.
+	FormatGoBench Format = "gobench"
+
+	// FormatCSV is one CSV row per variant, with machine columns.
+	FormatCSV Format = "csv"
+
+	// FormatJSON is one JSON document in the -save baseline schema.
+	FormatJSON Format = "json"
+
+	// FormatJUnit is a JUnit XML report, one test case per variant.
+	FormatJUnit Format = "junit"
+
+	// FormatBenchmarkAction is github-action-benchmark's custom JSON,
+	// one entry per metric.
+	FormatBenchmarkAction Format = "benchmark-action"
+)
+
+// Formats lists every supported Format, for flag help.
+var Formats = []Format{FormatText, FormatGoBench, FormatCSV, FormatJSON, FormatJUnit, FormatBenchmarkAction}
+
+// FormatUsage is the help text for a -format flag.
+func FormatUsage() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "output format: " + strings.Join(names, ", ")
+}
+
+// ParseFormat returns the Format named s.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+}
+
+// ParseCounts parses a comma-separated list of positive counts, such as
+// a -producers flag's "1,2,4,8,16".
+func ParseCounts(s string) ([]int, error) {
+	var out []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, err
+		}
+		if n < 1 {
+			return nil, fmt.Errorf("count must be at least 1, got %d", n)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// QuietUsage is the help text for a -quiet flag.
+const QuietUsage = "print only the structured results, with no warnings, comparison table or prose (text becomes json)"
+
+// Quiet applies a -quiet flag to format f. It returns the format to
+// print in, with json in place of text's prose, and where to write the
+// notes that otherwise go to stderr beside machine-readable results:
+// machine warnings and the -compare table. Errors still go to stderr.
+func Quiet(quiet bool, f Format) (Format, io.Writer) {
+	if !quiet {
+		return f, os.Stderr
+	}
+	if f == FormatText {
+		f = FormatJSON
+	}
+	return f, io.Discard
+}
+
+// Result is one timed variant, run one or more times.
+type Result struct {
+	Name    string          // Variant name, e.g. "AtomicTicker" or "Channel/P=4"
+	N       int             // Iterations per sample
+	Samples []time.Duration // Wall time of each run of N iterations
+	Cycles  []uint64        // TSC cycles of each sample, with -cycles; see CyclesPerOp
+	Dropped int             // Samples removed by WithoutOutliers
+
+	AllocsPerOp int64 // Heap allocations per iteration, over all samples
+	BytesPerOp  int64 // Heap bytes allocated per iteration, over all samples
+	GCs         int64 // Garbage collections during the samples, not counting forced ones
+
+	MinMHz int // Lowest CPU frequency seen during the samples; 0 if unknown
+	MaxMHz int // Highest CPU frequency seen during the samples; 0 if unknown
+
+	Seed uint64 // Seed of the synthetic data the variant processed; 0 if none
+
+	Latency *tdigest.Digest // Operation latencies in ns, with -latency-trace; nil without
+
+	Partial bool // From a run cut short by an interrupt; see Partial
+}
+
+// Sample calls timed count times and returns the durations it reports.
+// timed should run the variant's loop inline and time only that, so the
+// closure call is not part of any per-op cost.
+func Sample(count int, timed func() time.Duration) []time.Duration {
+	samples := make([]time.Duration, count)
+	for i := range samples {
+		samples[i] = timed()
+	}
+	return samples
+}
+
+// NsPerOp returns the median time per iteration in nanoseconds. With a
+// single sample that is simply its time per iteration.
+func (r Result) NsPerOp() float64 {
+	return r.Stats().Median
+}
+
+// Elapsed returns the median sample's wall time.
+func (r Result) Elapsed() time.Duration {
+	return time.Duration(r.NsPerOp() * float64(r.N))
+}
+
+// nsPerOp returns each sample's time per iteration in nanoseconds.
+func (r Result) nsPerOp() []float64 {
+	if r.N == 0 {
+		return nil
+	}
+	out := make([]float64, len(r.Samples))
+	for i, d := range r.Samples {
+		out[i] = float64(d.Nanoseconds()) / float64(r.N)
+	}
+	return out
+}
+
+// Stats summarizes the samples' ns/op.
+func (r Result) Stats() Stats {
+	return Summarize(r.nsPerOp())
+}
+
+// MemPerOp formats BytesPerOp and AllocsPerOp for text reports.
+func (r Result) MemPerOp() string {
+	return fmt.Sprintf("%d B/op, %d allocs/op", r.BytesPerOp, r.AllocsPerOp)
+}
+
+// Write writes results for benchmark bench in format f. FormatText is
+// the caller's own report, so Write rejects it. FormatJUnit results all
+// pass; see WriteJUnit for failing regressions.
+func Write(w io.Writer, f Format, bench string, results []Result) error {
+	switch f {
+	case FormatGoBench:
+		return WriteGoBench(w, bench, CurrentMachine(), results)
+	case FormatCSV:
+		return WriteCSV(w, bench, CurrentMachine(), results)
+	case FormatJSON:
+		return WriteJSON(w, bench, CurrentMachine(), results)
+	case FormatJUnit:
+		return WriteJUnit(w, bench, CurrentMachine(), results, nil)
+	case FormatBenchmarkAction:
+		return WriteBenchmarkAction(w, bench, CurrentMachine(), results)
+	default:
+		return fmt.Errorf("%w: %q has no generic writer", ErrUnknownFormat, f)
+	}
+}
+
+// WriteGoBench writes results as Go benchmark lines under the benchmark
+// name Benchmark<bench>, one sub-benchmark per result and one line per
+// sample, as `go test -count` does:
+//
+//	BenchmarkTicker/AtomicTicker-8   10000000   3.21 ns/op   0 B/op   0 allocs/op
+//
+// The -N suffix is m's GOMAXPROCS, as `go test` prints it. Spaces in names
+// are replaced with underscores, since benchstat splits lines on
+// whitespace. The lines are preceded by m as "key: value" configuration
+// lines, which benchstat carries along with the results; goos, goarch and
+// cpu are the ones `go test` prints, and fields m doesn't know are left out.
+// Results from an interrupted run add "partial: true", and results with
+// counted cycles a cycles/op metric on each line.
+func WriteGoBench(w io.Writer, bench string, m Machine, results []Result) error {
+	config := [][2]string{
+		{"goos", m.GOOS},
+		{"goarch", m.GOARCH},
+		{"cpu", m.CPUModel},
+		{"kernel", m.Kernel},
+		{"governor", m.Governor},
+		{"turbo", m.Turbo},
+		{"go", m.GoVersion},
+	}
+	if m.Cores > 0 {
+		config = append(config, [2]string{"topology",
+			fmt.Sprintf("%d sockets x %d cores x %d threads", m.Sockets, m.Cores/m.Sockets, m.ThreadsPerCore)})
+	}
+	for _, r := range results {
+		if r.Partial {
+			config = append(config, [2]string{"partial", "true"})
+			break
+		}
+	}
+	for _, kv := range config {
+		if kv[1] == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	procs := m.GOMAXPROCS
+	for _, r := range results {
+		name := strings.ReplaceAll(r.Name, " ", "_")
+		for i, ns := range r.nsPerOp() {
+			var cycles string
+			if i < len(r.Cycles) {
+				cycles = fmt.Sprintf("\t%.2f cycles/op", float64(r.Cycles[i])/float64(r.N))
+			}
+			if _, err := fmt.Fprintf(w, "Benchmark%s/%s-%d\t%d\t%.2f ns/op\t%d B/op\t%d allocs/op%s\n",
+				bench, name, procs, r.N, ns, r.BytesPerOp, r.AllocsPerOp, cycles); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CSVHeader is the header row WriteCSV writes. ns_per_op is the median
+// over count kept samples; the other *_ns columns summarize the same
+// samples, and dropped counts the outliers left out of them. cycles_per_op
+// is the median TSC cycles per iteration, empty without -cycles. New columns
+// are only ever appended (see SchemaVersion), so scripts can read these
+// by position.
+var CSVHeader = []string{
+	"benchmark", "variant", "iterations", "count", "dropped",
+	"ns_per_op", "min_ns", "mean_ns", "max_ns", "stddev_ns", "bytes_per_op", "allocs_per_op",
+	"hostname", "cpu_model", "num_cpu", "cores", "sockets", "threads_per_core",
+	"gomaxprocs", "governor", "turbo", "kernel", "goos", "goarch", "go_version",
+	"schema_version", "seed", "partial", "cycles_per_op",
+}
+
+// WriteCSV writes a header row and one row per result, each tagged with
+// bench and the machine columns from m.
+func WriteCSV(w io.Writer, bench string, m Machine, results []Result) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if err := cw.Write(csvRow(bench, m, r)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvRow returns r's row under CSVHeader.
+func csvRow(bench string, m Machine, r Result) []string {
+	ns := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	st := r.Stats()
+	return []string{
+		bench,
+		r.Name,
+		strconv.Itoa(r.N),
+		strconv.Itoa(st.Count),
+		strconv.Itoa(r.Dropped),
+		ns(st.Median),
+		ns(st.Min),
+		ns(st.Mean),
+		ns(st.Max),
+		ns(st.StdDev),
+		strconv.FormatInt(r.BytesPerOp, 10),
+		strconv.FormatInt(r.AllocsPerOp, 10),
+		m.Hostname,
+		m.CPUModel,
+		strconv.Itoa(m.NumCPU),
+		strconv.Itoa(m.Cores),
+		strconv.Itoa(m.Sockets),
+		strconv.Itoa(m.ThreadsPerCore),
+		strconv.Itoa(m.GOMAXPROCS),
+		m.Governor,
+		m.Turbo,
+		m.Kernel,
+		m.GOOS,
+		m.GOARCH,
+		m.GoVersion,
+		strconv.Itoa(SchemaVersion),
+		seed(r.Seed),
+		partial(r.Partial),
+		cyclesPerOp(r),
+	}
+}
+
+// seed formats a Result's Seed for CSV: empty if it has none.
+func seed(s uint64) string {
+	if s == 0 {
+		return ""
+	}
+	return strconv.FormatUint(s, 10)
+}
+
+// partial formats a Result's Partial for CSV: "true", or empty.
+func partial(p bool) string {
+	if !p {
+		return ""
+	}
+	return "true"
+}
+
+// cyclesPerOp formats a Result's CyclesPerOp for CSV: empty if its
+// cycles weren't counted.
+func cyclesPerOp(r Result) string {
+	if len(r.Cycles) == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(r.CyclesPerOp(), 'f', 2, 64)
+}
+
+// WriteJSON writes results as the indented JSON document -save writes: a
+// Baseline with the machine and each variant's median and samples.
+func WriteJSON(w io.Writer, bench string, m Machine, results []Result) error {
+	data, err := json.MarshalIndent(NewBaseline(bench, m, results), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
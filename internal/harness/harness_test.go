@@ -0,0 +1,1572 @@
+package harness_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/pprof/profile"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/harness"
+)
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range harness.Formats {
+		got, err := harness.ParseFormat(string(f))
+		if err != nil || got != f {
+			t.Errorf("ParseFormat(%q) = %q, %v", f, got, err)
+		}
+	}
+	if _, err := harness.ParseFormat("xml"); !errors.Is(err, harness.ErrUnknownFormat) {
+		t.Errorf("ParseFormat(xml) error = %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestParseCounts(t *testing.T) {
+	got, err := harness.ParseCounts("1, 2,16")
+	if err != nil || !slices.Equal(got, []int{1, 2, 16}) {
+		t.Errorf("ParseCounts(1, 2,16) = %v, %v", got, err)
+	}
+	for _, s := range []string{"", "0", "1,-2", "4,x"} {
+		if _, err := harness.ParseCounts(s); err == nil {
+			t.Errorf("ParseCounts(%q) succeeded", s)
+		}
+	}
+}
+
+func TestResult_NsPerOp(t *testing.T) {
+	r := harness.Result{Name: "x", N: 4, Samples: []time.Duration{10 * time.Nanosecond}}
+	if got := r.NsPerOp(); got != 2.5 {
+		t.Errorf("NsPerOp = %v, want 2.5", got)
+	}
+	if got := (harness.Result{}).NsPerOp(); got != 0 {
+		t.Errorf("zero Result NsPerOp = %v, want 0", got)
+	}
+}
+
+func TestResult_Samples(t *testing.T) {
+	r := harness.Result{Name: "x", N: 10, Samples: []time.Duration{30, 10, 20}}
+	if got := r.NsPerOp(); got != 2 {
+		t.Errorf("NsPerOp = %v, want median 2", got)
+	}
+	if got := r.Elapsed(); got != 20 {
+		t.Errorf("Elapsed = %v, want median sample 20ns", got)
+	}
+}
+
+func TestResult_CyclesPerOp(t *testing.T) {
+	r := harness.Result{N: 10, Samples: []time.Duration{1, 1, 1}, Cycles: []uint64{300, 100, 200}}
+	if got := r.CyclesPerOp(); got != 20 {
+		t.Errorf("CyclesPerOp() = %v, want 20 (the median)", got)
+	}
+	if got := r.CyclesText(); got != ", 20.00 cycles/op" {
+		t.Errorf("CyclesText() = %q", got)
+	}
+	r.Cycles = nil
+	if got, text := r.CyclesPerOp(), r.CyclesText(); got != 0 || text != "" {
+		t.Errorf("without cycles: CyclesPerOp() = %v, CyclesText() = %q; want 0 and empty", got, text)
+	}
+}
+
+func TestSample(t *testing.T) {
+	calls := 0
+	got := harness.Sample(3, func() time.Duration {
+		calls++
+		return time.Duration(calls)
+	})
+	if want := []time.Duration{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("Sample = %v, want %v", got, want)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	xs := []float64{4, 1, 3, 2}
+	st := harness.Summarize(xs)
+	want := harness.Stats{Count: 4, Min: 1, Median: 2.5, Mean: 2.5, Max: 4, StdDev: math.Sqrt(5.0 / 3)}
+	if st != want {
+		t.Errorf("Summarize = %+v, want %+v", st, want)
+	}
+	if !slices.Equal(xs, []float64{4, 1, 3, 2}) {
+		t.Errorf("Summarize reordered its input: %v", xs)
+	}
+
+	if st := harness.Summarize([]float64{7}); st.Median != 7 || st.StdDev != 0 {
+		t.Errorf("single sample: %+v", st)
+	}
+	if st := harness.Summarize(nil); st != (harness.Stats{}) {
+		t.Errorf("no samples: %+v", st)
+	}
+}
+
+func TestResult_WithoutOutliers(t *testing.T) {
+	r := harness.Result{Name: "x", N: 1, Samples: []time.Duration{100, 102, 98, 101, 99, 5000}, Cycles: []uint64{1, 2, 3, 4, 5, 6}}
+	got := r.WithoutOutliers(harness.DefaultOutliers)
+	if want := []time.Duration{100, 102, 98, 101, 99}; !slices.Equal(got.Samples, want) || got.Dropped != 1 {
+		t.Errorf("WithoutOutliers = %v dropped %d, want %v dropped 1", got.Samples, got.Dropped, want)
+	}
+	if want := []uint64{1, 2, 3, 4, 5}; !slices.Equal(got.Cycles, want) {
+		t.Errorf("WithoutOutliers kept cycles %v, want %v", got.Cycles, want)
+	}
+	if len(r.Samples) != 6 {
+		t.Errorf("WithoutOutliers modified its receiver: %v", r.Samples)
+	}
+
+	if got := r.WithoutOutliers(0); got.Dropped != 0 || len(got.Samples) != 6 {
+		t.Errorf("WithoutOutliers(0) dropped %d", got.Dropped)
+	}
+	keep := []harness.Result{
+		{N: 1, Samples: []time.Duration{1, 1000}},
+		{N: 1, Samples: []time.Duration{1000, 1000, 1000, 1000, 1003}},
+	}
+	for _, r := range keep {
+		if got := r.WithoutOutliers(harness.DefaultOutliers); got.Dropped != 0 {
+			t.Errorf("WithoutOutliers(%v) dropped %d, want 0", r.Samples, got.Dropped)
+		}
+	}
+
+	if err := harness.CheckOutliers(-1); !errors.Is(err, harness.ErrInvalidOutliers) {
+		t.Errorf("CheckOutliers(-1) error = %v, want ErrInvalidOutliers", err)
+	}
+}
+
+func TestWriteGoBench_Count(t *testing.T) {
+	var buf bytes.Buffer
+	err := harness.WriteGoBench(&buf, "Context", harness.CurrentMachine(), []harness.Result{
+		{Name: "Atomic", N: 100, Samples: []time.Duration{100, 200, 300}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "BenchmarkContext/Atomic-"); n != 3 {
+		t.Errorf("got %d benchmark lines, want one per sample (3):\n%s", n, buf.String())
+	}
+}
+
+func TestWriteGoBench(t *testing.T) {
+	m := harness.Machine{
+		CPUModel: "Test CPU", Cores: 4, Sockets: 1, ThreadsPerCore: 2, GOMAXPROCS: 8,
+		Governor: "performance", Kernel: "6.1.0", GOOS: "linux", GOARCH: "amd64", GoVersion: "go1.25.4",
+	}
+	var buf bytes.Buffer
+	err := harness.WriteGoBench(&buf, "Ticker", m, []harness.Result{
+		{Name: "AtomicTicker", N: 1000, Samples: []time.Duration{3210 * time.Nanosecond}},
+		{Name: "Batch Ticker", N: 10, Samples: []time.Duration{15 * time.Nanosecond}, Cycles: []uint64{45}, BytesPerOp: 48, AllocsPerOp: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"goos: linux",
+		"goarch: amd64",
+		"cpu: Test CPU",
+		"kernel: 6.1.0",
+		"governor: performance",
+		"go: go1.25.4",
+		"topology: 1 sockets x 4 cores x 2 threads",
+		"BenchmarkTicker/AtomicTicker-8\t1000\t3.21 ns/op\t0 B/op\t0 allocs/op",
+		"BenchmarkTicker/Batch_Ticker-8\t10\t1.50 ns/op\t48 B/op\t2 allocs/op\t4.50 cycles/op",
+	}
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("WriteGoBench output:\n%s\nwant:\n%s", buf.String(), strings.Join(want, "\n"))
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	results := []harness.Result{{Name: "Atomic", N: 100, Samples: []time.Duration{1000, 1200, 1100}, AllocsPerOp: 1}}
+	var buf bytes.Buffer
+	if err := harness.Write(&buf, harness.FormatJSON, "Context", results); err != nil {
+		t.Fatal(err)
+	}
+	var b harness.Baseline
+	if err := json.Unmarshal(buf.Bytes(), &b); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if len(b.Results) != 1 || b.Results[0].Variant != "Atomic" || b.Results[0].NsPerOp != 11 ||
+		len(b.Results[0].Samples) != 3 || b.Machine.GOOS != runtime.GOOS {
+		t.Errorf("WriteJSON = %+v", b)
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	if f, w := harness.Quiet(false, harness.FormatText); f != harness.FormatText || w != os.Stderr {
+		t.Errorf("Quiet(false, text) = %q, %v; want text, stderr", f, w)
+	}
+	if f, w := harness.Quiet(true, harness.FormatText); f != harness.FormatJSON || w != io.Discard {
+		t.Errorf("Quiet(true, text) = %q, %v; want json, io.Discard", f, w)
+	}
+	if f, _ := harness.Quiet(true, harness.FormatCSV); f != harness.FormatCSV {
+		t.Errorf("Quiet(true, csv) format = %q, want csv", f)
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		want   float64
+	}{
+		{"separated 5+5", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 2.0 / 252},
+		{"separated reversed", []float64{6, 7, 8, 9, 10}, []float64{1, 2, 3, 4, 5}, 2.0 / 252},
+		{"interleaved", []float64{1, 3, 5, 7, 9}, []float64{2, 4, 6, 8, 10}, 174.0 / 252},
+		{"one each", []float64{1}, []float64{2}, 1},
+		{"all tied", []float64{5, 5, 5}, []float64{5, 5, 5}, 1},
+		{"empty", nil, []float64{1, 2}, 1},
+		// Ties force the normal approximation
+		{"ties", []float64{1, 1, 2, 2, 3, 3}, []float64{4, 4, 5, 5, 6, 6}, 0.00462},
+	}
+	for _, tt := range tests {
+		if got := harness.MannWhitneyU(tt.xs, tt.ys); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("%s: MannWhitneyU = %.5f, want %.5f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	m := harness.Machine{
+		Hostname: "lab1", CPUModel: "Test CPU, 3GHz", NumCPU: 8, Cores: 4, Sockets: 1, ThreadsPerCore: 2,
+		GOMAXPROCS: 4, Governor: "performance", Turbo: "off", Kernel: "6.1.0",
+		GOOS: "linux", GOARCH: "amd64", GoVersion: "go1.25.4",
+	}
+	var buf bytes.Buffer
+	err := harness.WriteCSV(&buf, "Ticker", m, []harness.Result{
+		{Name: "AtomicTicker", N: 1000, Samples: []time.Duration{3210 * time.Nanosecond}, BytesPerOp: 16, AllocsPerOp: 1, Seed: 42, Partial: true, Cycles: []uint64{9_630_000}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want header + 1", len(rows))
+	}
+	if !slices.Equal(rows[0], harness.CSVHeader) {
+		t.Errorf("header = %v, want %v", rows[0], harness.CSVHeader)
+	}
+	want := []string{"Ticker", "AtomicTicker", "1000", "1", "0", "3.21", "3.21", "3.21", "3.21", "0.00", "16", "1",
+		"lab1", "Test CPU, 3GHz", "8", "4", "1", "2", "4", "performance", "off", "6.1.0", "linux", "amd64", "go1.25.4", strconv.Itoa(harness.SchemaVersion), "42", "true", "9630.00"}
+	if !slices.Equal(rows[1], want) {
+		t.Errorf("row = %v, want %v", rows[1], want)
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	results := []harness.Result{
+		{Name: `Std "ctx"`, N: 100, Samples: []time.Duration{1000, 1200, 1100}, BytesPerOp: 16, AllocsPerOp: 1},
+	}
+	m := harness.Machine{Hostname: "ci-1", Kernel: "6.8.0", GoVersion: "go1.25.4", NumCPU: 8, GOMAXPROCS: 8}
+	var buf bytes.Buffer
+	if err := harness.WritePrometheus(&buf, "Context", m, results); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# TYPE bench_ns_per_op gauge\n",
+		`bench_ns_per_op{benchmark="Context",variant="Std \"ctx\""} 11` + "\n",
+		`bench_bytes_per_op{benchmark="Context",variant="Std \"ctx\""} 16` + "\n",
+		`bench_allocs_per_op{benchmark="Context",variant="Std \"ctx\""} 1` + "\n",
+		`bench_iterations{benchmark="Context",variant="Std \"ctx\""} 100` + "\n",
+		`hostname="ci-1",`,
+		`kernel="6.8.0",`,
+		`go_version="go1.25.4"} 1` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WritePrometheus output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteSweep(t *testing.T) {
+	results := []harness.Result{
+		{Name: "queue/ring/size=64", N: 1, Samples: []time.Duration{54}},
+		{Name: "tick/batch/batch=100/interval=1ms", N: 1, Samples: []time.Duration{6}},
+		{Name: "cancel/std", N: 1, Samples: []time.Duration{11}},
+	}
+	var buf bytes.Buffer
+	if err := harness.WriteSweep(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		rows = append(rows, strings.Fields(line))
+	}
+	want := [][]string{
+		{"Variant", "size", "batch", "interval", "ns/op", "B/op", "allocs/op"},
+		{"queue/ring", "64", "-", "-", "54.00", "0", "0"},
+		{"tick/batch", "-", "100", "1ms", "6.00", "0", "0"},
+		{"cancel/std", "-", "-", "-", "11.00", "0", "0"},
+	}
+	if !slices.EqualFunc(rows, want, slices.Equal) {
+		t.Errorf("WriteSweep rows = %q, want %q", rows, want)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	var buf bytes.Buffer
+	p := harness.NewProgress(&buf, 2)
+	p.Start("queue/std")
+	p.Done()
+	p.Start("queue/ring")
+	p.Done()
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || lines[0] != "[1/2] queue/std" ||
+		!strings.HasPrefix(lines[1], "[2/2] queue/ring (") || !strings.Contains(lines[1], "left)") ||
+		!strings.HasPrefix(lines[2], "[2/2] done in ") {
+		t.Errorf("Progress output:\n%s", buf.String())
+	}
+
+	var nilProgress *harness.Progress
+	nilProgress.Start("x") // must not panic
+	nilProgress.Done()
+}
+
+func TestColor(t *testing.T) {
+	// /dev/null is a character device, so it passes for a terminal
+	tty, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer tty.Close()
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+
+	c := harness.NewColor(tty, false)
+	if got, want := c.Change("+5.00%", 5), "\033[31m+5.00%\033[0m"; got != want {
+		t.Errorf("Change(+5%%) = %q, want %q", got, want)
+	}
+	if got, want := c.Change("-5.00%", -5), "\033[32m-5.00%\033[0m"; got != want {
+		t.Errorf("Change(-5%%) = %q, want %q", got, want)
+	}
+	if got := c.Speedup("1.00x", 1); got != "1.00x" {
+		t.Errorf("Speedup(1x) = %q, want it unstyled", got)
+	}
+	if got, want := c.Winner("ring"), "\033[1mring\033[0m"; got != want {
+		t.Errorf("Winner = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	for name, c := range map[string]harness.Color{
+		"buffer":    harness.NewColor(&buf, false),
+		"-no-color": harness.NewColor(tty, true),
+		"zero":      {},
+	} {
+		if got := c.Speedup("2.00x", 2) + c.Winner("ring"); got != "2.00xring" {
+			t.Errorf("%s: styled output %q, want none", name, got)
+		}
+	}
+	t.Setenv("NO_COLOR", "1")
+	if got := harness.NewColor(tty, false).Change("+5.00%", 5); got != "+5.00%" {
+		t.Errorf("with NO_COLOR set: Change = %q, want it unstyled", got)
+	}
+}
+
+func TestFastest(t *testing.T) {
+	results := []harness.Result{
+		{Name: "std", N: 1, Samples: []time.Duration{30}},
+		{Name: "skipped", N: 1},
+		{Name: "ring", N: 1, Samples: []time.Duration{10}},
+	}
+	if got := harness.Fastest(results); got != 2 {
+		t.Errorf("Fastest = %d, want 2", got)
+	}
+	if got := harness.Fastest(results[:2]); got != -1 {
+		t.Errorf("Fastest of one measured result = %d, want -1", got)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	got := harness.Partial([]harness.Result{
+		{Name: "std", N: 1, Samples: []time.Duration{30}},
+		{Name: "skipped", N: 1},
+	})
+	if len(got) != 1 || got[0].Name != "std" || !got[0].Partial {
+		t.Errorf("Partial = %+v, want only std, marked partial", got)
+	}
+
+	var buf bytes.Buffer
+	if err := harness.WriteGoBench(&buf, "Ticker", harness.Machine{GOMAXPROCS: 1}, got); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "partial: true\n") {
+		t.Errorf("gobench output doesn't start with the partial config line:\n%s", buf.String())
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "base.json")
+	base := []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{98, 99, 100, 101, 102}},
+		{Name: "Atomic", N: 1, Samples: []time.Duration{10, 10, 10, 10, 10}},
+	}
+	if err := harness.SaveBaseline(path, harness.NewBaseline("Context", harness.Machine{}, base)); err != nil {
+		t.Fatal(err)
+	}
+	bs, err := harness.OpenBaselines("", path, "5%", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{113, 114, 115, 116, 117}, AllocsPerOp: 1},
+		{Name: "Atomic", N: 1, Samples: []time.Duration{10}},
+		{Name: "Batch <1000>", N: 1, Samples: []time.Duration{5}, Seed: 42},
+	}
+	regressed := bs.Regressions("Context", results)
+	if len(regressed) != 2 || regressed[0].String() != "Context ns/op +15.00%" || regressed[1].String() != "Context allocs/op 0 -> 1" {
+		t.Fatalf("Regressions = %v, want Context's ns/op and allocs/op", regressed)
+	}
+
+	var buf bytes.Buffer
+	if err := harness.WriteJUnit(&buf, "Context", harness.Machine{Hostname: "ci-1", GOOS: "linux", GOMAXPROCS: 8}, results, regressed); err != nil {
+		t.Fatal(err)
+	}
+	type property struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	}
+	var doc struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Name       string     `xml:"name,attr"`
+			Hostname   string     `xml:"hostname,attr"`
+			Properties []property `xml:"properties>property"`
+			Cases      []struct {
+				Name    string `xml:"name,attr"`
+				Time    string `xml:"time,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+				Properties []property `xml:"properties>property"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 3 || doc.Failures != 1 || len(doc.Suites) != 1 || len(doc.Suites[0].Cases) != 3 {
+		t.Fatalf("JUnit report:\n%s", buf.String())
+	}
+	suite := doc.Suites[0]
+	if suite.Name != "Context" || suite.Hostname != "ci-1" || !slices.Contains(suite.Properties, property{"gomaxprocs", "8"}) {
+		t.Errorf("testsuite %s on %s, properties %v", suite.Name, suite.Hostname, suite.Properties)
+	}
+	ctx, batch := suite.Cases[0], suite.Cases[2]
+	if ctx.Failure == nil || ctx.Failure.Message != "ns/op +15.00%, allocs/op 0 -> 1 against the baseline" || ctx.Time != "0.000001" {
+		t.Errorf("Context case: failure %+v, time %s; want ns/op and allocs/op regressions", ctx.Failure, ctx.Time)
+	}
+	if suite.Cases[1].Failure != nil {
+		t.Errorf("Atomic case failed: %+v", suite.Cases[1].Failure)
+	}
+	if batch.Name != "Batch <1000>" || batch.Properties[0].Name != "ns_per_op" || batch.Properties[0].Value != "5.00" ||
+		batch.Properties[len(batch.Properties)-1].Name != "seed" {
+		t.Errorf("Batch case: %+v", batch)
+	}
+
+	// Through Write, nothing fails
+	buf.Reset()
+	if err := harness.Write(&buf, harness.FormatJUnit, "Context", results); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) || !strings.Contains(buf.String(), `failures="0"`) || strings.Contains(buf.String(), "<failure") {
+		t.Errorf("Write(junit):\n%s", buf.String())
+	}
+}
+
+func TestWriteBenchmarkAction(t *testing.T) {
+	results := []harness.Result{
+		{Name: "Std", N: 100, Samples: []time.Duration{1000, 1200, 1100}, BytesPerOp: 16, AllocsPerOp: 1},
+		{Name: "TSC", N: 100, Samples: []time.Duration{500}, Cycles: []uint64{1500}, Partial: true},
+	}
+	var buf bytes.Buffer
+	if err := harness.WriteBenchmarkAction(&buf, "Ticker", harness.Machine{CPUModel: "Xeon", GoVersion: "go1.25"}, results); err != nil {
+		t.Fatal(err)
+	}
+	var entries []struct {
+		Name  string  `json:"name"`
+		Unit  string  `json:"unit"`
+		Value float64 `json:"value"`
+		Range string  `json:"range"`
+		Extra string  `json:"extra"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	want := []string{
+		"Ticker/Std - ns/op", "Ticker/Std - B/op", "Ticker/Std - allocs/op",
+		"Ticker/TSC - ns/op", "Ticker/TSC - B/op", "Ticker/TSC - allocs/op", "Ticker/TSC - cycles/op",
+	}
+	if !slices.Equal(names, want) {
+		t.Fatalf("entries %v, want %v", names, want)
+	}
+	if e := entries[0]; e.Unit != "ns/op" || e.Value != 11 || e.Range != "± 1.00" || e.Extra != "n=100 count=3\ncpu: Xeon\ngo: go1.25" {
+		t.Errorf("Std ns/op entry = %+v", e)
+	}
+	if e := entries[1]; e.Unit != "B/op" || e.Value != 16 || e.Range != "" {
+		t.Errorf("Std B/op entry = %+v", e)
+	}
+	if e := entries[3]; e.Range != "" {
+		t.Errorf("TSC ns/op entry from one run has range %q", e.Range)
+	}
+	if e := entries[6]; e.Value != 15 || !strings.Contains(e.Extra, "(partial)") {
+		t.Errorf("TSC cycles/op entry = %+v", e)
+	}
+
+	buf.Reset()
+	if err := harness.Write(&buf, harness.FormatBenchmarkAction, "Ticker", nil); err != nil || buf.String() != "[]\n" {
+		t.Errorf("Write(benchmark-action) of no results = %q, %v; want an empty array", buf.String(), err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	m := harness.Machine{Hostname: "soak-1"}
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	std := []harness.Result{{Name: "tick/std", N: 10, Samples: []time.Duration{1000}}}
+	atomic := []harness.Result{{Name: "tick/atomic", N: 10, Samples: []time.Duration{200}}}
+
+	var buf bytes.Buffer
+	s, err := harness.NewStream(&buf, harness.FormatCSV, "Scenario")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, rs := range [][]harness.Result{std, atomic, std} {
+		if err := s.Write(i/2+1, at, m, rs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || !slices.Equal(rows[0][:3], []string{"time", "round", "benchmark"}) ||
+		!slices.Equal(rows[3][:4], []string{"2026-10-16T12:00:00Z", "2", "Scenario", "tick/std"}) || rows[2][9] != "20.00" {
+		t.Errorf("csv stream, a header once then a row per result:\n%v", rows)
+	}
+
+	buf.Reset()
+	if s, err = harness.NewStream(&buf, harness.FormatJSON, "Scenario"); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Write(1, at, m, std)
+	_ = s.Write(1, at, m, atomic)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	var rec harness.StreamRecord
+	if len(lines) != 2 {
+		t.Fatalf("json stream wrote %d lines, want one per result:\n%s", len(lines), buf.String())
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Round != 1 || !rec.Time.Equal(at) || rec.Hostname != "soak-1" || rec.Variant != "tick/atomic" || rec.NsPerOp != 20 {
+		t.Errorf("json stream record = %+v", rec)
+	}
+
+	buf.Reset()
+	if s, err = harness.NewStream(&buf, harness.FormatText, "Scenario"); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Write(1, at, m, std)
+	if !strings.Contains(buf.String(), "tick/std") || !strings.Contains(buf.String(), "100.00 ns/op") {
+		t.Errorf("text stream wrote %q", buf.String())
+	}
+
+	if _, err := harness.NewStream(&buf, harness.FormatGoBench, "Scenario"); !errors.Is(err, harness.ErrUnknownFormat) {
+		t.Errorf("NewStream(gobench) error = %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestWrite_Text(t *testing.T) {
+	var buf bytes.Buffer
+	if err := harness.Write(&buf, harness.FormatText, "Ticker", nil); !errors.Is(err, harness.ErrUnknownFormat) {
+		t.Errorf("Write(text) error = %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestCurrentMachine(t *testing.T) {
+	m := harness.CurrentMachine()
+	if m.GOOS != runtime.GOOS || m.GOARCH != runtime.GOARCH || m.NumCPU < 1 || m.GoVersion == "" {
+		t.Errorf("CurrentMachine = %+v", m)
+	}
+	if m.Cores > 0 && (m.Sockets < 1 || m.ThreadsPerCore < 1 || m.Cores*m.ThreadsPerCore > m.NumCPU) {
+		t.Errorf("CurrentMachine topology = %d sockets, %d cores, %d threads per core, %d CPUs",
+			m.Sockets, m.Cores, m.ThreadsPerCore, m.NumCPU)
+	}
+	if s := m.String(); !strings.Contains(s, m.GoVersion) {
+		t.Errorf("Machine.String() = %q, want the Go version in it", s)
+	}
+}
+
+func TestIterations(t *testing.T) {
+	if got := harness.Iterations(1234, 0, nil); got != 1234 {
+		t.Errorf("Iterations without -time = %d, want -n", got)
+	}
+
+	var runs []int
+	loop := func(n int) time.Duration {
+		runs = append(runs, n)
+		return time.Duration(n) * 10 * time.Nanosecond
+	}
+	got := harness.Iterations(1234, 100*time.Millisecond, loop)
+	if got != 10_000_000 {
+		t.Errorf("Iterations(100ms) at 10ns/op = %d, want 10000000 (runs %v)", got, runs)
+	}
+	for i := 1; i < len(runs); i++ {
+		if runs[i] > 100*runs[i-1] {
+			t.Errorf("runs grew more than 100x: %v", runs)
+		}
+	}
+	if last := runs[len(runs)-1]; last > got {
+		t.Errorf("calibration ran %d iterations, more than the %d it chose", last, got)
+	}
+}
+
+func TestRunLength(t *testing.T) {
+	if got := harness.RunLength(1000, 0); got != "1000 iterations" {
+		t.Errorf("RunLength(1000, 0) = %q", got)
+	}
+	if got := harness.RunLength(1000, 2*time.Second); got != "about 2s per variant" {
+		t.Errorf("RunLength(1000, 2s) = %q", got)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "base.json")
+	old := []harness.Result{
+		{Name: "Context", N: 10, Samples: []time.Duration{100, 120, 110}},
+		{Name: "Atomic", N: 10, Samples: []time.Duration{5}},
+	}
+	m := harness.Machine{Hostname: "lab1"}
+	if err := harness.SaveBaseline(path, harness.NewBaseline("Context", m, old)); err != nil {
+		t.Fatal(err)
+	}
+	base, err := harness.LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.Machine.Hostname != "lab1" || len(base.Results) != 2 || base.Results[0].NsPerOp != 11 {
+		t.Errorf("LoadBaseline = %+v", base)
+	}
+
+	cur := []harness.Result{
+		{Name: "Context", N: 10, Samples: []time.Duration{99}},
+		{Name: "Batch", N: 10, Samples: []time.Duration{20}},
+	}
+	deltas := base.Compare("Context", cur)
+	if d := deltas[0]; !d.Found || d.Old != 11 || math.Abs(d.Percent()-(-10)) > 1e-9 {
+		t.Errorf("Context delta = %+v (%.2f%%), want 11 -> 9.9, -10%%", d, d.Percent())
+	}
+	if d := deltas[1]; d.Found || !math.IsNaN(d.Percent()) {
+		t.Errorf("Batch delta = %+v, want not found", d)
+	}
+	if deltas := base.Compare("Ticker", cur); deltas[0].Found {
+		t.Error("Compare matched a variant from another benchmark")
+	}
+
+	if _, err := harness.OpenBaselines("", filepath.Join(t.TempDir(), "missing.json"), "", 1); err == nil {
+		t.Error("OpenBaselines with a missing -compare file succeeded")
+	}
+	bs, err := harness.OpenBaselines("", path, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := bs.Apply(&buf, "Context", cur); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "-10.00%") || !strings.Contains(out, "(new)") || !strings.Contains(out, "(gone)") {
+		t.Errorf("Apply output:\n%s", out)
+	}
+}
+
+func TestLoadBaseline_SchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, doc string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// Written before versioning, and with a metric this build doesn't know
+	b, err := harness.LoadBaseline(write("v0.json", `{"machine": {"hostname": "lab1"},
+		"results": [{"benchmark": "Ticker", "variant": "Std", "ns_per_op": 12.5, "cycles_per_op": 40}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.SchemaVersion != 1 || len(b.Results) != 1 || b.Results[0].NsPerOp != 12.5 {
+		t.Errorf("unversioned baseline loaded as %+v, want version 1 with its result", b)
+	}
+
+	_, err = harness.LoadBaseline(write("next.json", `{"schema_version": 99, "results": []}`))
+	if !errors.Is(err, harness.ErrSchemaVersion) {
+		t.Errorf("LoadBaseline of version 99 error = %v, want ErrSchemaVersion", err)
+	}
+
+	var buf bytes.Buffer
+	if err := harness.WriteJSON(&buf, "Ticker", harness.Machine{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"schema_version": `+strconv.Itoa(harness.SchemaVersion)) {
+		t.Errorf("WriteJSON output has no schema_version:\n%s", buf.String())
+	}
+}
+
+func TestParseThreshold(t *testing.T) {
+	for spec, want := range map[string]float64{"5%": 5, "2.5%": 2.5, "10": 10, "0%": 0} {
+		if got, err := harness.ParseThreshold(spec); err != nil || got != want {
+			t.Errorf("ParseThreshold(%q) = %v, %v; want %v", spec, got, err, want)
+		}
+	}
+	for _, spec := range []string{"", "-5%", "five", "NaN%"} {
+		if _, err := harness.ParseThreshold(spec); !errors.Is(err, harness.ErrInvalidThreshold) {
+			t.Errorf("ParseThreshold(%q) error = %v, want ErrInvalidThreshold", spec, err)
+		}
+	}
+}
+
+func TestBaselines_FailOnRegression(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "base.json")
+	base := []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{98, 99, 100, 101, 102}, BytesPerOp: 100},
+		{Name: "Atomic", N: 1, Samples: []time.Duration{10, 10, 10, 10, 10}},
+	}
+	if err := harness.SaveBaseline(path, harness.NewBaseline("Context", harness.Machine{}, base)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := harness.OpenBaselines("", "", "5%", 5); !errors.Is(err, harness.ErrInvalidThreshold) {
+		t.Errorf("-fail-on-regression without -compare: error = %v", err)
+	}
+	// At the default -count 1, no ns/op change could be significant
+	if _, err := harness.OpenBaselines("", path, "5%", 1); !errors.Is(err, harness.ErrInvalidThreshold) {
+		t.Errorf("-fail-on-regression at -count 1: error = %v, want ErrInvalidThreshold", err)
+	}
+	short := filepath.Join(t.TempDir(), "short.json")
+	if err := harness.SaveBaseline(short, harness.NewBaseline("Context", harness.Machine{}, []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{100}},
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := harness.OpenBaselines("", short, "5%", 5); !errors.Is(err, harness.ErrInvalidThreshold) {
+		t.Errorf("-fail-on-regression against a one-run baseline: error = %v, want ErrInvalidThreshold", err)
+	}
+
+	bs, err := harness.OpenBaselines("", path, "5%", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	within := []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{103, 104, 105, 106, 107}, BytesPerOp: 105},
+		{Name: "Atomic", N: 1, Samples: []time.Duration{5}},
+		{Name: "Batch", N: 1, Samples: []time.Duration{1000}},
+	}
+	if err := bs.Apply(io.Discard, "Context", within); err != nil {
+		t.Errorf("Apply within threshold: %v", err)
+	}
+	// Median over the threshold, but the runs overlap the baseline's
+	noisy := []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{90, 95, 120, 125, 130}},
+		{Name: "Atomic", N: 1, Samples: []time.Duration{10, 10, 10, 10, 10}},
+	}
+	if err := bs.Apply(io.Discard, "Context", noisy); err != nil {
+		t.Errorf("Apply over threshold without significance: %v", err)
+	}
+	// Too few runs left to test, as after dropping outliers: the
+	// threshold alone decides
+	few := []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{900}},
+	}
+	err = bs.Apply(io.Discard, "Context", few)
+	if !errors.Is(err, harness.ErrRegression) || !strings.Contains(err.Error(), "Context ns/op +800.00%") {
+		t.Errorf("Apply over threshold with one run: error = %v, want ErrRegression naming Context", err)
+	}
+	slower := []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{104, 105, 106, 107, 108}, BytesPerOp: 100},
+		{Name: "Atomic", N: 1, Samples: []time.Duration{10}},
+	}
+	err = bs.Apply(io.Discard, "Context", slower)
+	if !errors.Is(err, harness.ErrRegression) || !strings.Contains(err.Error(), "Context ns/op +6.00%") {
+		t.Errorf("Apply over threshold: error = %v, want ErrRegression naming Context", err)
+	}
+	bigger := []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{100}, BytesPerOp: 200},
+		{Name: "Atomic", N: 1, Samples: []time.Duration{10}, AllocsPerOp: 1},
+	}
+	err = bs.Apply(io.Discard, "Context", bigger)
+	if !errors.Is(err, harness.ErrRegression) || !strings.Contains(err.Error(), "Context B/op +100.00%, Atomic allocs/op 0 -> 1") {
+		t.Errorf("Apply with more allocation: error = %v, want ErrRegression naming Context's B/op and Atomic's allocs/op", err)
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	old := harness.NewBaseline("Context", harness.Machine{}, []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{100, 101, 102, 103, 104}, AllocsPerOp: 1},
+		{Name: "Atomic", N: 1, Samples: []time.Duration{10, 12, 14, 16, 18}},
+	})
+	cur := harness.NewBaseline("Context", harness.Machine{}, []harness.Result{
+		{Name: "Context", N: 1, Samples: []time.Duration{200, 201, 202, 203, 204}, AllocsPerOp: 1},
+		{Name: "Atomic", N: 1, Samples: []time.Duration{11, 13, 15, 17, 19}},
+	})
+	var buf bytes.Buffer
+	if err := harness.WriteDiff(&buf, harness.Color{}, old, cur, harness.DefaultAlpha); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"+98.04%   (p=0.008 n=5+5)",
+		"+7.14% ~ (p=0.690 n=5+5)",
+		"geomean",
+		"allocs/op",
+		"+0.00% ~\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteDiff output lacks %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "Variant"); n != 2 {
+		t.Errorf("WriteDiff wrote %d tables, want ns/op and allocs/op but no B/op of zeros:\n%s", n, out)
+	}
+}
+
+func TestAddRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.db")
+	if _, err := harness.ReadRuns(path); err == nil {
+		t.Error("ReadRuns of a missing database succeeded")
+	}
+
+	bs := harness.Baselines{DB: path}
+	for _, ns := range []time.Duration{1000, 900} {
+		results := []harness.Result{{Name: "Atomic", N: 100, Samples: []time.Duration{ns}}}
+		if err := bs.Apply(io.Discard, "Context", results); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runs, err := harness.ReadRuns(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ID != 1 || runs[1].ID != 2 {
+		t.Fatalf("ReadRuns = %+v, want runs 1 and 2", runs)
+	}
+	if runs[0].Time.IsZero() || runs[1].Machine.GOOS != runtime.GOOS {
+		t.Errorf("run 2 = %+v, want a time and this machine", runs[1])
+	}
+	deltas := runs[0].Diff(runs[1].Baseline)
+	if len(deltas) != 1 || deltas[0].Benchmark != "Context" || deltas[0].Old != 10 || deltas[0].New != 9 {
+		t.Errorf("Diff = %+v, want Context/Atomic 10 -> 9", deltas)
+	}
+}
+
+func TestMachine_Warnings(t *testing.T) {
+	tests := []struct {
+		m    harness.Machine
+		want int
+	}{
+		{harness.Machine{}, 0},
+		{harness.Machine{Governor: "performance", Turbo: "off"}, 0},
+		{harness.Machine{Governor: "powersave", Turbo: "off"}, 1},
+		{harness.Machine{Governor: "performance", Turbo: "on"}, 1},
+		{harness.Machine{Governor: "schedutil", Turbo: "on"}, 2},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Warnings(); len(got) != tt.want {
+			t.Errorf("%+v: Warnings = %q, want %d", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestCheckMachine(t *testing.T) {
+	var buf bytes.Buffer
+	if err := harness.CheckMachine(&buf, harness.Machine{Governor: "performance"}, true); err != nil || buf.Len() != 0 {
+		t.Errorf("stable machine: err = %v, output %q", err, buf.String())
+	}
+
+	unstable := harness.Machine{Governor: "powersave", Turbo: "on"}
+	if err := harness.CheckMachine(&buf, unstable, false); err != nil {
+		t.Errorf("non-strict: err = %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "powersave") || !strings.Contains(out, "turbo") {
+		t.Errorf("warning output:\n%s", out)
+	}
+	if err := harness.CheckMachine(io.Discard, unstable, true); !errors.Is(err, harness.ErrUnstableMachine) {
+		t.Errorf("strict: err = %v, want ErrUnstableMachine", err)
+	}
+}
+
+func TestCheckFingerprint(t *testing.T) {
+	old := harness.Machine{Hostname: "lab1", CPUModel: "Test CPU", Governor: "performance", GoVersion: "go1.25.4", GOMAXPROCS: 8, Kernel: "6.1.0"}
+	same := old
+	same.Hostname, same.Kernel = "lab2", "6.8.0" // Not fingerprinted
+	var buf bytes.Buffer
+	if !harness.CheckFingerprint(&buf, old, same) || buf.Len() != 0 || old.Fingerprint() != same.Fingerprint() {
+		t.Errorf("same environment: fingerprints %s, %s, output %q", old.Fingerprint(), same.Fingerprint(), buf.String())
+	}
+
+	moved := same
+	moved.GoVersion, moved.Governor = "go1.26.0", ""
+	if harness.CheckFingerprint(&buf, old, moved) {
+		t.Error("CheckFingerprint matched a different Go version and governor")
+	}
+	out := buf.String()
+	for _, want := range []string{old.Fingerprint(), moved.Fingerprint(), "Go version: go1.25.4 -> go1.26.0", "governor: performance -> unknown"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("warning lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "CPU model") || strings.Contains(out, "GOMAXPROCS") {
+		t.Errorf("warning names unchanged fields:\n%s", out)
+	}
+}
+
+func TestCheckThrottling(t *testing.T) {
+	steady := harness.Result{Name: "Atomic", MinMHz: 3500, MaxMHz: 3600}
+	throttled := harness.Result{Name: "Context", MinMHz: 2400, MaxMHz: 3600}
+	unknown := harness.Result{Name: "VM"}
+	if got := throttled.FreqDrop(); math.Abs(got-1.0/3) > 1e-9 {
+		t.Errorf("FreqDrop = %v, want 1/3", got)
+	}
+	if got := unknown.FreqDrop(); got != 0 {
+		t.Errorf("FreqDrop without readings = %v, want 0", got)
+	}
+
+	var buf bytes.Buffer
+	if err := harness.CheckThrottling(&buf, []harness.Result{steady, unknown}); err != nil || buf.Len() != 0 {
+		t.Errorf("steady: err = %v, output %q", err, buf.String())
+	}
+	if err := harness.CheckThrottling(&buf, []harness.Result{steady, throttled}); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "Context: 3600 -> 2400 MHz (-33%)") || strings.Contains(out, "Atomic") {
+		t.Errorf("warning output:\n%s", out)
+	}
+
+	b := harness.NewBaseline("Context", harness.Machine{}, []harness.Result{throttled})
+	if br := b.Results[0]; br.MinMHz != 2400 || br.MaxMHz != 3600 {
+		t.Errorf("baseline frequencies = %d-%d MHz, want 2400-3600", br.MinMHz, br.MaxMHz)
+	}
+}
+
+var sinkBytes []byte
+
+func TestProfiler(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "prof")
+	p := &harness.Profiler{Bench: "Channel", CPUDir: dir, MemDir: dir, BlockDir: dir, MutexDir: dir}
+	r := p.Measure("Channel/P=4", 100, 2, func() time.Duration {
+		for i := 0; i < 100; i++ {
+			sinkBytes = make([]byte, 64)
+		}
+		return time.Microsecond
+	})
+	if r.Name != "Channel/P=4" || r.N != 100 || len(r.Samples) != 2 {
+		t.Errorf("Measure = %+v", r)
+	}
+	if r.AllocsPerOp != 1 || r.BytesPerOp != 64 {
+		t.Errorf("Measure counted %s, want 64 B/op, 1 allocs/op", r.MemPerOp())
+	}
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"cpu.pprof", "mem.base.pprof", "mem.pprof", "block.base.pprof", "block.pprof", "mutex.base.pprof", "mutex.pprof"} {
+		if _, err := os.Stat(filepath.Join(dir, "Channel_Channel_P=4."+name)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	var none *harness.Profiler
+	if got := none.Measure("x", 1, 3, func() time.Duration { return 1 }); len(got.Samples) != 3 || none.Err() != nil {
+		t.Errorf("nil Profiler: %+v, %v", got, none.Err())
+	}
+}
+
+func TestProfiler_Cycles(t *testing.T) {
+	if err := harness.CheckCycles(true); err != nil {
+		if !errors.Is(err, harness.ErrNoTSC) {
+			t.Fatalf("CheckCycles(true) error = %v, want ErrNoTSC", err)
+		}
+		t.Skip(err)
+	}
+	p := &harness.Profiler{Cycles: true}
+	r := p.Measure("x", 10, 3, func() time.Duration {
+		time.Sleep(time.Millisecond)
+		return time.Millisecond
+	})
+	if len(r.Cycles) != 3 || r.CyclesPerOp() <= 0 {
+		t.Errorf("Measure with Cycles counted %v", r.Cycles)
+	}
+	shuffled := p.MeasureAll([]harness.Variant{{Name: "a", N: 1, Timed: func() time.Duration { return 1 }}}, 2, harness.Shuffle{On: true})
+	if len(shuffled[0].Cycles) != 2 {
+		t.Errorf("MeasureAll with -shuffle counted %v, want a count per sample", shuffled[0].Cycles)
+	}
+	if got := (&harness.Profiler{}).Measure("x", 1, 2, func() time.Duration { return 1 }); got.Cycles != nil {
+		t.Errorf("Measure without Cycles counted %v", got.Cycles)
+	}
+}
+
+func TestParseShuffle(t *testing.T) {
+	for spec, want := range map[string]harness.Shuffle{
+		"":    {},
+		"off": {},
+		"42":  {On: true, Seed: 42},
+		"-7":  {On: true, Seed: -7},
+	} {
+		got, err := harness.ParseShuffle(spec)
+		if err != nil || got != want {
+			t.Errorf("ParseShuffle(%q) = %+v, %v; want %+v", spec, got, err, want)
+		}
+	}
+	if s, err := harness.ParseShuffle("on"); err != nil || !s.On {
+		t.Errorf("ParseShuffle(\"on\") = %+v, %v", s, err)
+	}
+	if _, err := harness.ParseShuffle("sometimes"); !errors.Is(err, harness.ErrInvalidShuffle) {
+		t.Errorf("ParseShuffle(\"sometimes\") error = %v, want ErrInvalidShuffle", err)
+	}
+	on := harness.Shuffle{On: true, Seed: 1}
+	if err := harness.CheckShuffle(on, &harness.Profiler{FoldedDir: "x"}); !errors.Is(err, harness.ErrShuffleProfile) {
+		t.Errorf("CheckShuffle with -folded-dir = %v, want ErrShuffleProfile", err)
+	}
+	if err := harness.CheckShuffle(on, &harness.Profiler{}); err != nil {
+		t.Errorf("CheckShuffle without profiles = %v", err)
+	}
+}
+
+func TestProfiler_MeasureAll(t *testing.T) {
+	var order []string
+	variants := make([]harness.Variant, 3)
+	for i, name := range []string{"a", "b", "c"} {
+		variants[i] = harness.Variant{Name: name, N: 100, Timed: func() time.Duration {
+			order = append(order, name)
+			if name == "b" {
+				for j := 0; j < 100; j++ {
+					sinkBytes = make([]byte, 64)
+				}
+			}
+			return time.Microsecond
+		}}
+	}
+
+	var p *harness.Profiler
+	rs := p.MeasureAll(variants, 2, harness.Shuffle{})
+	if got := strings.Join(order, ""); got != "aabbcc" {
+		t.Errorf("unshuffled order = %s, want aabbcc", got)
+	}
+
+	measure := func(seed int64) string {
+		order = nil
+		rs = p.MeasureAll(variants, 4, harness.Shuffle{On: true, Seed: seed})
+		return strings.Join(order, "")
+	}
+	got := measure(1)
+	for i := 0; i < len(got); i += 3 {
+		if rep := []byte(got[i : i+3]); !slices.Contains(rep, 'a') || !slices.Contains(rep, 'b') || !slices.Contains(rep, 'c') {
+			t.Errorf("repetition %d ran %s, want each variant once", i/3, rep)
+		}
+	}
+	if again := measure(1); again != got {
+		t.Errorf("seed 1 ran %s, then %s", got, again)
+	}
+	for i, r := range rs {
+		if r.Name != variants[i].Name || r.N != 100 || len(r.Samples) != 4 {
+			t.Errorf("result %d = %+v", i, r)
+		}
+	}
+	if rs[0].AllocsPerOp != 0 || rs[1].AllocsPerOp != 1 || rs[1].BytesPerOp != 64 {
+		t.Errorf("allocations: a %s, b %s; want b alone at 64 B/op, 1 allocs/op", rs[0].MemPerOp(), rs[1].MemPerOp())
+	}
+}
+
+func TestParseGC(t *testing.T) {
+	tests := []struct {
+		gogc, ballast string
+		off           bool
+		want          string
+	}{
+		{"", "", false, "default"},
+		{"400", "", false, "GOGC=400"},
+		{"off", "1gb", false, "GOGC=off, 1GB ballast"},
+		{"", "256MB", true, "256MB ballast, off during timed runs"},
+		{"", "1536KB", false, "1536KB ballast"},
+		{"", "100", false, "100B ballast"},
+	}
+	for _, tt := range tests {
+		g, err := harness.ParseGC(tt.gogc, tt.ballast, tt.off)
+		if err != nil || g.String() != tt.want {
+			t.Errorf("ParseGC(%q, %q, %v) = %q, %v; want %q", tt.gogc, tt.ballast, tt.off, g, err, tt.want)
+		}
+		if g.IsZero() != (tt.want == "default") {
+			t.Errorf("ParseGC(%q, %q, %v).IsZero() = %v", tt.gogc, tt.ballast, tt.off, g.IsZero())
+		}
+	}
+	for _, bad := range [][2]string{{"-1", ""}, {"lots", ""}, {"", "1TB"}, {"", "-5MB"}, {"", "MB"}} {
+		if _, err := harness.ParseGC(bad[0], bad[1], false); !errors.Is(err, harness.ErrInvalidGC) {
+			t.Errorf("ParseGC(%q, %q) error = %v, want ErrInvalidGC", bad[0], bad[1], err)
+		}
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	for _, tc := range []struct {
+		nice, rt int
+		want     string
+	}{
+		{0, 0, "default"},
+		{-10, 0, "nice -10"},
+		{19, 0, "nice 19"},
+		{0, 50, "SCHED_FIFO 50"},
+		{-20, 99, "SCHED_FIFO 99, nice -20"},
+	} {
+		pr, err := harness.ParsePriority(tc.nice, tc.rt)
+		if err != nil {
+			t.Errorf("ParsePriority(%d, %d) error: %v", tc.nice, tc.rt, err)
+			continue
+		}
+		if got := pr.String(); got != tc.want {
+			t.Errorf("ParsePriority(%d, %d) = %q, want %q", tc.nice, tc.rt, got, tc.want)
+		}
+		if pr.IsZero() != (tc.want == "default") {
+			t.Errorf("ParsePriority(%d, %d).IsZero() = %v", tc.nice, tc.rt, pr.IsZero())
+		}
+	}
+	for _, bad := range [][2]int{{-21, 0}, {20, 0}, {0, -1}, {0, 100}} {
+		if _, err := harness.ParsePriority(bad[0], bad[1]); !errors.Is(err, harness.ErrInvalidPriority) {
+			t.Errorf("ParsePriority(%d, %d) error = %v, want ErrInvalidPriority", bad[0], bad[1], err)
+		}
+	}
+
+	var notes bytes.Buffer
+	if got := (harness.Priority{}).Apply(&notes); !got.IsZero() || notes.Len() != 0 {
+		t.Errorf("zero Priority Apply = %v, notes %q; want nothing done", got, notes.String())
+	}
+}
+
+func TestProfiler_GCOff(t *testing.T) {
+	before := debug.SetGCPercent(100)
+	defer debug.SetGCPercent(before)
+
+	var during []int
+	timed := func() time.Duration {
+		pct := debug.SetGCPercent(-1)
+		debug.SetGCPercent(pct)
+		during = append(during, pct)
+		return time.Microsecond
+	}
+	p := &harness.Profiler{GCOff: true}
+	r := p.Measure("x", 1, 2, timed)
+	rs := p.MeasureAll([]harness.Variant{{Name: "y", N: 1, Timed: timed}}, 1, harness.Shuffle{On: true})
+	if !slices.Equal(during, []int{-1, -1, -1}) {
+		t.Errorf("GOGC during timed runs = %v, want off for all three", during)
+	}
+	if r.GCs != 0 || rs[0].GCs != 0 {
+		t.Errorf("GCs = %d and %d, want the forced collections left out", r.GCs, rs[0].GCs)
+	}
+	if pct := debug.SetGCPercent(100); pct != 100 {
+		t.Errorf("GOGC after = %d, want 100 restored", pct)
+	}
+}
+
+func TestProfiler_Isolate(t *testing.T) {
+	// The children re-execute the test binary with its arguments; run only
+	// this test in them. Each reports its pid as its sample.
+	args := os.Args
+	os.Args = []string{args[0], "-test.run=^TestProfiler_Isolate$"}
+	defer func() { os.Args = args }()
+	pid := func() time.Duration { return time.Duration(os.Getpid()) }
+	p := &harness.Profiler{Isolate: true}
+	variant := func(name string) harness.Variant {
+		if !p.Runs(name) {
+			return harness.Variant{Name: name}
+		}
+		return harness.Variant{Name: name, N: 7, Timed: pid}
+	}
+
+	solo := variant("Solo")
+	rs := []harness.Result{p.Measure(solo.Name, solo.N, 2, solo.Timed)}
+	rs = append(rs, p.MeasureAll([]harness.Variant{variant("A"), variant("B")}, 2, harness.Shuffle{})...)
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+	seen := map[time.Duration]bool{pid(): true}
+	for i, r := range rs {
+		if r.Name != []string{"Solo", "A", "B"}[i] || r.N != 7 || len(r.Samples) != 2 || r.Samples[0] != r.Samples[1] {
+			t.Fatalf("result %d = %+v, want 2 samples of 7 iterations from one child", i, r)
+		}
+		if seen[r.Samples[0]] {
+			t.Errorf("%s ran in pid %d, not a child of its own", r.Name, r.Samples[0])
+		}
+		seen[r.Samples[0]] = true
+	}
+}
+
+func TestCheckIsolate(t *testing.T) {
+	p := &harness.Profiler{Isolate: true}
+	if err := harness.CheckIsolate(p, harness.Shuffle{}, ""); err != nil {
+		t.Errorf("-isolate alone: %v", err)
+	}
+	if err := harness.CheckIsolate(p, harness.Shuffle{On: true}, ""); !errors.Is(err, harness.ErrIsolate) {
+		t.Errorf("-isolate -shuffle: %v, want ErrIsolate", err)
+	}
+	if err := harness.CheckIsolate(p, harness.Shuffle{}, "trace.csv"); !errors.Is(err, harness.ErrIsolate) {
+		t.Errorf("-isolate -latency-trace: %v, want ErrIsolate", err)
+	}
+	if err := harness.CheckIsolate(&harness.Profiler{}, harness.Shuffle{On: true}, "trace.csv"); err != nil {
+		t.Errorf("no -isolate: %v", err)
+	}
+}
+
+func TestLatencyTrace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "latency.csv")
+	lt, err := harness.OpenLatencyTrace(path, "Ticker", "every=3", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	lt.Record("Std", 10, func() { calls++ })
+	if calls != 10 {
+		t.Errorf("every=3 made %d calls for n=10", calls)
+	}
+	if err := lt.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seqs []string
+	for _, row := range rows[1:] {
+		if row[0] != "Ticker" || row[1] != "Std" {
+			t.Errorf("row %v", row)
+		}
+		seqs = append(seqs, row[2])
+	}
+	if want := []string{"0", "3", "6", "9"}; !slices.Equal(seqs, want) {
+		t.Errorf("every=3 kept seqs %v, want %v", seqs, want)
+	}
+
+	// A reservoir keeps K operations in order, whichever they are
+	res := filepath.Join(t.TempDir(), "reservoir.csv")
+	lt, err = harness.OpenLatencyTrace(res, "Ticker", "reservoir=4", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lt.Record("Std", 1000, func() {})
+	if err := lt.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(res)
+	rows, _ = csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if len(rows) != 5 || !slices.IsSortedFunc(rows[1:], func(a, b []string) int {
+		x, _ := strconv.Atoi(a[2])
+		y, _ := strconv.Atoi(b[2])
+		return x - y
+	}) {
+		t.Errorf("reservoir=4 wrote:\n%s", data)
+	}
+
+	if lt, err := harness.OpenLatencyTrace("", "Ticker", "every=1", 1); lt != nil || err != nil {
+		t.Errorf("no path: %v, %v; want nil, nil", lt, err)
+	}
+	for _, spec := range []string{"", "every", "every=0", "reservoir=-1", "sometimes=5"} {
+		if _, err := harness.OpenLatencyTrace("", "Ticker", spec, 1); !errors.Is(err, harness.ErrInvalidLatencySample) {
+			t.Errorf("OpenLatencyTrace(%q) error = %v, want ErrInvalidLatencySample", spec, err)
+		}
+	}
+}
+
+func TestLatencyTrace_Passes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "latency.csv")
+	lt, err := harness.OpenLatencyTrace(path, "Ticker", "reservoir=5", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	lt.Record("Std", 100, func() { calls++ })
+	if err := lt.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 300 {
+		t.Errorf("3 passes made %d calls for n=100, want 300", calls)
+	}
+	data, _ := os.ReadFile(path)
+	rows, _ := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	passes := make(map[string]int)
+	for _, row := range rows[1:] {
+		passes[row[5]]++
+	}
+	if len(rows) != 16 || passes["0"] != 5 || passes["1"] != 5 || passes["2"] != 5 {
+		t.Errorf("reservoir=5 over 3 passes wrote:\n%s", data)
+	}
+
+	// The digest holds every operation timed, not only those kept
+	d := lt.Digest("Std")
+	if d == nil || d.Count() != 300 {
+		t.Fatalf("Digest(Std) = %v, want 300 latencies", d)
+	}
+	if lt.Digest("Other") != nil {
+		t.Error("Digest of an unrecorded variant is not nil")
+	}
+	var nilTrace *harness.LatencyTrace
+	if nilTrace.Digest("Std") != nil {
+		t.Error("nil trace has a digest")
+	}
+
+	// Baselines carry the percentiles and the digest
+	r := harness.Result{Name: "Std", N: 100, Samples: []time.Duration{time.Microsecond}, Latency: d}
+	var buf bytes.Buffer
+	if err := harness.WriteJSON(&buf, "Ticker", harness.Machine{}, []harness.Result{r}); err != nil {
+		t.Fatal(err)
+	}
+	var b harness.Baseline
+	if err := json.Unmarshal(buf.Bytes(), &b); err != nil {
+		t.Fatal(err)
+	}
+	l := b.Results[0].Latency
+	if l == nil || l.Digest.Count() != 300 || l.P50 != d.Quantile(0.5) || l.Max != d.Max() {
+		t.Errorf("JSON latency = %+v, want the digest's 300 latencies and percentiles", l)
+	}
+	buf.Reset()
+	if err := harness.WriteLatency(&buf, []harness.Result{r, {Name: "Untraced"}}); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "p99.9") || !strings.Contains(out, "Std") || strings.Contains(out, "Untraced") {
+		t.Errorf("WriteLatency wrote:\n%s", out)
+	}
+	r.Latency = nil
+	if got := harness.NewBaseline("Ticker", harness.Machine{}, []harness.Result{r}).Results[0].Latency; got != nil {
+		t.Errorf("untraced result has latency %+v", got)
+	}
+}
+
+func TestWriteFolded(t *testing.T) {
+	fn := func(name string) *profile.Function { return &profile.Function{Name: name} }
+	mainFn, run, tick, inlined := fn("main.main"), fn("main.run"), fn("tick.Tick"), fn("tick.now")
+	leaf := &profile.Location{Line: []profile.Line{{Function: inlined}, {Function: tick}}}
+	caller := &profile.Location{Line: []profile.Line{{Function: run}}}
+	root := &profile.Location{Line: []profile.Line{{Function: mainFn}}}
+	p := &profile.Profile{Sample: []*profile.Sample{
+		{Location: []*profile.Location{leaf, caller, root}, Value: []int64{3, 30}},
+		{Location: []*profile.Location{caller, root}, Value: []int64{1, 10}},
+		{Location: []*profile.Location{leaf, caller, root}, Value: []int64{2, 20}},
+	}}
+
+	var buf bytes.Buffer
+	if err := harness.WriteFolded(&buf, p); err != nil {
+		t.Fatal(err)
+	}
+	want := "main.main;main.run 1\nmain.main;main.run;tick.Tick;tick.now 5\n"
+	if buf.String() != want {
+		t.Errorf("WriteFolded:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestTopFunctions(t *testing.T) {
+	fn := func(name string) *profile.Function { return &profile.Function{Name: name} }
+	leaf := &profile.Location{Line: []profile.Line{{Function: fn("tick.now")}, {Function: fn("tick.Tick")}}}
+	caller := &profile.Location{Line: []profile.Line{{Function: fn("main.run")}}}
+	p := &profile.Profile{Sample: []*profile.Sample{
+		{Location: []*profile.Location{leaf, caller}, Value: []int64{3, 30}},
+		{Location: []*profile.Location{caller}, Value: []int64{1, 10}},
+		{Location: []*profile.Location{leaf, caller}, Value: []int64{4, 40}},
+	}}
+
+	top, total := harness.TopFunctions(p, 5)
+	want := []harness.FuncShare{{Name: "tick.now", Samples: 7, Percent: 87.5}, {Name: "main.run", Samples: 1, Percent: 12.5}}
+	if total != 8 || !slices.Equal(top, want) {
+		t.Errorf("TopFunctions = %+v, %d; want %+v, 8", top, total, want)
+	}
+	if top, _ := harness.TopFunctions(p, 1); len(top) != 1 || top[0].Name != "tick.now" {
+		t.Errorf("TopFunctions(1) = %+v, want only tick.now", top)
+	}
+}
+
+func TestParseWarmup(t *testing.T) {
+	tests := []struct {
+		spec string
+		want harness.Warmup
+	}{
+		{"", harness.Warmup{}},
+		{"0", harness.Warmup{}},
+		{"1000", harness.Warmup{N: 1000}},
+		{"200ms", harness.Warmup{D: 200 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		got, err := harness.ParseWarmup(tt.spec)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseWarmup(%q) = %+v, %v; want %+v", tt.spec, got, err, tt.want)
+		}
+	}
+	for _, spec := range []string{"-5", "-1s", "soon"} {
+		if _, err := harness.ParseWarmup(spec); !errors.Is(err, harness.ErrInvalidWarmup) {
+			t.Errorf("ParseWarmup(%q) error = %v, want ErrInvalidWarmup", spec, err)
+		}
+	}
+}
+
+func TestWarmup_Run(t *testing.T) {
+	calls := 0
+	harness.Warmup{N: 5}.Run(func() { calls++ })
+	if calls != 5 {
+		t.Errorf("N=5 warmup made %d calls", calls)
+	}
+
+	calls = 0
+	start := time.Now()
+	harness.Warmup{D: 10 * time.Millisecond}.Run(func() { calls++ })
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("D=10ms warmup returned after %v", elapsed)
+	}
+	if calls == 0 {
+		t.Error("duration warmup made no calls")
+	}
+
+	calls = 0
+	harness.Warmup{}.Run(func() { calls++ })
+	if calls != 0 {
+		t.Errorf("zero warmup made %d calls", calls)
+	}
+}
+
+func TestRunner_Parse(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	r := harness.NewRunner(fs, "Test", 1000, 1, 0)
+	if err := fs.Parse([]string{"-n", "50", "-count", "3", "-format", "csv", "-shuffle", "7", "-gogc", "200"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Parse(); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if r.N != 50 || r.Count != 3 || r.Format != harness.FormatCSV || !r.Shuffle.On || r.Shuffle.Seed != 7 || r.Prof.Bench != "Test" {
+		t.Errorf("Parse set %+v", r)
+	}
+	if got, want := r.Settings(), []string{"Shuffle: 7", "GC: GOGC=200"}; !slices.Equal(got, want) {
+		t.Errorf("Settings = %q, want %q", got, want)
+	}
+
+	base := filepath.Join(t.TempDir(), "base.json")
+	if err := harness.SaveBaseline(base, harness.NewBaseline("Test", harness.Machine{}, []harness.Result{
+		{Name: "Op", N: 1, Samples: []time.Duration{1, 2, 3, 4, 5}},
+	})); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args []string
+		want error
+	}{
+		{[]string{"-count", "0"}, harness.ErrInvalidCount},
+		{[]string{"-format", "xml"}, harness.ErrUnknownFormat},
+		{[]string{"-isolate", "-shuffle", "on"}, harness.ErrIsolate},
+		{[]string{"-latency-sample", "every=0"}, harness.ErrInvalidLatencySample},
+		{[]string{"-fail-on-regression", "5%"}, harness.ErrInvalidThreshold},
+		// The default -count 1 gives a gate that could never fail
+		{[]string{"-compare", base, "-fail-on-regression", "5%"}, harness.ErrInvalidThreshold},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		r := harness.NewRunner(fs, "Test", 1000, 1, 0)
+		if err := fs.Parse(tc.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Parse(); !errors.Is(err, tc.want) {
+			t.Errorf("Parse(%q) error = %v, want %v", tc.args, err, tc.want)
+		}
+	}
+}
+
+func TestRunner_Measure(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	r := harness.NewRunner(fs, "Test", 1000, 1, 0)
+	if err := fs.Parse([]string{"-n", "50", "-count", "3", "-warmup", "10", "-quiet"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	var ops int
+	op := func() { ops++ }
+	loop := func(n int) time.Duration {
+		for range n {
+			op()
+		}
+		return time.Duration(n) * time.Nanosecond
+	}
+	v := r.Variant("Counter", op, loop)
+	if ops != 10 || v.N != 50 || v.Op == nil {
+		t.Errorf("Variant: %d warmup ops, N = %d, Op set %t; want 10, 50, true", ops, v.N, v.Op != nil)
+	}
+	results, err := r.Measure([]harness.Variant{v})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Name != "Counter" || len(results[0].Samples) != 3 || results[0].NsPerOp() != 1 {
+		t.Errorf("Measure = %+v, want 3 samples of 1 ns/op", results)
+	}
+	if ops != 10+3*50 {
+		t.Errorf("ops = %d, want %d", ops, 10+3*50)
+	}
+}
+
+func TestReporter(t *testing.T) {
+	std := harness.Result{Name: "std", N: 1, Samples: []time.Duration{100}}
+	opt := harness.Result{Name: "opt", N: 1, Samples: []time.Duration{25}}
+	if got := harness.Speedup(std, opt); got != 4 {
+		t.Errorf("Speedup = %v, want 4", got)
+	}
+	if got := harness.Savings(std, opt, 1_000_000); math.Abs(got-0.075) > 1e-12 {
+		t.Errorf("Savings at 1M ops/s = %v, want 0.075", got)
+	}
+
+	var buf bytes.Buffer
+	rp := harness.Reporter{W: &buf}
+	rp.Header("Benchmarking test", "Shuffle: 7")
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 5 || lines[0] != "Benchmarking test" || !strings.HasPrefix(lines[1], "Machine: ") ||
+		lines[2] != "Shuffle: 7" || !strings.HasPrefix(lines[3], "───") {
+		t.Errorf("Header wrote:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	rp.Throughput([]string{"std:", "opt:"}, []harness.Result{std, opt})
+	rp.Impact(std, opt, 1_000_000)
+	for _, want := range []string{
+		"  std: 10.00 M ops/sec\n",
+		"  opt: 40.00 M ops/sec\n",
+		"  Savings per iteration: 75.00 ns\n",
+		"  At 1000K ops/sec: save 75.00 ms/sec (7.50% of 1 core)\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report is missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	rp.Summary(1, []harness.Result{std})
+	if buf.Len() != 0 {
+		t.Errorf("Summary of one run wrote %q, want nothing", buf.String())
+	}
+	if got := rp.Winners([]harness.Result{std, opt}, "std", "opt"); !slices.Equal(got, []string{"std", "opt"}) {
+		t.Errorf("Winners without color = %q", got)
+	}
+}
@@ -0,0 +1,62 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+)
+
+// ErrInterrupted is returned by Runner.Finish after reporting and saving
+// the results of a run that SIGINT or SIGTERM cut short.
+var ErrInterrupted = errors.New("harness: interrupted; results are partial")
+
+// interrupted is set once the run has been interrupted.
+var interrupted atomic.Bool
+
+// CatchInterrupts makes the first SIGINT or SIGTERM interrupt the run
+// rather than kill it: the variant being measured finishes, or with
+// -shuffle the repetition, the rest are skipped, and the results so far
+// are reported and saved, marked partial. It says so on w. A second
+// signal kills the process as usual.
+//
+// An -isolate child ignores both signals, which a terminal sends its
+// whole process group, so that it finishes its variant for the parent.
+func CatchInterrupts(w io.Writer) {
+	if _, ok := childTarget(); ok {
+		signal.Ignore(os.Interrupt, syscall.SIGTERM)
+		return
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-c
+		interrupted.Store(true)
+		signal.Reset(os.Interrupt, syscall.SIGTERM)
+		fmt.Fprintf(w, "\n%v: stopping after the current variant; interrupt again to quit now\n", sig)
+	}()
+}
+
+// Interrupted reports whether a signal caught by CatchInterrupts has
+// interrupted the run, for commands that measure in sections of their
+// own to stop between them.
+func Interrupted() bool {
+	return interrupted.Load()
+}
+
+// Partial returns the results of an interrupted run that were measured,
+// dropping the variants it skipped, each marked Partial.
+func Partial(results []Result) []Result {
+	var kept []Result
+	for _, r := range results {
+		if len(r.Samples) == 0 {
+			continue
+		}
+		r.Partial = true
+		kept = append(kept, r)
+	}
+	return kept
+}
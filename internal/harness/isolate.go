@@ -0,0 +1,143 @@
+package harness
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrIsolate is returned by CheckIsolate for flags -isolate can't be
+// combined with.
+var ErrIsolate = errors.New("harness: -isolate can't be combined with -shuffle or -latency-trace")
+
+// IsolateUsage is the help text for an -isolate flag.
+const IsolateUsage = "measure each variant in a fresh child process, so the heap, GC pacing and timers one leaves behind can't affect the next"
+
+// isolateEnv names the environment variable that makes a process an
+// -isolate child, set to "<call>/<variant>": measure variant in the
+// call'th Measure or MeasureAll call, counting from 0, and exit.
+const isolateEnv = "SOME_GO_BENCHMARKS_ISOLATE"
+
+// isolateTarget is what an -isolate child measures.
+type isolateTarget struct {
+	call int
+	name string
+}
+
+// childTarget returns this process's isolateTarget, and false if it isn't
+// an -isolate child.
+var childTarget = sync.OnceValues(func() (isolateTarget, bool) {
+	call, name, ok := strings.Cut(os.Getenv(isolateEnv), "/")
+	if !ok {
+		return isolateTarget{}, false
+	}
+	n, err := strconv.Atoi(call)
+	if err != nil {
+		return isolateTarget{}, false
+	}
+	return isolateTarget{n, name}, true
+})
+
+// CheckIsolate validates p's Isolate against the other flags: shuffled
+// runs interleave variants within one process, and every child would
+// write the same latency trace file.
+func CheckIsolate(p *Profiler, s Shuffle, latencyTrace string) error {
+	if p != nil && p.Isolate && (s.On || latencyTrace != "") {
+		return ErrIsolate
+	}
+	return nil
+}
+
+// Runs reports whether this process measures variant name in the next
+// Measure or MeasureAll call, so the cmd tools warm up and calibrate only
+// the variants that will run here. Without Isolate that is every variant.
+// With it, the parent process measures none itself, and each child only
+// its own.
+func (p *Profiler) Runs(name string) bool {
+	if p == nil {
+		return true
+	}
+	if t, ok := childTarget(); ok {
+		return p.calls == t.call && name == t.name
+	}
+	return !p.Isolate
+}
+
+// run measures variant name for the call'th Measure or MeasureAll call:
+// here; in a child process of its own if Isolate is set; or, in a child,
+// only if it is the child's variant, after which the child sends the
+// Result to its parent and exits. Variants a child skips come back with
+// no samples.
+func (p *Profiler) run(call int, name string, n, count int, timed func() time.Duration) Result {
+	if t, ok := childTarget(); ok && p != nil {
+		if call != t.call || name != t.name {
+			return Result{Name: name, N: n}
+		}
+		p.report(p.measure(name, n, count, timed))
+	}
+	if p != nil && p.Isolate {
+		return p.spawn(call, name)
+	}
+	return p.measure(name, n, count, timed)
+}
+
+// report writes r to the pipe the parent passed as file descriptor 3 and
+// exits: 0 on success, or 1 after writing any profiling error to stderr.
+func (p *Profiler) report(r Result) {
+	if err := p.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := json.NewEncoder(os.NewFile(3, "result")).Encode(r); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// spawn re-executes this program, with the same arguments, as a child
+// that measures variant name for the call'th call, and returns the
+// Result it sends back. The child's output is discarded unless it fails,
+// when its stderr becomes p's error.
+func (p *Profiler) spawn(call int, name string) Result {
+	r := Result{Name: name}
+	if p.err != nil {
+		return r
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		p.err = err
+		return r
+	}
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		p.err = err
+		return r
+	}
+	defer pr.Close()
+	var stderr bytes.Buffer
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d/%s", isolateEnv, call, name))
+	cmd.ExtraFiles = []*os.File{pw}
+	cmd.Stderr = &stderr
+	err = cmd.Start()
+	pw.Close()
+	if err != nil {
+		p.err = err
+		return r
+	}
+	decodeErr := json.NewDecoder(pr).Decode(&r)
+	if err := cmd.Wait(); err != nil {
+		p.err = fmt.Errorf("harness: isolated variant %s: %v\n%s", name, err, bytes.TrimSpace(stderr.Bytes()))
+	} else if decodeErr != nil {
+		p.err = fmt.Errorf("harness: isolated variant %s: reading its result: %v", name, decodeErr)
+	}
+	return r
+}
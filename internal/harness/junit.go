@@ -0,0 +1,149 @@
+package harness
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// junitSuites is the root of a JUnit XML report, in the schema Jenkins'
+// JUnit plugin and GitLab CI's test reports read.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+// junitSuite is one benchmark's results.
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Hostname   string          `xml:"hostname,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+// junitCase is one variant.
+type junitCase struct {
+	Classname  string          `xml:"classname,attr"`
+	Name       string          `xml:"name,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Failure    *junitFailure   `xml:"failure"`
+	SystemOut  string          `xml:"system-out"`
+}
+
+// junitProperty is a name and value, for the machine and each metric.
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// junitFailure is a variant's regression.
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes results as a JUnit XML report, for CI dashboards
+// that display test results: one testsuite for benchmark bench, with m as
+// its properties, and one testcase per result, named by variant, with its
+// metrics as properties and a one-line summary as its output. A
+// testcase's time is the total of its samples. Results whose variant has
+// a Delta in regressed, such as Baselines.Regressions returns, fail with
+// the change in each metric that regressed; the rest pass.
+func WriteJUnit(w io.Writer, bench string, m Machine, results []Result, regressed []Delta) error {
+	failed := make(map[string][]Delta, len(regressed))
+	for _, d := range regressed {
+		failed[d.Variant] = append(failed[d.Variant], d)
+	}
+	suite := junitSuite{Name: bench, Tests: len(results), Hostname: m.Hostname}
+	for _, kv := range [][2]string{
+		{"goos", m.GOOS},
+		{"goarch", m.GOARCH},
+		{"cpu", m.CPUModel},
+		{"kernel", m.Kernel},
+		{"governor", m.Governor},
+		{"turbo", m.Turbo},
+		{"go", m.GoVersion},
+		{"gomaxprocs", strconv.Itoa(m.GOMAXPROCS)},
+		{"fingerprint", m.Fingerprint()},
+		{"schema_version", strconv.Itoa(SchemaVersion)},
+	} {
+		if kv[1] != "" {
+			suite.Properties = append(suite.Properties, junitProperty{kv[0], kv[1]})
+		}
+	}
+
+	var total time.Duration
+	for _, r := range results {
+		var elapsed time.Duration
+		for _, s := range r.Samples {
+			elapsed += s
+		}
+		total += elapsed
+		st := r.Stats()
+		ns := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+		tc := junitCase{
+			Classname: bench,
+			Name:      r.Name,
+			Time:      seconds(elapsed),
+			Properties: []junitProperty{
+				{"ns_per_op", ns(st.Median)},
+				{"min_ns", ns(st.Min)},
+				{"max_ns", ns(st.Max)},
+				{"stddev_ns", ns(st.StdDev)},
+				{"bytes_per_op", strconv.FormatInt(r.BytesPerOp, 10)},
+				{"allocs_per_op", strconv.FormatInt(r.AllocsPerOp, 10)},
+				{"iterations", strconv.Itoa(r.N)},
+				{"count", strconv.Itoa(st.Count)},
+			},
+			SystemOut: fmt.Sprintf("%s: %.2f ns/op%s, %s (%d runs)", r.Name, st.Median, r.CyclesText(), r.MemPerOp(), st.Count),
+		}
+		for _, kv := range [][2]string{{"cycles_per_op", cyclesPerOp(r)}, {"seed", seed(r.Seed)}, {"partial", partial(r.Partial)}} {
+			if kv[1] != "" {
+				tc.Properties = append(tc.Properties, junitProperty{kv[0], kv[1]})
+			}
+		}
+		if ds, ok := failed[r.Name]; ok {
+			changes := make([]string, len(ds))
+			lines := make([]string, len(ds))
+			for i, d := range ds {
+				changes[i] = d.change()
+				lines[i] = fmt.Sprintf("%s: %.2f %s, against %.2f in the baseline (%s)", r.Name, d.New, d.Metric, d.Old, d.change())
+			}
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: strings.Join(changes, ", ") + " against the baseline",
+				Type:    "regression",
+				Text:    strings.Join(lines, "\n"),
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = seconds(total)
+
+	doc := junitSuites{Name: bench, Tests: suite.Tests, Failures: suite.Failures, Time: suite.Time, Suites: []junitSuite{suite}}
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, data)
+	return err
+}
+
+// seconds formats d in seconds, as JUnit times are.
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 6, 64)
+}
@@ -0,0 +1,241 @@
+package harness
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tdigest"
+)
+
+// ErrInvalidLatencySample is returned by OpenLatencyTrace for a malformed
+// sampling spec.
+var ErrInvalidLatencySample = errors.New("harness: latency sample must be every=N or reservoir=K with N, K >= 1")
+
+// LatencyTraceUsage and LatencySampleUsage are the help text for
+// -latency-trace and -latency-sample.
+const (
+	LatencyTraceUsage  = "time individual operations in extra untimed passes per variant, one per -count, and write them as CSV to this file"
+	LatencySampleUsage = "which operations -latency-trace keeps: every=N (every Nth) or reservoir=K (K chosen uniformly at random)"
+)
+
+// DefaultLatencySample is the default -latency-sample spec.
+const DefaultLatencySample = "reservoir=10000"
+
+// LatencyTrace records the latencies of individual operations, for
+// percentile and outlier analysis beyond the aggregate ns/op. Timing every
+// operation of a timed run would dominate what it measures, so each
+// Record makes passes of its own, one per repetition, after the
+// variant's timed runs, and writes one CSV row per sampled operation:
+//
+//	benchmark,variant,seq,start_unix_ns,latency_ns,pass
+//	Ticker,StdTicker,4711,1760630682123456789,38,0
+//
+// seq is the operation's index in its pass. The latency includes one
+// clock read, tens of nanoseconds, so for the fastest operations the
+// shape of the distribution says more than its floor.
+//
+// Every operation timed, sampled into the CSV or not, also goes into a
+// t-digest per variant (see Digest), which Runner.Finish attaches to the
+// results as their Latency, for percentiles that merge across passes,
+// runs and machines.
+//
+// A nil LatencyTrace records nothing. Errors are sticky, and Close
+// reports the first.
+type LatencyTrace struct {
+	bench     string
+	every     int // Time every Nth operation; 0 in reservoir mode
+	reservoir int // Keep this many operations; 0 in every mode
+	passes    int // Passes per Record
+	digests   map[string]*tdigest.Digest
+	f         *os.File
+	w         *csv.Writer
+	err       error
+}
+
+// latencySample is one timed operation.
+type latencySample struct {
+	seq   int
+	start time.Time
+	d     time.Duration
+}
+
+// OpenLatencyTrace parses spec, a -latency-sample value, and creates
+// path for the trace of benchmark bench, recording passes passes (at
+// least 1) per variant. An empty path returns nil.
+func OpenLatencyTrace(path, bench, spec string, passes int) (*LatencyTrace, error) {
+	lt := &LatencyTrace{bench: bench, passes: max(passes, 1), digests: make(map[string]*tdigest.Digest)}
+	mode, value, _ := strings.Cut(spec, "=")
+	k, err := strconv.Atoi(value)
+	switch {
+	case err != nil || k < 1:
+		return nil, fmt.Errorf("%w: got %q", ErrInvalidLatencySample, spec)
+	case mode == "every":
+		lt.every = k
+	case mode == "reservoir":
+		lt.reservoir = k
+	default:
+		return nil, fmt.Errorf("%w: got %q", ErrInvalidLatencySample, spec)
+	}
+	if path == "" {
+		return nil, nil
+	}
+	if lt.f, err = os.Create(path); err != nil {
+		return nil, err
+	}
+	lt.w = csv.NewWriter(lt.f)
+	lt.err = lt.w.Write([]string{"benchmark", "variant", "seq", "start_unix_ns", "latency_ns", "pass"})
+	return lt, nil
+}
+
+// Record calls op n times in each of the passes as variant name, timing
+// the operations the sampling spec selects, and writes them. op must be
+// safe to call on its own, outside the variant's timed loop.
+func (lt *LatencyTrace) Record(name string, n int, op func()) {
+	if lt == nil || lt.err != nil {
+		return
+	}
+	d := lt.digests[name]
+	if d == nil {
+		d = tdigest.New()
+		lt.digests[name] = d
+	}
+	rng := rand.New(rand.NewPCG(uint64(n), uint64(lt.reservoir)))
+	for pass := range lt.passes {
+		for _, s := range lt.pass(n, op, rng, d) {
+			if lt.err = lt.w.Write([]string{
+				lt.bench, name, strconv.Itoa(s.seq),
+				strconv.FormatInt(s.start.UnixNano(), 10), strconv.FormatInt(s.d.Nanoseconds(), 10),
+				strconv.Itoa(pass),
+			}); lt.err != nil {
+				return
+			}
+		}
+	}
+}
+
+// pass calls op n times, adds every latency it times to d, and returns
+// those the sampling spec keeps, in order.
+func (lt *LatencyTrace) pass(n int, op func(), rng *rand.Rand, d *tdigest.Digest) []latencySample {
+	var samples []latencySample
+	if lt.every > 0 {
+		samples = make([]latencySample, 0, n/lt.every+1)
+		for i := 0; i < n; i++ {
+			if i%lt.every != 0 {
+				op()
+				continue
+			}
+			start := time.Now()
+			op()
+			samples = append(samples, latencySample{i, start, time.Since(start)})
+		}
+		for _, s := range samples {
+			d.Add(float64(s.d.Nanoseconds()))
+		}
+		return samples
+	}
+	// Algorithm R: after i operations, each has been kept with
+	// probability reservoir/i
+	samples = make([]latencySample, 0, min(n, lt.reservoir))
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		op()
+		s := latencySample{i, start, time.Since(start)}
+		d.Add(float64(s.d.Nanoseconds()))
+		if len(samples) < lt.reservoir {
+			samples = append(samples, s)
+		} else if j := rng.IntN(i + 1); j < lt.reservoir {
+			samples[j] = s
+		}
+	}
+	// Replacement scrambles the order; restore it
+	slices.SortFunc(samples, func(a, b latencySample) int { return a.seq - b.seq })
+	return samples
+}
+
+// Digest returns the t-digest of the latencies recorded for variant
+// name over all its passes, or nil if there are none or lt is nil.
+func (lt *LatencyTrace) Digest(name string) *tdigest.Digest {
+	if lt == nil {
+		return nil
+	}
+	return lt.digests[name]
+}
+
+// Close flushes and closes the trace file, returning the first error
+// recording or writing it.
+func (lt *LatencyTrace) Close() error {
+	if lt == nil {
+		return nil
+	}
+	lt.w.Flush()
+	if err := lt.w.Error(); err != nil && lt.err == nil {
+		lt.err = err
+	}
+	if err := lt.f.Close(); err != nil && lt.err == nil {
+		lt.err = err
+	}
+	return lt.err
+}
+
+// LatencyQuantiles are the quantiles latency reports show, with their
+// column labels.
+var LatencyQuantiles = []struct {
+	Label string
+	Q     float64
+}{{"p50", 0.5}, {"p90", 0.9}, {"p99", 0.99}, {"p99.9", 0.999}}
+
+// LatencySummary is a variant's operation latency percentiles in a
+// baseline, with the t-digest they came from so that runs can be merged
+// (see tdigest.Digest.Merge) and other percentiles read later.
+type LatencySummary struct {
+	P50    float64         `json:"p50_ns"`
+	P90    float64         `json:"p90_ns"`
+	P99    float64         `json:"p99_ns"`
+	P999   float64         `json:"p999_ns"`
+	Max    float64         `json:"max_ns"`
+	Digest *tdigest.Digest `json:"digest"`
+}
+
+// NewLatencySummary summarizes d, or returns nil for a nil or empty d.
+func NewLatencySummary(d *tdigest.Digest) *LatencySummary {
+	if d == nil || d.Count() == 0 {
+		return nil
+	}
+	return &LatencySummary{
+		P50: d.Quantile(0.5), P90: d.Quantile(0.9), P99: d.Quantile(0.99), P999: d.Quantile(0.999),
+		Max: d.Max(), Digest: d,
+	}
+}
+
+// WriteLatency writes the operation latency percentiles of each result
+// with a Latency digest, and how many operations it summarizes.
+func WriteLatency(w io.Writer, results []Result) error {
+	header := fmt.Sprintf("  %-24s %10s", "Variant", "Ops")
+	for _, q := range LatencyQuantiles {
+		header += fmt.Sprintf(" %9s", q.Label)
+	}
+	if _, err := fmt.Fprintf(w, "%s %9s  (ns)\n", header, "Max"); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Latency == nil || r.Latency.Count() == 0 {
+			continue
+		}
+		row := fmt.Sprintf("  %-24s %10d", r.Name, r.Latency.Count())
+		for _, q := range LatencyQuantiles {
+			row += fmt.Sprintf(" %9.0f", r.Latency.Quantile(q.Q))
+		}
+		if _, err := fmt.Fprintf(w, "%s %9.0f\n", row, r.Latency.Max()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package harness
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/affinity"
+)
+
+const sysCPU = "/sys/devices/system/cpu"
+
+// Machine describes where results were measured, so rows collected from
+// many machines can be told apart and numbers quoted in an issue come
+// with the settings that explain them.
+//
+// The topology, governor, turbo and kernel fields are read from sysfs and
+// procfs on Linux; elsewhere, and wherever a VM hides them, they are
+// zero or empty.
+type Machine struct {
+	Hostname       string `json:"hostname"`
+	CPUModel       string `json:"cpu_model"`
+	NumCPU         int    `json:"num_cpu"`
+	Cores          int    `json:"cores"`            // Physical cores among the allowed CPUs
+	Sockets        int    `json:"sockets"`          // Packages among the allowed CPUs
+	ThreadsPerCore int    `json:"threads_per_core"` // SMT width; 1 with SMT off
+	GOMAXPROCS     int    `json:"gomaxprocs"`
+	Governor       string `json:"governor"` // cpufreq scaling governor of CPU 0
+	Turbo          string `json:"turbo"`    // "on", "off", or "" if unknown
+	Kernel         string `json:"kernel"`
+	GOOS           string `json:"goos"`
+	GOARCH         string `json:"goarch"`
+	GoVersion      string `json:"go_version"`
+}
+
+// CurrentMachine describes the machine the process is running on. Fields
+// that can't be read are left empty.
+func CurrentMachine() Machine {
+	host, _ := os.Hostname()
+	m := Machine{
+		Hostname:   host,
+		CPUModel:   cpuModel(),
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Governor:   readSys(sysCPU + "/cpu0/cpufreq/scaling_governor"),
+		Turbo:      turbo(),
+		Kernel:     readSys("/proc/sys/kernel/osrelease"),
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		GoVersion:  runtime.Version(),
+	}
+	if cpus, err := affinity.Topology(); err == nil && len(cpus) > 0 {
+		cores := make(map[[2]int]bool)
+		sockets := make(map[int]bool)
+		for _, c := range cpus {
+			cores[[2]int{c.Package, c.Core}] = true
+			sockets[c.Package] = true
+		}
+		m.Cores, m.Sockets = len(cores), len(sockets)
+		m.ThreadsPerCore = len(cpus) / len(cores)
+	}
+	return m
+}
+
+// String summarizes m on one line for text reports.
+func (m Machine) String() string {
+	var b strings.Builder
+	if m.CPUModel != "" {
+		b.WriteString(m.CPUModel + ", ")
+	}
+	fmt.Fprintf(&b, "%d CPUs", m.NumCPU)
+	if m.Cores > 0 {
+		fmt.Fprintf(&b, " (%d sockets x %d cores x %d threads)",
+			m.Sockets, m.Cores/m.Sockets, m.ThreadsPerCore)
+	}
+	if m.Governor != "" {
+		fmt.Fprintf(&b, ", governor %s", m.Governor)
+	}
+	if m.Turbo != "" {
+		fmt.Fprintf(&b, ", turbo %s", m.Turbo)
+	}
+	fmt.Fprintf(&b, ", %s/%s", m.GOOS, m.GOARCH)
+	if m.Kernel != "" {
+		fmt.Fprintf(&b, " %s", m.Kernel)
+	}
+	fmt.Fprintf(&b, ", %s, GOMAXPROCS=%d", m.GoVersion, m.GOMAXPROCS)
+	return b.String()
+}
+
+// cpuModel returns the first "model name" in /proc/cpuinfo.
+func cpuModel() string {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if ok && strings.TrimSpace(key) == "model name" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// turbo reports whether turbo boost is enabled: intel_pstate exposes
+// no_turbo, acpi-cpufreq (and AMD) expose boost.
+func turbo() string {
+	switch readSys(sysCPU + "/intel_pstate/no_turbo") {
+	case "0":
+		return "on"
+	case "1":
+		return "off"
+	}
+	switch readSys(sysCPU + "/cpufreq/boost") {
+	case "0":
+		return "off"
+	case "1":
+		return "on"
+	}
+	return ""
+}
+
+// readSys returns the trimmed contents of a one-line sysfs or procfs
+// file, or "" if it can't be read.
+func readSys(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Warnings lists the settings of m that make ns/op unstable: a scaling
+// governor other than "performance" lets the clock follow load, and
+// turbo lets it follow temperature and what the other cores are doing.
+// Either also skews the TSC ticker's calibration against wall time.
+// Settings that couldn't be read produce no warning.
+func (m Machine) Warnings() []string {
+	var ws []string
+	if m.Governor != "" && m.Governor != "performance" {
+		ws = append(ws, fmt.Sprintf("CPU scaling governor is %q, not \"performance\"", m.Governor))
+	}
+	if m.Turbo == "on" {
+		ws = append(ws, "turbo boost is on")
+	}
+	return ws
+}
+
+// ErrUnstableMachine is returned by CheckMachine in strict mode when the
+// machine has Warnings.
+var ErrUnstableMachine = errors.New("harness: refusing to benchmark with frequency scaling or turbo enabled (-strict)")
+
+// StrictUsage is the help text for a -strict flag.
+const StrictUsage = "refuse to run when the CPU governor isn't performance or turbo is on"
+
+// CheckMachine writes m's Warnings to w, if it has any. In strict mode it
+// then returns ErrUnstableMachine instead of letting the run go ahead.
+func CheckMachine(w io.Writer, m Machine, strict bool) error {
+	ws := m.Warnings()
+	if len(ws) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("!!! WARNING: results on this machine will be unstable:\n")
+	for _, msg := range ws {
+		fmt.Fprintf(&b, "!!!   - %s\n", msg)
+	}
+	b.WriteString("!!! See Environment Setup in BENCHMARKING.md to fix this.\n")
+	if !strict {
+		b.WriteString("!!! Pass -strict to refuse to run instead.\n")
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+	if strict {
+		return ErrUnstableMachine
+	}
+	return nil
+}
+
+// fingerprintFields are the fields of a Machine that Fingerprint covers:
+// those that most change ns/op between two runs of the same code.
+func (m Machine) fingerprintFields() [][2]string {
+	return [][2]string{
+		{"CPU model", m.CPUModel},
+		{"governor", m.Governor},
+		{"Go version", m.GoVersion},
+		{"GOMAXPROCS", fmt.Sprint(m.GOMAXPROCS)},
+	}
+}
+
+// Fingerprint returns a short hash of m's CPU model, governor, Go version
+// and GOMAXPROCS. Results measured under different fingerprints differ
+// for reasons other than the code, so comparing them is suspect.
+func (m Machine) Fingerprint() string {
+	h := sha256.New()
+	for _, f := range m.fingerprintFields() {
+		fmt.Fprintf(h, "%s=%s\n", f[0], f[1])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// CheckFingerprint compares the Fingerprint of the machine a baseline was
+// measured on, old, with that of cur, and if they differ writes a warning
+// to w naming each field that changed. Comparisons across machines, Go
+// versions or settings are the most common source of bogus regressions.
+// It reports whether the fingerprints match.
+func CheckFingerprint(w io.Writer, old, cur Machine) bool {
+	if old.Fingerprint() == cur.Fingerprint() {
+		return true
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "!!! WARNING: the baseline was measured in another environment (fingerprint %s, now %s):\n", old.Fingerprint(), cur.Fingerprint())
+	of, cf := old.fingerprintFields(), cur.fingerprintFields()
+	for i := range of {
+		if of[i][1] != cf[i][1] {
+			fmt.Fprintf(&b, "!!!   - %s: %s -> %s\n", of[i][0], orUnknown(of[i][1]), orUnknown(cf[i][1]))
+		}
+	}
+	b.WriteString("!!! Differences below may come from the environment, not the code.\n")
+	_, _ = io.WriteString(w, b.String())
+	return false
+}
+
+// orUnknown returns s, or "unknown" if it is empty.
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
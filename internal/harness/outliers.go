@@ -0,0 +1,80 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// ErrInvalidOutliers is returned by CheckOutliers for a negative cutoff.
+var ErrInvalidOutliers = errors.New("harness: outlier cutoff must not be negative")
+
+// DefaultOutliers is the default -outliers cutoff. 3.5 is the usual
+// modified z-score cutoff (Iglewicz and Hoaglin).
+const DefaultOutliers = 3.5
+
+// OutliersUsage is the help text for an -outliers flag.
+const OutliersUsage = "drop samples more than this many scaled MADs from the median (0 keeps all)"
+
+// madScale makes the median absolute deviation estimate the standard
+// deviation for normally distributed samples.
+const madScale = 1.4826
+
+// minSpread floors the scaled MAD at this fraction of the median. Very
+// fast loops give runs within a fraction of a percent of each other, and
+// without a floor that jitter alone would mark half of them as outliers.
+const minSpread = 0.01
+
+// CheckOutliers validates an -outliers value.
+func CheckOutliers(k float64) error {
+	if k < 0 || math.IsNaN(k) {
+		return fmt.Errorf("%w: got %v", ErrInvalidOutliers, k)
+	}
+	return nil
+}
+
+// WithoutOutliers returns r with every sample more than k scaled median
+// absolute deviations from the median removed, along with their Cycles,
+// and Dropped counting them.
+// A GC pause or a cron job landing in one run then cannot drag the mean,
+// max or stddev with it.
+//
+// k of 0 keeps every sample, and so do fewer than three samples, where
+// the median can't outvote anything.
+func (r Result) WithoutOutliers(k float64) Result {
+	if k == 0 || len(r.Samples) < 3 {
+		return r
+	}
+	xs := make([]float64, len(r.Samples))
+	for i, d := range r.Samples {
+		xs[i] = float64(d)
+	}
+	slices.Sort(xs)
+	med := median(xs)
+
+	devs := make([]float64, len(xs))
+	for i, x := range xs {
+		devs[i] = math.Abs(x - med)
+	}
+	slices.Sort(devs)
+	mad := max(median(devs)*madScale, med*minSpread)
+	if mad == 0 {
+		return r
+	}
+
+	kept := make([]time.Duration, 0, len(r.Samples))
+	var cycles []uint64
+	for i, d := range r.Samples {
+		if math.Abs(float64(d)-med) <= k*mad {
+			kept = append(kept, d)
+			if i < len(r.Cycles) {
+				cycles = append(cycles, r.Cycles[i])
+			}
+		}
+	}
+	r.Dropped += len(r.Samples) - len(kept)
+	r.Samples, r.Cycles = kept, cycles
+	return r
+}
@@ -0,0 +1,99 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidPriority is returned by ParsePriority for an out-of-range
+// -nice or -rt.
+var ErrInvalidPriority = errors.New("harness: invalid priority")
+
+// errPriorityUnsupported is returned by setNice and setFIFO on platforms
+// without per-thread scheduling control.
+var errPriorityUnsupported = errors.New("not supported on this platform")
+
+// NiceUsage and RTUsage are the help text for -nice and -rt.
+const (
+	NiceUsage = "run at this nice value, -20 (highest priority) to 19; below 0 needs root or CAP_SYS_NICE (default: unchanged)"
+	RTUsage   = "run under the SCHED_FIFO real-time policy at this priority, 1 to 99; needs root or CAP_SYS_NICE (default: off)"
+)
+
+// Priority is the scheduling priority a run asks for. The zero Priority
+// leaves it as the process started.
+//
+// A timed loop of a few nanoseconds per operation loses whole samples to
+// a single preemption by another process. A lower nice value gives the
+// run a bigger share of the CPU when it is contended; SCHED_FIFO goes
+// further, and no ordinary process can preempt it at all. The kernel's
+// real-time throttling (sched_rt_runtime_us, 95% by default) still keeps
+// a spinning loop from locking up the machine.
+type Priority struct {
+	Nice int // Nice value; 0 leaves it unchanged
+	RT   int // SCHED_FIFO priority; 0 for the normal policy
+}
+
+// ParsePriority validates -nice and -rt.
+func ParsePriority(nice, rt int) (Priority, error) {
+	if nice < -20 || nice > 19 {
+		return Priority{}, fmt.Errorf("%w: -nice %d: want -20 to 19", ErrInvalidPriority, nice)
+	}
+	if rt < 0 || rt > 99 {
+		return Priority{}, fmt.Errorf("%w: -rt %d: want 1 to 99, or 0 for off", ErrInvalidPriority, rt)
+	}
+	return Priority{Nice: nice, RT: rt}, nil
+}
+
+// Apply sets pr for every thread of the process, once, before any
+// variant runs, and returns the Priority now in effect. The Go scheduler
+// moves goroutines between threads, and scenarios run their loops on
+// threads of their own, so the measuring thread can only be covered by
+// covering them all; threads started later inherit it from the ones that
+// start them. A setting the process isn't allowed, or that the platform
+// doesn't support, is skipped with a warning on w, falling back to -nice
+// if -rt fails, and to the normal priority if that fails too.
+func (pr Priority) Apply(w io.Writer) Priority {
+	var got Priority
+	if pr.Nice != 0 {
+		if err := setNice(pr.Nice); err != nil {
+			fmt.Fprintf(w, "!!! WARNING: -nice %d: %v; running at the inherited nice value (raising priority needs root or CAP_SYS_NICE)\n", pr.Nice, err)
+		} else {
+			got.Nice = pr.Nice
+		}
+	}
+	if pr.RT != 0 {
+		if err := setFIFO(pr.RT); err != nil {
+			fallback := "the normal policy"
+			if got.Nice != 0 {
+				fallback = fmt.Sprintf("nice %d", got.Nice)
+			}
+			fmt.Fprintf(w, "!!! WARNING: -rt %d: %v; running at %s instead (SCHED_FIFO needs root or CAP_SYS_NICE)\n", pr.RT, err, fallback)
+		} else {
+			got.RT = pr.RT
+		}
+	}
+	return got
+}
+
+// IsZero reports whether pr leaves the priority as the process started.
+func (pr Priority) IsZero() bool {
+	return pr.Nice == 0 && pr.RT == 0
+}
+
+// String describes pr for text reports, such as "SCHED_FIFO 50, nice -10",
+// or "default".
+func (pr Priority) String() string {
+	var parts []string
+	if pr.RT != 0 {
+		parts = append(parts, fmt.Sprintf("SCHED_FIFO %d", pr.RT))
+	}
+	if pr.Nice != 0 {
+		parts = append(parts, fmt.Sprintf("nice %d", pr.Nice))
+	}
+	if len(parts) == 0 {
+		return "default"
+	}
+	return strings.Join(parts, ", ")
+}
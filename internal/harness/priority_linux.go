@@ -0,0 +1,53 @@
+//go:build linux
+
+package harness
+
+import (
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// setNice sets nice as every thread's nice value.
+func setNice(nice int) error {
+	return forEachThread(func(tid int) error {
+		return unix.Setpriority(unix.PRIO_PROCESS, tid, nice)
+	})
+}
+
+// setFIFO puts every thread under SCHED_FIFO at priority prio.
+func setFIFO(prio int) error {
+	return forEachThread(func(tid int) error {
+		return unix.SchedSetAttr(tid, &unix.SchedAttr{Policy: unix.SCHED_FIFO, Priority: uint32(prio)}, 0)
+	})
+}
+
+// forEachThread calls set for the id of every thread of the process,
+// stopping at the first error. The runtime may start threads meanwhile,
+// from ones set or not, so it makes passes over /proc/self/task until
+// one finds no thread it hasn't set.
+func forEachThread(set func(tid int) error) error {
+	done := make(map[int]bool)
+	for {
+		entries, err := os.ReadDir("/proc/self/task")
+		if err != nil {
+			return err
+		}
+		added := false
+		for _, e := range entries {
+			tid, err := strconv.Atoi(e.Name())
+			if err != nil || done[tid] {
+				continue
+			}
+			// A thread that exited since ReadDir is nothing to set
+			if err := set(tid); err != nil && err != unix.ESRCH {
+				return err
+			}
+			done[tid], added = true, true
+		}
+		if !added {
+			return nil
+		}
+	}
+}
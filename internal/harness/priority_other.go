@@ -0,0 +1,11 @@
+//go:build !linux
+
+package harness
+
+func setNice(nice int) error {
+	return errPriorityUnsupported
+}
+
+func setFIFO(prio int) error {
+	return errPriorityUnsupported
+}
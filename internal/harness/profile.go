@@ -0,0 +1,274 @@
+package harness
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/google/pprof/profile"
+)
+
+// CPUProfileDirUsage, MemProfileDirUsage, BlockProfileDirUsage,
+// MutexProfileDirUsage and FoldedDirUsage are the help text for
+// -cpuprofile-dir, -memprofile-dir, -blockprofile-dir, -mutexprofile-dir
+// and -folded-dir.
+const (
+	CPUProfileDirUsage   = "write a CPU profile of each variant's timed runs to this directory"
+	MemProfileDirUsage   = "write allocation profiles around each variant's timed runs to this directory"
+	BlockProfileDirUsage = "write blocking profiles around each variant's timed runs to this directory"
+	MutexProfileDirUsage = "write mutex contention profiles around each variant's timed runs to this directory"
+	FoldedDirUsage       = "write each variant's sampled CPU stacks as folded stacks for flame graphs to this directory"
+)
+
+// Profiler measures variants, counting their allocations and, if asked,
+// profiling each variant's timed runs into its own files, so the
+// standard-library variant's profile can be opened next to the optimized
+// one's:
+//
+//	go tool pprof -top prof/Ticker_StdTicker.cpu.pprof
+//
+// Allocation profiles are cumulative over the whole process, so for each
+// variant Measure writes one just before its timed runs and one just
+// after; the difference is the variant's own allocations:
+//
+//	go tool pprof -base prof/Ticker_StdTicker.mem.base.pprof prof/Ticker_StdTicker.mem.pprof
+//
+// Blocking and mutex contention profiles work the same way, and show
+// where channel-based variants wait, which a CPU profile can't: a
+// goroutine blocked on a channel isn't on a CPU. Measure records every
+// blocking event and contended lock while the variant runs, which slows
+// those down, so read ns/op from a run without them:
+//
+//	go tool pprof -base prof/MPSC_Channel_P=4.block.base.pprof prof/MPSC_Channel_P=4.block.pprof
+//
+// Folded stacks are the same CPU samples in the text format flame graph
+// tools read, for when pprof isn't at hand (see WriteFolded):
+//
+//	flamegraph.pl prof/Ticker_StdTicker.folded > std.svg
+//
+// With Cycles set, the time stamp counter is read around every timed run
+// too, for Result.CyclesPerOp.
+//
+// With GCOff set, every timed run starts from a forced collection and
+// runs with the GC off (see GC).
+//
+// With Isolate set, each variant is measured in a child process of its
+// own instead, re-executed from this one (see Runs).
+//
+// A nil Profiler, or one with no directories set, profiles nothing but
+// still counts allocations. Errors are sticky: after the first, Measure
+// stops profiling and Err reports it.
+type Profiler struct {
+	Bench     string // Benchmark name, the first part of each file name
+	CPUDir    string // Directory for <bench>_<variant>.cpu.pprof files
+	MemDir    string // Directory for <bench>_<variant>.mem[.base].pprof files
+	BlockDir  string // Directory for <bench>_<variant>.block[.base].pprof files
+	MutexDir  string // Directory for <bench>_<variant>.mutex[.base].pprof files
+	FoldedDir string // Directory for <bench>_<variant>.folded files
+	GCOff     bool   // Force a GC before each timed run and turn it off during it
+	Cycles    bool   // Count TSC cycles around each timed run; see CheckCycles
+	Isolate   bool   // Measure each variant in a fresh child process
+
+	calls int // Measure and MeasureAll calls so far
+	err   error
+}
+
+// Measure times variant name: it calls timed count times, as Sample does,
+// and returns the durations as a Result for n iterations per call, with
+// the allocations made during all count calls averaged per iteration as
+// `go test -benchmem` does, and the range of CPU frequencies seen.
+func (p *Profiler) Measure(name string, n, count int, timed func() time.Duration) Result {
+	return p.run(p.nextCall(), name, n, count, timed)
+}
+
+// nextCall counts a Measure or MeasureAll call and returns its index.
+func (p *Profiler) nextCall() int {
+	if p == nil {
+		return 0
+	}
+	p.calls++
+	return p.calls - 1
+}
+
+// measure is Measure in this process.
+func (p *Profiler) measure(name string, n, count int, timed func() time.Duration) Result {
+	var stopCPU func()
+	if p != nil && (p.CPUDir != "" || p.FoldedDir != "") && p.err == nil {
+		stopCPU = p.startCPU(name)
+	}
+	if p != nil && p.MemDir != "" && p.err == nil {
+		p.writeProfile(p.MemDir, "allocs", name, "mem.base.pprof")
+	}
+	if p != nil && p.BlockDir != "" && p.err == nil {
+		runtime.SetBlockProfileRate(1)
+		defer runtime.SetBlockProfileRate(0)
+		p.writeProfile(p.BlockDir, "block", name, "block.base.pprof")
+	}
+	if p != nil && p.MutexDir != "" && p.err == nil {
+		defer runtime.SetMutexProfileFraction(runtime.SetMutexProfileFraction(1))
+		p.writeProfile(p.MutexDir, "mutex", name, "mutex.base.pprof")
+	}
+
+	freq := startFreq()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	var cycles []uint64
+	samples := Sample(count, p.gc(p.cycles(timed, &cycles)))
+	runtime.ReadMemStats(&after)
+	r := Result{Name: name, N: n, Samples: samples, Cycles: cycles}
+	r.GCs = gcCycles(&before, &after)
+	freq.Stop(&r)
+
+	if stopCPU != nil {
+		stopCPU()
+	}
+	if p != nil && p.MemDir != "" && p.err == nil {
+		p.writeProfile(p.MemDir, "allocs", name, "mem.pprof")
+	}
+	if p != nil && p.BlockDir != "" && p.err == nil {
+		p.writeProfile(p.BlockDir, "block", name, "block.pprof")
+	}
+	if p != nil && p.MutexDir != "" && p.err == nil {
+		p.writeProfile(p.MutexDir, "mutex", name, "mutex.pprof")
+	}
+
+	if ops := uint64(n) * uint64(count); ops > 0 {
+		r.AllocsPerOp = int64((after.Mallocs - before.Mallocs) / ops)
+		r.BytesPerOp = int64((after.TotalAlloc - before.TotalAlloc) / ops)
+	}
+	return r
+}
+
+// gc returns timed as Measure runs it: without the GC if GCOff is set.
+func (p *Profiler) gc(timed func() time.Duration) func() time.Duration {
+	if p == nil || !p.GCOff {
+		return timed
+	}
+	return withoutGC(timed)
+}
+
+// cycles returns timed as Measure runs it: appending the TSC cycles of
+// each call to *counted if Cycles is set.
+func (p *Profiler) cycles(timed func() time.Duration, counted *[]uint64) func() time.Duration {
+	if p == nil || !p.Cycles {
+		return timed
+	}
+	return countCycles(timed, counted)
+}
+
+// Err returns the first error writing a profile, if any.
+func (p *Profiler) Err() error {
+	if p == nil {
+		return nil
+	}
+	return p.err
+}
+
+// startCPU starts a CPU profile for variant name, into its .cpu.pprof
+// file if CPUDir is set and into a buffer for its .folded file if
+// FoldedDir is. It returns a func that stops the profile and writes the
+// files, or nil after recording an error.
+func (p *Profiler) startCPU(name string) (stop func()) {
+	var ws []io.Writer
+	var f *os.File
+	if p.CPUDir != "" {
+		var err error
+		if f, err = p.create(p.CPUDir, name, "cpu.pprof"); err != nil {
+			p.err = err
+			return nil
+		}
+		ws = append(ws, f)
+	}
+	var buf *bytes.Buffer
+	if p.FoldedDir != "" {
+		buf = new(bytes.Buffer)
+		ws = append(ws, buf)
+	}
+	if err := pprof.StartCPUProfile(io.MultiWriter(ws...)); err != nil {
+		if f != nil {
+			f.Close()
+		}
+		p.err = err
+		return nil
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		if f != nil {
+			if err := f.Close(); err != nil {
+				p.err = err
+			}
+		}
+		if buf != nil && p.err == nil {
+			p.writeFolded(name, buf)
+		}
+	}
+}
+
+// writeFolded converts the CPU profile in buf to folded stacks for
+// variant name.
+func (p *Profiler) writeFolded(name string, buf *bytes.Buffer) {
+	prof, err := profile.Parse(buf)
+	if err != nil {
+		p.err = err
+		return
+	}
+	f, err := p.create(p.FoldedDir, name, "folded")
+	if err != nil {
+		p.err = err
+		return
+	}
+	if err := WriteFolded(f, prof); err != nil {
+		p.err = err
+	}
+	if err := f.Close(); err != nil && p.err == nil {
+		p.err = err
+	}
+}
+
+// writeProfile writes the cumulative runtime profile kind (allocs, block
+// or mutex) for variant name into dir. The allocs profile is only up to
+// date as of the last GC, so for it a GC runs first.
+func (p *Profiler) writeProfile(dir, kind, name, suffix string) {
+	f, err := p.create(dir, name, suffix)
+	if err != nil {
+		p.err = err
+		return
+	}
+	if kind == "allocs" {
+		runtime.GC()
+	}
+	if err := pprof.Lookup(kind).WriteTo(f, 0); err != nil {
+		p.err = err
+	}
+	if err := f.Close(); err != nil && p.err == nil {
+		p.err = err
+	}
+}
+
+// create makes dir if needed and creates the profile file for variant
+// name in it.
+func (p *Profiler) create(dir, name, suffix string) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(filepath.Join(dir, fileName(p.Bench)+"_"+fileName(name)+"."+suffix))
+}
+
+// fileName replaces everything in s but letters, digits and ._=- with
+// underscores, so variant names like "Channel/P=4" or "Batch Ticker(10)"
+// make plain file names.
+func fileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9',
+			r == '.', r == '_', r == '=', r == '-':
+			return r
+		}
+		return '_'
+	}, s)
+}
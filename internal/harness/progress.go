@@ -0,0 +1,72 @@
+package harness
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Progress reports how far a long run has got through its variants, with
+// an estimate of the time left, so a run of several minutes doesn't look
+// hung. On a terminal it redraws one status line; elsewhere, such as in
+// a CI log, it writes a line per variant.
+//
+// A nil Progress reports nothing, for machine-readable output.
+type Progress struct {
+	w     io.Writer
+	tty   bool
+	total int
+	done  int
+	start time.Time
+}
+
+// NewProgress returns a Progress over total variants that writes to w.
+func NewProgress(w io.Writer, total int) *Progress {
+	return &Progress{w: w, tty: isTerminal(w), total: total, start: time.Now()}
+}
+
+// Start reports that variant name is starting. Once one has finished, it
+// adds the time left, assuming the rest take as long on average as those
+// done so far.
+func (p *Progress) Start(name string) {
+	if p == nil {
+		return
+	}
+	line := fmt.Sprintf("[%d/%d] %s", p.done+1, p.total, name)
+	if p.done > 0 {
+		elapsed := time.Since(p.start)
+		left := elapsed / time.Duration(p.done) * time.Duration(p.total-p.done)
+		line += fmt.Sprintf(" (%v elapsed, about %v left)", roundDuration(elapsed), roundDuration(left))
+	}
+	if p.tty {
+		fmt.Fprintf(p.w, "\r\033[K%s", line)
+	} else {
+		fmt.Fprintln(p.w, line)
+	}
+}
+
+// Done reports that the variant last started has finished. After the
+// last one it clears the status line, or reports the total time.
+func (p *Progress) Done() {
+	if p == nil {
+		return
+	}
+	p.done++
+	if p.done < p.total {
+		return
+	}
+	if p.tty {
+		fmt.Fprint(p.w, "\r\033[K")
+	} else {
+		fmt.Fprintf(p.w, "[%d/%d] done in %v\n", p.total, p.total, roundDuration(time.Since(p.start)))
+	}
+}
+
+// roundDuration rounds d to tenths of a second under a minute and to
+// seconds above.
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Minute {
+		return d.Round(100 * time.Millisecond)
+	}
+	return d.Round(time.Second)
+}
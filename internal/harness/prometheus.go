@@ -0,0 +1,62 @@
+package harness
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// WritePrometheus writes results as gauges in the Prometheus text
+// exposition format, one series per variant labelled with benchmark
+// bench, plus a bench_machine_info series whose labels describe m:
+//
+//	bench_ns_per_op{benchmark="Scenario",variant="cancel-tick/std"} 24.61
+//	bench_machine_info{hostname="ci-1",kernel="6.8.0",go_version="go1.25.4",...} 1
+//
+// Joining a variant's series on bench_machine_info makes a jump that
+// lines up with a kernel or Go upgrade easy to spot. Values are the
+// median over the kept samples, as in every other format. Results with
+// counted cycles add bench_cycles_per_op.
+func WritePrometheus(w io.Writer, bench string, m Machine, results []Result) error {
+	gauges := []struct {
+		name, help string
+		value      func(Result) float64
+	}{
+		{"bench_ns_per_op", "Median nanoseconds per iteration.", Result.NsPerOp},
+		{"bench_bytes_per_op", "Bytes allocated per iteration.", func(r Result) float64 { return float64(r.BytesPerOp) }},
+		{"bench_allocs_per_op", "Allocations per iteration.", func(r Result) float64 { return float64(r.AllocsPerOp) }},
+		{"bench_iterations", "Iterations per timed run.", func(r Result) float64 { return float64(r.N) }},
+	}
+	if slices.ContainsFunc(results, func(r Result) bool { return len(r.Cycles) > 0 }) {
+		gauges = append(gauges, struct {
+			name, help string
+			value      func(Result) float64
+		}{"bench_cycles_per_op", "Median TSC cycles per iteration.", Result.CyclesPerOp})
+	}
+	var b strings.Builder
+	for _, g := range gauges {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name)
+		for _, r := range results {
+			fmt.Fprintf(&b, "%s{benchmark=%s,variant=%s} %s\n", g.name,
+				promLabel(bench), promLabel(r.Name), strconv.FormatFloat(g.value(r), 'g', -1, 64))
+		}
+	}
+	b.WriteString("# HELP bench_machine_info Machine the benchmarks ran on; always 1.\n")
+	b.WriteString("# TYPE bench_machine_info gauge\n")
+	fmt.Fprintf(&b, "bench_machine_info{hostname=%s,cpu_model=%s,num_cpu=\"%d\",gomaxprocs=\"%d\",governor=%s,turbo=%s,kernel=%s,goos=%s,goarch=%s,go_version=%s} 1\n",
+		promLabel(m.Hostname), promLabel(m.CPUModel), m.NumCPU, m.GOMAXPROCS, promLabel(m.Governor),
+		promLabel(m.Turbo), promLabel(m.Kernel), promLabel(m.GOOS), promLabel(m.GOARCH), promLabel(m.GoVersion))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// promLabelEscaper escapes the three characters the text format doesn't
+// allow bare in a label value.
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promLabel quotes s as a label value.
+func promLabel(s string) string {
+	return `"` + promLabelEscaper.Replace(s) + `"`
+}
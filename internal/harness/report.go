@@ -0,0 +1,109 @@
+package harness
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// rule underlines a text report's header and section titles.
+var rule = strings.Repeat("─", 49)
+
+// Reporter writes the parts of a text report the cmd tools share, in one
+// layout. As with the rest of a text report, errors writing to W are
+// ignored.
+type Reporter struct {
+	W     io.Writer
+	Color Color
+}
+
+// Header writes the report's title, the machine it runs on, one line per
+// setting (see Runner.Settings) and a rule under them.
+func (rp Reporter) Header(title string, settings ...string) {
+	fmt.Fprintln(rp.W, title)
+	fmt.Fprintf(rp.W, "Machine: %s\n", CurrentMachine())
+	for _, s := range settings {
+		fmt.Fprintln(rp.W, s)
+	}
+	fmt.Fprintln(rp.W, rule)
+}
+
+// Winners returns labels, one per result in the same order, with the
+// fastest result's highlighted. Pad the labels to a common width first.
+func (rp Reporter) Winners(results []Result, labels ...string) []string {
+	labels = append([]string(nil), labels...)
+	if best := Fastest(results); best >= 0 {
+		labels[best] = rp.Color.Winner(labels[best])
+	}
+	return labels
+}
+
+// Speedup formats x, a speedup such as Speedup returns, with the verb
+// format, such as "%6.2fx", colored green or red.
+func (rp Reporter) Speedup(format string, x float64) string {
+	return rp.Color.Speedup(fmt.Sprintf(format, x), x)
+}
+
+// Summary writes the spread of each result's samples after a blank line,
+// when there were count > 1 runs to spread.
+func (rp Reporter) Summary(count int, results []Result) {
+	if count <= 1 {
+		return
+	}
+	fmt.Fprintf(rp.W, "\nAcross %d runs (median shown above):\n", count)
+	_ = WriteSummary(rp.W, results)
+}
+
+// Latency writes the operation latency percentiles -latency-trace
+// recorded, after a blank line, for the results that have them.
+func (rp Reporter) Latency(results []Result) {
+	for _, r := range results {
+		if r.Latency != nil {
+			fmt.Fprintf(rp.W, "\nOperation latency, from -latency-trace:\n")
+			_ = WriteLatency(rp.W, results)
+			return
+		}
+	}
+}
+
+// Partial writes the results of an interrupted run in place of the
+// command's own report, which compares variants that may not have run.
+func (rp Reporter) Partial(results []Result) {
+	fmt.Fprintf(rp.W, "\nInterrupted: partial results, for only the variants measured:\n")
+	if len(results) > 0 {
+		_ = WriteSummary(rp.W, results)
+	}
+}
+
+// Throughput writes the operations per second each result's median
+// ns/op allows on one thread, under the label in labels at its index.
+func (rp Reporter) Throughput(labels []string, results []Result) {
+	fmt.Fprintf(rp.W, "\nThroughput (theoretical max):\n")
+	for i, r := range results {
+		fmt.Fprintf(rp.W, "  %s %.2f M ops/sec\n", labels[i], 1000/r.NsPerOp())
+	}
+}
+
+// Impact writes what replacing base with opt saves per iteration, and in
+// CPU time at each of rates iterations per second.
+func (rp Reporter) Impact(base, opt Result, rates ...int) {
+	fmt.Fprintln(rp.W, "Impact Analysis:")
+	fmt.Fprintln(rp.W, rule)
+	fmt.Fprintf(rp.W, "  Savings per iteration: %.2f ns\n\n", base.NsPerOp()-opt.NsPerOp())
+	for _, rate := range rates {
+		s := Savings(base, opt, rate)
+		fmt.Fprintf(rp.W, "  At %dK ops/sec: save %.2f ms/sec (%.2f%% of 1 core)\n", rate/1000, s*1000, s*100)
+	}
+}
+
+// Speedup returns how many times faster r is than base, by median ns/op.
+func Speedup(base, r Result) float64 {
+	return base.NsPerOp() / r.NsPerOp()
+}
+
+// Savings returns the share of one core that replacing base with opt
+// frees at rate iterations per second: 0.01 is 10ms of CPU time a
+// second.
+func Savings(base, opt Result, rate int) float64 {
+	return (base.NsPerOp() - opt.NsPerOp()) * float64(rate) / 1e9
+}
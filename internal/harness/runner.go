@@ -0,0 +1,313 @@
+package harness
+
+import (
+	"flag"
+	"io"
+	"os"
+	"time"
+)
+
+// Runner is the measurement setup the cmd tools share. NewRunner
+// registers the flags they all take on a FlagSet; once it is parsed,
+// Parse validates them and Start prepares the process, and the command
+// then builds its variants with Variant, measures them with Measure and
+// ends with Finish. Commands add flags of their own to the same FlagSet
+// and check them between Parse and Start, so every bad flag fails before
+// anything is created or changed.
+type Runner struct {
+	Bench string // Benchmark name for results, profiles and traces
+
+	// Set by Parse
+	N         int           // -n
+	Benchtime time.Duration // -time
+	Count     int           // -count
+	Format    Format        // -format, text unless -quiet picked another
+	Notes     io.Writer     // Where warnings and -compare go; see Quiet
+	Warm      Warmup
+	Outliers  float64
+	Shuffle   Shuffle
+	GC        GC
+	Priority  Priority // After Start, the priority in effect
+	Prof      *Profiler
+	Baselines Baselines
+	Color     Color // For the text report on stdout
+
+	// Set by Start
+	Trace *LatencyTrace // nil without -latency-trace
+
+	flags   runnerFlags
+	partial bool // The run was interrupted
+}
+
+// runnerFlags are the flag values Parse reads.
+type runnerFlags struct {
+	n, count, nice, rt                                 *int
+	benchtime                                          *time.Duration
+	outliers                                           *float64
+	quiet, noColor, gcOff, isolate, strict, cycles     *bool
+	format, warmup, shuffle, gogc, ballast             *string
+	save, compare, failOn, db                          *string
+	cpuProfile, memProfile, blockProfile, mutexProfile *string
+	folded, latencyTrace, latencySample                *string
+}
+
+// NewRunner registers the shared flags on fs for benchmark bench, with
+// defaults of n iterations, count runs and, if benchtime is set, a run
+// length instead.
+func NewRunner(fs *flag.FlagSet, bench string, n, count int, benchtime time.Duration) *Runner {
+	return &Runner{Bench: bench, flags: runnerFlags{
+		n:             fs.Int("n", n, "number of iterations, when -time is 0"),
+		count:         fs.Int("count", count, CountUsage),
+		format:        fs.String("format", "text", FormatUsage()),
+		quiet:         fs.Bool("quiet", false, QuietUsage),
+		noColor:       fs.Bool("no-color", false, NoColorUsage),
+		warmup:        fs.String("warmup", "0", WarmupUsage),
+		benchtime:     fs.Duration("time", benchtime, TimeUsage),
+		outliers:      fs.Float64("outliers", DefaultOutliers, OutliersUsage),
+		shuffle:       fs.String("shuffle", "off", ShuffleUsage),
+		gogc:          fs.String("gogc", "", GOGCUsage),
+		ballast:       fs.String("ballast", "", BallastUsage),
+		gcOff:         fs.Bool("gc-off", false, GCOffUsage),
+		isolate:       fs.Bool("isolate", false, IsolateUsage),
+		cycles:        fs.Bool("cycles", false, CyclesUsage),
+		nice:          fs.Int("nice", 0, NiceUsage),
+		rt:            fs.Int("rt", 0, RTUsage),
+		save:          fs.String("save", "", SaveUsage),
+		compare:       fs.String("compare", "", CompareUsage),
+		failOn:        fs.String("fail-on-regression", "", FailOnRegressionUsage),
+		db:            fs.String("db", "", DBUsage),
+		strict:        fs.Bool("strict", false, StrictUsage),
+		cpuProfile:    fs.String("cpuprofile-dir", "", CPUProfileDirUsage),
+		memProfile:    fs.String("memprofile-dir", "", MemProfileDirUsage),
+		blockProfile:  fs.String("blockprofile-dir", "", BlockProfileDirUsage),
+		mutexProfile:  fs.String("mutexprofile-dir", "", MutexProfileDirUsage),
+		folded:        fs.String("folded-dir", "", FoldedDirUsage),
+		latencyTrace:  fs.String("latency-trace", "", LatencyTraceUsage),
+		latencySample: fs.String("latency-sample", DefaultLatencySample, LatencySampleUsage),
+	}}
+}
+
+// Parse validates the shared flags, after the FlagSet is parsed, and
+// loads the -compare baseline. It changes nothing outside r, so a
+// command can still reject flags of its own after it.
+func (r *Runner) Parse() error {
+	f := r.flags
+	var err error
+	if r.Format, err = ParseFormat(*f.format); err != nil {
+		return err
+	}
+	r.Format, r.Notes = Quiet(*f.quiet, r.Format)
+	if r.Warm, err = ParseWarmup(*f.warmup); err != nil {
+		return err
+	}
+	if err := CheckCount(*f.count); err != nil {
+		return err
+	}
+	if err := CheckOutliers(*f.outliers); err != nil {
+		return err
+	}
+	if r.Shuffle, err = ParseShuffle(*f.shuffle); err != nil {
+		return err
+	}
+	if r.GC, err = ParseGC(*f.gogc, *f.ballast, *f.gcOff); err != nil {
+		return err
+	}
+	if r.Priority, err = ParsePriority(*f.nice, *f.rt); err != nil {
+		return err
+	}
+	if err := CheckCycles(*f.cycles); err != nil {
+		return err
+	}
+	r.Prof = &Profiler{Bench: r.Bench, CPUDir: *f.cpuProfile, MemDir: *f.memProfile, BlockDir: *f.blockProfile, MutexDir: *f.mutexProfile, FoldedDir: *f.folded, GCOff: r.GC.Off, Isolate: *f.isolate, Cycles: *f.cycles}
+	if err := CheckShuffle(r.Shuffle, r.Prof); err != nil {
+		return err
+	}
+	if err := CheckIsolate(r.Prof, r.Shuffle, *f.latencyTrace); err != nil {
+		return err
+	}
+	// An empty path only validates the sampling spec
+	if _, err := OpenLatencyTrace("", r.Bench, *f.latencySample, 1); err != nil {
+		return err
+	}
+	if r.Baselines, err = OpenBaselines(*f.save, *f.compare, *f.failOn, *f.count); err != nil {
+		return err
+	}
+	r.Baselines.DB = *f.db
+	r.Baselines.NoColor = *f.noColor
+	r.Color = NewColor(os.Stdout, *f.noColor)
+	r.N, r.Benchtime, r.Count, r.Outliers = *f.n, *f.benchtime, *f.count, *f.outliers
+	return nil
+}
+
+// LatencyTracing reports whether -latency-trace was given, for commands
+// with modes that can't be traced.
+func (r *Runner) LatencyTracing() bool {
+	return *r.flags.latencyTrace != ""
+}
+
+// Start prepares the process for measuring, after Parse and the
+// command's own checks: it creates the -latency-trace file, checks the
+// machine, which fails with -strict on a noisy one, and applies the GC
+// settings and priority. From then on SIGINT and SIGTERM interrupt the
+// run rather than kill it; see CatchInterrupts.
+func (r *Runner) Start() error {
+	var err error
+	if r.Trace, err = OpenLatencyTrace(*r.flags.latencyTrace, r.Bench, *r.flags.latencySample, r.Count); err != nil {
+		return err
+	}
+	if err := CheckMachine(r.Notes, CurrentMachine(), *r.flags.strict); err != nil {
+		return err
+	}
+	r.GC.Apply()
+	r.Priority = r.Priority.Apply(r.Notes)
+	CatchInterrupts(r.Notes)
+	return nil
+}
+
+// Variant returns variant name ready to measure: warmed up by calling op
+// as -warmup says, with its iterations calibrated on loop when -time is
+// set, and timed by loop, which must call the operation n times and
+// return how long that took. op is also the operation -latency-trace
+// times. If r doesn't measure the variant in this process (see
+// -isolate), only the name is set, and neither op nor loop is called.
+//
+// loop stays with the command, written out for its operation, so that
+// the timed loop makes no call the operation doesn't make itself.
+func (r *Runner) Variant(name string, op func(), loop func(n int) time.Duration) Variant {
+	if !r.Prof.Runs(name) {
+		return Variant{Name: name}
+	}
+	r.Warm.Run(op)
+	n := Iterations(r.N, r.Benchtime, loop)
+	return Variant{Name: name, N: n, Timed: func() time.Duration { return loop(n) }, Op: op}
+}
+
+// Measure measures variants as -count, -shuffle and -isolate say, drops
+// outliers beyond -outliers, and records the latencies of those with an
+// Op for -latency-trace. It returns the results in variant order, or the
+// first profiling or isolation error, and warns on Notes of any result
+// that ran throttled. If the run is interrupted, the results are only
+// those measured before it stopped (see Collected), so a command checks
+// Partial before reporting them by position.
+func (r *Runner) Measure(variants []Variant) ([]Result, error) {
+	results := r.Prof.MeasureAll(variants, r.Count, r.Shuffle)
+	for i := range results {
+		results[i] = results[i].WithoutOutliers(r.Outliers)
+	}
+	for i, v := range variants {
+		if v.Op != nil && len(results[i].Samples) > 0 {
+			r.Trace.Record(v.Name, v.N, v.Op)
+		}
+	}
+	if err := r.Prof.Err(); err != nil {
+		return nil, err
+	}
+	results = r.Collected(results)
+	_ = CheckThrottling(r.Notes, results)
+	return results, nil
+}
+
+// Collected returns results as measured, or, once the run is
+// Interrupted, only those with samples, marked Partial. Measure calls it;
+// commands that measure with the Profiler themselves call it on all they
+// measured before Finish.
+func (r *Runner) Collected(results []Result) []Result {
+	if !r.Interrupted() {
+		return results
+	}
+	return Partial(results)
+}
+
+// Interrupted reports whether the run has been interrupted (see
+// CatchInterrupts), for commands that measure in sections of their own
+// to stop before starting the next. Once it reports true, r is Partial.
+func (r *Runner) Interrupted() bool {
+	if Interrupted() {
+		r.partial = true
+	}
+	return r.partial
+}
+
+// Partial reports whether the run was interrupted before Measure or
+// Collected returned the last results, so they may not cover every
+// variant. A command's own text report, which reports variants by
+// position, is then skipped: Finish lists the results there are instead,
+// and ends with ErrInterrupted.
+func (r *Runner) Partial() bool {
+	return r.partial
+}
+
+// Finish ends a run after the text report, if any: it closes the
+// latency trace and gives results their Latency digests from it, writes
+// results to stdout in a machine-readable -format, with junit failing
+// the variants -fail-on-regression fails, or their latency percentiles
+// after the text report, and applies -save, -compare,
+// -fail-on-regression and -db, writing the comparison after the text
+// report or, in other formats, to Notes. It returns ErrRegression if
+// -fail-on-regression fails the run, or else ErrInterrupted for a
+// Partial one, whose text report it writes itself.
+func (r *Runner) Finish(results []Result) error {
+	if err := r.Trace.Close(); err != nil {
+		return err
+	}
+	for i := range results {
+		results[i].Latency = r.Trace.Digest(results[i].Name)
+	}
+	if r.partial {
+		// Earlier sections finished, but belong to a partial run too
+		for i := range results {
+			results[i].Partial = true
+		}
+	}
+	w := io.Writer(os.Stdout)
+	if r.Format == FormatJUnit {
+		if err := WriteJUnit(os.Stdout, r.Bench, CurrentMachine(), results, r.Baselines.Regressions(r.Bench, results)); err != nil {
+			return err
+		}
+		w = r.Notes
+	} else if r.Format != FormatText {
+		if err := Write(os.Stdout, r.Format, r.Bench, results); err != nil {
+			return err
+		}
+		w = r.Notes
+	} else if r.partial {
+		r.Reporter().Partial(results)
+	} else if r.Trace != nil {
+		r.Reporter().Latency(results)
+	}
+	if err := r.Baselines.Apply(w, r.Bench, results); err != nil {
+		return err
+	}
+	if r.partial {
+		return ErrInterrupted
+	}
+	return nil
+}
+
+// Reporter returns a Reporter for the text report on stdout.
+func (r *Runner) Reporter() Reporter {
+	return Reporter{W: os.Stdout, Color: r.Color}
+}
+
+// Settings returns the header lines for the settings that change how r
+// measures, such as "Shuffle: 42", for Reporter.Header. Settings left at
+// their defaults are left out.
+func (r *Runner) Settings() []string {
+	var lines []string
+	if r.Shuffle.On {
+		lines = append(lines, "Shuffle: "+r.Shuffle.String())
+	}
+	if r.Prof.Isolate {
+		lines = append(lines, "Isolate: a fresh process per variant")
+	}
+	if !r.GC.IsZero() {
+		lines = append(lines, "GC: "+r.GC.String())
+	}
+	if !r.Priority.IsZero() {
+		lines = append(lines, "Priority: "+r.Priority.String())
+	}
+	if r.Prof.Cycles {
+		lines = append(lines, TSCRate())
+	}
+	return lines
+}
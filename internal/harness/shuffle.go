@@ -0,0 +1,136 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidShuffle is returned by ParseShuffle for a spec that is not
+// off, on or a seed.
+var ErrInvalidShuffle = errors.New("harness: shuffle must be off, on or an integer seed")
+
+// ErrShuffleProfile is returned by CheckShuffle when shuffling would
+// interleave runs that a profile needs back to back.
+var ErrShuffleProfile = errors.New("harness: -shuffle can't be combined with the -*profile-dir flags or -folded-dir")
+
+// ShuffleUsage is the help text for a -shuffle flag.
+const ShuffleUsage = "interleave variants' runs in a random order each repetition: off, on (seeded from the clock) or a seed"
+
+// Shuffle is a parsed -shuffle flag, as go test -shuffle takes it.
+//
+// Measured one after another, each variant runs on the caches, branch
+// predictors and CPU frequency the one before left behind, so a fixed
+// order favors the same variants every time. With Shuffle on, MeasureAll
+// instead runs every variant once per repetition, in an order drawn
+// afresh each time, and the bias averages out across the samples. The
+// same Seed gives the same orders, to reproduce a run.
+type Shuffle struct {
+	On   bool
+	Seed int64
+}
+
+// ParseShuffle parses a -shuffle value: "off" or "" for the fixed order,
+// "on" for a seed taken from the clock, or an integer seed.
+func ParseShuffle(s string) (Shuffle, error) {
+	switch s {
+	case "", "off":
+		return Shuffle{}, nil
+	case "on":
+		return Shuffle{On: true, Seed: time.Now().UnixNano()}, nil
+	}
+	seed, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return Shuffle{}, fmt.Errorf("%w: got %q", ErrInvalidShuffle, s)
+	}
+	return Shuffle{On: true, Seed: seed}, nil
+}
+
+// String returns the spec form accepted by ParseShuffle: "off", or the
+// seed, so a shuffled run can be repeated with -shuffle <seed>.
+func (s Shuffle) String() string {
+	if !s.On {
+		return "off"
+	}
+	return strconv.FormatInt(s.Seed, 10)
+}
+
+// CheckShuffle validates s against p. Profiles cover each variant's runs
+// as one block, so they can't be taken while runs are interleaved.
+func CheckShuffle(s Shuffle, p *Profiler) error {
+	if s.On && p != nil && (p.CPUDir != "" || p.MemDir != "" || p.BlockDir != "" || p.MutexDir != "" || p.FoldedDir != "") {
+		return ErrShuffleProfile
+	}
+	return nil
+}
+
+// Variant is a variant ready to measure, set up, warmed and calibrated:
+// Timed runs its loop N times and returns how long that took, as the
+// timed func passed to Measure does. Op, if set, runs one operation on
+// its own, for Runner.Measure to time for -latency-trace.
+type Variant struct {
+	Name  string
+	N     int
+	Timed func() time.Duration
+	Op    func()
+}
+
+// MeasureAll measures each of variants count times and returns their
+// Results in the same order. With s off it measures each in turn, as
+// Measure does, so a variant's runs are back to back. With s on, each of the
+// count repetitions runs every variant once, in an order shuffled by s;
+// allocations and CPU frequencies are still tracked per variant.
+//
+// Once the run is Interrupted, MeasureAll measures no further variants,
+// or with s on no further repetitions; the variants it skips come back
+// with no samples.
+func (p *Profiler) MeasureAll(variants []Variant, count int, s Shuffle) []Result {
+	results := make([]Result, len(variants))
+	call := p.nextCall()
+	if !s.On {
+		for i, v := range variants {
+			if Interrupted() {
+				results[i] = Result{Name: v.Name, N: v.N}
+				continue
+			}
+			results[i] = p.run(call, v.Name, v.N, count, v.Timed)
+		}
+		return results
+	}
+
+	mallocs := make([]uint64, len(variants))
+	bytes := make([]uint64, len(variants))
+	timed := make([]func() time.Duration, len(variants))
+	for i, v := range variants {
+		results[i] = Result{Name: v.Name, N: v.N, Samples: make([]time.Duration, 0, count)}
+		timed[i] = p.gc(p.cycles(v.Timed, &results[i].Cycles))
+	}
+	rng := rand.New(rand.NewPCG(uint64(s.Seed), 0))
+	var before, after runtime.MemStats
+	for range count {
+		if Interrupted() {
+			break
+		}
+		for _, i := range rng.Perm(len(variants)) {
+			freq := startFreq()
+			runtime.ReadMemStats(&before)
+			d := timed[i]()
+			runtime.ReadMemStats(&after)
+			freq.Stop(&results[i])
+			results[i].Samples = append(results[i].Samples, d)
+			mallocs[i] += after.Mallocs - before.Mallocs
+			bytes[i] += after.TotalAlloc - before.TotalAlloc
+			results[i].GCs += gcCycles(&before, &after)
+		}
+	}
+	for i := range results {
+		if ops := uint64(results[i].N) * uint64(len(results[i].Samples)); ops > 0 {
+			results[i].AllocsPerOp = int64(mallocs[i] / ops)
+			results[i].BytesPerOp = int64(bytes[i] / ops)
+		}
+	}
+	return results
+}
@@ -0,0 +1,127 @@
+package harness
+
+import (
+	"math"
+	"slices"
+)
+
+// exactLimit is the largest combined sample size for which MannWhitneyU
+// computes the exact p-value; above it the normal approximation is
+// within the precision anyone reads a p-value to.
+const exactLimit = 50
+
+// MannWhitneyU returns the two-sided p-value of the Mann-Whitney U test
+// that xs and ys come from the same distribution. It is the test
+// benchstat uses: it compares ranks rather than means, so it assumes
+// nothing about the shape of the distribution, which for benchmark
+// timings is rarely normal and often has a long tail.
+//
+// Small samples without ties get the exact p-value, so 5 runs against 5
+// fully separated runs give p=0.008 as in benchstat; the rest use the
+// normal approximation with tie and continuity corrections. An empty
+// sample, or one where every value ties, gives 1.
+func MannWhitneyU(xs, ys []float64) float64 {
+	n1, n2 := len(xs), len(ys)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	// Rank the pooled samples, averaging the ranks of ties
+	type obs struct {
+		v     float64
+		fromX bool
+	}
+	pooled := make([]obs, 0, n1+n2)
+	for _, x := range xs {
+		pooled = append(pooled, obs{x, true})
+	}
+	for _, y := range ys {
+		pooled = append(pooled, obs{y, false})
+	}
+	slices.SortFunc(pooled, func(a, b obs) int {
+		switch {
+		case a.v < b.v:
+			return -1
+		case a.v > b.v:
+			return 1
+		}
+		return 0
+	})
+	var rankX, tieTerm float64
+	for i := 0; i < len(pooled); {
+		j := i + 1
+		for j < len(pooled) && pooled[j].v == pooled[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // Mean of ranks i+1..j
+		for _, o := range pooled[i:j] {
+			if o.fromX {
+				rankX += rank
+			}
+		}
+		t := float64(j - i)
+		tieTerm += t*t*t - t
+		i = j
+	}
+
+	u1 := rankX - float64(n1*(n1+1))/2
+	u := min(u1, float64(n1*n2)-u1)
+	if tieTerm == 0 && n1+n2 <= exactLimit {
+		return min(1, 2*uCDF(n1, n2, int(u)))
+	}
+
+	n := float64(n1 + n2)
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (float64(n1*n2)/2 - u - 0.5) / math.Sqrt(variance)
+	if z <= 0 {
+		return 1
+	}
+	return min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// uCDF returns P(U <= u) for samples of n1 and n2 with no ties, by
+// counting the arrangements of the pooled ranks that give each U.
+func uCDF(n1, n2, u int) float64 {
+	// ways[m][k] counts the orderings of m x's and, for the current n, n
+	// y's in which k (x, y) pairs have the x ranked above the y: U = k.
+	ways := make([][]float64, n1+1)
+	for m := range ways {
+		ways[m] = make([]float64, n1*n2+1)
+	}
+	ways[0][0] = 1
+	for n := 0; n <= n2; n++ {
+		for m := 0; m <= n1; m++ {
+			if n == 0 && m == 0 {
+				continue
+			}
+			// Arrangements of m x's and n y's end in an x, which beats all
+			// n y's, or in a y, which beats none. Iterating n outward and m
+			// inward, ways[m] still holds the counts for n-1 y's until
+			// it is overwritten here.
+			next := make([]float64, n1*n2+1)
+			if m > 0 {
+				for k := n; k < len(next); k++ {
+					next[k] += ways[m-1][k-n]
+				}
+			}
+			if n > 0 {
+				for k := range next {
+					next[k] += ways[m][k]
+				}
+			}
+			ways[m] = next
+		}
+	}
+
+	var below, total float64
+	for k, c := range ways[n1] {
+		if k <= u {
+			below += c
+		}
+		total += c
+	}
+	return below / total
+}
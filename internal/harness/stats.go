@@ -0,0 +1,89 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// ErrInvalidCount is returned by CheckCount for a count below 1.
+var ErrInvalidCount = errors.New("harness: count must be at least 1")
+
+// CountUsage is the help text for a -count flag.
+const CountUsage = "run each variant this many times and report min/median/mean/max/stddev"
+
+// CheckCount validates a -count value.
+func CheckCount(count int) error {
+	if count < 1 {
+		return fmt.Errorf("%w: got %d", ErrInvalidCount, count)
+	}
+	return nil
+}
+
+// Stats summarizes repeated measurements of one variant, in ns/op.
+type Stats struct {
+	Count  int
+	Min    float64
+	Median float64
+	Mean   float64
+	Max    float64
+	StdDev float64 // Sample standard deviation; 0 for fewer than 2 samples
+}
+
+// Summarize computes Stats over xs. It does not modify xs.
+func Summarize(xs []float64) Stats {
+	if len(xs) == 0 {
+		return Stats{}
+	}
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+
+	st := Stats{
+		Count:  len(sorted),
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+		Median: median(sorted),
+	}
+	var sum float64
+	for _, x := range sorted {
+		sum += x
+	}
+	st.Mean = sum / float64(len(sorted))
+	if len(sorted) > 1 {
+		var sq float64
+		for _, x := range sorted {
+			sq += (x - st.Mean) * (x - st.Mean)
+		}
+		st.StdDev = math.Sqrt(sq / float64(len(sorted)-1))
+	}
+	return st
+}
+
+// median returns the median of an already sorted, non-empty slice.
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// WriteSummary writes a table of each result's Stats, for text output
+// when variants were run more than once. Runs counts the kept samples and
+// Dropped the outliers removed before summarizing.
+func WriteSummary(w io.Writer, results []Result) error {
+	if _, err := fmt.Fprintf(w, "  %-24s %6s %7s %10s %10s %10s %10s %10s  (ns/op)\n",
+		"Variant", "Runs", "Dropped", "Min", "Median", "Mean", "Max", "StdDev"); err != nil {
+		return err
+	}
+	for _, r := range results {
+		st := r.Stats()
+		if _, err := fmt.Fprintf(w, "  %-24s %6d %7d %10.2f %10.2f %10.2f %10.2f %10.2f\n",
+			r.Name, st.Count, r.Dropped, st.Min, st.Median, st.Mean, st.Max, st.StdDev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package harness
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// StreamRecord is one result in a json Stream: the -save fields of a
+// BaselineResult, after when it was measured, in which round, and on
+// which host, so that streams from several machines can be concatenated.
+type StreamRecord struct {
+	Time     time.Time `json:"time"`
+	Round    int       `json:"round"`
+	Hostname string    `json:"hostname,omitempty"`
+	BaselineResult
+}
+
+// Stream writes results round by round as they are measured, for runs
+// that go on for hours, such as a soak test, where waiting for the end
+// to write anything isn't an option. Each Write is flushed, so the output
+// can be followed with tail -f or piped on as it grows:
+//
+//   - text is a line per result: the time, variant, ns/op and memory.
+//   - csv is a header row once, then a row per result with time and
+//     round columns before those WriteCSV writes.
+//   - json is JSON Lines, one StreamRecord per line.
+type Stream struct {
+	w      io.Writer
+	f      Format
+	bench  string
+	cw     *csv.Writer // csv only
+	header bool        // Whether the csv header is written
+}
+
+// NewStream returns a Stream writing benchmark bench's results to w in
+// format f: text, csv or json.
+func NewStream(w io.Writer, f Format, bench string) (*Stream, error) {
+	switch f {
+	case FormatText, FormatJSON:
+		return &Stream{w: w, f: f, bench: bench}, nil
+	case FormatCSV:
+		return &Stream{w: w, f: f, bench: bench, cw: csv.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q can't be streamed", ErrUnknownFormat, f)
+	}
+}
+
+// Write writes results measured in round, numbered from 1, at time at on
+// machine m.
+func (s *Stream) Write(round int, at time.Time, m Machine, results []Result) error {
+	switch s.f {
+	case FormatCSV:
+		if !s.header {
+			if err := s.cw.Write(append([]string{"time", "round"}, CSVHeader...)); err != nil {
+				return err
+			}
+			s.header = true
+		}
+		for _, r := range results {
+			row := append([]string{at.UTC().Format(time.RFC3339), strconv.Itoa(round)}, csvRow(s.bench, m, r)...)
+			if err := s.cw.Write(row); err != nil {
+				return err
+			}
+		}
+		s.cw.Flush()
+		return s.cw.Error()
+	case FormatJSON:
+		b := NewBaseline(s.bench, m, results)
+		for _, br := range b.Results {
+			data, err := json.Marshal(StreamRecord{Time: at, Round: round, Hostname: m.Hostname, BaselineResult: br})
+			if err != nil {
+				return err
+			}
+			if _, err := s.w.Write(append(data, '\n')); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		for _, r := range results {
+			if _, err := fmt.Fprintf(s.w, "%s %-32s %10.2f ns/op%s  %s\n",
+				at.Format(time.DateTime), r.Name, r.NsPerOp(), r.CyclesText(), r.MemPerOp()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package harness
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteSweep writes results as a long-format table, one row per result.
+// Variants named the way go test names sub-benchmarks, with key=value
+// segments ("queue/ring/size=64"), get a column per key, so a parameter
+// sweep reads, sorts and pivots like a spreadsheet. A row without a key
+// shows "-" in its column:
+//
+//	Variant     size  batch  ns/op  B/op  allocs/op
+//	queue/ring  64    -      54.01  0     0
+//	tick/batch  -     100    6.12   0     0
+func WriteSweep(w io.Writer, results []Result) error {
+	type row struct {
+		variant string
+		params  map[string]string
+		r       Result
+	}
+	var keys []string
+	rows := make([]row, len(results))
+	for i, r := range results {
+		rw := row{params: make(map[string]string), r: r}
+		var base []string
+		for _, seg := range strings.Split(r.Name, "/") {
+			k, v, ok := strings.Cut(seg, "=")
+			if !ok {
+				base = append(base, seg)
+				continue
+			}
+			if !slices.Contains(keys, k) {
+				keys = append(keys, k)
+			}
+			rw.params[k] = v
+		}
+		rw.variant = strings.Join(base, "/")
+		rows[i] = rw
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := slices.Concat([]string{"Variant"}, keys, []string{"ns/op", "B/op", "allocs/op"})
+	fmt.Fprintf(tw, "  %s\n", strings.Join(header, "\t"))
+	for _, rw := range rows {
+		cells := []string{rw.variant}
+		for _, k := range keys {
+			v, ok := rw.params[k]
+			if !ok {
+				v = "-"
+			}
+			cells = append(cells, v)
+		}
+		cells = append(cells, fmt.Sprintf("%.2f", rw.r.NsPerOp()),
+			fmt.Sprint(rw.r.BytesPerOp), fmt.Sprint(rw.r.AllocsPerOp))
+		fmt.Fprintf(tw, "  %s\n", strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
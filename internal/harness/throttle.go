@@ -0,0 +1,152 @@
+package harness
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ThrottleDrop is the fraction by which the CPU frequency may fall while
+// a variant runs before CheckThrottling flags it.
+const ThrottleDrop = 0.10
+
+// freqInterval is how often a freqMonitor reads the frequency. Each read
+// is a handful of sysfs files, microseconds every 50ms, so it costs the
+// timed loop well under 0.1%.
+const freqInterval = 50 * time.Millisecond
+
+// freqFiles are the open cpufreq files holding each CPU's current
+// frequency in kHz. They stay open so that reading them, which happens
+// inside the window Measure counts allocations over, allocates nothing.
+// There are none where cpufreq isn't exposed, as in most VMs.
+var freqFiles = sync.OnceValue(func() []*os.File {
+	paths, _ := filepath.Glob(sysCPU + "/cpu[0-9]*/cpufreq/scaling_cur_freq")
+	var files []*os.File
+	for _, p := range paths {
+		if f, err := os.Open(p); err == nil {
+			files = append(files, f)
+		}
+	}
+	return files
+})
+
+// readMHz returns the highest current frequency of any CPU in MHz, or 0
+// if none can be read, using buf for the reads. The busy CPU running the
+// timed loop is the fastest one, so a drop here is the loop's own clock
+// slowing down.
+func readMHz(buf []byte) int {
+	var maxKHz int
+	for _, f := range freqFiles() {
+		n, _ := f.ReadAt(buf, 0)
+		khz := 0
+		for _, c := range buf[:n] {
+			if c < '0' || c > '9' {
+				break
+			}
+			khz = khz*10 + int(c-'0')
+		}
+		maxKHz = max(maxKHz, khz)
+	}
+	return maxKHz / 1000
+}
+
+// freqMonitor samples the CPU frequency in the background while
+// variants run, keeping the lowest and highest reading.
+type freqMonitor struct {
+	stop chan struct{}
+	done chan struct{}
+	buf  [32]byte
+	mu   sync.Mutex
+	min  int
+	max  int
+}
+
+// startFreq starts monitoring the frequency, or returns nil where
+// cpufreq isn't exposed. A nil *freqMonitor is a no-op.
+func startFreq() *freqMonitor {
+	if len(freqFiles()) == 0 {
+		return nil
+	}
+	fm := &freqMonitor{stop: make(chan struct{}), done: make(chan struct{})}
+	fm.record()
+	go func() {
+		defer close(fm.done)
+		t := time.NewTicker(freqInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-fm.stop:
+				return
+			case <-t.C:
+				fm.record()
+			}
+		}
+	}()
+	return fm
+}
+
+// record takes one reading. The lock also guards buf.
+func (fm *freqMonitor) record() {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	mhz := readMHz(fm.buf[:])
+	if mhz == 0 {
+		return
+	}
+	if fm.min == 0 || mhz < fm.min {
+		fm.min = mhz
+	}
+	fm.max = max(fm.max, mhz)
+}
+
+// Stop takes a last reading, stops the monitor and widens r's MinMHz and
+// MaxMHz to cover what it saw.
+func (fm *freqMonitor) Stop(r *Result) {
+	if fm == nil {
+		return
+	}
+	close(fm.stop)
+	<-fm.done
+	fm.record()
+	if fm.min == 0 {
+		return
+	}
+	if r.MinMHz == 0 || fm.min < r.MinMHz {
+		r.MinMHz = fm.min
+	}
+	r.MaxMHz = max(r.MaxMHz, fm.max)
+}
+
+// FreqDrop returns how far the CPU frequency fell below its peak while r
+// was measured, as a fraction: 0.25 means the clock ran as slow as 75% of
+// its fastest. It is 0 where the frequency couldn't be read.
+func (r Result) FreqDrop() float64 {
+	if r.MaxMHz == 0 {
+		return 0
+	}
+	return 1 - float64(r.MinMHz)/float64(r.MaxMHz)
+}
+
+// CheckThrottling writes a warning to w naming every result whose CPU
+// frequency fell by more than ThrottleDrop while it ran. Thermal or power
+// throttling slows a loop without changing its code, so a variant that
+// ran throttled can't be compared with one that didn't, and the TSC
+// ticker's cycles-per-ns calibration, taken once, no longer holds.
+func CheckThrottling(w io.Writer, results []Result) error {
+	var b strings.Builder
+	for _, r := range results {
+		if drop := r.FreqDrop(); drop > ThrottleDrop {
+			fmt.Fprintf(&b, "!!!   - %s: %d -> %d MHz (-%.0f%%)\n", r.Name, r.MaxMHz, r.MinMHz, drop*100)
+		}
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "!!! WARNING: CPU frequency dropped while these variants ran (throttling?):\n%s"+
+		"!!! Their ns/op are suspect; let the machine cool, or set the performance governor and turn off turbo.\n", b.String())
+	return err
+}
@@ -0,0 +1,48 @@
+package harness
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/google/pprof/profile"
+)
+
+// FuncShare is one function's share of the CPU samples in a profile.
+type FuncShare struct {
+	Name    string
+	Samples int64
+	Percent float64 // Of all samples in the profile
+}
+
+// TopFunctions returns the n functions the most CPU samples in p were
+// taken in, most first, with the total sample count. A sample counts for
+// the function at the top of its stack only (pprof's "flat"), with
+// inlined calls as functions of their own, so the list says where the
+// time went rather than which callers it went through.
+func TopFunctions(p *profile.Profile, n int) ([]FuncShare, int64) {
+	counts := make(map[string]int64)
+	var total int64
+	for _, s := range p.Sample {
+		if len(s.Value) == 0 || len(s.Location) == 0 {
+			continue
+		}
+		total += s.Value[0]
+		// The leaf location's first Line is its innermost inlined call
+		loc := s.Location[0]
+		name := fmt.Sprintf("0x%x", loc.Address)
+		if len(loc.Line) > 0 && loc.Line[0].Function != nil {
+			name = loc.Line[0].Function.Name
+		}
+		counts[name] += s.Value[0]
+	}
+
+	top := make([]FuncShare, 0, len(counts))
+	for name, c := range counts {
+		top = append(top, FuncShare{Name: name, Samples: c, Percent: float64(c) / float64(total) * 100})
+	}
+	slices.SortFunc(top, func(a, b FuncShare) int {
+		return cmp.Or(cmp.Compare(b.Samples, a.Samples), cmp.Compare(a.Name, b.Name))
+	})
+	return top[:min(n, len(top))], total
+}
@@ -0,0 +1,75 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidWarmup is returned by ParseWarmup for a spec that is neither
+// a non-negative iteration count nor a non-negative duration.
+var ErrInvalidWarmup = errors.New("harness: warmup must be an iteration count or a duration")
+
+// WarmupUsage is the help text for a -warmup flag.
+const WarmupUsage = "untimed run before each variant: iterations (1000000) or duration (200ms)"
+
+// Warmup is how long to exercise a variant before timing it, so the timed
+// loop doesn't pay for cold caches, lazy initialization and untrained
+// branch predictors. Without it, whichever variant runs first is measured
+// cold and every later one warm. The zero Warmup does nothing.
+type Warmup struct {
+	N int           // Iterations; used when D is zero
+	D time.Duration // Wall time
+}
+
+// ParseWarmup parses a -warmup value: a plain integer is an iteration
+// count ("1000000"), anything else a duration ("200ms"). "" and "0" mean
+// no warmup.
+func ParseWarmup(s string) (Warmup, error) {
+	if s == "" {
+		return Warmup{}, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 {
+			return Warmup{}, fmt.Errorf("%w: got %q", ErrInvalidWarmup, s)
+		}
+		return Warmup{N: n}, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return Warmup{}, fmt.Errorf("%w: got %q", ErrInvalidWarmup, s)
+	}
+	return Warmup{D: d}, nil
+}
+
+// IsZero reports whether w does nothing.
+func (w Warmup) IsZero() bool {
+	return w.N == 0 && w.D == 0
+}
+
+// String returns the spec form accepted by ParseWarmup.
+func (w Warmup) String() string {
+	if w.D > 0 {
+		return w.D.String()
+	}
+	return strconv.Itoa(w.N)
+}
+
+// Run calls op until the warmup is spent: N times, or repeatedly for D.
+// In duration mode the clock is read once per 1024 calls, so cheap ops
+// are not dominated by time.Since.
+func (w Warmup) Run(op func()) {
+	if w.D > 0 {
+		start := time.Now()
+		for time.Since(start) < w.D {
+			for i := 0; i < 1024; i++ {
+				op()
+			}
+		}
+		return
+	}
+	for i := 0; i < w.N; i++ {
+		op()
+	}
+}
@@ -0,0 +1,29 @@
+package hash
+
+import "hash/fnv"
+
+// FNV-1a's 64-bit parameters
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// FNV returns the 64-bit FNV-1a hash of b from hash/fnv, as code written
+// against hash.Hash64 computes it: a Write of the key into the hash's
+// state, then Sum64.
+func FNV(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+// FNV1a returns the 64-bit FNV-1a hash of b, the same value as FNV,
+// computed in place: an xor and a multiply per byte.
+func FNV1a(b []byte) uint64 {
+	h := uint64(fnvOffset)
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= fnvPrime
+	}
+	return h
+}
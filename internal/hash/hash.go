@@ -0,0 +1,40 @@
+// Package hash provides non-cryptographic hashes of byte keys, for
+// benchmarking the choice behind a sharded structure's shard index or a
+// hash table's bucket.
+//
+// This package offers four functions with the same signature:
+//   - Maphash: Standard library hash/maphash, the runtime's map hash
+//     (AES-based on CPUs that have AES instructions)
+//   - FNV: Standard library hash/fnv's 64-bit FNV-1a, through its
+//     hash.Hash64 methods
+//   - FNV1a: Optimized FNV-1a written out as a loop the compiler can
+//     inline into the caller
+//   - XX: Optimized xxHash64, which mixes 8 bytes per step in four
+//     independent lanes
+//
+// FNV-1a handles a byte per multiply, so it is quick on short keys and
+// slow on long ones; maphash and xxHash take 8 or more bytes per step and
+// pull ahead as keys grow. maphash's seed changes with every process, so
+// its values can't be stored or sent elsewhere; the others are fixed
+// functions of the key.
+package hash
+
+import "hash/maphash"
+
+// seed is Maphash's seed, random per process as maphash requires.
+var seed = maphash.MakeSeed()
+
+// Maphash returns the maphash of b with a per-process seed.
+func Maphash(b []byte) uint64 {
+	return maphash.Bytes(seed, b)
+}
+
+// Key returns a key of size bytes to hash: a counting pattern, since none
+// of the hashes is faster or slower on particular bytes.
+func Key(size int) []byte {
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
@@ -0,0 +1,49 @@
+package hash_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/hash"
+)
+
+// Sink variables to prevent compiler from eliminating benchmark loops
+var sinkUint64 uint64
+
+// keySizes are the key sizes the benchmarks hash, in bytes.
+var keySizes = []int{8, 16, 64, 256, 1024}
+
+// BenchmarkHash hashes a key of each size, reporting MB/s as well as
+// ns/op.
+func BenchmarkHash(b *testing.B) {
+	for _, size := range keySizes {
+		key := hash.Key(size)
+		for _, h := range hashes {
+			b.Run(fmt.Sprintf("%s/size=%d", h.name, size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				var sum uint64
+				for i := 0; i < b.N; i++ {
+					sum ^= h.sum(key)
+				}
+				sinkUint64 = sum
+			})
+		}
+	}
+}
+
+// BenchmarkFNV1a_Inlined calls FNV1a directly, where the compiler can
+// inline it, rather than through BenchmarkHash's function value.
+func BenchmarkFNV1a_Inlined(b *testing.B) {
+	for _, size := range keySizes {
+		key := hash.Key(size)
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			var sum uint64
+			for i := 0; i < b.N; i++ {
+				sum ^= hash.FNV1a(key)
+			}
+			sinkUint64 = sum
+		})
+	}
+}
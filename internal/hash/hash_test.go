@@ -0,0 +1,85 @@
+package hash_test
+
+import (
+	"hash/maphash"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/hash"
+)
+
+// hashes are the functions under test.
+var hashes = []struct {
+	name string
+	sum  func([]byte) uint64
+}{
+	{"Maphash", hash.Maphash},
+	{"FNV", hash.FNV},
+	{"FNV1a", hash.FNV1a},
+	{"XX", hash.XX},
+}
+
+func TestXX(t *testing.T) {
+	for _, tt := range []struct {
+		key  []byte
+		want uint64
+	}{
+		{nil, 0xef46db3751d8e999},
+		{[]byte("abc"), 0x44bc2cf5ad770999},
+		{[]byte("hello, world"), 0xb33a384e6d1b1242},
+		{hash.Key(100), 0x6ac1e58032166597},
+	} {
+		if got := hash.XX(tt.key); got != tt.want {
+			t.Errorf("XX(%d bytes) = %#x, want %#x", len(tt.key), got, tt.want)
+		}
+	}
+}
+
+func TestFNV1a(t *testing.T) {
+	if got := hash.FNV1a([]byte("a")); got != 0xaf63dc4c8601ec8c {
+		t.Errorf("FNV1a(a) = %#x, want 0xaf63dc4c8601ec8c", got)
+	}
+	for size := range 70 {
+		key := hash.Key(size)
+		if a, b := hash.FNV1a(key), hash.FNV(key); a != b {
+			t.Errorf("%d bytes: FNV1a = %#x, FNV = %#x", size, a, b)
+		}
+	}
+}
+
+func TestMaphash(t *testing.T) {
+	key := hash.Key(40)
+	if a, b := hash.Maphash(key), hash.Maphash(key); a != b {
+		t.Errorf("Maphash of one key = %#x, then %#x", a, b)
+	}
+	if hash.Maphash(key) == maphash.Bytes(maphash.MakeSeed(), key) {
+		t.Error("Maphash matches a new seed's hash; want a seed of its own")
+	}
+}
+
+// TestHashes_Spread checks each hash tells apart keys a byte apart, in
+// the first and in the last byte.
+func TestHashes_Spread(t *testing.T) {
+	for _, h := range hashes {
+		for _, size := range []int{1, 8, 33, 1024} {
+			key := hash.Key(size)
+			base := h.sum(key)
+			for _, i := range []int{0, size - 1} {
+				key[i]++
+				if h.sum(key) == base {
+					t.Errorf("%s: %d-byte keys differing in byte %d hash alike", h.name, size, i)
+				}
+				key[i]--
+			}
+		}
+	}
+}
+
+func TestHashes_NoAlloc(t *testing.T) {
+	key := hash.Key(64)
+	var sink uint64
+	for _, h := range hashes {
+		if n := testing.AllocsPerRun(100, func() { sink ^= h.sum(key) }); n != 0 {
+			t.Errorf("%s allocated %v times, want 0", h.name, n)
+		}
+	}
+}
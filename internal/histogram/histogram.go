@@ -0,0 +1,131 @@
+// Package histogram provides an HDR-style latency histogram with
+// lock-free recording.
+//
+// Values are bucketed log-linearly: every power-of-two range is split into
+// 128 equal sub-buckets, so any recorded value is reported within 1/128
+// (about 0.8%) of its true value, from 1ns up to the full int64 range,
+// in a fixed 57KB of counters. Values below 256 are recorded exactly.
+//
+// Record is a couple of atomic adds and never allocates, so it can sit in
+// the consumer's hot loop, and any number of goroutines may record into
+// the same Histogram. Quantile and Max may be called while recording is in
+// progress; they then see some recent values and not others.
+package histogram
+
+import (
+	"math"
+	"math/bits"
+	"sync/atomic"
+)
+
+// subBits is the number of significant bits kept per value.
+const subBits = 8
+
+const (
+	subCount = 1 << subBits       // Exact buckets for values below this
+	subHalf  = 1 << (subBits - 1) // Sub-buckets per power of two above it
+)
+
+// numBuckets covers every non-negative int64.
+const numBuckets = subCount + (63-subBits)*subHalf
+
+// Histogram counts int64 values (typically nanoseconds) in log-linear
+// buckets. The zero value is not usable; create one with New.
+type Histogram struct {
+	counts []atomic.Uint64
+	total  atomic.Uint64
+	max    atomic.Int64
+}
+
+// New creates an empty Histogram.
+func New() *Histogram {
+	return &Histogram{
+		counts: make([]atomic.Uint64, numBuckets),
+	}
+}
+
+// bucket returns the index of the bucket holding v (v >= 0).
+func bucket(v int64) int {
+	u := uint64(v)
+	if u < subCount {
+		return int(u)
+	}
+	shift := bits.Len64(u) - subBits // >= 1
+	return shift<<(subBits-1) + int(u>>shift)
+}
+
+// upper returns the largest value that maps to bucket i.
+func upper(i int) int64 {
+	if i < subCount {
+		return int64(i)
+	}
+	shift := i>>(subBits-1) - 1
+	m := uint64(i - shift<<(subBits-1))
+	hi := (m+1)<<shift - 1
+	if hi > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(hi)
+}
+
+// Record adds one observation of v. Negative values are recorded as 0.
+//
+// Safe for concurrent use.
+func (h *Histogram) Record(v int64) {
+	if v < 0 {
+		v = 0
+	}
+	h.counts[bucket(v)].Add(1)
+	h.total.Add(1)
+
+	for {
+		m := h.max.Load()
+		if v <= m || h.max.CompareAndSwap(m, v) {
+			return
+		}
+	}
+}
+
+// Count returns the number of recorded values.
+func (h *Histogram) Count() uint64 {
+	return h.total.Load()
+}
+
+// Max returns the largest recorded value, exactly, or 0 if empty.
+func (h *Histogram) Max() int64 {
+	return h.max.Load()
+}
+
+// Quantile returns the value at quantile q (0..1): the smallest bucket
+// bound such that at least q of the recorded values are at or below it.
+// The result is never larger than Max. Returns 0 if nothing is recorded.
+func (h *Histogram) Quantile(q float64) int64 {
+	total := h.total.Load()
+	if total == 0 {
+		return 0
+	}
+	q = min(max(q, 0), 1)
+	rank := uint64(math.Ceil(q * float64(total)))
+	rank = max(rank, 1)
+
+	var seen uint64
+	for i := range h.counts {
+		seen += h.counts[i].Load()
+		if seen >= rank {
+			return min(upper(i), h.max.Load())
+		}
+	}
+	// Counts were still being added when total was read
+	return h.max.Load()
+}
+
+// Reset clears all recorded values.
+//
+// Not safe to call concurrently with Record.
+func (h *Histogram) Reset() {
+	for i := range h.counts {
+		h.counts[i].Store(0)
+	}
+	h.total.Store(0)
+	h.max.Store(0)
+}
@@ -0,0 +1,161 @@
+package histogram_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/histogram"
+)
+
+// Sink variables to prevent compiler from eliminating benchmark loops
+var sinkInt64 int64
+
+func TestHistogram_Empty(t *testing.T) {
+	h := histogram.New()
+	if h.Count() != 0 || h.Max() != 0 || h.Quantile(0.5) != 0 {
+		t.Errorf("empty histogram: Count=%d Max=%d p50=%d, want all 0",
+			h.Count(), h.Max(), h.Quantile(0.5))
+	}
+}
+
+func TestHistogram_SmallValuesExact(t *testing.T) {
+	h := histogram.New()
+	for v := int64(1); v <= 100; v++ {
+		h.Record(v)
+	}
+
+	tests := []struct {
+		q    float64
+		want int64
+	}{
+		{0, 1},
+		{0.01, 1},
+		{0.5, 50},
+		{0.99, 99},
+		{1, 100},
+	}
+	for _, tt := range tests {
+		if got := h.Quantile(tt.q); got != tt.want {
+			t.Errorf("Quantile(%v) = %d, want %d", tt.q, got, tt.want)
+		}
+	}
+	if h.Count() != 100 {
+		t.Errorf("expected Count() = 100, got %d", h.Count())
+	}
+	if h.Max() != 100 {
+		t.Errorf("expected Max() = 100, got %d", h.Max())
+	}
+}
+
+// TestHistogram_RelativeError checks that a lone value is reported within
+// the advertised precision across the whole range.
+func TestHistogram_RelativeError(t *testing.T) {
+	const maxErr = 1.0 / 128
+
+	for v := int64(1); v > 0 && v < math.MaxInt64/3; v = v*3 + 1 {
+		h := histogram.New()
+		h.Record(v)
+		h.Record(math.MaxInt64) // Keep Max from clamping the result
+
+		got := h.Quantile(0.5)
+		if got < v {
+			t.Fatalf("value %d reported as %d, below the true value", v, got)
+		}
+		if rel := float64(got-v) / float64(v); rel > maxErr {
+			t.Fatalf("value %d reported as %d, relative error %.4f > %.4f", v, got, rel, maxErr)
+		}
+	}
+}
+
+func TestHistogram_QuantileClampedToMax(t *testing.T) {
+	h := histogram.New()
+	h.Record(1000)
+	if got := h.Quantile(1); got != 1000 {
+		t.Errorf("Quantile(1) = %d, want exact Max 1000", got)
+	}
+}
+
+func TestHistogram_NegativeRecordedAsZero(t *testing.T) {
+	h := histogram.New()
+	h.Record(-5)
+	if h.Count() != 1 || h.Quantile(1) != 0 {
+		t.Errorf("Record(-5): Count=%d p100=%d, want 1 and 0", h.Count(), h.Quantile(1))
+	}
+}
+
+func TestHistogram_Reset(t *testing.T) {
+	h := histogram.New()
+	h.Record(42)
+	h.Reset()
+	if h.Count() != 0 || h.Max() != 0 || h.Quantile(1) != 0 {
+		t.Errorf("after Reset: Count=%d Max=%d p100=%d, want all 0",
+			h.Count(), h.Max(), h.Quantile(1))
+	}
+}
+
+func TestHistogram_ConcurrentRecord(t *testing.T) {
+	const goroutines = 4
+	const perGoroutine = 10000
+
+	h := histogram.New()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				h.Record(int64(g*perGoroutine + i))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if h.Count() != goroutines*perGoroutine {
+		t.Errorf("expected Count() = %d, got %d", goroutines*perGoroutine, h.Count())
+	}
+	if h.Max() != goroutines*perGoroutine-1 {
+		t.Errorf("expected Max() = %d, got %d", goroutines*perGoroutine-1, h.Max())
+	}
+}
+
+func BenchmarkHistogram_Record(b *testing.B) {
+	h := histogram.New()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		h.Record(int64(i & 0xFFFF))
+	}
+	sinkInt64 = h.Max()
+}
+
+func BenchmarkHistogram_Record_Parallel(b *testing.B) {
+	h := histogram.New()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := int64(0)
+		for pb.Next() {
+			h.Record(i & 0xFFFF)
+			i++
+		}
+	})
+	sinkInt64 = h.Max()
+}
+
+func BenchmarkHistogram_Quantile(b *testing.B) {
+	h := histogram.New()
+	for i := int64(0); i < 1<<16; i++ {
+		h.Record(i * 1000)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var v int64
+	for i := 0; i < b.N; i++ {
+		v = h.Quantile(0.99)
+	}
+	sinkInt64 = v
+}
@@ -0,0 +1,121 @@
+package jsonbench
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ErrUnsupportedValue is returned by AppendJSON for a Value JSON can't
+// represent, NaN or an infinity, as json.Marshal rejects them.
+var ErrUnsupportedValue = errors.New("jsonbench: value must be finite")
+
+// AppendJSON appends s's JSON to b, the same bytes json.Marshal writes,
+// but for invalid UTF-8, which some Go versions' json.Marshal replaces
+// with U+FFFD itself rather than its escape.
+func AppendJSON(b []byte, s *Sample) ([]byte, error) {
+	if math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+		return b, fmt.Errorf("%w: %v", ErrUnsupportedValue, s.Value)
+	}
+	b = append(b, `{"host":`...)
+	b = appendString(b, s.Host)
+	b = append(b, `,"service":`...)
+	b = appendString(b, s.Service)
+	b = append(b, `,"metric":`...)
+	b = appendString(b, s.Metric)
+	b = append(b, `,"ts":`...)
+	b = strconv.AppendInt(b, s.Timestamp, 10)
+	b = append(b, `,"value":`...)
+	b = appendFloat(b, s.Value)
+	b = append(b, `,"count":`...)
+	b = strconv.AppendUint(b, s.Count, 10)
+	b = append(b, `,"ok":`...)
+	b = strconv.AppendBool(b, s.OK)
+	b = append(b, `,"tags":`...)
+	if s.Tags == nil {
+		b = append(b, "null"...)
+	} else {
+		b = append(b, '[')
+		for i, t := range s.Tags {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendString(b, t)
+		}
+		b = append(b, ']')
+	}
+	return append(b, '}'), nil
+}
+
+// appendFloat appends f as json.Marshal writes a float64: the shortest
+// decimal that round-trips, in exponent form below 1e-6 or from 1e21,
+// with the exponent unpadded.
+func appendFloat(b []byte, f float64) []byte {
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+	if n := len(b); format == 'e' && n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+		// e-07 to e-7
+		b[n-2] = b[n-1]
+		b = b[:n-1]
+	}
+	return b
+}
+
+// hex is the digits of \u escapes.
+const hex = "0123456789abcdef"
+
+// appendString appends s as a JSON string, escaped as json.Marshal
+// escapes it: quotes, backslashes and control characters, the HTML
+// characters <, > and &, U+2028 and U+2029, and invalid UTF-8 as U+FFFD.
+func appendString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\b':
+				b = append(b, '\\', 'b')
+			case '\f':
+				b = append(b, '\\', 'f')
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+		} else if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hex[r&0xF])
+		} else {
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
@@ -0,0 +1,43 @@
+package jsonbench_test
+
+import (
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/jsonbench"
+)
+
+// Sink variables to prevent compiler from eliminating benchmark loops
+var sinkBytes []byte
+
+// BenchmarkEncode encodes NewSample into a reused buffer.
+func BenchmarkEncode(b *testing.B) {
+	s := jsonbench.NewSample()
+	for _, e := range encoders {
+		b.Run(e.name, func(b *testing.B) {
+			b.ReportAllocs()
+			buf := make([]byte, 0, 512)
+			for i := 0; i < b.N; i++ {
+				buf, _ = e.encode(buf[:0], s)
+			}
+			b.SetBytes(int64(len(buf)))
+			sinkBytes = buf
+		})
+	}
+}
+
+// BenchmarkEncode_Parallel runs BenchmarkEncode's loop on GOMAXPROCS
+// goroutines, each with its own buffer, sharing Encode's pool.
+func BenchmarkEncode_Parallel(b *testing.B) {
+	s := jsonbench.NewSample()
+	for _, e := range encoders {
+		b.Run(e.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				buf := make([]byte, 0, 512)
+				for pb.Next() {
+					buf, _ = e.encode(buf[:0], s)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package jsonbench_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/jsonbench"
+)
+
+// encoders are the functions under test.
+var encoders = []struct {
+	name   string
+	encode func([]byte, *jsonbench.Sample) ([]byte, error)
+}{
+	{"Marshal", jsonbench.Marshal},
+	{"Encode", jsonbench.Encode},
+	{"AppendJSON", jsonbench.AppendJSON},
+}
+
+// samples covers the escaping and number formats json.Marshal has rules
+// for.
+func samples() []*jsonbench.Sample {
+	odd := jsonbench.NewSample()
+	odd.Host = "a\"b\\c\n\r\t\b\f\x00\x1f<script>&</script>"
+	odd.Service = "caf\u00e9 \u2028 \u2029 end"
+	odd.Tags = []string{}
+	tiny := jsonbench.NewSample()
+	tiny.Value, tiny.Tags, tiny.OK = 1e-7, nil, false
+	huge := jsonbench.NewSample()
+	huge.Value, huge.Timestamp = -1.5e21, -1
+	zero := &jsonbench.Sample{Value: math.Copysign(0, -1)}
+	return []*jsonbench.Sample{jsonbench.NewSample(), odd, tiny, huge, zero, {Value: 123456789.125}}
+}
+
+func TestEncoders_MatchMarshal(t *testing.T) {
+	for _, s := range samples() {
+		want, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range encoders {
+			got, err := e.encode([]byte("x"), s)
+			if err != nil || string(got) != "x"+string(want) {
+				t.Errorf("%s = %s, %v\nwant x%s", e.name, got, err, want)
+			}
+		}
+	}
+}
+
+// TestEncoders_InvalidUTF8 checks invalid UTF-8 decodes as U+FFFD. Go
+// versions differ in whether json.Marshal writes it escaped, so the
+// bytes aren't compared.
+func TestEncoders_InvalidUTF8(t *testing.T) {
+	s := jsonbench.NewSample()
+	s.Host = "bad \xff\xfe host"
+	for _, e := range encoders {
+		out, err := e.encode(nil, s)
+		if err != nil {
+			t.Fatalf("%s: %v", e.name, err)
+		}
+		var got jsonbench.Sample
+		if err := json.Unmarshal(out, &got); err != nil || got.Host != "bad \ufffd\ufffd host" {
+			t.Errorf("%s wrote %s, decoding to Host %q, %v", e.name, out, got.Host, err)
+		}
+	}
+}
+
+func TestEncoders_NonFinite(t *testing.T) {
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		s := jsonbench.NewSample()
+		s.Value = v
+		for _, e := range encoders {
+			if got, err := e.encode(nil, s); err == nil {
+				t.Errorf("%s(Value: %v) = %s, want an error", e.name, v, got)
+			}
+		}
+		if _, err := jsonbench.AppendJSON(nil, s); !errors.Is(err, jsonbench.ErrUnsupportedValue) {
+			t.Errorf("AppendJSON(Value: %v) error = %v, want ErrUnsupportedValue", v, err)
+		}
+	}
+}
+
+func TestAppendJSON_NoAlloc(t *testing.T) {
+	s := jsonbench.NewSample()
+	buf := make([]byte, 0, 512)
+	if n := testing.AllocsPerRun(100, func() { buf, _ = jsonbench.AppendJSON(buf[:0], s) }); n != 0 {
+		t.Errorf("AppendJSON into a reused buffer allocated %v times, want 0", n)
+	}
+}
@@ -0,0 +1,134 @@
+// Package lock provides ways to guard shared state to compare with each
+// other: sync.Mutex, sync.RWMutex, a CAS spinlock with backoff, and a
+// lock-free compare-and-swap loop.
+//
+// Each guards one uint64 behind the Updater interface, whose Update
+// applies a function to it atomically, so the function is the critical
+// section: Work with a few rounds makes it short, with many, long. The
+// locks run it once, holding everyone else off; the lock-free Atomic runs
+// it outside any lock and retries when another Update got in first, so it
+// never blocks but repeats the work under contention. Which wins depends
+// on how many goroutines contend and for how long each holds the state.
+package lock
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Updater guards a uint64 that goroutines update concurrently.
+//
+// Update replaces the value v with f(v), with no other Update between
+// reading v and storing f(v). f must be a pure function of v: Atomic may
+// call it more than once for one Update. Load returns the value.
+//
+// All Updaters are safe for concurrent use, and start at 0.
+type Updater interface {
+	Update(f func(uint64) uint64)
+	Load() uint64
+}
+
+// Work is a critical section of rounds dependent multiply-adds: it
+// counts the Update in v's low 32 bits and steps a linear congruential
+// generator in its high 32 bits, so uint32(Work(v, rounds)) counts the
+// Updates whatever rounds is. With 0 rounds it is just v+1.
+func Work(v uint64, rounds int) uint64 {
+	count := uint32(v) + 1
+	x := uint32(v >> 32)
+	for range rounds {
+		x = x*1664525 + 1013904223
+	}
+	return uint64(x)<<32 | uint64(count)
+}
+
+// Mutex is an Updater that holds a sync.Mutex for each Update.
+//
+// This is the standard approach: uncontended, Lock and Unlock are a CAS
+// each; contended, waiters spin briefly and then park, handing the lock
+// over through the scheduler.
+type Mutex struct {
+	mu sync.Mutex
+	v  uint64
+}
+
+// NewMutex creates a Mutex.
+func NewMutex() *Mutex {
+	return &Mutex{}
+}
+
+// Update applies f under the lock.
+func (m *Mutex) Update(f func(uint64) uint64) {
+	m.mu.Lock()
+	m.v = f(m.v)
+	m.mu.Unlock()
+}
+
+// Load returns the value under the lock.
+func (m *Mutex) Load() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.v
+}
+
+// RWMutex is an Updater that holds a sync.RWMutex's write lock for each
+// Update and its read lock for each Load.
+//
+// Readers share the lock, but every Update is a writer, so with Updates
+// alone it is a Mutex that also does the reader bookkeeping: what it
+// shows is the price of that bookkeeping.
+type RWMutex struct {
+	mu sync.RWMutex
+	v  uint64
+}
+
+// NewRWMutex creates an RWMutex.
+func NewRWMutex() *RWMutex {
+	return &RWMutex{}
+}
+
+// Update applies f under the write lock.
+func (m *RWMutex) Update(f func(uint64) uint64) {
+	m.mu.Lock()
+	m.v = f(m.v)
+	m.mu.Unlock()
+}
+
+// Load returns the value under the read lock.
+func (m *RWMutex) Load() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.v
+}
+
+// Atomic is a lock-free Updater: each Update loads the value, computes
+// f(v) without holding anything, and stores the result with a
+// compare-and-swap, starting over if another Update changed the value in
+// between.
+//
+// Nothing ever waits for a goroutine that was descheduled mid-update,
+// but under contention the losers of each CAS throw away their f(v) and
+// compute it again, so long critical sections waste more the more
+// goroutines contend.
+type Atomic struct {
+	v atomic.Uint64
+}
+
+// NewAtomic creates an Atomic.
+func NewAtomic() *Atomic {
+	return &Atomic{}
+}
+
+// Update applies f, retrying until no other Update intervenes.
+func (a *Atomic) Update(f func(uint64) uint64) {
+	for {
+		old := a.v.Load()
+		if a.v.CompareAndSwap(old, f(old)) {
+			return
+		}
+	}
+}
+
+// Load returns the value.
+func (a *Atomic) Load() uint64 {
+	return a.v.Load()
+}
@@ -0,0 +1,70 @@
+package lock_test
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/lock"
+)
+
+// workRounds are the critical section lengths the benchmarks sweep:
+// just the increment, and a long section.
+var workRounds = []int{0, 100}
+
+var sinkUint64 uint64
+
+// BenchmarkUpdate_Uncontended times Updates from one goroutine.
+func BenchmarkUpdate_Uncontended(b *testing.B) {
+	for _, rounds := range workRounds {
+		for _, nu := range updaters() {
+			u := nu.u
+			b.Run(fmt.Sprintf("%s/work=%d", nu.name, rounds), func(b *testing.B) {
+				f := func(v uint64) uint64 { return lock.Work(v, rounds) }
+				for range b.N {
+					u.Update(f)
+				}
+				sinkUint64 = u.Load()
+			})
+		}
+	}
+}
+
+// runSplit runs b.N calls of fn split across goroutines goroutines.
+// Unlike b.RunParallel, whose goroutine count is a multiple of
+// GOMAXPROCS, it runs exactly as many as asked for.
+func runSplit(b *testing.B, goroutines int, fn func()) {
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		count := b.N / goroutines
+		if g < b.N%goroutines {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range count {
+				fn()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkUpdate_Contended times Updates from 2 and from GOMAXPROCS*4
+// goroutines at once.
+func BenchmarkUpdate_Contended(b *testing.B) {
+	for _, goroutines := range []int{2, runtime.GOMAXPROCS(0) * 4} {
+		for _, rounds := range workRounds {
+			for _, nu := range updaters() {
+				u := nu.u
+				b.Run(fmt.Sprintf("%s/G=%d/work=%d", nu.name, goroutines, rounds), func(b *testing.B) {
+					f := func(v uint64) uint64 { return lock.Work(v, rounds) }
+					runSplit(b, goroutines, func() { u.Update(f) })
+					sinkUint64 = u.Load()
+				})
+			}
+		}
+	}
+}
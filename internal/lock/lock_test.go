@@ -0,0 +1,87 @@
+package lock_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/lock"
+)
+
+// namedUpdater is an Updater and its name in test and benchmark names.
+type namedUpdater struct {
+	name string
+	u    lock.Updater
+}
+
+// updaters returns a fresh Updater of each kind.
+func updaters() []namedUpdater {
+	return []namedUpdater{
+		{"Mutex", lock.NewMutex()},
+		{"RWMutex", lock.NewRWMutex()},
+		{"Spin", lock.NewSpin()},
+		{"Atomic", lock.NewAtomic()},
+	}
+}
+
+func TestWork(t *testing.T) {
+	v := uint64(0)
+	for i := 1; i <= 100; i++ {
+		v = lock.Work(v, 10)
+		if uint32(v) != uint32(i) {
+			t.Fatalf("after %d Works the count is %d", i, uint32(v))
+		}
+	}
+	if lock.Work(41, 0) != 42 {
+		t.Errorf("Work(41, 0) = %d, want 42", lock.Work(41, 0))
+	}
+	if lock.Work(0, 10) == lock.Work(0, 11) {
+		t.Error("Work ignores rounds")
+	}
+}
+
+// TestUpdater_NoLostUpdates checks that concurrent Updates all land,
+// including Atomic's retried ones.
+func TestUpdater_NoLostUpdates(t *testing.T) {
+	const goroutines, perGoroutine = 8, 2000
+	for _, nu := range updaters() {
+		t.Run(nu.name, func(t *testing.T) {
+			u := nu.u
+			var wg sync.WaitGroup
+			for range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range perGoroutine {
+						u.Update(func(v uint64) uint64 { return lock.Work(v, 8) })
+						_ = u.Load()
+					}
+				}()
+			}
+			wg.Wait()
+			if got := uint32(u.Load()); got != goroutines*perGoroutine {
+				t.Errorf("count = %d, want %d", got, goroutines*perGoroutine)
+			}
+		})
+	}
+}
+
+func TestSpinLock(t *testing.T) {
+	var l lock.SpinLock
+	if !l.TryLock() {
+		t.Fatal("TryLock() on a free lock = false")
+	}
+	if l.TryLock() {
+		t.Fatal("TryLock() on a held lock = true")
+	}
+	acquired := make(chan struct{})
+	go func() {
+		l.Lock()
+		close(acquired)
+		l.Unlock()
+	}()
+	l.Unlock()
+	<-acquired
+	if !l.TryLock() {
+		t.Error("TryLock() after the other goroutine unlocked = false")
+	}
+}
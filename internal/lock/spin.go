@@ -0,0 +1,79 @@
+package lock
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// maxSpinBackoff is the most loads of the lock word a SpinLock waits
+// between attempts before it also yields the processor.
+const maxSpinBackoff = 1 << 10
+
+// SpinLock is a sync.Locker that never parks: Lock retries a
+// compare-and-swap, waiting between attempts with exponential backoff,
+// and once the backoff is at its limit yields with runtime.Gosched
+// instead of sleeping.
+//
+// The wait reads the lock word until it looks free, so waiters share its
+// cache line rather than bouncing it with failed CASes
+// (test-and-test-and-set). A handoff costs no scheduler round trip, which
+// makes short critical sections cheap; but a goroutine descheduled while
+// holding the lock leaves everyone else spinning, so with more goroutines
+// than processors, or long critical sections, it burns CPU that a
+// sync.Mutex would give away.
+//
+// The zero value is unlocked. A SpinLock must not be copied after first
+// use.
+type SpinLock struct {
+	state atomic.Uint32 // 1 while held
+}
+
+// Lock acquires the lock, spinning until it is free.
+func (l *SpinLock) Lock() {
+	backoff := 1
+	for !l.TryLock() {
+		for i := 0; i < backoff && l.state.Load() != 0; i++ {
+		}
+		if backoff < maxSpinBackoff {
+			backoff <<= 1
+		} else {
+			runtime.Gosched()
+		}
+	}
+}
+
+// TryLock acquires the lock if it is free, and reports whether it did.
+func (l *SpinLock) TryLock() bool {
+	return l.state.Load() == 0 && l.state.CompareAndSwap(0, 1)
+}
+
+// Unlock releases the lock. Unlike sync.Mutex it doesn't check that the
+// lock is held.
+func (l *SpinLock) Unlock() {
+	l.state.Store(0)
+}
+
+// Spin is an Updater that holds a SpinLock for each Update.
+type Spin struct {
+	mu SpinLock
+	v  uint64
+}
+
+// NewSpin creates a Spin.
+func NewSpin() *Spin {
+	return &Spin{}
+}
+
+// Update applies f under the lock.
+func (s *Spin) Update(f func(uint64) uint64) {
+	s.mu.Lock()
+	s.v = f(s.v)
+	s.mu.Unlock()
+}
+
+// Load returns the value under the lock.
+func (s *Spin) Load() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.v
+}
@@ -0,0 +1,101 @@
+package logbench
+
+import (
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// timeLayout is slog's TextHandler time format: RFC 3339 to the
+// millisecond.
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// Append is a Logger that builds each line in a buffer it keeps, with
+// time.Time.AppendFormat and strconv, and writes it in one call.
+//
+// This is the optimized approach. Its lines are the same as Slog's, but
+// the fields arrive typed and are appended in place, so no line, written
+// or dropped, allocates. A mutex guards the buffer, as the TextHandler's
+// does its writer.
+type Append struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level slog.Level
+	buf   []byte
+}
+
+// NewAppend creates an Append writing to w lines at level and above.
+func NewAppend(w io.Writer, level slog.Level) *Append {
+	return &Append{w: w, level: level, buf: make([]byte, 0, 256)}
+}
+
+// Debug logs msg at debug level.
+func (l *Append) Debug(msg string, id, size int) {
+	if l.level <= slog.LevelDebug {
+		l.log(slog.LevelDebug, msg, id, size)
+	}
+}
+
+// Info logs msg at info level.
+func (l *Append) Info(msg string, id, size int) {
+	if l.level <= slog.LevelInfo {
+		l.log(slog.LevelInfo, msg, id, size)
+	}
+}
+
+// log writes a line at level.
+func (l *Append) log(level slog.Level, msg string, id, size int) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := append(l.buf[:0], "time="...)
+	b = now.AppendFormat(b, timeLayout)
+	b = append(b, " level="...)
+	b = append(b, level.String()...)
+	b = append(b, " msg="...)
+	b = appendValue(b, msg)
+	b = append(b, " id="...)
+	b = strconv.AppendInt(b, int64(id), 10)
+	b = append(b, " size="...)
+	b = strconv.AppendInt(b, int64(size), 10)
+	b = append(b, '\n')
+	l.buf = b
+	_, _ = l.w.Write(b)
+}
+
+// appendValue appends s to b, quoted if the TextHandler would quote it.
+func appendValue(b []byte, s string) []byte {
+	if needsQuoting(s) {
+		return strconv.AppendQuote(b, s)
+	}
+	return append(b, s...)
+}
+
+// needsQuoting reports whether s needs quoting as a text value, by the
+// TextHandler's rules: if it is empty, or has a space, '=', '"', a
+// control character, or a rune that is invalid, a space or unprintable.
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c <= ' ' || c == '=' || c == '"' {
+				return true
+			}
+			i++
+			continue
+		}
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+		i += n
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+// Package logbench provides loggers for a hot loop, for benchmarking what
+// a log line costs when it is written and when its level is off.
+//
+// This package offers four implementations of the Logger interface:
+//   - Printf: Standard library log.Logger's Printf, behind a level check
+//   - Slog: Standard library log/slog with a TextHandler, called directly
+//   - SlogChecked: the same slog.Logger, called only once Enabled reports
+//     the level is on
+//   - Append: Optimized logger that appends slog's text format to a
+//     reused buffer with strconv, without allocating
+//
+// A disabled debug line is the one a loop pays for on every iteration.
+// slog's Debug takes its fields as ...any, and each field the runtime
+// can't box without allocating goes to the heap before Debug finds the
+// level off; a call to Enabled first, or typed parameters as Printf and
+// Append take, leave a compare. A written line costs its formatting:
+// log.Printf's goes through fmt, slog's through a Record and a handler,
+// each boxing the fields, and Append's is appended in place.
+package logbench
+
+import "io"
+
+// Logger writes a line for an event in a loop: a message and two integer
+// fields, at debug or info level. Lines below the level the Logger was
+// created with are dropped.
+//
+// All implementations are safe for concurrent use.
+type Logger interface {
+	Debug(msg string, id, size int)
+	Info(msg string, id, size int)
+}
+
+// Discard is an io.Writer that drops what is written to it. Unlike
+// io.Discard, log.Logger doesn't recognize it, so Printf formats its
+// lines as it would for a file.
+var Discard io.Writer = discard{}
+
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) {
+	return len(p), nil
+}
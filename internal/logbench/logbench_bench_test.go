@@ -0,0 +1,51 @@
+package logbench_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/logbench"
+)
+
+// BenchmarkInfo writes an info line to logbench.Discard.
+func BenchmarkInfo(b *testing.B) {
+	for _, lg := range loggers {
+		b.Run(lg.name, func(b *testing.B) {
+			b.ReportAllocs()
+			l := lg.new(logbench.Discard, slog.LevelInfo)
+			for i := 0; i < b.N; i++ {
+				l.Info("packet done", i, 1500)
+			}
+		})
+	}
+}
+
+// BenchmarkDebug_Disabled drops a debug line, the cost a hot loop pays
+// for logging it doesn't write.
+func BenchmarkDebug_Disabled(b *testing.B) {
+	for _, lg := range loggers {
+		b.Run(lg.name, func(b *testing.B) {
+			b.ReportAllocs()
+			l := lg.new(logbench.Discard, slog.LevelInfo)
+			for i := 0; i < b.N; i++ {
+				l.Debug("packet done", i, 1500)
+			}
+		})
+	}
+}
+
+// BenchmarkInfo_Parallel runs BenchmarkInfo's loop on GOMAXPROCS
+// goroutines sharing one Logger.
+func BenchmarkInfo_Parallel(b *testing.B) {
+	for _, lg := range loggers {
+		b.Run(lg.name, func(b *testing.B) {
+			b.ReportAllocs()
+			l := lg.new(logbench.Discard, slog.LevelInfo)
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					l.Info("packet done", i, 1500)
+				}
+			})
+		})
+	}
+}
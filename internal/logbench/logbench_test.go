@@ -0,0 +1,104 @@
+package logbench_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/logbench"
+)
+
+// loggers are the implementations under test.
+var loggers = []struct {
+	name string
+	new  func(io.Writer, slog.Level) logbench.Logger
+}{
+	{"Printf", func(w io.Writer, l slog.Level) logbench.Logger { return logbench.NewPrintf(w, l) }},
+	{"Slog", func(w io.Writer, l slog.Level) logbench.Logger { return logbench.NewSlog(w, l) }},
+	{"SlogChecked", func(w io.Writer, l slog.Level) logbench.Logger { return logbench.NewSlogChecked(w, l) }},
+	{"Append", func(w io.Writer, l slog.Level) logbench.Logger { return logbench.NewAppend(w, l) }},
+}
+
+func TestLoggers_Levels(t *testing.T) {
+	for _, lg := range loggers {
+		var buf bytes.Buffer
+		l := lg.new(&buf, slog.LevelInfo)
+		l.Debug("dropped", 1, 2)
+		l.Info("kept", 3, 4)
+		out := buf.String()
+		if strings.Contains(out, "dropped") || !strings.Contains(out, "kept") || strings.Count(out, "\n") != 1 {
+			t.Errorf("%s at info level wrote %q, want only the info line", lg.name, out)
+		}
+
+		buf.Reset()
+		l = lg.new(&buf, slog.LevelDebug)
+		l.Debug("debug line", 5, 6)
+		if out := buf.String(); !strings.Contains(out, "DEBUG") || !strings.Contains(out, "id=5 size=6\n") {
+			t.Errorf("%s at debug level wrote %q, want the debug line", lg.name, out)
+		}
+	}
+}
+
+// TestAppend_MatchSlog checks Append writes Slog's lines, but for the
+// time.
+func TestAppend_MatchSlog(t *testing.T) {
+	for _, msg := range []string{"done", "packet done", "", "a=b", `say "hi"`, "tab\there", "café", "nbsp ", "bad\xff", "back\\slash", "del\x7f"} {
+		var want, got bytes.Buffer
+		logbench.NewSlog(&want, slog.LevelDebug).Debug(msg, -7, 1500)
+		logbench.NewAppend(&got, slog.LevelDebug).Debug(msg, -7, 1500)
+		_, w, _ := strings.Cut(want.String(), " ")
+		_, g, _ := strings.Cut(got.String(), " ")
+		if g != w {
+			t.Errorf("Append(%q) wrote %q, want %q", msg, g, w)
+		}
+		if !strings.HasPrefix(got.String(), "time=") {
+			t.Errorf("Append(%q) wrote %q, want a time field first", msg, got.String())
+		}
+	}
+}
+
+func TestLoggers_Concurrent(t *testing.T) {
+	const goroutines, lines = 4, 100
+	for _, lg := range loggers {
+		var buf bytes.Buffer
+		l := lg.new(&buf, slog.LevelInfo)
+		var wg sync.WaitGroup
+		for g := range goroutines {
+			wg.Go(func() {
+				for i := range lines {
+					l.Info("line", g, i)
+				}
+			})
+		}
+		wg.Wait()
+		if n := strings.Count(buf.String(), "\n"); n != goroutines*lines {
+			t.Errorf("%s wrote %d lines, want %d", lg.name, n, goroutines*lines)
+		}
+	}
+}
+
+// TestLoggers_DisabledNoAlloc checks a dropped debug line costs no
+// allocation, except Slog's, which boxes its fields first.
+func TestLoggers_DisabledNoAlloc(t *testing.T) {
+	for _, lg := range loggers {
+		l := lg.new(logbench.Discard, slog.LevelInfo)
+		n := testing.AllocsPerRun(100, func() { l.Debug("dropped", 100_000, 1500) })
+		if lg.name == "Slog" {
+			if n == 0 {
+				t.Errorf("Slog allocated 0 times, want its fields boxed")
+			}
+		} else if n != 0 {
+			t.Errorf("%s allocated %v times, want 0", lg.name, n)
+		}
+	}
+}
+
+func TestAppend_NoAlloc(t *testing.T) {
+	l := logbench.NewAppend(logbench.Discard, slog.LevelDebug)
+	if n := testing.AllocsPerRun(100, func() { l.Info("packet done", 100_000, 1500) }); n != 0 {
+		t.Errorf("Append allocated %v times, want 0", n)
+	}
+}
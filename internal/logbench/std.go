@@ -0,0 +1,93 @@
+package logbench
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+)
+
+// Printf is a Logger on a log.Logger, which has no levels: a level check
+// in front of Printf, as leveled wrappers around the log package do.
+//
+// Each line is the standard date and time prefix, then the level, the
+// message and the fields.
+type Printf struct {
+	log   *log.Logger
+	level slog.Level
+}
+
+// NewPrintf creates a Printf writing to w lines at level and above.
+func NewPrintf(w io.Writer, level slog.Level) *Printf {
+	return &Printf{log: log.New(w, "", log.LstdFlags), level: level}
+}
+
+// Debug logs msg at debug level.
+func (l *Printf) Debug(msg string, id, size int) {
+	if l.level <= slog.LevelDebug {
+		l.log.Printf("DEBUG %s id=%d size=%d", msg, id, size)
+	}
+}
+
+// Info logs msg at info level.
+func (l *Printf) Info(msg string, id, size int) {
+	if l.level <= slog.LevelInfo {
+		l.log.Printf("INFO %s id=%d size=%d", msg, id, size)
+	}
+}
+
+// Slog is a Logger on a slog.Logger with a TextHandler, its fields
+// passed as key-value pairs.
+//
+// This is the standard approach. The level check is inside Debug and
+// Info, after the fields have been boxed into their ...any arguments.
+type Slog struct {
+	log *slog.Logger
+}
+
+// NewSlog creates a Slog writing to w lines at level and above.
+func NewSlog(w io.Writer, level slog.Level) *Slog {
+	return &Slog{log: newSlogger(w, level)}
+}
+
+// Debug logs msg at debug level.
+func (l *Slog) Debug(msg string, id, size int) {
+	l.log.Debug(msg, "id", id, "size", size)
+}
+
+// Info logs msg at info level.
+func (l *Slog) Info(msg string, id, size int) {
+	l.log.Info(msg, "id", id, "size", size)
+}
+
+// SlogChecked is Slog with a call to Enabled before each line, so a
+// disabled one returns before its fields are boxed.
+type SlogChecked struct {
+	log *slog.Logger
+}
+
+// NewSlogChecked creates a SlogChecked writing to w lines at level and
+// above.
+func NewSlogChecked(w io.Writer, level slog.Level) *SlogChecked {
+	return &SlogChecked{log: newSlogger(w, level)}
+}
+
+// Debug logs msg at debug level.
+func (l *SlogChecked) Debug(msg string, id, size int) {
+	if l.log.Enabled(context.Background(), slog.LevelDebug) {
+		l.log.Debug(msg, "id", id, "size", size)
+	}
+}
+
+// Info logs msg at info level.
+func (l *SlogChecked) Info(msg string, id, size int) {
+	if l.log.Enabled(context.Background(), slog.LevelInfo) {
+		l.log.Info(msg, "id", id, "size", size)
+	}
+}
+
+// newSlogger returns a slog.Logger with a TextHandler writing to w lines
+// at level and above.
+func newSlogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
@@ -0,0 +1,164 @@
+// Package mapbench provides concurrent maps to compare with each other: a
+// map behind a sync.Mutex, a map behind a sync.RWMutex, sync.Map, and a
+// map split into shards that each have their own lock.
+//
+// Which is fastest depends on the mix of reads and writes and on how many
+// keys there are. One lock around one map serializes everything on the
+// lock's cache line, even readers, since RLock writes the reader count;
+// sharding divides that contention by the shard count; sync.Map reads
+// without any lock at all, but pays for it on writes, boxes its values,
+// and walks a pointer-heavy structure.
+package mapbench
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+)
+
+// ErrInvalidShards is returned by NewSharded for a shard count that is
+// zero, negative, or above MaxShards.
+var ErrInvalidShards = errors.New("mapbench: shards must be between 1 and MaxShards")
+
+// MaxShards is the most shards a Sharded map has.
+const MaxShards = 1 << 16
+
+// Map is a map goroutines read and write concurrently.
+//
+// Load returns the value stored for key and true, or the zero value and
+// false if there is none. Store sets key's value.
+//
+// All Maps are safe for concurrent use, and start empty.
+type Map[K comparable, V any] interface {
+	Load(key K) (V, bool)
+	Store(key K, value V)
+}
+
+// Mutex is a Map behind a sync.Mutex, for reads and writes alike.
+type Mutex[K comparable, V any] struct {
+	mu sync.Mutex
+	m  map[K]V
+}
+
+// NewMutex creates an empty Mutex.
+func NewMutex[K comparable, V any]() *Mutex[K, V] {
+	return &Mutex[K, V]{m: make(map[K]V)}
+}
+
+// Load returns key's value under the lock.
+func (m *Mutex[K, V]) Load(key K) (V, bool) {
+	m.mu.Lock()
+	v, ok := m.m[key]
+	m.mu.Unlock()
+	return v, ok
+}
+
+// Store sets key's value under the lock.
+func (m *Mutex[K, V]) Store(key K, value V) {
+	m.mu.Lock()
+	m.m[key] = value
+	m.mu.Unlock()
+}
+
+// RWMutex is a Map behind a sync.RWMutex: readers share the read lock,
+// writers hold the write lock.
+//
+// Readers don't wait for each other, but each RLock and RUnlock is an
+// atomic add on the same reader count, so with many readers the count's
+// cache line is as contended as a Mutex's.
+type RWMutex[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// NewRWMutex creates an empty RWMutex.
+func NewRWMutex[K comparable, V any]() *RWMutex[K, V] {
+	return &RWMutex[K, V]{m: make(map[K]V)}
+}
+
+// Load returns key's value under the read lock.
+func (m *RWMutex[K, V]) Load(key K) (V, bool) {
+	m.mu.RLock()
+	v, ok := m.m[key]
+	m.mu.RUnlock()
+	return v, ok
+}
+
+// Store sets key's value under the write lock.
+func (m *RWMutex[K, V]) Store(key K, value V) {
+	m.mu.Lock()
+	m.m[key] = value
+	m.mu.Unlock()
+}
+
+// Op is one operation of a workload: a Load of Key, or, if Store is
+// set, a Store to it.
+type Op struct {
+	Key   uint64
+	Store bool
+}
+
+// NewWorkload returns n Ops on keys drawn uniformly from [0, keys),
+// reads percent of them Loads and the rest Stores, drawn from rng.
+// Generating the Ops up front keeps the random number generation out of
+// the timing.
+func NewWorkload(rng *rand.Rand, n, keys, reads int) []Op {
+	ops := make([]Op, n)
+	for i := range ops {
+		ops[i] = Op{Key: rng.Uint64N(uint64(keys)), Store: rng.IntN(100) >= reads}
+	}
+	return ops
+}
+
+// Fill stores every key in [0, keys) in m, with the key as its value, so
+// a workload's Loads find what they look for and its Stores overwrite
+// rather than grow the map.
+func Fill(m Map[uint64, uint64], keys int) {
+	for k := range uint64(keys) {
+		m.Store(k, k)
+	}
+}
+
+// Run applies ops to m, starting at ops[start] and wrapping around, count
+// Ops in all, and returns the sum of the values it loaded, for the caller
+// to keep so that the Loads can't be eliminated. Each Store writes the
+// key plus one.
+func Run(m Map[uint64, uint64], ops []Op, start, count int) uint64 {
+	var sum uint64
+	i := start % len(ops)
+	for range count {
+		op := ops[i]
+		if op.Store {
+			m.Store(op.Key, op.Key+1)
+		} else {
+			v, _ := m.Load(op.Key)
+			sum += v
+		}
+		if i++; i == len(ops) {
+			i = 0
+		}
+	}
+	return sum
+}
+
+// Must wraps a constructor call and panics if it returned an error.
+//
+// It is intended for tests, benchmarks and package-level variables with
+// constant shard counts:
+//
+//	m := mapbench.Must(mapbench.NewSharded[string, int](16))
+func Must[M any](m M, err error) M {
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// checkShards returns ErrInvalidShards unless 1 <= shards <= MaxShards.
+func checkShards(shards int) error {
+	if shards < 1 || shards > MaxShards {
+		return fmt.Errorf("%w: got %d", ErrInvalidShards, shards)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package mapbench_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/mapbench"
+)
+
+var sinkUint64 atomic.Uint64
+
+// BenchmarkMap runs a uniform workload on each Map across GOMAXPROCS
+// goroutines, at several read shares and key counts.
+func BenchmarkMap(b *testing.B) {
+	for _, keys := range []int{100, 100_000} {
+		for _, reads := range []int{50, 90, 99} {
+			for _, nm := range maps() {
+				mapbench.Fill(nm.m, keys)
+				b.Run(fmt.Sprintf("%s/keys=%d/reads=%d", nm.name, keys, reads), func(b *testing.B) {
+					var stream atomic.Uint64
+					b.RunParallel(func(pb *testing.PB) {
+						ops := mapbench.NewWorkload(rand.New(rand.NewPCG(1, stream.Add(1))), 4096, keys, reads)
+						var sum uint64
+						for i := 0; pb.Next(); i++ {
+							sum += mapbench.Run(nm.m, ops, i, 1)
+						}
+						sinkUint64.Add(sum)
+					})
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package mapbench_test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/mapbench"
+)
+
+// namedMap is a Map and its name in test and benchmark names.
+type namedMap struct {
+	name string
+	m    mapbench.Map[uint64, uint64]
+}
+
+// maps returns a fresh, empty Map of each kind.
+func maps() []namedMap {
+	return []namedMap{
+		{"Mutex", mapbench.NewMutex[uint64, uint64]()},
+		{"RWMutex", mapbench.NewRWMutex[uint64, uint64]()},
+		{"SyncMap", mapbench.NewSyncMap[uint64, uint64]()},
+		{"Sharded", mapbench.Must(mapbench.NewSharded[uint64, uint64](8))},
+	}
+}
+
+func TestNewSharded_InvalidShards(t *testing.T) {
+	for _, shards := range []int{0, -1, mapbench.MaxShards + 1} {
+		if m, err := mapbench.NewSharded[string, int](shards); !errors.Is(err, mapbench.ErrInvalidShards) || m != nil {
+			t.Errorf("NewSharded(%d) = %v, %v; want nil, ErrInvalidShards", shards, m, err)
+		}
+	}
+	if m := mapbench.Must(mapbench.NewSharded[string, int](5)); m.Shards() != 8 {
+		t.Errorf("Shards() = %d, want 5 rounded up to 8", m.Shards())
+	}
+}
+
+func TestMap_LoadStore(t *testing.T) {
+	for _, nm := range maps() {
+		if v, ok := nm.m.Load(1); ok || v != 0 {
+			t.Errorf("%s: Load on an empty map = %d, %v; want 0, false", nm.name, v, ok)
+		}
+		mapbench.Fill(nm.m, 100)
+		for k := range uint64(100) {
+			if v, ok := nm.m.Load(k); !ok || v != k {
+				t.Fatalf("%s: Load(%d) = %d, %v after Fill; want %d, true", nm.name, k, v, ok, k)
+			}
+		}
+		nm.m.Store(7, 70)
+		if v, _ := nm.m.Load(7); v != 70 {
+			t.Errorf("%s: Load(7) = %d after Store(7, 70)", nm.name, v)
+		}
+	}
+}
+
+func TestNewWorkload(t *testing.T) {
+	ops := mapbench.NewWorkload(rand.New(rand.NewPCG(1, 2)), 10_000, 50, 90)
+	stores := 0
+	for _, op := range ops {
+		if op.Key >= 50 {
+			t.Fatalf("key %d outside [0, 50)", op.Key)
+		}
+		if op.Store {
+			stores++
+		}
+	}
+	if stores < 800 || stores > 1200 {
+		t.Errorf("%d Stores in 10000 Ops at 90%% reads, want about 1000", stores)
+	}
+	again := mapbench.NewWorkload(rand.New(rand.NewPCG(1, 2)), 10_000, 50, 90)
+	for i := range ops {
+		if ops[i] != again[i] {
+			t.Fatal("the same generator gave a different workload")
+		}
+	}
+}
+
+// TestMap_Concurrent runs a mixed workload on each Map from several
+// goroutines, for the race detector, and checks that every key still
+// holds a value a Store or Fill wrote.
+func TestMap_Concurrent(t *testing.T) {
+	const keys = 64
+	for _, nm := range maps() {
+		t.Run(nm.name, func(t *testing.T) {
+			mapbench.Fill(nm.m, keys)
+			var wg sync.WaitGroup
+			for g := range 8 {
+				ops := mapbench.NewWorkload(rand.New(rand.NewPCG(1, uint64(g))), 1000, keys, 50)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					mapbench.Run(nm.m, ops, 0, 5000)
+				}()
+			}
+			wg.Wait()
+			for k := range uint64(keys) {
+				if v, ok := nm.m.Load(k); !ok || (v != k && v != k+1) {
+					t.Errorf("Load(%d) = %d, %v; want %d or %d", k, v, ok, k, k+1)
+				}
+			}
+		})
+	}
+}
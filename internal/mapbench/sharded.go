@@ -0,0 +1,74 @@
+package mapbench
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// mapShard is one shard of a Sharded map, padded so that neighbouring
+// shards' locks don't share a cache line.
+type mapShard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+
+	_pad0 [32]byte //nolint:unused
+}
+
+// Sharded is a Map split into shards, each a map behind its own
+// sync.RWMutex, with keys assigned to shards by hash.
+//
+// Goroutines working on keys in different shards never touch the same
+// lock, so contention falls with the shard count, at the price of hashing
+// each key twice: once with maphash to pick the shard, and once in the
+// shard's map.
+type Sharded[K comparable, V any] struct {
+	shards []mapShard[K, V]
+	mask   uint64
+	seed   maphash.Seed
+}
+
+// NewSharded creates an empty Sharded map with shards shards, rounded up
+// to a power of two.
+//
+// Returns ErrInvalidShards if shards is not between 1 and MaxShards.
+func NewSharded[K comparable, V any](shards int) (*Sharded[K, V], error) {
+	if err := checkShards(shards); err != nil {
+		return nil, err
+	}
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+	s := &Sharded[K, V]{shards: make([]mapShard[K, V], n), mask: uint64(n - 1), seed: maphash.MakeSeed()}
+	for i := range s.shards {
+		s.shards[i].m = make(map[K]V)
+	}
+	return s, nil
+}
+
+// shard returns key's shard.
+func (s *Sharded[K, V]) shard(key K) *mapShard[K, V] {
+	return &s.shards[maphash.Comparable(s.seed, key)&s.mask]
+}
+
+// Load returns key's value under its shard's read lock.
+func (s *Sharded[K, V]) Load(key K) (V, bool) {
+	sh := s.shard(key)
+	sh.mu.RLock()
+	v, ok := sh.m[key]
+	sh.mu.RUnlock()
+	return v, ok
+}
+
+// Store sets key's value under its shard's write lock.
+func (s *Sharded[K, V]) Store(key K, value V) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	sh.m[key] = value
+	sh.mu.Unlock()
+}
+
+// Shards returns the number of shards.
+func (s *Sharded[K, V]) Shards() int {
+	return len(s.shards)
+}
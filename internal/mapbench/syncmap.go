@@ -0,0 +1,34 @@
+package mapbench
+
+import "sync"
+
+// SyncMap is a Map backed by sync.Map.
+//
+// This is the standard library's concurrent map: Loads take no lock, and
+// Stores to keys already present don't either, which makes it the one to
+// beat for read-mostly maps. But it stores keys and values as any, so
+// each Store of a value that doesn't fit in an interface's data word
+// allocates, and each Load asserts the type back.
+type SyncMap[K comparable, V any] struct {
+	m sync.Map
+}
+
+// NewSyncMap creates an empty SyncMap.
+func NewSyncMap[K comparable, V any]() *SyncMap[K, V] {
+	return &SyncMap[K, V]{}
+}
+
+// Load returns key's value.
+func (m *SyncMap[K, V]) Load(key K) (V, bool) {
+	v, ok := m.m.Load(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return v.(V), true
+}
+
+// Store sets key's value.
+func (m *SyncMap[K, V]) Store(key K, value V) {
+	m.m.Store(key, value)
+}
@@ -0,0 +1,67 @@
+package once
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DoubleChecked computes its value under a mutex behind an atomic.Bool:
+// the classic double-checked locking pattern, written by hand.
+//
+// This is the optimized approach. Get checks the flag with one atomic
+// load and returns the field if it is set; only the first callers take
+// the mutex, check the flag again, and compute the value. It is what
+// sync.Once does inside, minus the call through Do and its func value,
+// so the fast path fully inlines into the caller.
+type DoubleChecked[T any] struct {
+	done atomic.Bool
+	mu   sync.Mutex
+	init func() T
+	v    T
+}
+
+// NewDoubleChecked creates a DoubleChecked that computes its value with
+// init.
+func NewDoubleChecked[T any](init func() T) *DoubleChecked[T] {
+	return &DoubleChecked[T]{init: init}
+}
+
+// Get returns the value, calling init on the first call.
+func (d *DoubleChecked[T]) Get() T {
+	if d.done.Load() {
+		return d.v
+	}
+	return d.getSlow()
+}
+
+func (d *DoubleChecked[T]) getSlow() T {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.done.Load() {
+		d.v = d.init()
+		d.init = nil
+		// Publish v: a Get that loads done == true sees it
+		d.done.Store(true)
+	}
+	return d.v
+}
+
+// Eager computes its value when it is created, as a package-level
+// variable initializer or an init function does at program start.
+//
+// Get is a plain field read with nothing to check. The cost moves to
+// startup, paid whether or not the value is ever used; this is the floor
+// the lazy approaches are measured against.
+type Eager[T any] struct {
+	v T
+}
+
+// NewEager creates an Eager, calling init now.
+func NewEager[T any](init func() T) *Eager[T] {
+	return &Eager[T]{v: init()}
+}
+
+// Get returns the value.
+func (e *Eager[T]) Get() T {
+	return e.v
+}
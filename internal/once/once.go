@@ -0,0 +1,27 @@
+// Package once provides one-time initialization implementations for
+// benchmarking.
+//
+// This package offers four implementations of the Value interface:
+//   - SyncOnce: Standard approach using sync.Once and a field
+//   - OnceValue: Standard library sync.OnceValue closure
+//   - DoubleChecked: Optimized approach using an atomic.Bool flag in
+//     front of a mutex
+//   - Eager: Package-level init: computed up front, Get is a field read
+//
+// After the first call every lazy approach is a flag check on the hot
+// path, so they differ by nanoseconds; the first call, which allocates
+// and synchronizes, differs more. Eager shows the floor a lazy Value
+// is paying above, and moves the whole cost to startup.
+package once
+
+// Value is a value computed by an init function at most once and read
+// from then on.
+//
+// Implementations must be safe for concurrent use: any number of
+// goroutines may call Get at once, including before the value is
+// computed, and all see the same value. The init function is called once
+// however many goroutines race for the first Get.
+type Value[T any] interface {
+	// Get returns the value, computing it first if no Get has yet.
+	Get() T
+}
@@ -0,0 +1,123 @@
+package once_test
+
+import (
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/once"
+)
+
+// Sink variables to prevent compiler from eliminating benchmark loops
+var sinkInt int
+
+// config is what the Values compute: cheap, so the benchmarks measure
+// the initialization mechanism rather than the work it guards.
+func config() int { return 42 }
+
+// Direct type benchmarks (hot path: Get after the value is computed)
+
+func BenchmarkOnce_SyncOnce_Get_Direct(b *testing.B) {
+	v := once.NewSyncOnce(config)
+	v.Get()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var result int
+	for i := 0; i < b.N; i++ {
+		result = v.Get()
+	}
+	sinkInt = result
+}
+
+func BenchmarkOnce_OnceValue_Get_Direct(b *testing.B) {
+	v := once.NewOnceValue(config)
+	v.Get()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var result int
+	for i := 0; i < b.N; i++ {
+		result = v.Get()
+	}
+	sinkInt = result
+}
+
+func BenchmarkOnce_DoubleChecked_Get_Direct(b *testing.B) {
+	v := once.NewDoubleChecked(config)
+	v.Get()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var result int
+	for i := 0; i < b.N; i++ {
+		result = v.Get()
+	}
+	sinkInt = result
+}
+
+func BenchmarkOnce_Eager_Get_Direct(b *testing.B) {
+	v := once.NewEager(config)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var result int
+	for i := 0; i < b.N; i++ {
+		result = v.Get()
+	}
+	sinkInt = result
+}
+
+// Interface benchmarks (realistic usage with dynamic dispatch)
+
+func BenchmarkOnce_Get_Interface(b *testing.B) {
+	for _, c := range constructors {
+		b.Run(c.name, func(b *testing.B) {
+			v := c.new(config)
+			v.Get()
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var result int
+			for i := 0; i < b.N; i++ {
+				result = v.Get()
+			}
+			sinkInt = result
+		})
+	}
+}
+
+// Parallel benchmarks (multiple goroutines reading)
+
+func BenchmarkOnce_Get_Parallel(b *testing.B) {
+	for _, c := range constructors {
+		b.Run(c.name, func(b *testing.B) {
+			v := c.new(config)
+			v.Get()
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				var result int
+				for pb.Next() {
+					result = v.Get()
+				}
+				sinkInt = result
+			})
+		})
+	}
+}
+
+// First-call benchmarks (create, then the Get that computes the value)
+
+func BenchmarkOnce_First(b *testing.B) {
+	for _, c := range constructors {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			var result int
+			for i := 0; i < b.N; i++ {
+				result = c.new(config).Get()
+			}
+			sinkInt = result
+		})
+	}
+}
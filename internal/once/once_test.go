@@ -0,0 +1,73 @@
+package once_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/once"
+)
+
+// constructors creates each Value implementation from an init function.
+var constructors = []struct {
+	name string
+	new  func(init func() int) once.Value[int]
+}{
+	{"SyncOnce", func(init func() int) once.Value[int] { return once.NewSyncOnce(init) }},
+	{"OnceValue", func(init func() int) once.Value[int] { return once.NewOnceValue(init) }},
+	{"DoubleChecked", func(init func() int) once.Value[int] { return once.NewDoubleChecked(init) }},
+	{"Eager", func(init func() int) once.Value[int] { return once.NewEager(init) }},
+}
+
+func TestValue_Get(t *testing.T) {
+	for _, c := range constructors {
+		calls := 0
+		v := c.new(func() int { calls++; return 42 })
+		for range 3 {
+			if got := v.Get(); got != 42 {
+				t.Errorf("%s: Get() = %d, want 42", c.name, got)
+			}
+		}
+		if calls != 1 {
+			t.Errorf("%s: init called %d times, want 1", c.name, calls)
+		}
+	}
+}
+
+func TestValue_Lazy(t *testing.T) {
+	for _, c := range constructors {
+		called := false
+		_ = c.new(func() int { called = true; return 1 })
+		if want := c.name == "Eager"; called != want {
+			t.Errorf("%s: init called before Get = %v, want %v", c.name, called, want)
+		}
+	}
+}
+
+// TestValue_ConcurrentFirstGet races goroutines for the first Get: init
+// must run once, and every goroutine must see its result.
+func TestValue_ConcurrentFirstGet(t *testing.T) {
+	for _, c := range constructors {
+		t.Run(c.name, func(t *testing.T) {
+			var calls atomic.Int64
+			v := c.new(func() int { calls.Add(1); return 7 })
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			for range 16 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					if got := v.Get(); got != 7 {
+						t.Errorf("Get() = %d, want 7", got)
+					}
+				}()
+			}
+			close(start)
+			wg.Wait()
+			if calls.Load() != 1 {
+				t.Errorf("init called %d times, want 1", calls.Load())
+			}
+		})
+	}
+}
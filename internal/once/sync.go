@@ -0,0 +1,49 @@
+package once
+
+import "sync"
+
+// SyncOnce computes its value under a sync.Once and keeps it in a field.
+//
+// This is the standard approach. Get is sync.Once.Do, whose fast path
+// is an atomic load of the done flag, inlined, and then a read of the
+// field.
+type SyncOnce[T any] struct {
+	once sync.Once
+	init func() T
+	v    T
+}
+
+// NewSyncOnce creates a SyncOnce that computes its value with init.
+func NewSyncOnce[T any](init func() T) *SyncOnce[T] {
+	return &SyncOnce[T]{init: init}
+}
+
+// Get returns the value, calling init on the first call.
+func (o *SyncOnce[T]) Get() T {
+	o.once.Do(o.compute)
+	return o.v
+}
+
+func (o *SyncOnce[T]) compute() {
+	o.v = o.init()
+	o.init = nil
+}
+
+// OnceValue wraps the function sync.OnceValue returns.
+//
+// The standard library's closure form of SyncOnce: it keeps the value and
+// a sync.Once in a closure, so Get is an indirect call on top of the
+// same atomic load. It also re-panics on every call if init panicked.
+type OnceValue[T any] struct {
+	get func() T
+}
+
+// NewOnceValue creates a OnceValue that computes its value with init.
+func NewOnceValue[T any](init func() T) *OnceValue[T] {
+	return &OnceValue[T]{get: sync.OnceValue(init)}
+}
+
+// Get returns the value, calling init on the first call.
+func (o *OnceValue[T]) Get() T {
+	return o.get()
+}
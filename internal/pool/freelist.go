@@ -0,0 +1,109 @@
+package pool
+
+import "sync/atomic"
+
+type freeSlot[T any] struct {
+	seq atomic.Uint64
+	val *T
+}
+
+// Freelist is a bounded lock-free Pool: a ring of free objects in Dmitry
+// Vyukov's bounded MPMC queue design, as queue.MPSCRing is, with both
+// ends claimed by CAS. Unlike sync.Pool it keeps what it holds across
+// garbage collections, but every Get and Put is a CAS on a cache line
+// all goroutines share.
+//
+// Objects come back first in, first out, so a Get returns the object
+// Put longest ago, whose cache lines are the least likely to still be
+// warm. Get allocates when the ring is empty, and Put drops the object
+// when it is full.
+//
+// Safe for concurrent use.
+type Freelist[T any] struct {
+	slots []freeSlot[T]
+	mask  uint64
+	newFn func() *T
+
+	_pad0 [56]byte //nolint:unused
+
+	head atomic.Uint64 // Next position to Put into
+
+	_pad1 [56]byte //nolint:unused
+
+	tail atomic.Uint64 // Next position to Get from
+}
+
+// NewFreelist creates an empty Freelist holding up to capacity objects,
+// rounded up to a power of two, that allocates with newFn when empty.
+//
+// Returns ErrInvalidCapacity if capacity is not between 1 and
+// MaxCapacity.
+func NewFreelist[T any](capacity int, newFn func() *T) (*Freelist[T], error) {
+	if err := checkCapacity(capacity); err != nil {
+		return nil, err
+	}
+	n := uint64(1)
+	for n < uint64(capacity) {
+		n <<= 1
+	}
+	f := &Freelist[T]{slots: make([]freeSlot[T], n), mask: n - 1, newFn: newFn}
+	for i := range f.slots {
+		f.slots[i].seq.Store(uint64(i))
+	}
+	return f, nil
+}
+
+// Get returns the oldest free object, or a new one if there are none.
+func (f *Freelist[T]) Get() *T {
+	for {
+		pos := f.tail.Load()
+		s := &f.slots[pos&f.mask]
+		switch dif := int64(s.seq.Load() - (pos + 1)); {
+		case dif == 0:
+			if f.tail.CompareAndSwap(pos, pos+1) {
+				x := s.val
+				s.val = nil
+				// Free the slot for the Put one lap ahead
+				s.seq.Store(pos + f.mask + 1)
+				return x
+			}
+		case dif < 0:
+			// Nothing Put at pos yet: empty
+			return f.newFn()
+		}
+		// Another Get claimed pos first; retry
+	}
+}
+
+// Put adds x to the free objects, or drops it if the Freelist is full.
+func (f *Freelist[T]) Put(x *T) {
+	for {
+		pos := f.head.Load()
+		s := &f.slots[pos&f.mask]
+		switch dif := int64(s.seq.Load() - pos); {
+		case dif == 0:
+			if f.head.CompareAndSwap(pos, pos+1) {
+				s.val = x
+				s.seq.Store(pos + 1)
+				return
+			}
+		case dif < 0:
+			// Slot still holds the object from one lap ago: full
+			return
+		}
+		// Another Put claimed pos first; retry
+	}
+}
+
+// Len returns how many free objects the Freelist holds. It may be stale
+// by the time it returns.
+func (f *Freelist[T]) Len() int {
+	// tail first: head only grows, so it can't have fallen behind
+	tail := f.tail.Load()
+	return int(f.head.Load() - tail)
+}
+
+// Cap returns the most free objects the Freelist holds.
+func (f *Freelist[T]) Cap() int {
+	return len(f.slots)
+}
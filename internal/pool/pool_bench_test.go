@@ -0,0 +1,62 @@
+package pool_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/pool"
+)
+
+// poolSizes are the buffer sizes the benchmarks pool, in bytes.
+var poolSizes = []int{64, 1024, 16 << 10}
+
+// buffer is a pooled buffer.
+type buffer struct {
+	b []byte
+}
+
+// sinkByte keeps the compiler from eliminating the buffer writes.
+var sinkByte byte
+
+// benchPool runs Get, a write to every cache line, and Put, b.N times
+// across GOMAXPROCS goroutines, each with its own Pool from newPool.
+// Pools shared by every goroutine return the same one each call.
+func benchPool(b *testing.B, size int, newPool func() pool.Pool[buffer]) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		p := newPool()
+		var x byte
+		for pb.Next() {
+			buf := p.Get()
+			for i := 0; i < len(buf.b); i += 64 {
+				buf.b[i]++
+			}
+			x += buf.b[0]
+			p.Put(buf)
+		}
+		sinkByte = x
+	})
+}
+
+func BenchmarkPool(b *testing.B) {
+	for _, size := range poolSizes {
+		newBuf := func() *buffer { return &buffer{b: make([]byte, size)} }
+		shared := func(p pool.Pool[buffer]) func() pool.Pool[buffer] {
+			return func() pool.Pool[buffer] { return p }
+		}
+		strategies := []struct {
+			name    string
+			newPool func() pool.Pool[buffer]
+		}{
+			{"Alloc", shared(pool.NewAlloc(newBuf))},
+			{"SyncPool", shared(pool.NewSyncPool(newBuf))},
+			{"Freelist", shared(pool.Must(pool.NewFreelist(1024, newBuf)))},
+			{"Stack", func() pool.Pool[buffer] { return pool.Must(pool.NewStack(16, newBuf)) }},
+		}
+		for _, s := range strategies {
+			b.Run(fmt.Sprintf("%s/size=%d", s.name, size), func(b *testing.B) {
+				benchPool(b, size, s.newPool)
+			})
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package pool_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/pool"
+)
+
+// obj is a pooled object that records who holds it.
+type obj struct {
+	owner atomic.Int64 // Goroutine holding it, or 0 when free
+}
+
+// counter returns a newFn that counts its calls in n.
+func counter(n *atomic.Int64) func() *obj {
+	return func() *obj {
+		n.Add(1)
+		return &obj{}
+	}
+}
+
+func TestNew_InvalidCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1, pool.MaxCapacity + 1} {
+		if f, err := pool.NewFreelist(capacity, func() *obj { return &obj{} }); !errors.Is(err, pool.ErrInvalidCapacity) || f != nil {
+			t.Errorf("NewFreelist(%d) = %v, %v; want nil, ErrInvalidCapacity", capacity, f, err)
+		}
+		if s, err := pool.NewStack(capacity, func() *obj { return &obj{} }); !errors.Is(err, pool.ErrInvalidCapacity) || s != nil {
+			t.Errorf("NewStack(%d) = %v, %v; want nil, ErrInvalidCapacity", capacity, s, err)
+		}
+	}
+}
+
+func TestAlloc(t *testing.T) {
+	var n atomic.Int64
+	a := pool.NewAlloc(counter(&n))
+	x := a.Get()
+	a.Put(x)
+	if y := a.Get(); y == x || n.Load() != 2 {
+		t.Errorf("Alloc reused an object: %d allocations for 2 Gets", n.Load())
+	}
+}
+
+func TestSyncPool(t *testing.T) {
+	var n atomic.Int64
+	p := pool.NewSyncPool(counter(&n))
+	if p.Get() == nil || n.Load() != 1 {
+		t.Fatalf("empty SyncPool: %d allocations for a Get", n.Load())
+	}
+	// sync.Pool may drop anything, randomly so under the race detector,
+	// so only check that what comes back is usable
+	p.Put(&obj{})
+	if p.Get() == nil {
+		t.Error("Get() = nil")
+	}
+}
+
+func TestFreelist(t *testing.T) {
+	var n atomic.Int64
+	f, err := pool.NewFreelist(3, counter(&n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Cap() != 4 {
+		t.Errorf("Cap() = %d, want 3 rounded up to 4", f.Cap())
+	}
+	objs := make([]*obj, 5)
+	for i := range objs {
+		objs[i] = f.Get()
+	}
+	if n.Load() != 5 {
+		t.Fatalf("empty Freelist: %d allocations for 5 Gets", n.Load())
+	}
+	for _, x := range objs {
+		f.Put(x)
+	}
+	if f.Len() != 4 {
+		t.Errorf("Len() = %d after 5 Puts, want the capacity, 4", f.Len())
+	}
+	// First in, first out; the fifth was dropped
+	for i := range 4 {
+		if x := f.Get(); x != objs[i] {
+			t.Errorf("Get() #%d returned object %p, want %p", i, x, objs[i])
+		}
+	}
+	if f.Get(); n.Load() != 6 {
+		t.Errorf("drained Freelist: %d allocations, want 6", n.Load())
+	}
+}
+
+func TestStack(t *testing.T) {
+	var n atomic.Int64
+	s, err := pool.NewStack(2, counter(&n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, c := s.Get(), s.Get(), s.Get()
+	s.Put(a)
+	s.Put(b)
+	s.Put(c)
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d after 3 Puts, want the capacity, 2", s.Len())
+	}
+	// Last in, first out; c was dropped
+	if x, y := s.Get(), s.Get(); x != b || y != a {
+		t.Error("Stack isn't last in, first out")
+	}
+	if s.Get(); n.Load() != 4 {
+		t.Errorf("drained Stack: %d allocations, want 4", n.Load())
+	}
+}
+
+// TestPool_Exclusive checks that the concurrent Pools never hand one
+// object to two goroutines at once.
+func TestPool_Exclusive(t *testing.T) {
+	newObj := func() *obj { return &obj{} }
+	freelist, err := pool.NewFreelist(8, newObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, p := range map[string]pool.Pool[obj]{
+		"SyncPool": pool.NewSyncPool(newObj),
+		"Freelist": freelist,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			var clashes atomic.Int64
+			for g := int64(1); g <= 8; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 10_000 {
+						x := p.Get()
+						if !x.owner.CompareAndSwap(0, g) {
+							clashes.Add(1)
+							continue
+						}
+						x.owner.Store(0)
+						p.Put(x)
+					}
+				}()
+			}
+			wg.Wait()
+			if clashes.Load() > 0 {
+				t.Errorf("%d Gets returned an object another goroutine held", clashes.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package pool
+
+// Stack is a Pool for one goroutine: a plain slice of free objects, last
+// in, first out, with no synchronization at all. Each goroutine keeps its
+// own, so it is the cheapest reuse there is, and the object Get returns
+// is the one just Put, still warm in cache; but objects can't move
+// between goroutines, so a goroutine that only Puts fills its Stack while
+// one that only Gets allocates.
+//
+// Get allocates when the Stack is empty, and Put drops the object when
+// it is full.
+//
+// CONTRACT: Only ONE goroutine may use a Stack.
+type Stack[T any] struct {
+	free  []*T
+	newFn func() *T
+}
+
+// NewStack creates an empty Stack holding up to capacity objects, that
+// allocates with newFn when empty.
+//
+// Returns ErrInvalidCapacity if capacity is not between 1 and
+// MaxCapacity.
+func NewStack[T any](capacity int, newFn func() *T) (*Stack[T], error) {
+	if err := checkCapacity(capacity); err != nil {
+		return nil, err
+	}
+	return &Stack[T]{free: make([]*T, 0, capacity), newFn: newFn}, nil
+}
+
+// Get returns the free object Put last, or a new one if there are none.
+func (s *Stack[T]) Get() *T {
+	n := len(s.free)
+	if n == 0 {
+		return s.newFn()
+	}
+	x := s.free[n-1]
+	s.free[n-1] = nil
+	s.free = s.free[:n-1]
+	return x
+}
+
+// Put adds x to the free objects, or drops it if the Stack is full.
+func (s *Stack[T]) Put(x *T) {
+	if len(s.free) < cap(s.free) {
+		s.free = append(s.free, x)
+	}
+}
+
+// Len returns how many free objects the Stack holds.
+func (s *Stack[T]) Len() int {
+	return len(s.free)
+}
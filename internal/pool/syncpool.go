@@ -0,0 +1,30 @@
+package pool
+
+import "sync"
+
+// SyncPool is a Pool backed by sync.Pool: per-P caches, so Get and Put
+// by goroutines on different Ps rarely touch shared state, but whatever
+// it holds is dropped across two garbage collections.
+//
+// Safe for concurrent use.
+type SyncPool[T any] struct {
+	p sync.Pool
+}
+
+// NewSyncPool creates a SyncPool that allocates with newFn when it has
+// nothing to reuse.
+func NewSyncPool[T any](newFn func() *T) *SyncPool[T] {
+	s := &SyncPool[T]{}
+	s.p.New = func() any { return newFn() }
+	return s
+}
+
+// Get returns a pooled object, or a new one.
+func (s *SyncPool[T]) Get() *T {
+	return s.p.Get().(*T)
+}
+
+// Put returns x to the pool.
+func (s *SyncPool[T]) Put(x *T) {
+	s.p.Put(x)
+}
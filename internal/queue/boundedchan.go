@@ -0,0 +1,219 @@
+package queue
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BoundedChan is a channel-equivalent built on RingBuffer plus two
+// counting semaphores (free slots and ready items).
+//
+// It mirrors buffered chan T semantics so it can be compared against a
+// native channel like-for-like, without the non-blocking select/default
+// asymmetry of ChannelQueue vs RingBuffer:
+//   - Send blocks while full; Recv blocks while empty
+//   - Close wakes blocked receivers; Recv drains remaining items, then
+//     returns false
+//   - Send on a closed BoundedChan panics, as does closing it twice
+//
+// Push and Pop are the non-blocking forms, so BoundedChan also satisfies
+// Queue. Any number of goroutines may send and receive concurrently.
+type BoundedChan[T any] struct {
+	ring *RingBuffer[T]
+	size int
+
+	slots *sema
+	items *sema
+
+	sendMu sync.Mutex // Serializes producers onto the SPSC ring
+	recvMu sync.Mutex // Serializes consumers onto the SPSC ring
+
+	closed atomic.Bool
+	done   chan struct{}
+
+	notify atomic.Bool
+	ready  chan struct{}
+}
+
+// NewBoundedChan creates a BoundedChan holding up to size items.
+//
+// Returns ErrInvalidSize if size is not between 1 and MaxInt/2
+// (unbuffered rendezvous is not supported).
+func NewBoundedChan[T any](size int) (*BoundedChan[T], error) {
+	ring, err := NewRingBuffer[T](size)
+	if err != nil {
+		return nil, err
+	}
+	done := make(chan struct{})
+	return &BoundedChan[T]{
+		ring:  ring,
+		size:  size,
+		slots: newSema(size, done),
+		items: newSema(0, done),
+		done:  done,
+		ready: make(chan struct{}, 1),
+	}, nil
+}
+
+// Send adds an item, blocking while the channel is full.
+// Panics if the channel is closed, including while blocked.
+func (c *BoundedChan[T]) Send(v T) {
+	if c.closed.Load() || !c.slots.acquire(nil) {
+		panic("queue: send on closed BoundedChan")
+	}
+	c.put(v)
+}
+
+// Recv removes an item, blocking while the channel is empty.
+// Returns false once the channel is closed and drained.
+func (c *BoundedChan[T]) Recv() (T, bool) {
+	if !c.items.acquire(nil) {
+		var zero T
+		return zero, false
+	}
+	return c.take(), true
+}
+
+// PopTimeout removes an item, blocking for up to d while the channel is
+// empty. Returns false on timeout or once the channel is closed and
+// drained. A d of zero or less behaves like Pop.
+//
+// This lets a consumer loop sleep instead of spin while still bounding
+// how long it goes without checking other work (cancellation, ticks).
+func (c *BoundedChan[T]) PopTimeout(d time.Duration) (T, bool) {
+	if c.items.tryAcquire() {
+		return c.take(), true
+	}
+	if d <= 0 {
+		var zero T
+		return zero, false
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	if !c.items.acquire(timer.C) {
+		var zero T
+		return zero, false
+	}
+	return c.take(), true
+}
+
+// Push adds an item without blocking.
+// Returns false if the channel is full. Panics if the channel is closed.
+func (c *BoundedChan[T]) Push(v T) bool {
+	if c.closed.Load() {
+		panic("queue: send on closed BoundedChan")
+	}
+	if !c.slots.tryAcquire() {
+		return false
+	}
+	c.put(v)
+	return true
+}
+
+// Pop removes an item without blocking.
+// Returns false if the channel is empty.
+func (c *BoundedChan[T]) Pop() (T, bool) {
+	if !c.items.tryAcquire() {
+		var zero T
+		return zero, false
+	}
+	return c.take(), true
+}
+
+// Drain pops up to len(dst) currently available items into dst without
+// blocking and returns how many were copied.
+func (c *BoundedChan[T]) Drain(dst []T) int {
+	for i := range dst {
+		v, ok := c.Pop()
+		if !ok {
+			return i
+		}
+		dst[i] = v
+	}
+	return len(dst)
+}
+
+// Range pops each currently available item and passes it to fn without
+// blocking, stopping early if fn returns false. The item passed to the
+// call that returned false is still consumed.
+func (c *BoundedChan[T]) Range(fn func(T) bool) {
+	for n := c.Len(); n > 0; n-- {
+		v, ok := c.Pop()
+		if !ok || !fn(v) {
+			return
+		}
+	}
+}
+
+// Close marks the channel closed and wakes every blocked receiver.
+// Panics if already closed.
+func (c *BoundedChan[T]) Close() {
+	if !c.closed.CompareAndSwap(false, true) {
+		panic("queue: close of closed BoundedChan")
+	}
+	close(c.done)
+	c.signalReady()
+}
+
+// Closed reports whether Close has been called.
+func (c *BoundedChan[T]) Closed() bool {
+	return c.closed.Load()
+}
+
+// Ready returns a channel that receives a signal after items are sent
+// or the channel is closed, so a BoundedChan can take part in a select:
+//
+//	select {
+//	case <-bc.Ready():
+//	    for v, ok := bc.Pop(); ok; v, ok = bc.Pop() { ... }
+//	case <-ctx.Done():
+//	}
+//
+// Signals coalesce, so always drain with Pop after waking. Notification
+// is off until Ready is first called, keeping Send free of channel ops
+// for users that never select.
+func (c *BoundedChan[T]) Ready() <-chan struct{} {
+	c.notify.Store(true)
+	if c.items.n.Load() > 0 || c.closed.Load() {
+		c.signalReady()
+	}
+	return c.ready
+}
+
+// Len returns the current number of items in the channel.
+func (c *BoundedChan[T]) Len() int {
+	return c.ring.Len()
+}
+
+// Cap returns the capacity of the channel.
+func (c *BoundedChan[T]) Cap() int {
+	return c.size
+}
+
+func (c *BoundedChan[T]) put(v T) {
+	c.sendMu.Lock()
+	c.ring.Push(v) // Cannot fail: a slot permit is held
+	c.sendMu.Unlock()
+	c.items.release()
+	if c.notify.Load() {
+		c.signalReady()
+	}
+}
+
+func (c *BoundedChan[T]) take() T {
+	c.recvMu.Lock()
+	v, _ := c.ring.Pop() // Cannot fail: an item permit is held
+	c.recvMu.Unlock()
+	c.slots.release()
+	return v
+}
+
+func (c *BoundedChan[T]) signalReady() {
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
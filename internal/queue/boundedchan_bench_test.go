@@ -0,0 +1,141 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// BoundedChan vs native chan T with identical blocking semantics.
+//
+// ChannelQueue and RingBuffer are compared through non-blocking
+// Push/Pop, which puts a select/default on the channel side only. Here
+// both sides block, so the difference is purely the implementation.
+
+func BenchmarkQueue_NativeChan_SendRecv(b *testing.B) {
+	ch := make(chan int, 1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	for i := 0; i < b.N; i++ {
+		ch <- i
+		val = <-ch
+	}
+	sinkInt = val
+}
+
+func BenchmarkQueue_BoundedChan_SendRecv(b *testing.B) {
+	q := queue.Must(queue.NewBoundedChan[int](1024))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	for i := 0; i < b.N; i++ {
+		q.Send(i)
+		val, _ = q.Recv()
+	}
+	sinkInt = val
+}
+
+// Producer/consumer goroutines: blocking handoff, including parking when
+// one side outruns the other.
+
+func BenchmarkQueue_NativeChan_Pipeline(b *testing.B) {
+	ch := make(chan int, 1024)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for range ch {
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ch <- i
+	}
+	close(ch)
+	<-done
+}
+
+func BenchmarkQueue_BoundedChan_Pipeline(b *testing.B) {
+	q := queue.Must(queue.NewBoundedChan[int](1024))
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for {
+			if _, ok := q.Recv(); !ok {
+				return
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		q.Send(i)
+	}
+	q.Close()
+	<-done
+}
+
+// PopTimeout wake-up latency.
+//
+// A producer sends a timestamped item every 200µs; the consumer sleeps in
+// PopTimeout(d). wake-ns is the time from Send to PopTimeout returning
+// the item. timeouts/op counts how often the consumer woke empty-handed
+// to do other work before the item arrived, which is the price of a
+// shorter bound on latency.
+
+var popTimeouts = []time.Duration{
+	50 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+}
+
+func BenchmarkQueue_BoundedChan_PopTimeout(b *testing.B) {
+	for _, d := range popTimeouts {
+		b.Run("timeout="+d.String(), func(b *testing.B) {
+			q := queue.Must(queue.NewBoundedChan[int64](16))
+			base := time.Now()
+			done := make(chan struct{})
+
+			go func() {
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					time.Sleep(200 * time.Microsecond)
+					q.Push(int64(time.Since(base)))
+				}
+			}()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var wake, timeouts int64
+			for i := 0; i < b.N; {
+				sent, ok := q.PopTimeout(d)
+				if !ok {
+					timeouts++
+					continue
+				}
+				wake += int64(time.Since(base)) - sent
+				i++
+			}
+
+			b.StopTimer()
+			close(done)
+			b.ReportMetric(float64(wake)/float64(b.N), "wake-ns")
+			b.ReportMetric(float64(timeouts)/float64(b.N), "timeouts/op")
+		})
+	}
+}
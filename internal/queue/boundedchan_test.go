@@ -0,0 +1,238 @@
+package queue_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+func TestBoundedChan(t *testing.T) {
+	q := queue.Must(queue.NewBoundedChan[int](8))
+	testQueue(t, q, 42, "BoundedChan")
+}
+
+func TestBoundedChan_Full(t *testing.T) {
+	q := queue.Must(queue.NewBoundedChan[int](3))
+	if q.Cap() != 3 {
+		t.Errorf("expected Cap() = 3 (exact, not rounded), got %d", q.Cap())
+	}
+	for i := 0; i < 3; i++ {
+		if !q.Push(i) {
+			t.Fatalf("expected Push(%d) = true", i)
+		}
+	}
+	if q.Push(3) {
+		t.Error("expected Push(3) = false on full channel")
+	}
+}
+
+func TestBoundedChan_SendBlocksUntilRecv(t *testing.T) {
+	q := queue.Must(queue.NewBoundedChan[int](1))
+	q.Send(1)
+
+	sent := make(chan struct{})
+	go func() {
+		q.Send(2)
+		close(sent)
+	}()
+
+	// Send can't be shown to block forever; it must not complete within a
+	// window long enough for the goroutine to have run
+	select {
+	case <-sent:
+		t.Fatal("expected Send() to block on full channel")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if v, ok := q.Recv(); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%d, %v)", v, ok)
+	}
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected blocked Send() to complete after Recv()")
+	}
+	if v, ok := q.Recv(); !ok || v != 2 {
+		t.Fatalf("expected (2, true), got (%d, %v)", v, ok)
+	}
+}
+
+func TestBoundedChan_CloseDrains(t *testing.T) {
+	q := queue.Must(queue.NewBoundedChan[int](4))
+	q.Send(1)
+	q.Send(2)
+	q.Close()
+
+	for _, want := range []int{1, 2} {
+		if v, ok := q.Recv(); !ok || v != want {
+			t.Errorf("expected (%d, true), got (%d, %v)", want, v, ok)
+		}
+	}
+	if _, ok := q.Recv(); ok {
+		t.Error("expected Recv() = false on closed, drained channel")
+	}
+}
+
+func TestBoundedChan_CloseWakesReceivers(t *testing.T) {
+	q := queue.Must(queue.NewBoundedChan[int](4))
+
+	const receivers = 4
+	var wg sync.WaitGroup
+	for i := 0; i < receivers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := q.Recv(); ok {
+				t.Error("expected Recv() = false after Close")
+			}
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	q.Close()
+	wg.Wait()
+}
+
+func TestBoundedChan_SendOnClosed_Panics(t *testing.T) {
+	q := queue.Must(queue.NewBoundedChan[int](4))
+	q.Close()
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on Send after Close")
+		}
+	}()
+	q.Send(1)
+}
+
+func TestBoundedChan_DoubleClose_Panics(t *testing.T) {
+	q := queue.Must(queue.NewBoundedChan[int](4))
+	q.Close()
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on second Close")
+		}
+	}()
+	q.Close()
+}
+
+func TestBoundedChan_Ready(t *testing.T) {
+	q := queue.Must(queue.NewBoundedChan[int](4))
+	ready := q.Ready()
+
+	select {
+	case <-ready:
+		t.Fatal("expected no signal on empty channel")
+	default:
+	}
+
+	go q.Send(7)
+
+	select {
+	case <-ready:
+	case <-time.After(time.Second):
+		t.Fatal("expected Ready() to signal after Send")
+	}
+	if v, ok := q.Pop(); !ok || v != 7 {
+		t.Errorf("expected (7, true), got (%d, %v)", v, ok)
+	}
+}
+
+// TestBoundedChan_MPMC checks that concurrent senders and receivers
+// deliver every item exactly once.
+func TestBoundedChan_MPMC(t *testing.T) {
+	const senders = 4
+	const receivers = 4
+	const perSender = 2000
+
+	q := queue.Must(queue.NewBoundedChan[int](16))
+	var sum atomic.Int64
+	var count atomic.Int64
+
+	var rwg sync.WaitGroup
+	for i := 0; i < receivers; i++ {
+		rwg.Add(1)
+		go func() {
+			defer rwg.Done()
+			for {
+				v, ok := q.Recv()
+				if !ok {
+					return
+				}
+				sum.Add(int64(v))
+				count.Add(1)
+			}
+		}()
+	}
+
+	var swg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		swg.Add(1)
+		go func() {
+			defer swg.Done()
+			for j := 1; j <= perSender; j++ {
+				q.Send(j)
+			}
+		}()
+	}
+
+	swg.Wait()
+	q.Close()
+	rwg.Wait()
+
+	wantCount := int64(senders * perSender)
+	wantSum := int64(senders * perSender * (perSender + 1) / 2)
+	if count.Load() != wantCount {
+		t.Errorf("expected %d items, got %d", wantCount, count.Load())
+	}
+	if sum.Load() != wantSum {
+		t.Errorf("expected sum %d, got %d", wantSum, sum.Load())
+	}
+}
+
+func TestBoundedChan_PopTimeout(t *testing.T) {
+	q := queue.Must(queue.NewBoundedChan[int](4))
+
+	start := time.Now()
+	if _, ok := q.PopTimeout(20 * time.Millisecond); ok {
+		t.Error("expected PopTimeout() = false on empty channel")
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected PopTimeout() to wait 20ms, returned after %v", elapsed)
+	}
+
+	q.Push(5)
+	if v, ok := q.PopTimeout(time.Second); !ok || v != 5 {
+		t.Errorf("expected (5, true), got (%d, %v)", v, ok)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Send(6)
+	}()
+	if v, ok := q.PopTimeout(time.Second); !ok || v != 6 {
+		t.Errorf("expected (6, true) after wake-up, got (%d, %v)", v, ok)
+	}
+
+	if _, ok := q.PopTimeout(0); ok {
+		t.Error("expected PopTimeout(0) = false on empty channel")
+	}
+}
+
+func TestBoundedChan_PopTimeout_Closed(t *testing.T) {
+	q := queue.Must(queue.NewBoundedChan[int](4))
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Close()
+	}()
+
+	start := time.Now()
+	if _, ok := q.PopTimeout(time.Second); ok {
+		t.Error("expected PopTimeout() = false on closed channel")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected Close to wake PopTimeout early, waited %v", elapsed)
+	}
+}
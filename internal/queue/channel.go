@@ -9,10 +9,31 @@ type ChannelQueue[T any] struct {
 }
 
 // NewChannel creates a ChannelQueue with the specified buffer size.
-func NewChannel[T any](size int) *ChannelQueue[T] {
+//
+// Returns ErrInvalidSize if size is not between 1 and MaxInt/2. A size
+// of zero would make an unbuffered channel, on which the non-blocking
+// Push can never succeed.
+func NewChannel[T any](size int) (*ChannelQueue[T], error) {
+	if err := checkSize(size); err != nil {
+		return nil, err
+	}
 	return &ChannelQueue[T]{
 		ch: make(chan T, size),
-	}
+	}, nil
+}
+
+// FromChan wraps an existing channel as a ChannelQueue, so channel-based
+// code can be dropped into Queue-based benchmarks and pipelines.
+//
+// The channel keeps its own semantics underneath the non-blocking
+// Push/Pop:
+//   - Unbuffered: Push succeeds only if a receiver is already waiting,
+//     Pop only if a sender is.
+//   - nil: Push and Pop never succeed.
+//   - Closed: Pop returns the remaining buffered items, then false; Push
+//     panics, like a send on a closed channel.
+func FromChan[T any](ch chan T) *ChannelQueue[T] {
+	return &ChannelQueue[T]{ch: ch}
 }
 
 // Push adds an item to the queue.
@@ -27,17 +48,53 @@ func (q *ChannelQueue[T]) Push(v T) bool {
 }
 
 // Pop removes and returns an item from the queue.
-// Returns false if the queue is empty (non-blocking).
+// Returns false if the queue is empty (non-blocking), or closed and
+// drained.
 func (q *ChannelQueue[T]) Pop() (T, bool) {
 	select {
-	case v := <-q.ch:
-		return v, true
+	case v, ok := <-q.ch:
+		return v, ok
 	default:
 		var zero T
 		return zero, false
 	}
 }
 
+// Drain pops up to len(dst) currently available items into dst and
+// returns how many were copied. It receives until dst is full or a
+// receive would block, so on an unbuffered channel it takes from the
+// senders waiting, as Pop does.
+func (q *ChannelQueue[T]) Drain(dst []T) int {
+	for i := range dst {
+		select {
+		case v, ok := <-q.ch:
+			if !ok {
+				return i
+			}
+			dst[i] = v
+		default:
+			return i
+		}
+	}
+	return len(dst)
+}
+
+// Range pops each currently available item and passes it to fn, stopping
+// early if fn returns false. The item passed to the call that returned
+// false is still consumed.
+func (q *ChannelQueue[T]) Range(fn func(T) bool) {
+	for n := len(q.ch); n > 0; n-- {
+		select {
+		case v, ok := <-q.ch:
+			if !ok || !fn(v) {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
 // Len returns the current number of items in the queue.
 func (q *ChannelQueue[T]) Len() int {
 	return len(q.ch)
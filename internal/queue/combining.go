@@ -0,0 +1,223 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+var (
+	// ErrInvalidProducers is returned by NewCombiningQueue for a
+	// maxProducers outside 1..65536.
+	ErrInvalidProducers = errors.New("queue: maxProducers must be between 1 and 65536")
+
+	// ErrTooManyProducers is returned by CombiningQueue.NewProducer once
+	// every publication slot has been handed out.
+	ErrTooManyProducers = errors.New("queue: all producer slots are in use")
+)
+
+// Publication slot states.
+const (
+	fcIdle    uint32 = iota // No request
+	fcPending               // Value written, waiting for a combiner
+	fcDone                  // Value is in the ring
+	fcFull                  // Ring was full; value was not added
+)
+
+type fcSlot[T any] struct {
+	state atomic.Uint32
+	val   T
+
+	_pad [56]byte //nolint:unused
+}
+
+// CombiningQueue is a bounded MPSC queue using flat combining.
+//
+// Instead of every producer contending on a shared index, each producer
+// writes its item into its own publication slot and then either waits or,
+// if the combiner lock is free, becomes the combiner: it scans all slots,
+// appends every pending item to the ring, publishes head once for the
+// whole batch, and then releases the waiting producers. Under heavy
+// producer contention one thread does the work of many while the others
+// spin on their own cache lines.
+//
+// Producers get a slot with NewProducer and push through the returned
+// CombiningProducer. The consumer side is a plain SPSC ring, since only
+// the lock holder ever writes.
+//
+// CONTRACT: Each CombiningProducer is used by ONE goroutine at a time.
+// Only ONE goroutine may call Pop().
+type CombiningQueue[T any] struct {
+	buf  []T
+	mask uint64
+
+	slots  []fcSlot[T]
+	nslots atomic.Int32 // Slots handed out by NewProducer
+
+	_pad0 [56]byte //nolint:unused
+
+	lock atomic.Bool // Held by the current combiner
+
+	_pad1 [56]byte //nolint:unused
+
+	head atomic.Uint64 // Written by the combiner, read by the consumer
+
+	_pad2 [56]byte //nolint:unused
+
+	tail atomic.Uint64 // Written by the consumer, read by the combiner
+
+	_pad3 [56]byte //nolint:unused
+
+	served []int // Combiner-owned scratch: slots filled this pass
+}
+
+// CombiningProducer is one producer's handle on a CombiningQueue.
+type CombiningProducer[T any] struct {
+	q    *CombiningQueue[T]
+	slot *fcSlot[T]
+}
+
+// NewCombiningQueue creates a CombiningQueue with the specified size and
+// room for up to maxProducers producers.
+// Size will be rounded up to the next power of 2.
+//
+// Returns ErrInvalidSize if size is not between 1 and MaxInt/2, and
+// ErrInvalidProducers if maxProducers is not between 1 and 65536.
+func NewCombiningQueue[T any](size, maxProducers int) (*CombiningQueue[T], error) {
+	if err := checkSize(size); err != nil {
+		return nil, err
+	}
+	if maxProducers < 1 || maxProducers > 1<<16 {
+		return nil, fmt.Errorf("%w: got %d", ErrInvalidProducers, maxProducers)
+	}
+	n := roundPow2(size)
+
+	return &CombiningQueue[T]{
+		buf:    make([]T, n),
+		mask:   n - 1,
+		slots:  make([]fcSlot[T], maxProducers),
+		served: make([]int, 0, maxProducers),
+	}, nil
+}
+
+// NewProducer allocates a publication slot for a new producer.
+// Returns ErrTooManyProducers once maxProducers handles exist.
+//
+// Safe for concurrent use.
+func (q *CombiningQueue[T]) NewProducer() (*CombiningProducer[T], error) {
+	for {
+		n := q.nslots.Load()
+		if int(n) >= len(q.slots) {
+			return nil, ErrTooManyProducers
+		}
+		if q.nslots.CompareAndSwap(n, n+1) {
+			return &CombiningProducer[T]{q: q, slot: &q.slots[n]}, nil
+		}
+	}
+}
+
+// Push adds an item to the queue.
+// Returns false if the queue was full when a combiner reached it.
+//
+// The item is visible to Pop by the time Push returns true.
+func (p *CombiningProducer[T]) Push(v T) bool {
+	s := p.slot
+	s.val = v
+	s.state.Store(fcPending)
+
+	for spins := 0; ; spins++ {
+		switch s.state.Load() {
+		case fcDone:
+			s.state.Store(fcIdle)
+			return true
+		case fcFull:
+			var zero T
+			s.val = zero
+			s.state.Store(fcIdle)
+			return false
+		}
+
+		if p.q.lock.CompareAndSwap(false, true) {
+			p.q.combine()
+			p.q.lock.Store(false)
+			continue
+		}
+
+		if spins&63 == 63 {
+			// Let the combiner run if it shares our P
+			runtime.Gosched()
+		}
+	}
+}
+
+// combine applies every pending request. Caller holds q.lock.
+func (q *CombiningQueue[T]) combine() {
+	head := q.head.Load()
+	tail := q.tail.Load()
+	size := uint64(len(q.buf))
+
+	served := q.served[:0]
+	n := int(q.nslots.Load())
+	for i := 0; i < n; i++ {
+		s := &q.slots[i]
+		if s.state.Load() != fcPending {
+			continue
+		}
+		if head-tail >= size {
+			tail = q.tail.Load()
+			if head-tail >= size {
+				s.state.Store(fcFull)
+				continue
+			}
+		}
+		q.buf[head&q.mask] = s.val
+		var zero T
+		s.val = zero
+		head++
+		served = append(served, i)
+	}
+
+	if len(served) == 0 {
+		return
+	}
+
+	// Publish the whole batch, then release its producers
+	q.head.Store(head)
+	for _, i := range served {
+		q.slots[i].state.Store(fcDone)
+	}
+	q.served = served
+}
+
+// Pop removes and returns an item from the queue.
+// Returns false if the queue is empty.
+//
+// CONTRACT: Only ONE goroutine may call Pop().
+func (q *CombiningQueue[T]) Pop() (T, bool) {
+	tail := q.tail.Load()
+	head := q.head.Load()
+	if tail == head {
+		var zero T
+		return zero, false
+	}
+
+	v := q.buf[tail&q.mask]
+	var zero T
+	q.buf[tail&q.mask] = zero
+	q.tail.Store(tail + 1)
+	return v, true
+}
+
+// Len returns the current number of items in the queue.
+// This is an approximation and may be slightly stale.
+func (q *CombiningQueue[T]) Len() int {
+	tail := q.tail.Load()
+	head := q.head.Load()
+	return int(head - tail)
+}
+
+// Cap returns the capacity of the queue.
+func (q *CombiningQueue[T]) Cap() int {
+	return len(q.buf)
+}
@@ -0,0 +1,101 @@
+package queue_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+func TestCombiningQueue(t *testing.T) {
+	q := queue.Must(queue.NewCombiningQueue[int](4, 2))
+	p := queue.Must(q.NewProducer())
+
+	if _, ok := q.Pop(); ok {
+		t.Error("expected Pop() = false on empty queue")
+	}
+	for i := 0; i < 4; i++ {
+		if !p.Push(i) {
+			t.Fatalf("expected Push(%d) = true", i)
+		}
+	}
+	if p.Push(4) {
+		t.Error("expected Push() = false on full queue")
+	}
+	if q.Len() != 4 || q.Cap() != 4 {
+		t.Errorf("expected Len() = 4, Cap() = 4, got %d, %d", q.Len(), q.Cap())
+	}
+	for i := 0; i < 4; i++ {
+		if got, ok := q.Pop(); !ok || got != i {
+			t.Fatalf("Pop() = (%d, %v), want (%d, true)", got, ok, i)
+		}
+	}
+
+	// A rejected push leaves the producer usable
+	if !p.Push(5) {
+		t.Error("expected Push() = true after draining")
+	}
+}
+
+func TestCombiningQueue_Producers(t *testing.T) {
+	q := queue.Must(queue.NewCombiningQueue[int](8, 2))
+	for i := 0; i < 2; i++ {
+		if _, err := q.NewProducer(); err != nil {
+			t.Fatalf("NewProducer() %d: unexpected error %v", i, err)
+		}
+	}
+	if _, err := q.NewProducer(); !errors.Is(err, queue.ErrTooManyProducers) {
+		t.Errorf("expected ErrTooManyProducers, got %v", err)
+	}
+
+	for _, n := range []int{0, -1, 1<<16 + 1} {
+		if _, err := queue.NewCombiningQueue[int](8, n); !errors.Is(err, queue.ErrInvalidProducers) {
+			t.Errorf("NewCombiningQueue(8, %d): expected ErrInvalidProducers, got %v", n, err)
+		}
+	}
+	if _, err := queue.NewCombiningQueue[int](0, 1); !errors.Is(err, queue.ErrInvalidSize) {
+		t.Errorf("NewCombiningQueue(0, 1): expected ErrInvalidSize, got %v", err)
+	}
+}
+
+// TestCombiningQueue_MPSC verifies that concurrent producers never lose
+// items and that each producer's items arrive in the order it pushed them.
+func TestCombiningQueue_MPSC(t *testing.T) {
+	const producers = 4
+	const perProducer = 1000
+
+	q := queue.Must(queue.NewCombiningQueue[[2]int](1024, producers))
+
+	var wg sync.WaitGroup
+	for i := 0; i < producers; i++ {
+		p := queue.Must(q.NewProducer())
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				for !p.Push([2]int{id, i}) {
+					// Spin until push succeeds
+				}
+			}
+		}(i)
+	}
+
+	next := make([]int, producers)
+	for received := 0; received < producers*perProducer; {
+		v, ok := q.Pop()
+		if !ok {
+			continue
+		}
+		if v[1] != next[v[0]] {
+			t.Fatalf("producer %d: expected %d, got %d", v[0], next[v[0]], v[1])
+		}
+		next[v[0]]++
+		received++
+	}
+	wg.Wait()
+
+	if _, ok := q.Pop(); ok {
+		t.Error("expected Pop() = false after all items received")
+	}
+}
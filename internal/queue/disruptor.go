@@ -0,0 +1,191 @@
+package queue
+
+import (
+	"sync/atomic"
+)
+
+// Disruptor is a single-producer ring buffer with a consumer dependency
+// graph, modelled on the LMAX Disruptor.
+//
+// Instead of handing each item from stage to stage through separate
+// queues, every consumer reads the same slot in place. Each consumer
+// publishes the last sequence it has processed; a consumer's barrier is
+// either the producer cursor (no dependencies) or the minimum sequence of
+// the consumers it depends on. The producer may only reuse a slot once
+// every terminal consumer (one nothing else depends on) has passed it.
+//
+// Example: journal -> replicate -> apply
+//
+//	d, err := queue.Must(queue.NewDisruptor[Event](1024))
+//	journal := d.NewConsumer()
+//	replicate := d.NewConsumer(journal)
+//	apply := d.NewConsumer(replicate)
+//
+// CONTRACT: Exactly ONE goroutine calls Publish(). Each Consumer is
+// polled by exactly ONE goroutine. All consumers must be created before
+// the first Publish().
+type Disruptor[T any] struct {
+	buf  []T
+	mask int64
+
+	_pad0 [56]byte //nolint:unused
+
+	cursor atomic.Int64 // Last published sequence
+
+	_pad1 [56]byte //nolint:unused
+
+	cachedGate int64 // Producer-owned: last observed minimum terminal sequence
+	consumers  []*Consumer[T]
+	terminal   []*Consumer[T]
+}
+
+// Consumer is one stage of a Disruptor's dependency graph.
+type Consumer[T any] struct {
+	d    *Disruptor[T]
+	deps []*Consumer[T]
+
+	_pad0 [56]byte //nolint:unused
+
+	seq atomic.Int64 // Last processed sequence
+
+	_pad1 [56]byte //nolint:unused
+
+	cachedAvail int64 // Consumer-owned: last observed barrier
+	dependents  int
+}
+
+// NewDisruptor creates a Disruptor with the specified size.
+// Size will be rounded up to the next power of 2.
+//
+// Returns ErrInvalidSize if size is not between 1 and MaxInt/2.
+func NewDisruptor[T any](size int) (*Disruptor[T], error) {
+	if err := checkSize(size); err != nil {
+		return nil, err
+	}
+	n := int64(roundPow2(size))
+
+	d := &Disruptor[T]{
+		buf:        make([]T, n),
+		mask:       n - 1,
+		cachedGate: -1,
+	}
+	d.cursor.Store(-1)
+	return d, nil
+}
+
+// NewConsumer adds a consumer that only sees items after every consumer
+// in deps has processed them. With no deps, the consumer follows the
+// producer directly.
+func (d *Disruptor[T]) NewConsumer(deps ...*Consumer[T]) *Consumer[T] {
+	c := &Consumer[T]{
+		d:           d,
+		deps:        deps,
+		cachedAvail: -1,
+	}
+	c.seq.Store(-1)
+
+	for _, dep := range deps {
+		dep.dependents++
+	}
+	d.consumers = append(d.consumers, c)
+
+	d.terminal = d.terminal[:0]
+	for _, cc := range d.consumers {
+		if cc.dependents == 0 {
+			d.terminal = append(d.terminal, cc)
+		}
+	}
+	return c
+}
+
+// Publish writes an item into the next slot.
+// Returns false if the slowest terminal consumer has not freed it yet.
+//
+// CONTRACT: Only ONE goroutine may call Publish().
+func (d *Disruptor[T]) Publish(v T) bool {
+	next := d.cursor.Load() + 1
+	wrap := next - int64(len(d.buf))
+
+	if wrap > d.cachedGate {
+		d.cachedGate = d.minTerminal(next - 1)
+		if wrap > d.cachedGate {
+			return false
+		}
+	}
+
+	d.buf[next&d.mask] = v
+	d.cursor.Store(next)
+	return true
+}
+
+func (d *Disruptor[T]) minTerminal(limit int64) int64 {
+	m := limit
+	for _, c := range d.terminal {
+		if s := c.seq.Load(); s < m {
+			m = s
+		}
+	}
+	return m
+}
+
+// Cap returns the capacity of the ring.
+func (d *Disruptor[T]) Cap() int {
+	return len(d.buf)
+}
+
+// Poll returns the next item this consumer may process.
+// Returns false if the barrier has not advanced past it yet.
+//
+// CONTRACT: Only ONE goroutine may call Poll() or Consume() on a Consumer.
+func (c *Consumer[T]) Poll() (T, bool) {
+	next := c.seq.Load() + 1
+	if next > c.cachedAvail {
+		c.cachedAvail = c.barrier()
+		if next > c.cachedAvail {
+			var zero T
+			return zero, false
+		}
+	}
+
+	v := c.d.buf[next&c.d.mask]
+	c.seq.Store(next)
+	return v, true
+}
+
+// Consume passes every currently available item to fn and then publishes
+// progress once. Returns the number of items processed.
+//
+// This is the Disruptor's batching effect: one barrier read and one
+// sequence store per batch instead of per item.
+func (c *Consumer[T]) Consume(fn func(T)) int {
+	next := c.seq.Load() + 1
+	avail := c.barrier()
+	if next > avail {
+		return 0
+	}
+
+	for s := next; s <= avail; s++ {
+		fn(c.d.buf[s&c.d.mask])
+	}
+	c.cachedAvail = avail
+	c.seq.Store(avail)
+	return int(avail - next + 1)
+}
+
+// Sequence returns the last sequence this consumer has processed.
+func (c *Consumer[T]) Sequence() int64 {
+	return c.seq.Load()
+}
+
+func (c *Consumer[T]) barrier() int64 {
+	if len(c.deps) == 0 {
+		return c.d.cursor.Load()
+	}
+	m := c.deps[0].seq.Load()
+	for _, dep := range c.deps[1:] {
+		if s := dep.seq.Load(); s < m {
+			m = s
+		}
+	}
+	return m
+}
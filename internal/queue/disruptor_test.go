@@ -0,0 +1,139 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+func TestDisruptor_Basic(t *testing.T) {
+	d := queue.Must(queue.NewDisruptor[int](8))
+	c := d.NewConsumer()
+
+	if _, ok := c.Poll(); ok {
+		t.Error("expected Poll() = false on empty ring")
+	}
+	if !d.Publish(42) {
+		t.Fatal("expected Publish() = true")
+	}
+	got, ok := c.Poll()
+	if !ok || got != 42 {
+		t.Errorf("expected (42, true), got (%d, %v)", got, ok)
+	}
+	if _, ok := c.Poll(); ok {
+		t.Error("expected Poll() = false after draining")
+	}
+}
+
+func TestDisruptor_Full(t *testing.T) {
+	d := queue.Must(queue.NewDisruptor[int](4))
+	c := d.NewConsumer()
+
+	for i := 0; i < 4; i++ {
+		if !d.Publish(i) {
+			t.Fatalf("expected Publish(%d) = true", i)
+		}
+	}
+	if d.Publish(4) {
+		t.Error("expected Publish() = false while consumer holds every slot")
+	}
+
+	c.Poll()
+	if !d.Publish(4) {
+		t.Error("expected Publish() = true after consumer frees a slot")
+	}
+}
+
+// TestDisruptor_Dependencies verifies that a downstream consumer never
+// sees an item before its upstream consumer has processed it, and that
+// the producer is gated by the last stage, not the first.
+func TestDisruptor_Dependencies(t *testing.T) {
+	d := queue.Must(queue.NewDisruptor[int](4))
+	journal := d.NewConsumer()
+	replicate := d.NewConsumer(journal)
+	apply := d.NewConsumer(replicate)
+
+	d.Publish(1)
+	d.Publish(2)
+
+	if _, ok := replicate.Poll(); ok {
+		t.Error("replicate must wait for journal")
+	}
+	if v, ok := journal.Poll(); !ok || v != 1 {
+		t.Fatalf("journal: expected (1, true), got (%d, %v)", v, ok)
+	}
+	if v, ok := replicate.Poll(); !ok || v != 1 {
+		t.Fatalf("replicate: expected (1, true), got (%d, %v)", v, ok)
+	}
+	if _, ok := replicate.Poll(); ok {
+		t.Error("replicate must not pass journal")
+	}
+
+	// Journal drains everything; apply has seen nothing, so the ring is
+	// still gated by apply.
+	journal.Poll()
+	d.Publish(3)
+	d.Publish(4)
+	for journal.Consume(func(int) {}) > 0 {
+	}
+	if d.Publish(5) {
+		t.Error("expected Publish() = false while terminal consumer lags")
+	}
+
+	replicate.Consume(func(int) {})
+	var got []int
+	apply.Consume(func(v int) { got = append(got, v) })
+	want := []int{1, 2, 3, 4}
+	if len(got) != len(want) {
+		t.Fatalf("apply: expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("apply: expected %v, got %v", want, got)
+			break
+		}
+	}
+	if !d.Publish(5) {
+		t.Error("expected Publish() = true once apply caught up")
+	}
+}
+
+// TestDisruptor_Pipeline runs a 1-producer/3-stage pipeline concurrently
+// and checks every stage sees every item in order.
+func TestDisruptor_Pipeline(t *testing.T) {
+	const count = 2000
+	d := queue.Must(queue.NewDisruptor[int](64))
+	stages := []*queue.Consumer[int]{d.NewConsumer()}
+	stages = append(stages, d.NewConsumer(stages[0]))
+	stages = append(stages, d.NewConsumer(stages[1]))
+
+	errs := make(chan string, len(stages))
+	for _, c := range stages {
+		go func(c *queue.Consumer[int]) {
+			expected := 0
+			for expected < count {
+				v, ok := c.Poll()
+				if !ok {
+					continue
+				}
+				if v != expected {
+					errs <- "FIFO violation"
+					return
+				}
+				expected++
+			}
+			errs <- ""
+		}(c)
+	}
+
+	for i := 0; i < count; i++ {
+		for !d.Publish(i) {
+		}
+	}
+
+	for range stages {
+		if e := <-errs; e != "" {
+			t.Error(e)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package queue
+
+// SetIndices positions both head and tail of an empty RingBuffer at v,
+// so tests can exercise index wraparound without pushing 2^32 items.
+func (r *RingBuffer[T]) SetIndices(v uint64) {
+	r.head.Store(v)
+	r.tail.Store(v)
+}
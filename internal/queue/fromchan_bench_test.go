@@ -0,0 +1,49 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// FromChan dispatch overhead.
+//
+// RawChan is the select-with-default code people write inline; FromChan
+// and NewChannel go through the same ChannelQueue methods, called via the
+// Queue interface as the pipelines do.
+
+func BenchmarkQueue_RawChan_PushPop(b *testing.B) {
+	ch := make(chan int, 1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	var ok bool
+	for i := 0; i < b.N; i++ {
+		select {
+		case ch <- i:
+		default:
+		}
+		select {
+		case val, ok = <-ch:
+		default:
+		}
+	}
+	sinkInt = val
+	sinkBool = ok
+}
+
+func BenchmarkQueue_FromChan_PushPop_Interface(b *testing.B) {
+	var q queue.Queue[int] = queue.FromChan(make(chan int, 1024))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	var ok bool
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		val, ok = q.Pop()
+	}
+	sinkInt = val
+	sinkBool = ok
+}
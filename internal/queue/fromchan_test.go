@@ -0,0 +1,120 @@
+package queue_test
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+func TestFromChan(t *testing.T) {
+	ch := make(chan int, 4)
+	q := queue.FromChan(ch)
+	testQueue(t, q, 42, "FromChan")
+
+	if q.Cap() != 4 {
+		t.Errorf("expected Cap() = 4, got %d", q.Cap())
+	}
+
+	// Items sent on the channel directly are visible through the queue
+	ch <- 7
+	if got, ok := q.Pop(); !ok || got != 7 {
+		t.Errorf("Pop() = (%d, %v), want (7, true)", got, ok)
+	}
+	q.Push(8)
+	if got := <-ch; got != 8 {
+		t.Errorf("<-ch = %d, want 8", got)
+	}
+}
+
+func TestFromChan_Closed(t *testing.T) {
+	ch := make(chan int, 4)
+	q := queue.FromChan(ch)
+	q.Push(1)
+	q.Push(2)
+	close(ch)
+
+	// Buffered items are still delivered, then Pop reports empty
+	for want := 1; want <= 2; want++ {
+		if got, ok := q.Pop(); !ok || got != want {
+			t.Fatalf("Pop() = (%d, %v), want (%d, true)", got, ok, want)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if got, ok := q.Pop(); ok {
+			t.Fatalf("expected Pop() = false on closed, drained channel, got %d", got)
+		}
+	}
+
+	dst := make([]int, 4)
+	if n := q.Drain(dst); n != 0 {
+		t.Errorf("expected Drain() = 0 on closed channel, got %d", n)
+	}
+	q.Range(func(v int) bool {
+		t.Errorf("Range visited %d on closed channel", v)
+		return true
+	})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Push() on closed channel to panic")
+		}
+	}()
+	q.Push(3)
+}
+
+func TestFromChan_ClosedDrain(t *testing.T) {
+	ch := make(chan int, 4)
+	q := queue.FromChan(ch)
+	q.Push(1)
+	q.Push(2)
+	close(ch)
+
+	dst := make([]int, 4)
+	if n := q.Drain(dst); n != 2 || dst[0] != 1 || dst[1] != 2 {
+		t.Errorf("Drain() = %d %v, want 2 [1 2 ...]", n, dst)
+	}
+}
+
+func TestFromChan_UnbufferedAndNil(t *testing.T) {
+	q := queue.FromChan(make(chan int))
+	if q.Push(1) {
+		t.Error("expected Push() = false on unbuffered channel with no receiver")
+	}
+	if _, ok := q.Pop(); ok {
+		t.Error("expected Pop() = false on unbuffered channel with no sender")
+	}
+
+	var nilCh chan int
+	qn := queue.FromChan(nilCh)
+	if qn.Push(1) {
+		t.Error("expected Push() = false on nil channel")
+	}
+	if _, ok := qn.Pop(); ok {
+		t.Error("expected Pop() = false on nil channel")
+	}
+}
+
+func TestFromChan_UnbufferedDrain(t *testing.T) {
+	ch := make(chan int)
+	q := queue.FromChan(ch)
+	go func() {
+		ch <- 1
+		ch <- 2
+	}()
+
+	// Each item is available while its sender waits, so Drain takes it
+	// even though len(ch) is always 0
+	var got []int
+	dst := make([]int, 4)
+	for deadline := time.Now().Add(5 * time.Second); len(got) < 2 && time.Now().Before(deadline); {
+		n := q.Drain(dst)
+		got = append(got, dst[:n]...)
+		runtime.Gosched()
+	}
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Drain() took %v from an unbuffered channel, want [1 2]", got)
+	}
+}
@@ -0,0 +1,106 @@
+package queue_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// Fuzz ops, one per input byte (low two bits):
+//
+//	0, 1: Push the next sequence number
+//	2:    Pop
+//	3:    Len
+const (
+	fuzzPushA = iota
+	fuzzPushB
+	fuzzPop
+	fuzzLen
+)
+
+// runFuzzOps applies ops to q and to a slice-backed reference queue of
+// the same capacity, failing on the first divergence.
+func runFuzzOps(t *testing.T, q queue.Sized[int], ops []byte) {
+	t.Helper()
+
+	var model []int
+	capacity := q.Cap()
+	next := 0
+
+	for i, b := range ops {
+		switch b & 3 {
+		case fuzzPushA, fuzzPushB:
+			want := len(model) < capacity
+			if got := q.Push(next); got != want {
+				t.Fatalf("op %d: Push(%d) = %v, want %v (len %d, cap %d)", i, next, got, want, len(model), capacity)
+			}
+			if want {
+				model = append(model, next)
+			}
+			next++
+		case fuzzPop:
+			got, ok := q.Pop()
+			if len(model) == 0 {
+				if ok {
+					t.Fatalf("op %d: Pop() = (%d, true) on empty queue", i, got)
+				}
+				continue
+			}
+			if !ok || got != model[0] {
+				t.Fatalf("op %d: Pop() = (%d, %v), want (%d, true)", i, got, ok, model[0])
+			}
+			model = model[1:]
+		case fuzzLen:
+			if got := q.Len(); got != len(model) {
+				t.Fatalf("op %d: Len() = %d, want %d", i, got, len(model))
+			}
+		}
+	}
+
+	// Whatever is left must come out in order
+	for _, want := range model {
+		if got, ok := q.Pop(); !ok || got != want {
+			t.Fatalf("final drain: Pop() = (%d, %v), want (%d, true)", got, ok, want)
+		}
+	}
+	if got, ok := q.Pop(); ok {
+		t.Fatalf("final drain: Pop() = (%d, true) on empty queue", got)
+	}
+}
+
+func addFuzzSeeds(f *testing.F) {
+	f.Add(uint8(1), uint64(0), []byte{0, 2, 2, 3})
+	f.Add(uint8(2), uint64(0), []byte{0, 1, 0, 3, 2, 2, 2, 3})
+	f.Add(uint8(3), uint64(math.MaxUint32-2), []byte{0, 0, 0, 0, 0, 3, 2, 0, 2, 2, 3, 2})
+	f.Add(uint8(8), uint64(math.MaxUint64-4), []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 3})
+	f.Add(uint8(5), uint64(0), []byte("push pop wrap around the ring"))
+}
+
+// FuzzRingBuffer checks RingBuffer against the reference model. start
+// positions the indices so the fuzzer can explore wraparound of both the
+// slot mask and the uint64 counters.
+func FuzzRingBuffer(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, size uint8, start uint64, ops []byte) {
+		if size == 0 {
+			return
+		}
+		q := queue.Must(queue.NewRingBuffer[int](int(size)))
+		q.SetIndices(start)
+		runFuzzOps(t, q, ops)
+	})
+}
+
+// FuzzChannelQueue checks ChannelQueue against the reference model.
+// start is unused; it keeps the corpus format shared with FuzzRingBuffer.
+func FuzzChannelQueue(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, size uint8, start uint64, ops []byte) {
+		if size == 0 {
+			return
+		}
+		q := queue.Must(queue.NewChannel[int](int(size)))
+		runFuzzOps(t, q, ops)
+	})
+}
@@ -0,0 +1,135 @@
+package queue_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// Generic instantiation vs boxing vs hand-specialized code.
+//
+//   - RingBuffer[int]:  generic, int has its own GC shape so the compiler
+//     stencils a dedicated copy (no dictionary lookups)
+//   - RingBuffer[any]:  generic over an interface; every Push boxes the int
+//     (values above 255 escape to the heap)
+//   - intRing:          the same algorithm written without type parameters,
+//     including the SPSC guards, so the only difference is generics
+//
+// If RingBuffer[int] and intRing match, generics are free for this shape.
+
+var sinkAnyQ any
+
+// intRing is RingBuffer specialized by hand to int.
+type intRing struct {
+	buf  []int
+	mask uint64
+
+	_pad0 [56]byte //nolint:unused
+
+	head atomic.Uint64
+
+	_pad1 [56]byte //nolint:unused
+
+	tail atomic.Uint64
+
+	_pad2 [56]byte //nolint:unused
+
+	pushActive atomic.Uint32
+	popActive  atomic.Uint32
+}
+
+func newIntRing(size int) *intRing {
+	n := uint64(1)
+	for n < uint64(size) {
+		n <<= 1
+	}
+	return &intRing{buf: make([]int, n), mask: n - 1}
+}
+
+func (r *intRing) Push(v int) bool {
+	if !r.pushActive.CompareAndSwap(0, 1) {
+		panic("intRing: concurrent Push")
+	}
+	defer r.pushActive.Store(0)
+
+	head := r.head.Load()
+	tail := r.tail.Load()
+	if head-tail >= uint64(len(r.buf)) {
+		return false
+	}
+	r.buf[head&r.mask] = v
+	r.head.Store(head + 1)
+	return true
+}
+
+func (r *intRing) Pop() (int, bool) {
+	if !r.popActive.CompareAndSwap(0, 1) {
+		panic("intRing: concurrent Pop")
+	}
+	defer r.popActive.Store(0)
+
+	tail := r.tail.Load()
+	head := r.head.Load()
+	if tail == head {
+		return 0, false
+	}
+	v := r.buf[tail&r.mask]
+	r.tail.Store(tail + 1)
+	return v, true
+}
+
+func BenchmarkQueue_Generic_Int(b *testing.B) {
+	q := queue.Must(queue.NewRingBuffer[int](1024))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		val, _ = q.Pop()
+	}
+	sinkInt = val
+}
+
+func BenchmarkQueue_Generic_Any(b *testing.B) {
+	q := queue.Must(queue.NewRingBuffer[any](1024))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val any
+	for i := 0; i < b.N; i++ {
+		q.Push(i) // boxes i
+		val, _ = q.Pop()
+	}
+	sinkAnyQ = val
+}
+
+// BenchmarkQueue_Generic_AnyUnbox adds the type assertion a consumer of
+// RingBuffer[any] has to pay to get the int back.
+func BenchmarkQueue_Generic_AnyUnbox(b *testing.B) {
+	q := queue.Must(queue.NewRingBuffer[any](1024))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		v, _ := q.Pop()
+		val = v.(int)
+	}
+	sinkInt = val
+}
+
+func BenchmarkQueue_Generic_Concrete(b *testing.B) {
+	q := newIntRing(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		val, _ = q.Pop()
+	}
+	sinkInt = val
+}
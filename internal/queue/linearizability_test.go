@@ -0,0 +1,459 @@
+package queue_test
+
+import (
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// Linearizability checking for the concurrent queues.
+//
+// Each test records a concurrent history of Push/Pop calls (with call and
+// return times from a shared logical clock) and searches for a sequential
+// FIFO execution that explains it, using the Wing & Gong / Lowe (WGL)
+// algorithm with state memoization, as popularized by the porcupine
+// checker (github.com/anishathalye/porcupine).
+//
+// The checker is kept in-tree instead of importing porcupine so the
+// queue package has no test-only dependencies. The model has porcupine's
+// shape (init + step), and the one thing step can't see from a single
+// operation, whether a failed Pop overlaps a Push (for weakEmpty, below),
+// is worked out from the history beforehand and stored on the operation,
+// as it would be in porcupine's Input. Moving onto porcupine is then a
+// change of checker, not of model or histories.
+
+type opKind int
+
+const (
+	opPush opKind = iota
+	opPop
+)
+
+type histOp struct {
+	id    int
+	kind  opKind
+	value int
+	ok    bool
+	call  int64
+	ret   int64
+
+	// pushPending marks a failed Pop that overlaps a Push, set by
+	// checkLinearizable for fifoModel's weakEmpty
+	pushPending bool
+}
+
+// fifoModel is a sequential FIFO queue specification.
+//
+// weakEmpty accepts a failed Pop in any state, but only while a Push is
+// in progress. Vyukov's MPSC list can report empty while an earlier
+// producer is between its swap and its link store, even though a later
+// push has completed; that is a documented property of the design, not a
+// bug. An empty Pop with no Push overlapping it is held to strict FIFO
+// order, so an item lost for good still fails the check.
+type fifoModel struct {
+	weakEmpty bool
+}
+
+func (m fifoModel) init() []int { return nil }
+
+func (m fifoModel) step(state []int, o *histOp) (bool, []int) {
+	switch o.kind {
+	case opPush:
+		if !o.ok {
+			// Tests size queues so Push never fails
+			return false, state
+		}
+		next := make([]int, len(state)+1)
+		copy(next, state)
+		next[len(state)] = o.value
+		return true, next
+	default:
+		if !o.ok {
+			return len(state) == 0 || (m.weakEmpty && o.pushPending), state
+		}
+		if len(state) == 0 || state[0] != o.value {
+			return false, state
+		}
+		return true, state[1:]
+	}
+}
+
+func stateKey(state []int) string {
+	var sb strings.Builder
+	for _, v := range state {
+		sb.WriteString(strconv.Itoa(v))
+		sb.WriteByte(',')
+	}
+	return sb.String()
+}
+
+type entry struct {
+	op     *histOp
+	isCall bool
+	time   int64
+	match  *entry
+	prev   *entry
+	next   *entry
+}
+
+type bitset []uint64
+
+func (b bitset) set(i int)   { b[i/64] |= 1 << (i % 64) }
+func (b bitset) clear(i int) { b[i/64] &^= 1 << (i % 64) }
+
+func (b bitset) key() string {
+	buf := make([]byte, 8*len(b))
+	for i, w := range b {
+		binary.LittleEndian.PutUint64(buf[i*8:], w)
+	}
+	return string(buf)
+}
+
+// checkLinearizable reports whether ops has a linearization under m.
+func checkLinearizable(m fifoModel, ops []histOp) bool {
+	for i := range ops {
+		if ops[i].kind == opPop && !ops[i].ok {
+			ops[i].pushPending = pushOverlaps(ops, &ops[i])
+		}
+	}
+
+	events := make([]*entry, 0, 2*len(ops))
+	for i := range ops {
+		o := &ops[i]
+		call := &entry{op: o, isCall: true, time: o.call}
+		ret := &entry{op: o, time: o.ret}
+		call.match = ret
+		events = append(events, call, ret)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].time < events[j].time })
+
+	head := &entry{}
+	prev := head
+	for _, e := range events {
+		prev.next = e
+		e.prev = prev
+		prev = e
+	}
+
+	lift := func(e *entry) {
+		e.prev.next = e.next
+		e.next.prev = e.prev
+		m := e.match
+		m.prev.next = m.next
+		if m.next != nil {
+			m.next.prev = m.prev
+		}
+	}
+	unlift := func(e *entry) {
+		m := e.match
+		m.prev.next = m
+		if m.next != nil {
+			m.next.prev = m
+		}
+		e.prev.next = e
+		e.next.prev = e
+	}
+
+	type frame struct {
+		e     *entry
+		state []int
+	}
+
+	state := m.init()
+	linearized := make(bitset, (len(ops)+63)/64)
+	cache := make(map[string]struct{})
+	var stack []frame
+
+	e := head.next
+	for head.next != nil {
+		if e.isCall {
+			if ok, next := m.step(state, e.op); ok {
+				linearized.set(e.op.id)
+				key := linearized.key() + "|" + stateKey(next)
+				if _, seen := cache[key]; !seen {
+					cache[key] = struct{}{}
+					stack = append(stack, frame{e, state})
+					state = next
+					lift(e)
+					e = head.next
+					continue
+				}
+				linearized.clear(e.op.id)
+			}
+			e = e.next
+			continue
+		}
+
+		// A return with its call still pending: backtrack
+		if len(stack) == 0 {
+			return false
+		}
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		state = top.state
+		linearized.clear(top.e.op.id)
+		unlift(top.e)
+		e = top.e.next
+	}
+	return true
+}
+
+// pushOverlaps reports whether a successful Push in ops overlaps o: was
+// called before o returned and returned after o was called.
+func pushOverlaps(ops []histOp, o *histOp) bool {
+	for i := range ops {
+		p := &ops[i]
+		if p.kind == opPush && p.ok && p.call < o.ret && p.ret > o.call {
+			return true
+		}
+	}
+	return false
+}
+
+// recorder collects operations from several goroutines.
+type recorder struct {
+	clock atomic.Int64
+	mu    sync.Mutex
+	ops   []histOp
+}
+
+func (r *recorder) push(push func(int) bool, v int) histOp {
+	call := r.clock.Add(1)
+	ok := push(v)
+	return histOp{kind: opPush, value: v, ok: ok, call: call, ret: r.clock.Add(1)}
+}
+
+func (r *recorder) pop(pop func() (int, bool)) histOp {
+	call := r.clock.Add(1)
+	v, ok := pop()
+	return histOp{kind: opPop, value: v, ok: ok, call: call, ret: r.clock.Add(1)}
+}
+
+func (r *recorder) add(local []histOp) {
+	r.mu.Lock()
+	r.ops = append(r.ops, local...)
+	r.mu.Unlock()
+}
+
+// historyQueue supplies each producer goroutine with its own push
+// function (queues with per-producer handles need one each) and the
+// shared pop function.
+type historyQueue struct {
+	newPusher func() func(int) bool
+	pop       func() (int, bool)
+}
+
+func asHistoryQueue(q queue.Queue[int]) historyQueue {
+	return historyQueue{
+		newPusher: func() func(int) bool { return q.Push },
+		pop:       q.Pop,
+	}
+}
+
+// recordHistory runs producers and consumers concurrently against q.
+// Values are unique across producers so every Pop identifies its Push.
+func recordHistory(q historyQueue, producers, consumers, perProducer, popsPerConsumer int) []histOp {
+	var r recorder
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		push := q.newPusher()
+		go func(id int) {
+			defer wg.Done()
+			<-start
+			local := make([]histOp, 0, perProducer)
+			for i := 0; i < perProducer; i++ {
+				local = append(local, r.push(push, id*perProducer+i+1))
+			}
+			r.add(local)
+		}(p)
+	}
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			local := make([]histOp, 0, popsPerConsumer)
+			for i := 0; i < popsPerConsumer; i++ {
+				local = append(local, r.pop(q.pop))
+			}
+			r.add(local)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	for i := range r.ops {
+		r.ops[i].id = i
+	}
+	return r.ops
+}
+
+func testLinearizable(t *testing.T, m fifoModel, create func() queue.Queue[int], producers, consumers int) {
+	t.Helper()
+	testLinearizableHistory(t, m, func() historyQueue { return asHistoryQueue(create()) }, producers, consumers)
+}
+
+func testLinearizableHistory(t *testing.T, m fifoModel, create func() historyQueue, producers, consumers int) {
+	t.Helper()
+	const histories = 50
+	const perProducer = 20
+	popsPerConsumer := producers * perProducer / consumers
+
+	for h := 0; h < histories; h++ {
+		ops := recordHistory(create(), producers, consumers, perProducer, popsPerConsumer)
+		if !checkLinearizable(m, ops) {
+			t.Fatalf("history %d is not linearizable (%d ops)", h, len(ops))
+		}
+	}
+}
+
+func TestLinearizable_LinkedQueue_MPSC(t *testing.T) {
+	for _, alloc := range nodeAllocs {
+		t.Run(alloc.String(), func(t *testing.T) {
+			testLinearizable(t, fifoModel{weakEmpty: true}, func() queue.Queue[int] {
+				return queue.NewLinkedQueue[int](alloc)
+			}, 4, 1)
+		})
+	}
+}
+
+func TestLinearizable_MPSCRing(t *testing.T) {
+	// Like LinkedQueue, Pop can see empty while an earlier claim is unwritten
+	testLinearizable(t, fifoModel{weakEmpty: true}, func() queue.Queue[int] {
+		return queue.Must(queue.NewMPSCRing[int](1024))
+	}, 4, 1)
+}
+
+func TestLinearizable_SPMCRing(t *testing.T) {
+	testLinearizable(t, fifoModel{}, func() queue.Queue[int] {
+		return queue.Must(queue.NewSPMCRing[int](1024))
+	}, 1, 2)
+}
+
+func TestLinearizable_CombiningQueue(t *testing.T) {
+	testLinearizableHistory(t, fifoModel{}, func() historyQueue {
+		q := queue.Must(queue.NewCombiningQueue[int](1024, 4))
+		return historyQueue{
+			newPusher: func() func(int) bool {
+				return queue.Must(q.NewProducer()).Push
+			},
+			pop: q.Pop,
+		}
+	}, 4, 1)
+}
+
+func TestLinearizable_ChannelQueue_MPMC(t *testing.T) {
+	testLinearizable(t, fifoModel{}, func() queue.Queue[int] {
+		return queue.Must(queue.NewChannel[int](1024))
+	}, 3, 3)
+}
+
+func TestLinearizable_BoundedChan_MPMC(t *testing.T) {
+	testLinearizable(t, fifoModel{}, func() queue.Queue[int] {
+		return queue.Must(queue.NewBoundedChan[int](1024))
+	}, 3, 3)
+}
+
+func TestLinearizable_RingBuffer_SPSC(t *testing.T) {
+	testLinearizable(t, fifoModel{}, func() queue.Queue[int] {
+		return queue.Must(queue.NewRingBuffer[int](1024))
+	}, 1, 1)
+}
+
+// TestCheckLinearizable_RejectsViolations makes sure the checker itself
+// catches non-FIFO histories rather than accepting everything.
+func TestCheckLinearizable_RejectsViolations(t *testing.T) {
+	testCases := []struct {
+		name string
+		weak bool
+		ops  []histOp
+		want bool
+	}{
+		{
+			name: "sequential FIFO",
+			ops: []histOp{
+				{kind: opPush, value: 1, ok: true, call: 1, ret: 2},
+				{kind: opPush, value: 2, ok: true, call: 3, ret: 4},
+				{kind: opPop, value: 1, ok: true, call: 5, ret: 6},
+				{kind: opPop, value: 2, ok: true, call: 7, ret: 8},
+			},
+			want: true,
+		},
+		{
+			name: "sequential LIFO",
+			ops: []histOp{
+				{kind: opPush, value: 1, ok: true, call: 1, ret: 2},
+				{kind: opPush, value: 2, ok: true, call: 3, ret: 4},
+				{kind: opPop, value: 2, ok: true, call: 5, ret: 6},
+			},
+			want: false,
+		},
+		{
+			name: "concurrent pushes may reorder",
+			ops: []histOp{
+				{kind: opPush, value: 1, ok: true, call: 1, ret: 4},
+				{kind: opPush, value: 2, ok: true, call: 2, ret: 3},
+				{kind: opPop, value: 2, ok: true, call: 5, ret: 6},
+			},
+			want: true,
+		},
+		{
+			name: "empty pop after completed push",
+			ops: []histOp{
+				{kind: opPush, value: 1, ok: true, call: 1, ret: 2},
+				{kind: opPop, ok: false, call: 3, ret: 4},
+			},
+			want: false,
+		},
+		{
+			name: "weak empty pop during a push",
+			weak: true,
+			ops: []histOp{
+				{kind: opPush, value: 1, ok: true, call: 1, ret: 6},
+				{kind: opPush, value: 2, ok: true, call: 2, ret: 3},
+				{kind: opPop, ok: false, call: 4, ret: 5},
+				{kind: opPop, value: 1, ok: true, call: 7, ret: 8},
+			},
+			want: true,
+		},
+		{
+			name: "weak empty pop with no push in progress",
+			weak: true,
+			ops: []histOp{
+				{kind: opPush, value: 1, ok: true, call: 1, ret: 2},
+				{kind: opPop, ok: false, call: 3, ret: 4},
+			},
+			want: false,
+		},
+		{
+			name: "duplicate delivery",
+			ops: []histOp{
+				{kind: opPush, value: 1, ok: true, call: 1, ret: 2},
+				{kind: opPop, value: 1, ok: true, call: 3, ret: 4},
+				{kind: opPop, value: 1, ok: true, call: 5, ret: 6},
+			},
+			want: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := range tc.ops {
+				tc.ops[i].id = i
+			}
+			if got := checkLinearizable(fifoModel{weakEmpty: tc.weak}, tc.ops); got != tc.want {
+				t.Errorf("checkLinearizable() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// Single goroutine: isolates the allocation cost of each strategy.
+
+func BenchmarkQueue_Linked_PushPop(b *testing.B) {
+	for _, alloc := range nodeAllocs {
+		b.Run(alloc.String(), func(b *testing.B) {
+			q := queue.NewLinkedQueue[int](alloc)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var val int
+			var ok bool
+			for i := 0; i < b.N; i++ {
+				q.Push(i)
+				val, ok = q.Pop()
+			}
+			sinkInt = val
+			sinkBool = ok
+		})
+	}
+}
+
+// N producers -> 1 consumer: shows the contention side of the trade-off.
+// AllocFresh pays the allocator/GC, AllocPool pays per-P pool traffic,
+// AllocFreelist pays a lock shared by every producer and the consumer.
+
+func benchmarkLinkedMPSC(b *testing.B, alloc queue.NodeAlloc, producers int) {
+	q := queue.NewLinkedQueue[int](alloc)
+	done := make(chan struct{})
+	consumerDone := make(chan struct{})
+
+	// Consumer goroutine (single consumer - MPSC contract)
+	go func() {
+		defer close(consumerDone)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				q.Pop()
+			}
+		}
+	}()
+
+	b.SetParallelism(producers)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			q.Push(i)
+			i++
+		}
+	})
+
+	b.StopTimer()
+	close(done)
+	<-consumerDone
+}
+
+func BenchmarkQueue_Linked_MPSC_4P(b *testing.B) {
+	for _, alloc := range nodeAllocs {
+		b.Run(alloc.String(), func(b *testing.B) {
+			benchmarkLinkedMPSC(b, alloc, 4)
+		})
+	}
+}
+
+func BenchmarkQueue_Linked_MPSC_8P(b *testing.B) {
+	for _, alloc := range nodeAllocs {
+		b.Run(alloc.String(), func(b *testing.B) {
+			benchmarkLinkedMPSC(b, alloc, 8)
+		})
+	}
+}
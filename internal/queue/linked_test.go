@@ -0,0 +1,94 @@
+package queue_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+var nodeAllocs = []queue.NodeAlloc{
+	queue.AllocFresh,
+	queue.AllocPool,
+	queue.AllocFreelist,
+}
+
+func TestLinkedQueue(t *testing.T) {
+	for _, alloc := range nodeAllocs {
+		t.Run(alloc.String(), func(t *testing.T) {
+			q := queue.NewLinkedQueue[int](alloc)
+			testQueue(t, q, 42, "LinkedQueue/"+alloc.String())
+		})
+	}
+}
+
+func TestLinkedQueue_FIFO(t *testing.T) {
+	for _, alloc := range nodeAllocs {
+		t.Run(alloc.String(), func(t *testing.T) {
+			q := queue.NewLinkedQueue[int](alloc)
+
+			// Interleave so recycled nodes are reused mid-stream
+			for round := 0; round < 3; round++ {
+				for i := 0; i < 100; i++ {
+					if !q.Push(i) {
+						t.Fatalf("expected Push(%d) = true", i)
+					}
+				}
+				for i := 0; i < 100; i++ {
+					got, ok := q.Pop()
+					if !ok {
+						t.Fatalf("expected Pop() = true for item %d", i)
+					}
+					if got != i {
+						t.Errorf("FIFO violation: expected %d, got %d", i, got)
+					}
+				}
+				if _, ok := q.Pop(); ok {
+					t.Error("expected Pop() = false after draining")
+				}
+			}
+		})
+	}
+}
+
+// TestLinkedQueue_MPSC verifies that concurrent producers never lose items
+// and that each producer's items arrive in the order it pushed them.
+func TestLinkedQueue_MPSC(t *testing.T) {
+	const producers = 8
+	const perProducer = 5000
+
+	for _, alloc := range nodeAllocs {
+		t.Run(alloc.String(), func(t *testing.T) {
+			q := queue.NewLinkedQueue[[2]int](alloc)
+
+			var wg sync.WaitGroup
+			for p := 0; p < producers; p++ {
+				wg.Add(1)
+				go func(id int) {
+					defer wg.Done()
+					for i := 0; i < perProducer; i++ {
+						q.Push([2]int{id, i})
+					}
+				}(p)
+			}
+
+			next := make([]int, producers)
+			for received := 0; received < producers*perProducer; {
+				v, ok := q.Pop()
+				if !ok {
+					continue
+				}
+				if v[1] != next[v[0]] {
+					t.Fatalf("producer %d: expected %d, got %d", v[0], next[v[0]], v[1])
+				}
+				next[v[0]]++
+				received++
+			}
+			wg.Wait()
+
+			if _, ok := q.Pop(); ok {
+				t.Error("expected Pop() = false after all items received")
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package queue
+
+import (
+	"sync/atomic"
+)
+
+type mpscSlot[T any] struct {
+	seq atomic.Uint64
+	val T
+}
+
+// MPSCRing is a bounded lock-free MPSC (Multi-Producer Single-Consumer)
+// queue based on Dmitry Vyukov's bounded MPMC design, with the consumer
+// side simplified for a single reader.
+//
+// Every slot carries a sequence number. A producer claims a position by
+// CAS on head once the slot's sequence says it is free, writes the value,
+// then publishes it by advancing the slot's sequence. Producers only
+// contend on the head CAS; the consumer never executes an atomic
+// read-modify-write.
+//
+// Pop may briefly report empty while the producer that claimed the next
+// position has not finished writing it, even if later positions are
+// already filled; callers poll, as with the other queues in this package.
+//
+// CONTRACT: Any number of goroutines may call Push(). Only ONE goroutine
+// may call Pop().
+type MPSCRing[T any] struct {
+	slots []mpscSlot[T]
+	mask  uint64
+
+	_pad0 [56]byte //nolint:unused
+
+	head atomic.Uint64 // Next position to claim; CAS by producers
+
+	_pad1 [56]byte //nolint:unused
+
+	tail atomic.Uint64 // Next position to read; written only by the consumer
+}
+
+// NewMPSCRing creates an MPSCRing with the specified size.
+// Size will be rounded up to the next power of 2.
+//
+// Returns ErrInvalidSize if size is not between 1 and MaxInt/2.
+func NewMPSCRing[T any](size int) (*MPSCRing[T], error) {
+	if err := checkSize(size); err != nil {
+		return nil, err
+	}
+	n := roundPow2(size)
+
+	q := &MPSCRing[T]{
+		slots: make([]mpscSlot[T], n),
+		mask:  n - 1,
+	}
+	for i := range q.slots {
+		q.slots[i].seq.Store(uint64(i))
+	}
+	return q, nil
+}
+
+// Push adds an item to the queue.
+// Returns false if the queue is full.
+//
+// Safe for concurrent use by multiple producers.
+func (q *MPSCRing[T]) Push(v T) bool {
+	for {
+		pos := q.head.Load()
+		s := &q.slots[pos&q.mask]
+		seq := s.seq.Load()
+
+		switch dif := int64(seq - pos); {
+		case dif == 0:
+			// Slot is free for this position; try to claim it
+			if q.head.CompareAndSwap(pos, pos+1) {
+				s.val = v
+				s.seq.Store(pos + 1)
+				return true
+			}
+		case dif < 0:
+			// Slot still holds the item from one lap ago: full
+			return false
+		}
+		// Another producer claimed pos first; retry with the new head
+	}
+}
+
+// Pop removes and returns an item from the queue.
+// Returns false if the queue is empty.
+//
+// CONTRACT: Only ONE goroutine may call Pop().
+func (q *MPSCRing[T]) Pop() (T, bool) {
+	pos := q.tail.Load()
+	s := &q.slots[pos&q.mask]
+	if s.seq.Load() != pos+1 {
+		var zero T
+		return zero, false
+	}
+
+	v := s.val
+	var zero T
+	s.val = zero
+
+	// Free the slot for the producer one lap ahead
+	s.seq.Store(pos + q.mask + 1)
+	q.tail.Store(pos + 1)
+	return v, true
+}
+
+// Len returns the number of claimed positions not yet consumed, which
+// includes items still being written.
+// This is an approximation and may be slightly stale.
+func (q *MPSCRing[T]) Len() int {
+	tail := q.tail.Load()
+	head := q.head.Load()
+	return int(head - tail)
+}
+
+// Cap returns the capacity of the queue.
+func (q *MPSCRing[T]) Cap() int {
+	return len(q.slots)
+}
@@ -0,0 +1,83 @@
+package queue_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+func TestMPSCRing(t *testing.T) {
+	q := queue.Must(queue.NewMPSCRing[int](8))
+	testQueue(t, q, 42, "MPSCRing")
+}
+
+func TestMPSCRing_FullAndWrap(t *testing.T) {
+	q := queue.Must(queue.NewMPSCRing[int](4))
+
+	next, expected := 0, 0
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 4; i++ {
+			if !q.Push(next) {
+				t.Fatalf("round %d: expected Push(%d) = true", round, next)
+			}
+			next++
+		}
+		if q.Push(-1) {
+			t.Fatalf("round %d: expected Push() = false on full queue", round)
+		}
+		if q.Len() != 4 {
+			t.Fatalf("round %d: expected Len() = 4, got %d", round, q.Len())
+		}
+		for i := 0; i < 4; i++ {
+			got, ok := q.Pop()
+			if !ok || got != expected {
+				t.Fatalf("round %d: Pop() = (%d, %v), want (%d, true)", round, got, ok, expected)
+			}
+			expected++
+		}
+		if _, ok := q.Pop(); ok {
+			t.Fatalf("round %d: expected Pop() = false after draining", round)
+		}
+	}
+}
+
+// TestMPSCRing_MPSC verifies that concurrent producers never lose items
+// and that each producer's items arrive in the order it pushed them.
+func TestMPSCRing_MPSC(t *testing.T) {
+	const producers = 4
+	const perProducer = 1000
+
+	q := queue.Must(queue.NewMPSCRing[[2]int](1024))
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				for !q.Push([2]int{id, i}) {
+					// Spin until push succeeds
+				}
+			}
+		}(p)
+	}
+
+	next := make([]int, producers)
+	for received := 0; received < producers*perProducer; {
+		v, ok := q.Pop()
+		if !ok {
+			continue
+		}
+		if v[1] != next[v[0]] {
+			t.Fatalf("producer %d: expected %d, got %d", v[0], next[v[0]], v[1])
+		}
+		next[v[0]]++
+		received++
+	}
+	wg.Wait()
+
+	if _, ok := q.Pop(); ok {
+		t.Error("expected Pop() = false after all items received")
+	}
+}
@@ -0,0 +1,98 @@
+package queue
+
+import "errors"
+
+// Errors returned by NewMux and NewWeightedMux.
+var (
+	ErrNoQueues       = errors.New("queue: Mux requires at least one queue")
+	ErrWeightMismatch = errors.New("queue: Mux weights must match number of queues")
+)
+
+// Mux polls several RingBuffers and returns the next available item.
+//
+// This replaces a multi-case select (or reflect.Select for a dynamic
+// number of channels) on the lock-free path. Queues are serviced in
+// weighted round-robin order: queue i yields up to weights[i] consecutive
+// items before the Mux moves on, so a busy queue cannot starve the others.
+//
+// The Mux is the single consumer of every queue it wraps (SPSC contract).
+// It is not safe for concurrent use.
+type Mux[T any] struct {
+	queues  []*RingBuffer[T]
+	weights []int
+	cur     int
+	budget  int
+}
+
+// NewMux creates a round-robin Mux over the given queues.
+func NewMux[T any](queues ...*RingBuffer[T]) (*Mux[T], error) {
+	weights := make([]int, len(queues))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return NewWeightedMux(queues, weights)
+}
+
+// NewWeightedMux creates a Mux where queue i may yield up to weights[i]
+// items in a row. Weights below 1 are treated as 1.
+func NewWeightedMux[T any](queues []*RingBuffer[T], weights []int) (*Mux[T], error) {
+	if len(queues) == 0 {
+		return nil, ErrNoQueues
+	}
+	if len(weights) != len(queues) {
+		return nil, ErrWeightMismatch
+	}
+
+	w := make([]int, len(weights))
+	for i, v := range weights {
+		w[i] = max(v, 1)
+	}
+
+	return &Mux[T]{
+		queues:  queues,
+		weights: w,
+		budget:  w[0],
+	}, nil
+}
+
+// Pop returns the next available item from any queue.
+// Returns false if every queue is empty.
+//
+// Each call scans at most one full round of queues.
+func (m *Mux[T]) Pop() (T, bool) {
+	for i := 0; i < len(m.queues); i++ {
+		if v, ok := m.queues[m.cur].Pop(); ok {
+			m.budget--
+			if m.budget == 0 {
+				m.advance()
+			}
+			return v, true
+		}
+		m.advance()
+	}
+	var zero T
+	return zero, false
+}
+
+func (m *Mux[T]) advance() {
+	m.cur++
+	if m.cur == len(m.queues) {
+		m.cur = 0
+	}
+	m.budget = m.weights[m.cur]
+}
+
+// Len returns the total number of items across all queues.
+// This is an approximation and may be slightly stale.
+func (m *Mux[T]) Len() int {
+	n := 0
+	for _, q := range m.queues {
+		n += q.Len()
+	}
+	return n
+}
+
+// Queues returns the number of queues the Mux polls.
+func (m *Mux[T]) Queues() int {
+	return len(m.queues)
+}
@@ -0,0 +1,136 @@
+package queue_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// Mux vs select over N sources.
+//
+// Two traffic shapes, both single goroutine so only the dispatch cost is
+// measured:
+//   - Full:   every source has data; each item is pushed back to its source
+//   - Sparse: only the last source has data; the poller must scan past N-1
+//     empty sources every time
+//
+// Channels use reflect.Select with a default case, which is what a
+// dynamic number of channels requires. N=2 also has a literal select.
+
+var muxSizes = []int{2, 8, 32}
+
+func newMuxRings(n int) []*queue.RingBuffer[int] {
+	qs := make([]*queue.RingBuffer[int], n)
+	for i := range qs {
+		qs[i] = queue.Must(queue.NewRingBuffer[int](64))
+	}
+	return qs
+}
+
+func newSelectCases(n int) ([]chan int, []reflect.SelectCase) {
+	chs := make([]chan int, n)
+	cases := make([]reflect.SelectCase, n+1)
+	for i := range chs {
+		chs[i] = make(chan int, 64)
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(chs[i])}
+	}
+	cases[n] = reflect.SelectCase{Dir: reflect.SelectDefault}
+	return chs, cases
+}
+
+func BenchmarkQueue_Mux_Full(b *testing.B) {
+	for _, n := range muxSizes {
+		b.Run(fmt.Sprintf("N=%d/Mux", n), func(b *testing.B) {
+			qs := newMuxRings(n)
+			for i, q := range qs {
+				q.Push(i)
+			}
+			m := queue.Must(queue.NewMux(qs...))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var val int
+			for i := 0; i < b.N; i++ {
+				val, _ = m.Pop()
+				qs[val].Push(val)
+			}
+			sinkInt = val
+		})
+
+		b.Run(fmt.Sprintf("N=%d/ReflectSelect", n), func(b *testing.B) {
+			chs, cases := newSelectCases(n)
+			for i, ch := range chs {
+				ch <- i
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var val int
+			for i := 0; i < b.N; i++ {
+				_, v, _ := reflect.Select(cases)
+				val = int(v.Int())
+				chs[val] <- val
+			}
+			sinkInt = val
+		})
+	}
+}
+
+func BenchmarkQueue_Mux_Sparse(b *testing.B) {
+	for _, n := range muxSizes {
+		b.Run(fmt.Sprintf("N=%d/Mux", n), func(b *testing.B) {
+			qs := newMuxRings(n)
+			hot := qs[n-1]
+			m := queue.Must(queue.NewMux(qs...))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var val int
+			for i := 0; i < b.N; i++ {
+				hot.Push(i)
+				val, _ = m.Pop()
+			}
+			sinkInt = val
+		})
+
+		b.Run(fmt.Sprintf("N=%d/ReflectSelect", n), func(b *testing.B) {
+			chs, cases := newSelectCases(n)
+			hot := chs[n-1]
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var val int
+			for i := 0; i < b.N; i++ {
+				hot <- i
+				_, v, _ := reflect.Select(cases)
+				val = int(v.Int())
+			}
+			sinkInt = val
+		})
+	}
+}
+
+// BenchmarkQueue_Mux_Select2 is the hand-written two-case select that
+// reflect.Select replaces when N is known at compile time.
+func BenchmarkQueue_Mux_Select2(b *testing.B) {
+	a := make(chan int, 64)
+	c := make(chan int, 64)
+	a <- 0
+	c <- 1
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	for i := 0; i < b.N; i++ {
+		select {
+		case val = <-a:
+			a <- val
+		case val = <-c:
+			c <- val
+		default:
+		}
+	}
+	sinkInt = val
+}
@@ -0,0 +1,89 @@
+package queue_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+func TestMux_Empty(t *testing.T) {
+	m := queue.Must(queue.NewMux(queue.Must(queue.NewRingBuffer[int](4)), queue.Must(queue.NewRingBuffer[int](4))))
+	if _, ok := m.Pop(); ok {
+		t.Error("expected Pop() = false when all queues are empty")
+	}
+}
+
+func TestMux_RoundRobin(t *testing.T) {
+	qs := []*queue.RingBuffer[int]{
+		queue.Must(queue.NewRingBuffer[int](8)),
+		queue.Must(queue.NewRingBuffer[int](8)),
+		queue.Must(queue.NewRingBuffer[int](8)),
+	}
+	for i, q := range qs {
+		for j := 0; j < 3; j++ {
+			q.Push(i)
+		}
+	}
+
+	m := queue.Must(queue.NewMux(qs...))
+	want := []int{0, 1, 2, 0, 1, 2, 0, 1, 2}
+	for i, w := range want {
+		got, ok := m.Pop()
+		if !ok {
+			t.Fatalf("expected Pop() = true for item %d", i)
+		}
+		if got != w {
+			t.Errorf("item %d: expected queue %d, got %d", i, w, got)
+		}
+	}
+	if m.Len() != 0 {
+		t.Errorf("expected Len() = 0, got %d", m.Len())
+	}
+}
+
+func TestMux_SkipsEmpty(t *testing.T) {
+	qs := []*queue.RingBuffer[int]{
+		queue.Must(queue.NewRingBuffer[int](8)),
+		queue.Must(queue.NewRingBuffer[int](8)),
+		queue.Must(queue.NewRingBuffer[int](8)),
+	}
+	qs[2].Push(7)
+
+	m := queue.Must(queue.NewMux(qs...))
+	got, ok := m.Pop()
+	if !ok || got != 7 {
+		t.Errorf("expected (7, true), got (%d, %v)", got, ok)
+	}
+}
+
+func TestMux_Weighted(t *testing.T) {
+	qs := []*queue.RingBuffer[int]{
+		queue.Must(queue.NewRingBuffer[int](16)),
+		queue.Must(queue.NewRingBuffer[int](16)),
+	}
+	for j := 0; j < 6; j++ {
+		qs[0].Push(0)
+		qs[1].Push(1)
+	}
+
+	m := queue.Must(queue.NewWeightedMux(qs, []int{3, 1}))
+	want := []int{0, 0, 0, 1, 0, 0, 0, 1}
+	for i, w := range want {
+		got, _ := m.Pop()
+		if got != w {
+			t.Errorf("item %d: expected queue %d, got %d", i, w, got)
+		}
+	}
+}
+
+func TestMux_InvalidArgs(t *testing.T) {
+	if _, err := queue.NewMux[int](); !errors.Is(err, queue.ErrNoQueues) {
+		t.Errorf("expected ErrNoQueues, got %v", err)
+	}
+
+	qs := []*queue.RingBuffer[int]{queue.Must(queue.NewRingBuffer[int](4))}
+	if _, err := queue.NewWeightedMux(qs, []int{1, 2}); !errors.Is(err, queue.ErrWeightMismatch) {
+		t.Errorf("expected ErrWeightMismatch, got %v", err)
+	}
+}
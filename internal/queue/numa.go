@@ -0,0 +1,20 @@
+package queue
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// ErrNUMAUnsupported is returned by NewRingBuffer when WithNUMANode is
+// used on a platform without NUMA memory binding.
+var ErrNUMAUnsupported = errors.New("queue: NUMA binding not supported on this platform")
+
+// bindSlice binds the pages wholly inside buf to NUMA node node.
+func bindSlice[T any](buf []T, node int) error {
+	if len(buf) == 0 {
+		return nil
+	}
+	addr := uintptr(unsafe.Pointer(unsafe.SliceData(buf)))
+	size := uintptr(len(buf)) * unsafe.Sizeof(buf[0])
+	return bindNode(addr, size, node)
+}
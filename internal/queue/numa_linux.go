@@ -0,0 +1,64 @@
+//go:build linux
+
+package queue
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// mbind(2) mode and flags, from <linux/mempolicy.h>.
+const (
+	mpolBind     = 2
+	mpolMFMove   = 1 << 1
+	mpolMFStrict = 1 << 0
+)
+
+// nodeDir is where sysfs lists the NUMA nodes, one nodeN directory each.
+const nodeDir = "/sys/devices/system/node"
+
+// bindNode binds the whole pages in [addr, addr+size) to node, moving any
+// pages that are already populated. The Go heap does not move objects,
+// so the binding holds for the life of the allocation.
+//
+// The node is checked against sysfs first, so a nonexistent one is an
+// error even when there is no whole page to bind.
+func bindNode(addr, size uintptr, node int) error {
+	if err := checkNode(node); err != nil {
+		return err
+	}
+	page := uintptr(os.Getpagesize())
+	start := (addr + page - 1) &^ (page - 1)
+	end := (addr + size) &^ (page - 1)
+	if end <= start {
+		return nil
+	}
+
+	mask := make([]uint64, node/64+1)
+	mask[node/64] = 1 << (node % 64)
+
+	_, _, errno := unix.Syscall6(unix.SYS_MBIND,
+		start, end-start, mpolBind,
+		uintptr(unsafe.Pointer(&mask[0])), uintptr(len(mask)*64+1),
+		mpolMFMove|mpolMFStrict)
+	if errno != 0 {
+		return fmt.Errorf("queue: bind ring to NUMA node %d: %w", node, errno)
+	}
+	return nil
+}
+
+// checkNode returns an error unless sysfs lists node: EINVAL, as mbind
+// returns, for a node that doesn't exist, and ENOSYS when sysfs lists no
+// nodes at all, a kernel without NUMA support.
+func checkNode(node int) error {
+	if _, err := os.Stat(fmt.Sprintf("%s/node%d", nodeDir, node)); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(nodeDir); err != nil {
+		return fmt.Errorf("queue: bind ring to NUMA node %d: %w", node, unix.ENOSYS)
+	}
+	return fmt.Errorf("queue: bind ring to NUMA node %d: %w", node, unix.EINVAL)
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package queue
+
+func bindNode(addr, size uintptr, node int) error {
+	return ErrNUMAUnsupported
+}
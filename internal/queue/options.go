@@ -0,0 +1,44 @@
+package queue
+
+// Option configures optional RingBuffer behaviour.
+type Option func(*options)
+
+type options struct {
+	clearOnPop bool
+	numaNode   int // -1 = wherever the allocator puts it
+}
+
+func applyOptions(opts []Option) options {
+	o := options{numaNode: -1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithClearOnPop zeroes each slot after its item is read.
+//
+// By default a popped slot keeps its value until the producer overwrites
+// it, so when T contains pointers the ring keeps up to Cap() objects
+// alive after they have been consumed. Clearing releases them to the GC
+// at the cost of one extra store per Pop.
+func WithClearOnPop() Option {
+	return func(o *options) {
+		o.clearOnPop = true
+	}
+}
+
+// WithNUMANode binds the ring's backing array to NUMA node node, so a
+// producer and consumer pinned to that node's CPUs access local memory.
+//
+// Binding is done with mbind(2) on the pages that lie wholly inside the
+// array; a ring smaller than one page stays where the allocator put it.
+// Whatever the ring's size, NewRingBuffer returns an error wrapping
+// unix.EINVAL if the node does not exist (unix.ENOSYS if the kernel lists
+// no NUMA nodes at all), and ErrNUMAUnsupported on platforms other than
+// Linux.
+func WithNUMANode(node int) Option {
+	return func(o *options) {
+		o.numaNode = node
+	}
+}
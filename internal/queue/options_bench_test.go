@@ -0,0 +1,84 @@
+package queue_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// ClearOnPop cost and effect.
+//
+// PushPop measures the extra store per Pop. Retention fills a ring with
+// 1 KiB objects, pops them all, forces a GC, and reports how many bytes
+// above the pre-run baseline are still live: without clearing, the ring
+// pins Cap() objects.
+
+var sinkPayload *payload
+
+func BenchmarkQueue_RingBuffer_PushPop_Ptr(b *testing.B) {
+	p := new(payload)
+	for _, tc := range []struct {
+		name string
+		opts []queue.Option
+	}{
+		{"Default", nil},
+		{"ClearOnPop", []queue.Option{queue.WithClearOnPop()}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			q := queue.Must(queue.NewRingBuffer[*payload](1024, tc.opts...))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var val *payload
+			for i := 0; i < b.N; i++ {
+				q.Push(p)
+				val, _ = q.Pop()
+			}
+			sinkPayload = val
+		})
+	}
+}
+
+func BenchmarkQueue_RingBuffer_Retention(b *testing.B) {
+	const size = 4096
+	for _, tc := range []struct {
+		name string
+		opts []queue.Option
+	}{
+		{"Default", nil},
+		{"ClearOnPop", []queue.Option{queue.WithClearOnPop()}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			q := queue.Must(queue.NewRingBuffer[*payload](size, tc.opts...))
+			var base, after runtime.MemStats
+			var retained uint64
+
+			runtime.GC()
+			runtime.ReadMemStats(&base)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < size; j++ {
+					q.Push(new(payload))
+				}
+				for j := 0; j < size; j++ {
+					q.Pop()
+				}
+
+				b.StopTimer()
+				runtime.GC()
+				runtime.ReadMemStats(&after)
+				if after.HeapAlloc > base.HeapAlloc {
+					retained += after.HeapAlloc - base.HeapAlloc
+				}
+				b.StartTimer()
+			}
+
+			b.ReportMetric(float64(retained)/float64(b.N), "retained-B")
+			runtime.KeepAlive(q)
+		})
+	}
+}
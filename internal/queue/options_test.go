@@ -0,0 +1,95 @@
+package queue_test
+
+import (
+	"errors"
+	"runtime"
+	"syscall"
+	"testing"
+	"weak"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+type payload struct {
+	data [1024]byte
+}
+
+// pushPopWeak pushes a fresh object through q and returns a weak
+// pointer to it, so the caller holds no strong reference.
+func pushPopWeak(q *queue.RingBuffer[*payload]) weak.Pointer[payload] {
+	p := new(payload)
+	w := weak.Make(p)
+	q.Push(p)
+	q.Pop()
+	return w
+}
+
+func TestRingBuffer_ClearOnPop_ReleasesReference(t *testing.T) {
+	q := queue.Must(queue.NewRingBuffer[*payload](8, queue.WithClearOnPop()))
+	w := pushPopWeak(q)
+
+	runtime.GC()
+	runtime.GC()
+
+	if w.Value() != nil {
+		t.Error("expected popped object to be collected with ClearOnPop")
+	}
+}
+
+func TestRingBuffer_Default_RetainsReference(t *testing.T) {
+	q := queue.Must(queue.NewRingBuffer[*payload](8))
+	w := pushPopWeak(q)
+
+	runtime.GC()
+	runtime.GC()
+
+	if w.Value() == nil {
+		t.Error("expected popped object to stay reachable through its slot")
+	}
+	runtime.KeepAlive(q)
+}
+
+func TestRingBuffer_ClearOnPop_DrainRange(t *testing.T) {
+	q := queue.Must(queue.NewRingBuffer[*payload](8, queue.WithClearOnPop()))
+
+	weaks := make([]weak.Pointer[payload], 4)
+	for i := range weaks {
+		p := new(payload)
+		weaks[i] = weak.Make(p)
+		q.Push(p)
+	}
+	dst := make([]*payload, 2)
+	q.Drain(dst)
+	clear(dst)
+	q.Range(func(*payload) bool { return true })
+
+	runtime.GC()
+	runtime.GC()
+
+	for i, w := range weaks {
+		if w.Value() != nil {
+			t.Errorf("item %d: expected object to be collected after Drain/Range", i)
+		}
+	}
+	runtime.KeepAlive(q)
+}
+
+func TestRingBuffer_NUMANode(t *testing.T) {
+	q, err := queue.NewRingBuffer[int](1<<16, queue.WithNUMANode(0))
+	if errors.Is(err, queue.ErrNUMAUnsupported) || errors.Is(err, syscall.ENOSYS) || errors.Is(err, syscall.EPERM) {
+		// No NUMA support in the kernel, or mbind blocked by seccomp
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("NewRingBuffer(WithNUMANode(0)) error: %v", err)
+	}
+	testQueue(t, q, 42, "RingBuffer(NUMA node 0)")
+
+	if _, err := queue.NewRingBuffer[int](1<<16, queue.WithNUMANode(1023)); err == nil {
+		t.Error("expected error binding to a nonexistent NUMA node")
+	}
+	// Smaller than a page: nothing to mbind, but the node is still checked
+	if _, err := queue.NewRingBuffer[int](8, queue.WithNUMANode(1023)); err == nil {
+		t.Error("expected error binding a sub-page ring to a nonexistent NUMA node")
+	}
+}
@@ -0,0 +1,95 @@
+package queue_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// Pointer versus value payloads under GC.
+//
+// Each benchmark keeps a ring half full of 64-byte items and forces a GC
+// cycle every gcEvery operations. With *item the producer allocates every
+// item and the collector must trace every slot; with item the values are
+// copied into a pointer-free backing array the collector never scans.
+// Both rings clear slots on Pop, so the payload is the only difference:
+// the pointer ring needs the clear to let the collector free consumed
+// items, and the value ring pays the same store.
+//
+// ns/op includes the forced collections. gc-pause-ns/op is the
+// stop-the-world pause time (MemStats.PauseTotalNs) spread over b.N, and
+// gcs/op the number of cycles, including any the allocations triggered.
+
+// item is a 64-byte pointer-free payload.
+type item struct {
+	seq  int64
+	data [7]int64
+}
+
+const (
+	payloadRing = 1024
+	gcEvery     = 1 << 14
+)
+
+var sinkItem item
+
+// reportGC reports GC pause time and cycle count since before.
+func reportGC(b *testing.B, before *runtime.MemStats) {
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "gc-pause-ns/op")
+	b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gcs/op")
+}
+
+func BenchmarkQueue_Payload_Value(b *testing.B) {
+	q := queue.Must(queue.NewRingBuffer[item](payloadRing, queue.WithClearOnPop()))
+	for i := 0; i < payloadRing/2; i++ {
+		q.Push(item{seq: int64(i)})
+	}
+
+	var before runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val item
+	for i := 0; i < b.N; i++ {
+		q.Push(item{seq: int64(i)})
+		val, _ = q.Pop()
+		if i%gcEvery == 0 {
+			runtime.GC()
+		}
+	}
+
+	b.StopTimer()
+	reportGC(b, &before)
+	sinkItem = val
+}
+
+func BenchmarkQueue_Payload_Pointer(b *testing.B) {
+	q := queue.Must(queue.NewRingBuffer[*item](payloadRing, queue.WithClearOnPop()))
+	for i := 0; i < payloadRing/2; i++ {
+		q.Push(&item{seq: int64(i)})
+	}
+
+	var before runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val *item
+	for i := 0; i < b.N; i++ {
+		q.Push(&item{seq: int64(i)})
+		val, _ = q.Pop()
+		if i%gcEvery == 0 {
+			runtime.GC()
+		}
+	}
+
+	b.StopTimer()
+	reportGC(b, &before)
+	sinkItem = *val
+}
@@ -1,8 +1,30 @@
-// Package queue provides SPSC queue implementations for benchmarking.
+// Package queue provides queue implementations for benchmarking: SPSC
+// rings, MPSC and SPMC rings and lists, and MPMC channel equivalents.
 //
-// This package offers two implementations of the Queue interface:
-//   - ChannelQueue: Standard library approach using buffered channels
+// This package offers these implementations of the Queue interface:
+//   - ChannelQueue: Standard library approach using buffered channels;
+//     FromChan wraps a caller's existing channel
 //   - RingBuffer: Optimized lock-free ring buffer
+//   - LinkedQueue: Unbounded lock-free MPSC linked list with pluggable
+//     node recycling (fresh allocation, sync.Pool, or a private freelist)
+//   - UnsyncRing: Plain ring with no atomics, single goroutine only; the
+//     performance floor the other implementations are measured against
+//   - MPSCRing: Bounded lock-free MPSC ring (Vyukov per-slot sequences,
+//     producers CAS on head)
+//   - SPMCRing: Bounded lock-free SPMC ring, the mirror of MPSCRing
+//     (consumers CAS on tail)
+//   - CombiningQueue: Bounded MPSC queue using flat combining; producers
+//     publish into per-producer slots and one combiner applies them
+//
+// Mux polls several RingBuffers in weighted round-robin order, replacing
+// a multi-case select or reflect.Select on the lock-free path.
+//
+// Disruptor is an LMAX-style single-producer ring where several dependent
+// consumers (e.g. journal -> replicate -> apply) read slots in place,
+// coordinated by sequence barriers instead of chained queues.
+//
+// BoundedChan reproduces blocking buffered-channel semantics (Send, Recv,
+// Close) on top of RingBuffer, for like-for-like comparison with chan T.
 //
 // # RingBuffer Safety (IMPORTANT)
 //
@@ -18,6 +40,19 @@
 //   - These may be the same goroutine or different goroutines
 package queue
 
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidSize is returned by constructors given a size that is zero,
+// negative, or too large to round up to a power of two.
+var ErrInvalidSize = errors.New("queue: size must be between 1 and MaxInt/2")
+
+// maxSize is the largest size whose power-of-two round-up fits in an int.
+const maxSize = math.MaxInt / 2
+
 // Queue is a single-producer single-consumer queue.
 //
 // Implementations are non-blocking: Push returns false if full,
@@ -31,3 +66,52 @@ type Queue[T any] interface {
 	// Returns false if the queue is empty.
 	Pop() (T, bool)
 }
+
+// Sized is a Queue that reports its depth and capacity, for callers that
+// apply backpressure (e.g. stop producing above a high-water mark).
+//
+// ChannelQueue, RingBuffer and BoundedChan implement it. LinkedQueue is
+// unbounded and does not track its length. Close is deliberately not part
+// of the interface: only BoundedChan has close semantics; the polling
+// queues are shut down out of band (see the cancel package).
+type Sized[T any] interface {
+	Queue[T]
+
+	// Len returns the current number of items. Under concurrent use it
+	// is a snapshot and may be stale by the time it is returned.
+	Len() int
+
+	// Cap returns the maximum number of items the queue can hold.
+	Cap() int
+}
+
+// Must wraps a constructor call and panics if it returned an error.
+//
+// It is intended for tests, benchmarks and package-level variables with
+// constant sizes:
+//
+//	q := queue.Must(queue.NewRingBuffer[int](1024))
+func Must[Q any](q Q, err error) Q {
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// checkSize validates a requested queue size.
+func checkSize(size int) error {
+	if size < 1 || size > maxSize {
+		return fmt.Errorf("%w: got %d", ErrInvalidSize, size)
+	}
+	return nil
+}
+
+// roundPow2 returns the smallest power of two >= size.
+// size must already have passed checkSize.
+func roundPow2(size int) uint64 {
+	n := uint64(1)
+	for n < uint64(size) {
+		n <<= 1
+	}
+	return n
+}
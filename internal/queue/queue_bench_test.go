@@ -10,10 +10,11 @@ import (
 var sinkInt int
 var sinkBool bool
 
-// Direct type benchmarks (true performance floor)
+// Direct type benchmarks. UnsyncRing (no atomics, no guards) is the
+// floor: the gap to RingBuffer is the cost of cross-goroutine safety.
 
 func BenchmarkQueue_Channel_PushPop_Direct(b *testing.B) {
-	q := queue.NewChannel[int](1024)
+	q := queue.Must(queue.NewChannel[int](1024))
 	b.ReportAllocs()
 	b.ResetTimer()
 
@@ -28,7 +29,22 @@ func BenchmarkQueue_Channel_PushPop_Direct(b *testing.B) {
 }
 
 func BenchmarkQueue_RingBuffer_PushPop_Direct(b *testing.B) {
-	q := queue.NewRingBuffer[int](1024)
+	q := queue.Must(queue.NewRingBuffer[int](1024))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	var ok bool
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		val, ok = q.Pop()
+	}
+	sinkInt = val
+	sinkBool = ok
+}
+
+func BenchmarkQueue_UnsyncRing_PushPop_Direct(b *testing.B) {
+	q := queue.Must(queue.NewUnsyncRing[int](1024))
 	b.ReportAllocs()
 	b.ResetTimer()
 
@@ -45,7 +61,7 @@ func BenchmarkQueue_RingBuffer_PushPop_Direct(b *testing.B) {
 // Interface benchmarks (with dynamic dispatch overhead)
 
 func BenchmarkQueue_Channel_PushPop_Interface(b *testing.B) {
-	var q queue.Queue[int] = queue.NewChannel[int](1024)
+	var q queue.Queue[int] = queue.Must(queue.NewChannel[int](1024))
 	b.ReportAllocs()
 	b.ResetTimer()
 
@@ -60,7 +76,22 @@ func BenchmarkQueue_Channel_PushPop_Interface(b *testing.B) {
 }
 
 func BenchmarkQueue_RingBuffer_PushPop_Interface(b *testing.B) {
-	var q queue.Queue[int] = queue.NewRingBuffer[int](1024)
+	var q queue.Queue[int] = queue.Must(queue.NewRingBuffer[int](1024))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	var ok bool
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		val, ok = q.Pop()
+	}
+	sinkInt = val
+	sinkBool = ok
+}
+
+func BenchmarkQueue_UnsyncRing_PushPop_Interface(b *testing.B) {
+	var q queue.Queue[int] = queue.Must(queue.NewUnsyncRing[int](1024))
 	b.ReportAllocs()
 	b.ResetTimer()
 
@@ -77,7 +108,7 @@ func BenchmarkQueue_RingBuffer_PushPop_Interface(b *testing.B) {
 // Push-only benchmarks
 
 func BenchmarkQueue_Channel_Push(b *testing.B) {
-	q := queue.NewChannel[int](b.N + 1)
+	q := queue.Must(queue.NewChannel[int](b.N + 1))
 	b.ReportAllocs()
 	b.ResetTimer()
 
@@ -94,7 +125,23 @@ func BenchmarkQueue_RingBuffer_Push(b *testing.B) {
 	if size < 1024 {
 		size = 1024
 	}
-	q := queue.NewRingBuffer[int](size)
+	q := queue.Must(queue.NewRingBuffer[int](size))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var ok bool
+	for i := 0; i < b.N; i++ {
+		ok = q.Push(i)
+	}
+	sinkBool = ok
+}
+
+func BenchmarkQueue_UnsyncRing_Push(b *testing.B) {
+	size := b.N
+	if size < 1024 {
+		size = 1024
+	}
+	q := queue.Must(queue.NewUnsyncRing[int](size))
 	b.ReportAllocs()
 	b.ResetTimer()
 
@@ -108,7 +155,7 @@ func BenchmarkQueue_RingBuffer_Push(b *testing.B) {
 // Different queue sizes
 
 func BenchmarkQueue_Channel_PushPop_Size64(b *testing.B) {
-	q := queue.NewChannel[int](64)
+	q := queue.Must(queue.NewChannel[int](64))
 	b.ReportAllocs()
 	b.ResetTimer()
 
@@ -121,7 +168,20 @@ func BenchmarkQueue_Channel_PushPop_Size64(b *testing.B) {
 }
 
 func BenchmarkQueue_RingBuffer_PushPop_Size64(b *testing.B) {
-	q := queue.NewRingBuffer[int](64)
+	q := queue.Must(queue.NewRingBuffer[int](64))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var val int
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		val, _ = q.Pop()
+	}
+	sinkInt = val
+}
+
+func BenchmarkQueue_UnsyncRing_PushPop_Size64(b *testing.B) {
+	q := queue.Must(queue.NewUnsyncRing[int](64))
 	b.ReportAllocs()
 	b.ResetTimer()
 
@@ -12,7 +12,7 @@ import (
 //
 // This test intentionally violates the SPSC contract to verify the guard works.
 func TestRingBuffer_SPSC_ConcurrentPush_Panics(t *testing.T) {
-	q := queue.NewRingBuffer[int](1024)
+	q := queue.Must(queue.NewRingBuffer[int](1024))
 
 	// We need to catch the panic
 	panicked := make(chan bool, 1)
@@ -54,7 +54,7 @@ func TestRingBuffer_SPSC_ConcurrentPush_Panics(t *testing.T) {
 //
 // This test intentionally violates the SPSC contract to verify the guard works.
 func TestRingBuffer_SPSC_ConcurrentPop_Panics(t *testing.T) {
-	q := queue.NewRingBuffer[int](1024)
+	q := queue.Must(queue.NewRingBuffer[int](1024))
 
 	// Pre-fill the queue
 	for i := 0; i < 1024; i++ {
@@ -95,7 +95,7 @@ func TestRingBuffer_SPSC_ConcurrentPop_Panics(t *testing.T) {
 // TestRingBuffer_SPSC_Valid tests the valid SPSC pattern:
 // one producer goroutine, one consumer goroutine.
 func TestRingBuffer_SPSC_Valid(t *testing.T) {
-	q := queue.NewRingBuffer[int](64)
+	q := queue.Must(queue.NewRingBuffer[int](64))
 	count := 10000
 	done := make(chan struct{})
 
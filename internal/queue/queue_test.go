@@ -1,6 +1,8 @@
 package queue_test
 
 import (
+	"errors"
+	"math"
 	"testing"
 
 	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
@@ -35,17 +37,17 @@ func testQueue[T comparable](t *testing.T, q queue.Queue[T], val T, name string)
 }
 
 func TestChannelQueue(t *testing.T) {
-	q := queue.NewChannel[int](8)
+	q := queue.Must(queue.NewChannel[int](8))
 	testQueue(t, q, 42, "ChannelQueue")
 }
 
 func TestRingBuffer(t *testing.T) {
-	q := queue.NewRingBuffer[int](8)
+	q := queue.Must(queue.NewRingBuffer[int](8))
 	testQueue(t, q, 42, "RingBuffer")
 }
 
 func TestChannelQueue_Full(t *testing.T) {
-	q := queue.NewChannel[int](2)
+	q := queue.Must(queue.NewChannel[int](2))
 	if !q.Push(1) {
 		t.Error("expected Push(1) = true")
 	}
@@ -58,7 +60,7 @@ func TestChannelQueue_Full(t *testing.T) {
 }
 
 func TestRingBuffer_Full(t *testing.T) {
-	q := queue.NewRingBuffer[int](2)
+	q := queue.Must(queue.NewRingBuffer[int](2))
 	if !q.Push(1) {
 		t.Error("expected Push(1) = true")
 	}
@@ -71,7 +73,7 @@ func TestRingBuffer_Full(t *testing.T) {
 }
 
 func TestChannelQueue_FIFO(t *testing.T) {
-	q := queue.NewChannel[int](8)
+	q := queue.Must(queue.NewChannel[int](8))
 
 	for i := 0; i < 5; i++ {
 		if !q.Push(i) {
@@ -91,7 +93,7 @@ func TestChannelQueue_FIFO(t *testing.T) {
 }
 
 func TestRingBuffer_FIFO(t *testing.T) {
-	q := queue.NewRingBuffer[int](8)
+	q := queue.Must(queue.NewRingBuffer[int](8))
 
 	for i := 0; i < 5; i++ {
 		if !q.Push(i) {
@@ -111,7 +113,7 @@ func TestRingBuffer_FIFO(t *testing.T) {
 }
 
 func TestChannelQueue_LenCap(t *testing.T) {
-	q := queue.NewChannel[int](8)
+	q := queue.Must(queue.NewChannel[int](8))
 
 	if q.Len() != 0 {
 		t.Errorf("expected Len() = 0, got %d", q.Len())
@@ -129,7 +131,7 @@ func TestChannelQueue_LenCap(t *testing.T) {
 }
 
 func TestRingBuffer_LenCap(t *testing.T) {
-	q := queue.NewRingBuffer[int](8)
+	q := queue.Must(queue.NewRingBuffer[int](8))
 
 	if q.Len() != 0 {
 		t.Errorf("expected Len() = 0, got %d", q.Len())
@@ -148,13 +150,13 @@ func TestRingBuffer_LenCap(t *testing.T) {
 
 func TestRingBuffer_PowerOfTwo(t *testing.T) {
 	// Size 5 should round up to 8
-	q := queue.NewRingBuffer[int](5)
+	q := queue.Must(queue.NewRingBuffer[int](5))
 	if q.Cap() != 8 {
 		t.Errorf("expected Cap() = 8 (rounded up), got %d", q.Cap())
 	}
 
 	// Size 8 should stay 8
-	q2 := queue.NewRingBuffer[int](8)
+	q2 := queue.Must(queue.NewRingBuffer[int](8))
 	if q2.Cap() != 8 {
 		t.Errorf("expected Cap() = 8, got %d", q2.Cap())
 	}
@@ -166,8 +168,9 @@ func TestQueueInterface(t *testing.T) {
 		name string
 		q    queue.Queue[int]
 	}{
-		{"Channel", queue.NewChannel[int](8)},
-		{"RingBuffer", queue.NewRingBuffer[int](8)},
+		{"Channel", queue.Must(queue.NewChannel[int](8))},
+		{"RingBuffer", queue.Must(queue.NewRingBuffer[int](8))},
+		{"UnsyncRing", queue.Must(queue.NewUnsyncRing[int](8))},
 	}
 
 	for _, tc := range testCases {
@@ -176,3 +179,149 @@ func TestQueueInterface(t *testing.T) {
 		})
 	}
 }
+
+// Test Len/Cap through the Sized interface, filling each queue to Cap
+func TestSizedInterface(t *testing.T) {
+	testCases := []struct {
+		name    string
+		q       queue.Sized[int]
+		wantCap int
+	}{
+		{"Channel", queue.Must(queue.NewChannel[int](6)), 6},
+		{"RingBuffer", queue.Must(queue.NewRingBuffer[int](6)), 8},
+		{"BoundedChan", queue.Must(queue.NewBoundedChan[int](6)), 6},
+		{"UnsyncRing", queue.Must(queue.NewUnsyncRing[int](6)), 8},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.q.Cap(); got != tc.wantCap {
+				t.Fatalf("expected Cap() = %d, got %d", tc.wantCap, got)
+			}
+			for i := 0; i < tc.wantCap; i++ {
+				if got := tc.q.Len(); got != i {
+					t.Fatalf("expected Len() = %d, got %d", i, got)
+				}
+				if !tc.q.Push(i) {
+					t.Fatalf("expected Push(%d) = true below Cap", i)
+				}
+			}
+			if tc.q.Push(-1) {
+				t.Error("expected Push() = false at Cap")
+			}
+			tc.q.Pop()
+			if got := tc.q.Len(); got != tc.wantCap-1 {
+				t.Errorf("expected Len() = %d after Pop, got %d", tc.wantCap-1, got)
+			}
+		})
+	}
+}
+
+// batchQueue is implemented by queues that support batch consumption.
+type batchQueue interface {
+	queue.Queue[int]
+	Drain(dst []int) int
+	Range(fn func(int) bool)
+}
+
+func TestDrainRange(t *testing.T) {
+	testCases := []struct {
+		name   string
+		create func() batchQueue
+	}{
+		{"Channel", func() batchQueue { return queue.Must(queue.NewChannel[int](16)) }},
+		{"RingBuffer", func() batchQueue { return queue.Must(queue.NewRingBuffer[int](16)) }},
+		{"LinkedQueue", func() batchQueue { return queue.NewLinkedQueue[int](queue.AllocFreelist) }},
+		{"BoundedChan", func() batchQueue { return queue.Must(queue.NewBoundedChan[int](16)) }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := tc.create()
+
+			// Drain on empty queue
+			dst := make([]int, 4)
+			if n := q.Drain(dst); n != 0 {
+				t.Errorf("expected Drain() = 0 on empty queue, got %d", n)
+			}
+
+			// Drain is bounded by len(dst) and preserves FIFO order
+			for i := 0; i < 10; i++ {
+				q.Push(i)
+			}
+			if n := q.Drain(dst); n != 4 {
+				t.Fatalf("expected Drain() = 4, got %d", n)
+			}
+			for i, v := range dst {
+				if v != i {
+					t.Errorf("FIFO violation: expected %d, got %d", i, v)
+				}
+			}
+
+			// Range stops when fn returns false, consuming that item
+			var seen []int
+			q.Range(func(v int) bool {
+				seen = append(seen, v)
+				return v < 6
+			})
+			if len(seen) != 3 || seen[0] != 4 || seen[2] != 6 {
+				t.Errorf("expected Range to visit [4 5 6], got %v", seen)
+			}
+
+			// Range visits the rest
+			seen = seen[:0]
+			q.Range(func(v int) bool {
+				seen = append(seen, v)
+				return true
+			})
+			if len(seen) != 3 || seen[0] != 7 || seen[2] != 9 {
+				t.Errorf("expected Range to visit [7 8 9], got %v", seen)
+			}
+
+			if _, ok := q.Pop(); ok {
+				t.Error("expected Pop() = false after draining")
+			}
+		})
+	}
+}
+
+func TestConstructors_InvalidSize(t *testing.T) {
+	sizes := []int{0, -1, math.MinInt, math.MaxInt/2 + 1, math.MaxInt}
+
+	for _, size := range sizes {
+		if _, err := queue.NewChannel[int](size); !errors.Is(err, queue.ErrInvalidSize) {
+			t.Errorf("NewChannel(%d): expected ErrInvalidSize, got %v", size, err)
+		}
+		if _, err := queue.NewRingBuffer[int](size); !errors.Is(err, queue.ErrInvalidSize) {
+			t.Errorf("NewRingBuffer(%d): expected ErrInvalidSize, got %v", size, err)
+		}
+		if _, err := queue.NewBoundedChan[int](size); !errors.Is(err, queue.ErrInvalidSize) {
+			t.Errorf("NewBoundedChan(%d): expected ErrInvalidSize, got %v", size, err)
+		}
+		if _, err := queue.NewDisruptor[int](size); !errors.Is(err, queue.ErrInvalidSize) {
+			t.Errorf("NewDisruptor(%d): expected ErrInvalidSize, got %v", size, err)
+		}
+		if _, err := queue.NewUnsyncRing[int](size); !errors.Is(err, queue.ErrInvalidSize) {
+			t.Errorf("NewUnsyncRing(%d): expected ErrInvalidSize, got %v", size, err)
+		}
+	}
+}
+
+func TestConstructors_MinSize(t *testing.T) {
+	q, err := queue.NewRingBuffer[int](1)
+	if err != nil {
+		t.Fatalf("NewRingBuffer(1): unexpected error %v", err)
+	}
+	if q.Cap() != 1 {
+		t.Errorf("expected Cap() = 1, got %d", q.Cap())
+	}
+}
+
+func TestMust_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Must to panic on error")
+		}
+	}()
+	queue.Must(queue.NewRingBuffer[int](0))
+}
@@ -12,8 +12,9 @@ import (
 // The implementation includes runtime guards that panic if the SPSC contract
 // is violated. This catches bugs early during development.
 type RingBuffer[T any] struct {
-	buf  []T
-	mask uint64
+	buf   []T
+	mask  uint64
+	clear bool // Zero slots on Pop (WithClearOnPop)
 
 	// Cache line padding to prevent false sharing
 	_pad0 [56]byte //nolint:unused
@@ -33,17 +34,28 @@ type RingBuffer[T any] struct {
 
 // NewRingBuffer creates a RingBuffer with the specified size.
 // Size will be rounded up to the next power of 2.
-func NewRingBuffer[T any](size int) *RingBuffer[T] {
-	// Round up to power of 2
-	n := uint64(1)
-	for n < uint64(size) {
-		n <<= 1
+//
+// Returns ErrInvalidSize if size is not between 1 and MaxInt/2, or an
+// error from WithNUMANode if the backing array cannot be bound.
+func NewRingBuffer[T any](size int, opts ...Option) (*RingBuffer[T], error) {
+	if err := checkSize(size); err != nil {
+		return nil, err
+	}
+	o := applyOptions(opts)
+	n := roundPow2(size)
+
+	buf := make([]T, n)
+	if o.numaNode >= 0 {
+		if err := bindSlice(buf, o.numaNode); err != nil {
+			return nil, err
+		}
 	}
 
 	return &RingBuffer[T]{
-		buf:  make([]T, n),
-		mask: n - 1,
-	}
+		buf:   buf,
+		mask:  n - 1,
+		clear: o.clearOnPop,
+	}, nil
 }
 
 // Push adds an item to the queue.
@@ -88,14 +100,19 @@ func (r *RingBuffer[T]) Pop() (T, bool) {
 	tail := r.tail.Load()
 	head := r.head.Load()
 
-	// Check if empty
-	if tail >= head {
+	// Check if empty. Compare for equality rather than tail >= head so the
+	// check stays correct when head wraps past math.MaxUint64.
+	if tail == head {
 		var zero T
 		return zero, false
 	}
 
 	// Read value
 	v := r.buf[tail&r.mask]
+	if r.clear {
+		var zero T
+		r.buf[tail&r.mask] = zero
+	}
 
 	// Consume (store-release semantics via atomic)
 	r.tail.Store(tail + 1)
@@ -103,6 +120,68 @@ func (r *RingBuffer[T]) Pop() (T, bool) {
 	return v, true
 }
 
+// Drain pops up to len(dst) currently available items into dst and
+// returns how many were copied.
+//
+// Unlike repeated Pop calls, Drain reads head once and publishes tail
+// once for the whole batch.
+//
+// SPSC CONTRACT: Only ONE goroutine may call Pop(), Drain() or Range().
+func (r *RingBuffer[T]) Drain(dst []T) int {
+	if !r.popActive.CompareAndSwap(0, 1) {
+		panic("queue: concurrent Pop on SPSC RingBuffer - only one consumer allowed")
+	}
+	defer r.popActive.Store(0)
+
+	tail := r.tail.Load()
+	head := r.head.Load()
+
+	n := int(min(head-tail, uint64(len(dst))))
+	for i := 0; i < n; i++ {
+		idx := (tail + uint64(i)) & r.mask
+		dst[i] = r.buf[idx]
+		if r.clear {
+			var zero T
+			r.buf[idx] = zero
+		}
+	}
+
+	r.tail.Store(tail + uint64(n))
+	return n
+}
+
+// Range pops each currently available item and passes it to fn, stopping
+// early if fn returns false. The item passed to the call that returned
+// false is still consumed.
+//
+// Tail is published once when Range returns, so the producer cannot reuse
+// the slots until then; keep fn short.
+//
+// SPSC CONTRACT: Only ONE goroutine may call Pop(), Drain() or Range().
+func (r *RingBuffer[T]) Range(fn func(T) bool) {
+	if !r.popActive.CompareAndSwap(0, 1) {
+		panic("queue: concurrent Pop on SPSC RingBuffer - only one consumer allowed")
+	}
+	defer r.popActive.Store(0)
+
+	tail := r.tail.Load()
+	head := r.head.Load()
+
+	for tail != head {
+		v := r.buf[tail&r.mask]
+		if r.clear {
+			var zero T
+			r.buf[tail&r.mask] = zero
+		}
+		tail++
+		if !fn(v) {
+			break
+		}
+	}
+
+	r.tail.Store(tail)
+}
+
 // Len returns the current number of items in the queue.
 // This is an approximation and may be slightly stale.
 func (r *RingBuffer[T]) Len() int {
@@ -0,0 +1,24 @@
+//go:build 386 || arm || mips || mipsle
+
+package queue_test
+
+import (
+	"math"
+	"testing"
+)
+
+// On 32-bit platforms int and uintptr are 32 bits wide, so any index or
+// length conversion that truncates the uint64 head/tail would misbehave
+// once the counters pass math.MaxUint32.
+
+func TestRingBuffer_Wrap32_BelowBoundary(t *testing.T) {
+	testRingWrap(t, math.MaxUint32-12)
+}
+
+func TestRingBuffer_Wrap32_AtBoundary(t *testing.T) {
+	testRingWrap(t, math.MaxUint32)
+}
+
+func TestRingBuffer_Wrap32_MaxInt32(t *testing.T) {
+	testRingWrap(t, math.MaxInt32-12)
+}
@@ -0,0 +1,57 @@
+package queue_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+// testRingWrap pushes and pops across a starting index, checking FIFO
+// order, Len, and full/empty detection as the indices cross it.
+func testRingWrap(t *testing.T, start uint64) {
+	t.Helper()
+
+	q := queue.Must(queue.NewRingBuffer[int](8))
+	q.SetIndices(start)
+
+	next := 0
+	expected := 0
+	for round := 0; round < 4; round++ {
+		for i := 0; i < 8; i++ {
+			if !q.Push(next) {
+				t.Fatalf("start=%#x: expected Push(%d) = true", start, next)
+			}
+			next++
+		}
+		if q.Push(-1) {
+			t.Fatalf("start=%#x: expected Push() = false on full queue", start)
+		}
+		if q.Len() != 8 {
+			t.Fatalf("start=%#x: expected Len() = 8, got %d", start, q.Len())
+		}
+
+		for i := 0; i < 8; i++ {
+			got, ok := q.Pop()
+			if !ok {
+				t.Fatalf("start=%#x: expected Pop() = true for item %d", start, expected)
+			}
+			if got != expected {
+				t.Fatalf("start=%#x: FIFO violation: expected %d, got %d", start, expected, got)
+			}
+			expected++
+		}
+		if _, ok := q.Pop(); ok {
+			t.Fatalf("start=%#x: expected Pop() = false after draining", start)
+		}
+		if q.Len() != 0 {
+			t.Fatalf("start=%#x: expected Len() = 0, got %d", start, q.Len())
+		}
+	}
+}
+
+// TestRingBuffer_WrapUint64 starts the indices just below 2^64 so head
+// overflows to zero while tail is still near the top.
+func TestRingBuffer_WrapUint64(t *testing.T) {
+	testRingWrap(t, math.MaxUint64-12)
+}
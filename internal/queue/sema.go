@@ -0,0 +1,84 @@
+package queue
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// sema is a counting semaphore with an atomic fast path.
+//
+// Acquire spins on a CAS while permits are available and only parks on a
+// channel when the count is zero. Releases signal parked waiters only if
+// there are any, so the uncontended path never touches a channel.
+type sema struct {
+	n       atomic.Int64
+	waiters atomic.Int32
+	wake    chan struct{} // capacity 1; coalesced wake-ups are chained on
+	done    chan struct{} // closed to release every waiter
+}
+
+func newSema(n int, done chan struct{}) *sema {
+	s := &sema{
+		wake: make(chan struct{}, 1),
+		done: done,
+	}
+	s.n.Store(int64(n))
+	return s
+}
+
+// tryAcquire takes a permit without blocking.
+func (s *sema) tryAcquire() bool {
+	for {
+		v := s.n.Load()
+		if v <= 0 {
+			return false
+		}
+		if s.n.CompareAndSwap(v, v-1) {
+			return true
+		}
+	}
+}
+
+// acquire blocks until a permit is available, done is closed, or timeout
+// fires. A nil timeout waits forever. Returns false if no permit was taken.
+func (s *sema) acquire(timeout <-chan time.Time) bool {
+	if s.tryAcquire() {
+		return true
+	}
+
+	s.waiters.Add(1)
+	defer s.waiters.Add(-1)
+
+	for {
+		if s.tryAcquire() {
+			// Wake-ups coalesce in the 1-slot channel; pass one on if
+			// permits remain so no waiter sleeps through a release.
+			if s.n.Load() > 0 {
+				s.signal()
+			}
+			return true
+		}
+		select {
+		case <-s.wake:
+		case <-s.done:
+			return s.tryAcquire()
+		case <-timeout:
+			return s.tryAcquire()
+		}
+	}
+}
+
+// release returns a permit and wakes a waiter if one is parked.
+func (s *sema) release() {
+	s.n.Add(1)
+	if s.waiters.Load() > 0 {
+		s.signal()
+	}
+}
+
+func (s *sema) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,56 @@
+package queue
+
+import "fmt"
+
+// Snapshot is a point-in-time copy of a RingBuffer's indices and the
+// items between them, for debugging stalled pipelines.
+type Snapshot[T any] struct {
+	Head  uint64 // Next slot the producer will write
+	Tail  uint64 // Next slot the consumer will read
+	Cap   int
+	Items []T // Visible items, oldest first; len(Items) == Head-Tail
+}
+
+// String summarizes the snapshot without the items, e.g.
+// "head=1040 tail=16 len=1024/1024 (full)".
+func (s Snapshot[T]) String() string {
+	state := ""
+	switch {
+	case len(s.Items) == 0:
+		state = " (empty)"
+	case len(s.Items) == s.Cap:
+		state = " (full)"
+	}
+	return fmt.Sprintf("head=%d tail=%d len=%d/%d%s", s.Head, s.Tail, len(s.Items), s.Cap, state)
+}
+
+// Snapshot copies the items currently visible to the consumer without
+// consuming them.
+//
+// It must be called from the consumer side: the producer only writes
+// slots outside [tail, head), so while the consumer is not advancing tail
+// the copied slots cannot change and the snapshot never tears. A
+// concurrent Pop trips the SPSC guard as usual.
+//
+// SPSC CONTRACT: Call only from the goroutine that calls Pop().
+func (r *RingBuffer[T]) Snapshot() Snapshot[T] {
+	if !r.popActive.CompareAndSwap(0, 1) {
+		panic("queue: concurrent Pop on SPSC RingBuffer - only one consumer allowed")
+	}
+	defer r.popActive.Store(0)
+
+	tail := r.tail.Load()
+	head := r.head.Load()
+
+	items := make([]T, head-tail)
+	for i := range items {
+		items[i] = r.buf[(tail+uint64(i))&r.mask]
+	}
+
+	return Snapshot[T]{
+		Head:  head,
+		Tail:  tail,
+		Cap:   len(r.buf),
+		Items: items,
+	}
+}
@@ -0,0 +1,87 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+func TestRingBuffer_Snapshot(t *testing.T) {
+	q := queue.Must(queue.NewRingBuffer[int](4))
+
+	s := q.Snapshot()
+	if len(s.Items) != 0 || s.Head != 0 || s.Tail != 0 {
+		t.Fatalf("empty snapshot = %+v", s)
+	}
+	if got := s.String(); got != "head=0 tail=0 len=0/4 (empty)" {
+		t.Errorf("String() = %q", got)
+	}
+
+	for i := 1; i <= 4; i++ {
+		q.Push(i)
+	}
+	q.Pop()
+	q.Push(5)
+
+	s = q.Snapshot()
+	if s.Head != 5 || s.Tail != 1 {
+		t.Errorf("expected head=5 tail=1, got head=%d tail=%d", s.Head, s.Tail)
+	}
+	for i, v := range s.Items {
+		if v != i+2 {
+			t.Errorf("Items[%d] = %d, want %d", i, v, i+2)
+		}
+	}
+	if got := s.String(); got != "head=5 tail=1 len=4/4 (full)" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// Snapshot does not consume
+	if q.Len() != 4 {
+		t.Errorf("expected Len() = 4 after Snapshot, got %d", q.Len())
+	}
+}
+
+// pair is a two-word item; a torn read would break b == ^a.
+type pair struct {
+	a, b uint64
+}
+
+// TestRingBuffer_Snapshot_NoTearing takes snapshots on the consumer side
+// while the producer keeps writing. Every snapshot must hold a contiguous
+// run of intact items starting at the consumer's position. Run with -race
+// to also check that no snapshotted slot is written concurrently.
+func TestRingBuffer_Snapshot_NoTearing(t *testing.T) {
+	const count = 2000
+	q := queue.Must(queue.NewRingBuffer[pair](64))
+
+	go func() {
+		for i := uint64(0); i < count; i++ {
+			for !q.Push(pair{a: i, b: ^i}) {
+				// Spin until push succeeds
+			}
+		}
+	}()
+
+	next := uint64(0)
+	for next < count {
+		s := q.Snapshot()
+		if uint64(len(s.Items)) != s.Head-s.Tail {
+			t.Fatalf("len(Items) = %d, head-tail = %d", len(s.Items), s.Head-s.Tail)
+		}
+		if s.Tail != next {
+			t.Fatalf("snapshot tail = %d, consumer at %d", s.Tail, next)
+		}
+		for i, p := range s.Items {
+			if p.a != next+uint64(i) || p.b != ^p.a {
+				t.Fatalf("torn or out-of-order item at %d: %+v (want a=%d)", i, p, next+uint64(i))
+			}
+		}
+
+		// Consume part of what was seen so the window keeps moving
+		for range (len(s.Items) + 1) / 2 {
+			q.Pop()
+			next++
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package queue
+
+import (
+	"sync/atomic"
+)
+
+// SPMCRing is a bounded lock-free SPMC (Single-Producer Multi-Consumer)
+// queue, the mirror image of MPSCRing: the same per-slot sequence
+// numbers, but here the consumers race on tail while the single producer
+// never executes an atomic read-modify-write.
+//
+// A consumer claims a position by CAS on tail once the slot's sequence
+// says it holds an item, reads the value, then frees the slot for the
+// producer one lap ahead. The producer cannot pass a slot until that
+// happens, so Pop only reports empty when the queue really is.
+//
+// CONTRACT: Only ONE goroutine may call Push(). Any number of goroutines
+// may call Pop().
+type SPMCRing[T any] struct {
+	slots []mpscSlot[T]
+	mask  uint64
+
+	_pad0 [56]byte //nolint:unused
+
+	head atomic.Uint64 // Next position to write; written only by the producer
+
+	_pad1 [56]byte //nolint:unused
+
+	tail atomic.Uint64 // Next position to claim; CAS by consumers
+}
+
+// NewSPMCRing creates an SPMCRing with the specified size.
+// Size will be rounded up to the next power of 2.
+//
+// Returns ErrInvalidSize if size is not between 1 and MaxInt/2.
+func NewSPMCRing[T any](size int) (*SPMCRing[T], error) {
+	if err := checkSize(size); err != nil {
+		return nil, err
+	}
+	n := roundPow2(size)
+
+	q := &SPMCRing[T]{
+		slots: make([]mpscSlot[T], n),
+		mask:  n - 1,
+	}
+	for i := range q.slots {
+		q.slots[i].seq.Store(uint64(i))
+	}
+	return q, nil
+}
+
+// Push adds an item to the queue.
+// Returns false if the queue is full.
+//
+// CONTRACT: Only ONE goroutine may call Push().
+func (q *SPMCRing[T]) Push(v T) bool {
+	pos := q.head.Load()
+	s := &q.slots[pos&q.mask]
+	if s.seq.Load() != pos {
+		// A consumer has not yet freed the item from one lap ago
+		return false
+	}
+
+	s.val = v
+	s.seq.Store(pos + 1)
+	q.head.Store(pos + 1)
+	return true
+}
+
+// Pop removes and returns an item from the queue.
+// Returns false if the queue is empty.
+//
+// Safe for concurrent use by multiple consumers.
+func (q *SPMCRing[T]) Pop() (T, bool) {
+	for {
+		pos := q.tail.Load()
+		s := &q.slots[pos&q.mask]
+		seq := s.seq.Load()
+
+		switch dif := int64(seq - (pos + 1)); {
+		case dif == 0:
+			// Slot holds the item for this position; try to claim it
+			if q.tail.CompareAndSwap(pos, pos+1) {
+				v := s.val
+				var zero T
+				s.val = zero
+
+				// Free the slot for the producer one lap ahead
+				s.seq.Store(pos + q.mask + 1)
+				return v, true
+			}
+		case dif < 0:
+			// Producer has not written this position yet: empty
+			var zero T
+			return zero, false
+		}
+		// Another consumer claimed pos first; retry with the new tail
+	}
+}
+
+// Len returns the current number of items in the queue.
+// This is an approximation and may be slightly stale.
+func (q *SPMCRing[T]) Len() int {
+	tail := q.tail.Load()
+	head := q.head.Load()
+	return int(head - tail)
+}
+
+// Cap returns the capacity of the queue.
+func (q *SPMCRing[T]) Cap() int {
+	return len(q.slots)
+}
@@ -0,0 +1,101 @@
+package queue_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/queue"
+)
+
+func TestSPMCRing(t *testing.T) {
+	q := queue.Must(queue.NewSPMCRing[int](8))
+	testQueue(t, q, 42, "SPMCRing")
+}
+
+func TestSPMCRing_FullAndWrap(t *testing.T) {
+	q := queue.Must(queue.NewSPMCRing[int](4))
+
+	next, expected := 0, 0
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 4; i++ {
+			if !q.Push(next) {
+				t.Fatalf("round %d: expected Push(%d) = true", round, next)
+			}
+			next++
+		}
+		if q.Push(-1) {
+			t.Fatalf("round %d: expected Push() = false on full queue", round)
+		}
+		if q.Len() != 4 {
+			t.Fatalf("round %d: expected Len() = 4, got %d", round, q.Len())
+		}
+		for i := 0; i < 4; i++ {
+			got, ok := q.Pop()
+			if !ok || got != expected {
+				t.Fatalf("round %d: Pop() = (%d, %v), want (%d, true)", round, got, ok, expected)
+			}
+			expected++
+		}
+		if _, ok := q.Pop(); ok {
+			t.Fatalf("round %d: expected Pop() = false after draining", round)
+		}
+	}
+}
+
+// TestSPMCRing_SPMC verifies that concurrent consumers receive every item
+// exactly once and that each consumer sees items in increasing order.
+func TestSPMCRing_SPMC(t *testing.T) {
+	const consumers = 4
+	const items = 4000
+
+	q := queue.Must(queue.NewSPMCRing[int](1024))
+
+	var wg sync.WaitGroup
+	seen := make([][]int, consumers)
+	var remaining sync.WaitGroup
+	remaining.Add(items)
+	done := make(chan struct{})
+
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if v, ok := q.Pop(); ok {
+					seen[id] = append(seen[id], v)
+					remaining.Done()
+				}
+			}
+		}(c)
+	}
+
+	for i := 0; i < items; i++ {
+		for !q.Push(i) {
+			// Spin until push succeeds
+		}
+	}
+	remaining.Wait()
+	close(done)
+	wg.Wait()
+
+	got := make([]bool, items)
+	for id, vs := range seen {
+		for i, v := range vs {
+			if i > 0 && v <= vs[i-1] {
+				t.Fatalf("consumer %d: %d received after %d", id, v, vs[i-1])
+			}
+			if got[v] {
+				t.Fatalf("item %d received twice", v)
+			}
+			got[v] = true
+		}
+	}
+	if _, ok := q.Pop(); ok {
+		t.Error("expected Pop() = false after all items received")
+	}
+}
@@ -0,0 +1,66 @@
+package queue
+
+// UnsyncRing is a plain ring buffer with no atomics and no guards.
+//
+// It exists as the performance floor for the benchmarks: the difference
+// between UnsyncRing and RingBuffer is exactly what the atomic index
+// publication and the SPSC guards cost on top of raw array indexing.
+//
+// WARNING: Not safe for use by more than one goroutine, even with one
+// producer and one consumer. Push and Pop must be called from the same
+// goroutine (or with external synchronization).
+type UnsyncRing[T any] struct {
+	buf  []T
+	mask uint64
+	head uint64
+	tail uint64
+}
+
+// NewUnsyncRing creates an UnsyncRing with the specified size.
+// Size will be rounded up to the next power of 2.
+//
+// Returns ErrInvalidSize if size is not between 1 and MaxInt/2.
+func NewUnsyncRing[T any](size int) (*UnsyncRing[T], error) {
+	if err := checkSize(size); err != nil {
+		return nil, err
+	}
+	n := roundPow2(size)
+
+	return &UnsyncRing[T]{
+		buf:  make([]T, n),
+		mask: n - 1,
+	}, nil
+}
+
+// Push adds an item to the queue.
+// Returns false if the queue is full.
+func (r *UnsyncRing[T]) Push(v T) bool {
+	if r.head-r.tail >= uint64(len(r.buf)) {
+		return false
+	}
+	r.buf[r.head&r.mask] = v
+	r.head++
+	return true
+}
+
+// Pop removes and returns an item from the queue.
+// Returns false if the queue is empty.
+func (r *UnsyncRing[T]) Pop() (T, bool) {
+	if r.tail == r.head {
+		var zero T
+		return zero, false
+	}
+	v := r.buf[r.tail&r.mask]
+	r.tail++
+	return v, true
+}
+
+// Len returns the current number of items in the queue.
+func (r *UnsyncRing[T]) Len() int {
+	return int(r.head - r.tail)
+}
+
+// Cap returns the capacity of the queue.
+func (r *UnsyncRing[T]) Cap() int {
+	return len(r.buf)
+}
@@ -0,0 +1,44 @@
+package randbench
+
+import (
+	"math/rand"
+	randv2 "math/rand/v2"
+)
+
+// Rand is a Source on a math/rand *rand.Rand of its own. It is not safe
+// for concurrent use.
+type Rand struct {
+	r *rand.Rand
+}
+
+// NewRand creates a Rand seeded with seed.
+func NewRand(seed int64) *Rand {
+	return &Rand{r: rand.New(rand.NewSource(seed))}
+}
+
+// Uint64 returns the generator's next number.
+func (r *Rand) Uint64() uint64 {
+	return r.r.Uint64()
+}
+
+// PCG is a Source on a math/rand/v2 PCG of its own, held by value. It is
+// not safe for concurrent use.
+//
+// This is the optimized approach: the generator's state is two words,
+// stepped by a 128-bit multiply and add, with nothing shared to
+// synchronize on.
+type PCG struct {
+	pcg randv2.PCG
+}
+
+// NewPCG creates a PCG seeded with seed.
+func NewPCG(seed uint64) *PCG {
+	p := &PCG{}
+	p.pcg.Seed(seed, seed^0x9e3779b97f4a7c15)
+	return p
+}
+
+// Uint64 returns the generator's next number.
+func (p *PCG) Uint64() uint64 {
+	return p.pcg.Uint64()
+}
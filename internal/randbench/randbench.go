@@ -0,0 +1,83 @@
+// Package randbench provides random number sources to compare under
+// concurrent callers: the shared math/rand and math/rand/v2 generators, a
+// shared generator behind a mutex, and generators each goroutine keeps to
+// itself.
+//
+// This package offers five implementations of the Source interface:
+//   - Global: Standard math/rand top-level functions, lock-free since Go
+//     1.20 as long as nothing calls rand.Seed
+//   - Locked: Standard math/rand generator behind a mutex, as the
+//     top-level functions were before Go 1.20, and still are after
+//     rand.Seed with GODEBUG=randseednop=0
+//   - V2: Standard math/rand/v2 top-level functions, a ChaCha8 generator
+//     per thread in the runtime
+//   - Rand: a math/rand *rand.Rand per goroutine, no lock
+//   - PCG: Optimized math/rand/v2 PCG per goroutine, a multiply and an
+//     add per number
+//
+// A shared generator behind a lock is the hidden cost: every call is a
+// lock acquisition on one cache line, so a loop calling rand in each of
+// N goroutines serializes on it and gets slower, not faster, as N grows.
+// The runtime-backed top-level functions and per-goroutine generators
+// have no shared state and scale with the goroutines.
+package randbench
+
+import (
+	"math/rand"
+	randv2 "math/rand/v2"
+	"sync"
+)
+
+// Source produces random 64-bit numbers.
+//
+// Global, Locked and V2 are safe for concurrent use and meant to be
+// shared; Rand and PCG are not, and each goroutine needs its own.
+type Source interface {
+	Uint64() uint64
+}
+
+// Global is a Source on math/rand's top-level Uint64.
+type Global struct{}
+
+// NewGlobal creates a Global.
+func NewGlobal() *Global {
+	return &Global{}
+}
+
+// Uint64 returns rand.Uint64().
+func (*Global) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// Locked is a Source on a math/rand generator behind a sync.Mutex, the
+// same as math/rand's lockedSource.
+type Locked struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+// NewLocked creates a Locked seeded with seed.
+func NewLocked(seed int64) *Locked {
+	return &Locked{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+// Uint64 returns the generator's next number, holding the lock.
+func (l *Locked) Uint64() uint64 {
+	l.mu.Lock()
+	n := l.src.Uint64()
+	l.mu.Unlock()
+	return n
+}
+
+// V2 is a Source on math/rand/v2's top-level Uint64.
+type V2 struct{}
+
+// NewV2 creates a V2.
+func NewV2() *V2 {
+	return &V2{}
+}
+
+// Uint64 returns randv2.Uint64().
+func (*V2) Uint64() uint64 {
+	return randv2.Uint64()
+}
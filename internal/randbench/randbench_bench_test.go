@@ -0,0 +1,56 @@
+package randbench_test
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/randbench"
+)
+
+// Sink variables to prevent compiler from eliminating benchmark loops
+var sinkUint64 uint64
+
+// runSplit runs b.N calls of fn split across goroutines goroutines,
+// passing each its index. Unlike b.RunParallel, whose goroutine count is
+// a multiple of GOMAXPROCS, it runs exactly as many as asked for.
+func runSplit(b *testing.B, goroutines int, fn func(g int) uint64) {
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		count := b.N / goroutines
+		if g < b.N%goroutines {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var sum uint64
+			for range count {
+				sum ^= fn(g)
+			}
+			sinkUint64 = sum
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkSource times Uint64 from 1 and GOMAXPROCS*4 goroutines, the
+// shared Sources shared by all of them and the others one per goroutine.
+func BenchmarkSource(b *testing.B) {
+	for _, goroutines := range []int{1, runtime.GOMAXPROCS(0) * 4} {
+		for _, s := range sources {
+			b.Run(fmt.Sprintf("%s/G=%d", s.name, goroutines), func(b *testing.B) {
+				srcs := make([]randbench.Source, goroutines)
+				shared := s.new(1)
+				for g := range srcs {
+					srcs[g] = shared
+					if !s.shared {
+						srcs[g] = s.new(uint64(g))
+					}
+				}
+				runSplit(b, goroutines, func(g int) uint64 { return srcs[g].Uint64() })
+			})
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package randbench_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/randbench"
+)
+
+// sources creates each Source implementation, seeded with seed where it
+// takes one; shared ones are safe for concurrent use.
+var sources = []struct {
+	name   string
+	shared bool
+	new    func(seed uint64) randbench.Source
+}{
+	{"Global", true, func(uint64) randbench.Source { return randbench.NewGlobal() }},
+	{"Locked", true, func(seed uint64) randbench.Source { return randbench.NewLocked(int64(seed)) }},
+	{"V2", true, func(uint64) randbench.Source { return randbench.NewV2() }},
+	{"Rand", false, func(seed uint64) randbench.Source { return randbench.NewRand(int64(seed)) }},
+	{"PCG", false, func(seed uint64) randbench.Source { return randbench.NewPCG(seed) }},
+}
+
+func TestSource_Varies(t *testing.T) {
+	for _, s := range sources {
+		src := s.new(1)
+		seen := make(map[uint64]bool)
+		for range 1000 {
+			seen[src.Uint64()] = true
+		}
+		if len(seen) < 1000 {
+			t.Errorf("%s: %d distinct numbers in 1000", s.name, len(seen))
+		}
+	}
+}
+
+func TestSource_Seeded(t *testing.T) {
+	for _, s := range sources {
+		if s.shared {
+			continue
+		}
+		a, b, c := s.new(7), s.new(7), s.new(8)
+		x, y, z := a.Uint64(), b.Uint64(), c.Uint64()
+		if x != y {
+			t.Errorf("%s: one seed gave %#x and %#x", s.name, x, y)
+		}
+		if x == z {
+			t.Errorf("%s: seeds 7 and 8 both gave %#x", s.name, x)
+		}
+	}
+}
+
+// TestShared_Concurrent calls the shared Sources from several goroutines
+// at once, for the race detector.
+func TestShared_Concurrent(t *testing.T) {
+	for _, s := range sources {
+		if !s.shared {
+			continue
+		}
+		src := s.new(1)
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 1000 {
+					src.Uint64()
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,133 @@
+// Package ratelimit provides a token-bucket rate limiter built from the
+// repo's own pieces: an AtomicTicker decides when to refill, and an
+// atomic counter holds the tokens.
+//
+// It is meant to be compared against golang.org/x/time/rate.Limiter, which
+// takes a mutex and reads the clock on every call. Here the common case,
+// Allow with tokens available, is one nanotime read inside the ticker plus
+// one CAS on the counter; the clock is read properly only when the ticker
+// fires.
+//
+// The trade-off is granularity: tokens are added at most once per refill
+// interval, so a caller that drains the bucket waits for the next refill
+// rather than for exactly one token's worth of time.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tick"
+)
+
+var (
+	// ErrInvalidRate is returned by New for a rate outside 1..1e9 per
+	// second.
+	ErrInvalidRate = errors.New("ratelimit: rate must be between 1 and 1e9 per second")
+
+	// ErrInvalidBurst is returned by New for a burst below 1.
+	ErrInvalidBurst = errors.New("ratelimit: burst must be at least 1")
+)
+
+// MinRefill is the shortest refill interval. At high rates, refilling
+// every token period would make the ticker fire on almost every call.
+const MinRefill = 100 * time.Microsecond
+
+// Limiter is a token bucket that allows perSecond events on average and
+// bursts of up to burst events.
+//
+// All methods are safe for concurrent use.
+type Limiter struct {
+	tokens atomic.Int64
+
+	_pad0 [56]byte //nolint:unused
+
+	ticker     *tick.AtomicTicker
+	base       time.Time
+	nsPerToken int64
+	burst      int64
+	refilled   atomic.Int64 // Time since base covered by refills so far
+}
+
+// New creates a Limiter that starts with a full bucket.
+//
+// Returns ErrInvalidRate if perSecond is not between 1 and 1e9, and
+// ErrInvalidBurst if burst is less than 1.
+func New(perSecond, burst int) (*Limiter, error) {
+	if perSecond < 1 || perSecond > 1e9 {
+		return nil, fmt.Errorf("%w: got %d", ErrInvalidRate, perSecond)
+	}
+	if burst < 1 {
+		return nil, fmt.Errorf("%w: got %d", ErrInvalidBurst, burst)
+	}
+	nsPerToken := int64(time.Second) / int64(perSecond)
+
+	l := &Limiter{
+		ticker:     tick.NewAtomicTicker(max(time.Duration(nsPerToken), MinRefill)),
+		base:       time.Now(),
+		nsPerToken: nsPerToken,
+		burst:      int64(burst),
+	}
+	l.tokens.Store(int64(burst))
+	return l, nil
+}
+
+// Allow reports whether an event may happen now, taking a token if so.
+func (l *Limiter) Allow() bool {
+	if l.ticker.Tick() {
+		l.refill()
+	}
+	for {
+		n := l.tokens.Load()
+		if n <= 0 {
+			return false
+		}
+		if l.tokens.CompareAndSwap(n, n-1) {
+			return true
+		}
+	}
+}
+
+// Wait blocks until a token is available or ctx is done, yielding the
+// processor between attempts. Returns ctx.Err() if ctx ends first.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for !l.Allow() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		runtime.Gosched()
+	}
+	return nil
+}
+
+// Tokens returns the number of tokens currently in the bucket.
+func (l *Limiter) Tokens() int {
+	return int(l.tokens.Load())
+}
+
+// refill adds a token for every whole token period since the last refill,
+// up to burst. Only the caller that won the ticker's CAS gets here.
+func (l *Limiter) refill() {
+	now := int64(time.Since(l.base))
+	last := l.refilled.Load()
+	add := (now - last) / l.nsPerToken
+	if add <= 0 {
+		return
+	}
+	// Keep the fractional token period for next time
+	if !l.refilled.CompareAndSwap(last, last+add*l.nsPerToken) {
+		return
+	}
+
+	for {
+		n := l.tokens.Load()
+		next := min(n+add, l.burst)
+		if next <= n || l.tokens.CompareAndSwap(n, next) {
+			return
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/ratelimit"
+)
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		perSecond int
+		burst     int
+		want      error
+	}{
+		{"zero rate", 0, 1, ratelimit.ErrInvalidRate},
+		{"negative rate", -1, 1, ratelimit.ErrInvalidRate},
+		{"rate too high", 2e9, 1, ratelimit.ErrInvalidRate},
+		{"zero burst", 100, 0, ratelimit.ErrInvalidBurst},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := ratelimit.New(tt.perSecond, tt.burst)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("New(%d, %d) error = %v, want %v", tt.perSecond, tt.burst, err, tt.want)
+			}
+			if l != nil {
+				t.Error("expected nil Limiter on error")
+			}
+		})
+	}
+}
+
+func TestLimiter_Burst(t *testing.T) {
+	// One token per second: nothing refills during the test
+	l, err := ratelimit.New(1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if !l.Allow() {
+			t.Fatalf("Allow() #%d = false, want true within burst", i+1)
+		}
+	}
+	if l.Allow() {
+		t.Error("expected Allow() = false once burst is used")
+	}
+	if l.Tokens() != 0 {
+		t.Errorf("expected Tokens() = 0, got %d", l.Tokens())
+	}
+}
+
+func TestLimiter_Refill(t *testing.T) {
+	// 1000/s: a token every 1ms
+	l, err := ratelimit.New(1000, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for l.Allow() {
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	allowed := 0
+	for l.Allow() {
+		allowed++
+	}
+	// 20 periods elapsed, but the bucket holds at most 3
+	if allowed != 3 {
+		t.Errorf("allowed %d after refill, want burst of 3", allowed)
+	}
+}
+
+func TestLimiter_ConcurrentNeverExceedsBurst(t *testing.T) {
+	const burst = 100
+	l, err := ratelimit.New(1, burst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if l.Allow() {
+					allowed.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed.Load() != burst {
+		t.Errorf("allowed %d, want exactly %d", allowed.Load(), burst)
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	l, err := ratelimit.New(1000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("first Wait() = %v, want nil", err)
+	}
+
+	start := time.Now()
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("second Wait() = %v, want nil", err)
+	}
+	if waited := time.Since(start); waited < 500*time.Microsecond {
+		t.Errorf("second Wait() returned after %v, expected to wait for a refill", waited)
+	}
+}
+
+func TestLimiter_WaitCancelled(t *testing.T) {
+	l, err := ratelimit.New(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Allow()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() = %v, want context.Canceled", err)
+	}
+}
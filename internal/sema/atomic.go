@@ -0,0 +1,58 @@
+package sema
+
+import "sync/atomic"
+
+// Atomic is a Semaphore on an atomic counter that parks waiters on a
+// channel only when no slot is free: a "benaphore".
+//
+// This is the optimized approach. The counter holds the free slots, or
+// minus the number of waiters when there are none: Acquire decrements it
+// and returns if it didn't go negative, and Release increments it and
+// wakes a waiter if it wasn't positive. Uncontended, each is one atomic
+// add and nothing else; contended, a wakeup goes through the channel.
+// Waiters are woken in no particular order.
+type Atomic struct {
+	count atomic.Int64
+	wake  chan struct{} // A token per waiter a Release is waking
+}
+
+// NewAtomic creates an Atomic with limit slots.
+//
+// Returns ErrInvalidLimit if limit is not between 1 and MaxLimit.
+func NewAtomic(limit int) (*Atomic, error) {
+	if err := checkLimit(limit); err != nil {
+		return nil, err
+	}
+	a := &Atomic{wake: make(chan struct{}, limit)}
+	a.count.Store(int64(limit))
+	return a, nil
+}
+
+// Acquire takes a slot, blocking until one is free.
+func (a *Atomic) Acquire() {
+	if a.count.Add(-1) >= 0 {
+		return
+	}
+	// Counted as a waiter; a Release will hand over its slot
+	<-a.wake
+}
+
+// TryAcquire takes a slot if one is free.
+func (a *Atomic) TryAcquire() bool {
+	for {
+		n := a.count.Load()
+		if n <= 0 {
+			return false
+		}
+		if a.count.CompareAndSwap(n, n-1) {
+			return true
+		}
+	}
+}
+
+// Release gives a slot back, to a waiter if there is one.
+func (a *Atomic) Release() {
+	if a.count.Add(1) <= 0 {
+		a.wake <- struct{}{}
+	}
+}
@@ -0,0 +1,40 @@
+package sema
+
+// Chan is a Semaphore on a buffered channel with a slot per element:
+// Acquire sends, Release receives.
+//
+// This is the standard idiom. Each operation takes the channel's lock,
+// and a blocked Acquire parks on the channel until a Release receives.
+type Chan struct {
+	slots chan struct{}
+}
+
+// NewChan creates a Chan with limit slots.
+//
+// Returns ErrInvalidLimit if limit is not between 1 and MaxLimit.
+func NewChan(limit int) (*Chan, error) {
+	if err := checkLimit(limit); err != nil {
+		return nil, err
+	}
+	return &Chan{slots: make(chan struct{}, limit)}, nil
+}
+
+// Acquire takes a slot, blocking until one is free.
+func (c *Chan) Acquire() {
+	c.slots <- struct{}{}
+}
+
+// TryAcquire takes a slot if one is free.
+func (c *Chan) TryAcquire() bool {
+	select {
+	case c.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release gives a slot back.
+func (c *Chan) Release() {
+	<-c.slots
+}
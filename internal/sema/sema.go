@@ -0,0 +1,56 @@
+// Package sema provides counting semaphore implementations for
+// benchmarking: a buffered channel, golang.org/x/sync/semaphore.Weighted,
+// and an atomic counter that parks waiters on a channel.
+//
+// A semaphore caps how many goroutines do something at once, such as
+// how many push into a queue or hold a connection. Uncontended, the cost
+// is what Acquire and Release do when a slot is free: a channel send and
+// receive, a mutex around a counter, or one atomic add. Contended, it is
+// how a waiter is parked and handed the slot a Release frees, which all
+// three do through the scheduler.
+package sema
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidLimit is returned by constructors given a limit that is
+// zero, negative, or above MaxLimit.
+var ErrInvalidLimit = errors.New("sema: limit must be between 1 and MaxLimit")
+
+// MaxLimit is the most slots a Semaphore has.
+const MaxLimit = 1 << 30
+
+// Semaphore limits how many goroutines hold one of its slots at once.
+//
+// Implementations must be safe for concurrent use. Acquire takes a slot,
+// blocking until one is free. TryAcquire takes one only if one is free,
+// and reports whether it did. Release gives a slot back; each Release
+// must follow an Acquire or a successful TryAcquire.
+type Semaphore interface {
+	Acquire()
+	TryAcquire() bool
+	Release()
+}
+
+// Must wraps a constructor call and panics if it returned an error.
+//
+// It is intended for tests, benchmarks and package-level variables with
+// constant limits:
+//
+//	s := sema.Must(sema.NewChan(4))
+func Must[S any](s S, err error) S {
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// checkLimit returns ErrInvalidLimit unless 1 <= limit <= MaxLimit.
+func checkLimit(limit int) error {
+	if limit < 1 || limit > MaxLimit {
+		return fmt.Errorf("%w: got %d", ErrInvalidLimit, limit)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package sema_test
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/sema"
+)
+
+// BenchmarkSema_Uncontended times an Acquire and Release pair from one
+// goroutine, always finding a slot free.
+func BenchmarkSema_Uncontended(b *testing.B) {
+	for _, c := range constructors {
+		b.Run(c.name, func(b *testing.B) {
+			s := sema.Must(c.new(1))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s.Acquire()
+				s.Release()
+			}
+		})
+	}
+}
+
+// BenchmarkSema_Contended times Acquire and Release pairs from
+// GOMAXPROCS*4 goroutines, b.N split between them, at limits of 1 and
+// GOMAXPROCS*2.
+func BenchmarkSema_Contended(b *testing.B) {
+	goroutines := runtime.GOMAXPROCS(0) * 4
+	for _, limit := range []int{1, runtime.GOMAXPROCS(0) * 2} {
+		for _, c := range constructors {
+			b.Run(fmt.Sprintf("%s/limit=%d/G=%d", c.name, limit, goroutines), func(b *testing.B) {
+				s := sema.Must(c.new(limit))
+				b.ReportAllocs()
+				b.ResetTimer()
+				var wg sync.WaitGroup
+				for g := range goroutines {
+					count := b.N / goroutines
+					if g < b.N%goroutines {
+						count++
+					}
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for range count {
+							s.Acquire()
+							s.Release()
+						}
+					}()
+				}
+				wg.Wait()
+			})
+		}
+	}
+}
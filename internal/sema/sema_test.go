@@ -0,0 +1,83 @@
+package sema_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/sema"
+)
+
+// constructors creates each Semaphore implementation with a limit.
+var constructors = []struct {
+	name string
+	new  func(limit int) (sema.Semaphore, error)
+}{
+	{"Chan", func(limit int) (sema.Semaphore, error) { return sema.NewChan(limit) }},
+	{"Weighted", func(limit int) (sema.Semaphore, error) { return sema.NewWeighted(limit) }},
+	{"Atomic", func(limit int) (sema.Semaphore, error) { return sema.NewAtomic(limit) }},
+}
+
+func TestNew_InvalidLimit(t *testing.T) {
+	for _, c := range constructors {
+		for _, limit := range []int{0, -1, sema.MaxLimit + 1} {
+			if _, err := c.new(limit); !errors.Is(err, sema.ErrInvalidLimit) {
+				t.Errorf("%s(%d): err = %v, want ErrInvalidLimit", c.name, limit, err)
+			}
+		}
+	}
+}
+
+func TestSemaphore_TryAcquire(t *testing.T) {
+	for _, c := range constructors {
+		s := sema.Must(c.new(2))
+		if !s.TryAcquire() || !s.TryAcquire() {
+			t.Fatalf("%s: TryAcquire() with free slots = false", c.name)
+		}
+		if s.TryAcquire() {
+			t.Errorf("%s: TryAcquire() with no free slot = true", c.name)
+		}
+		s.Release()
+		if !s.TryAcquire() {
+			t.Errorf("%s: TryAcquire() after a Release = false", c.name)
+		}
+	}
+}
+
+// TestSemaphore_Limit checks that no more goroutines than the limit hold
+// a slot at once, and that every blocked Acquire is eventually woken.
+func TestSemaphore_Limit(t *testing.T) {
+	const limit, goroutines, perGoroutine = 3, 16, 500
+	for _, c := range constructors {
+		t.Run(c.name, func(t *testing.T) {
+			s := sema.Must(c.new(limit))
+			var held, most atomic.Int64
+			var wg sync.WaitGroup
+			for range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range perGoroutine {
+						s.Acquire()
+						n := held.Add(1)
+						for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+						}
+						held.Add(-1)
+						s.Release()
+					}
+				}()
+			}
+			wg.Wait()
+			if most.Load() > limit {
+				t.Errorf("%d goroutines held a slot at once, limit %d", most.Load(), limit)
+			}
+			// Every slot is free again
+			for range limit {
+				if !s.TryAcquire() {
+					t.Fatal("a slot was lost")
+				}
+			}
+		})
+	}
+}
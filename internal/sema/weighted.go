@@ -0,0 +1,43 @@
+package sema
+
+import (
+	"context"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// Weighted is a Semaphore on golang.org/x/sync/semaphore.Weighted,
+// taking one unit per slot.
+//
+// A mutex guards its count and a FIFO list of waiters, each parked on a
+// channel of its own, so waiters are served in order and a large request
+// can't be starved by small ones; weights other than 1 aren't used here.
+type Weighted struct {
+	w *semaphore.Weighted
+}
+
+// NewWeighted creates a Weighted with limit slots.
+//
+// Returns ErrInvalidLimit if limit is not between 1 and MaxLimit.
+func NewWeighted(limit int) (*Weighted, error) {
+	if err := checkLimit(limit); err != nil {
+		return nil, err
+	}
+	return &Weighted{w: semaphore.NewWeighted(int64(limit))}, nil
+}
+
+// Acquire takes a slot, blocking until one is free.
+func (w *Weighted) Acquire() {
+	// Background is never done, so Acquire can't fail
+	_ = w.w.Acquire(context.Background(), 1)
+}
+
+// TryAcquire takes a slot if one is free.
+func (w *Weighted) TryAcquire() bool {
+	return w.w.TryAcquire(1)
+}
+
+// Release gives a slot back.
+func (w *Weighted) Release() {
+	w.w.Release(1)
+}
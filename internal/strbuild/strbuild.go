@@ -0,0 +1,115 @@
+// Package strbuild provides ways of building a string from parts, for
+// benchmarking against each other.
+//
+// This package offers six functions with the same signature:
+//   - Concat: Standard approach, += in a loop
+//   - Sprintf: Standard library fmt.Sprintf, a %s verb per part
+//   - Builder: Standard library strings.Builder, growing as it goes
+//   - Buffer: Standard library bytes.Buffer, copied out by String
+//   - Grow: Optimized strings.Builder, sized up front with Grow
+//   - Append: Optimized []byte preallocated to the total length, then
+//     converted to a string
+//
+// Concat copies everything built so far on every part, quadratic in the
+// part count; Sprintf pays for boxing each part in an interface and for
+// parsing the format. Builder and Buffer double their buffer as they
+// grow, a logarithmic number of copies, and Buffer copies once more to
+// return a string. Knowing the total length up front brings Grow to a
+// single allocation, and Append to two, the buffer and the string, or one
+// when the total is short enough for the compiler to put the buffer on
+// the stack.
+package strbuild
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Concat builds the string with += per part.
+func Concat(parts []string) string {
+	var s string
+	for _, p := range parts {
+		s += p
+	}
+	return s
+}
+
+// maxFormatParts is how many parts Sprintf has a format ready for.
+const maxFormatParts = 1 << 10
+
+// formats is a %s verb for each of maxFormatParts parts, sliced down to
+// the part count: the format a caller would write as a literal.
+var formats = strings.Repeat("%s", maxFormatParts)
+
+// Sprintf builds the string with fmt.Sprintf, a %s verb per part.
+func Sprintf(parts []string) string {
+	args := make([]any, len(parts))
+	for i, p := range parts {
+		args[i] = p
+	}
+	if len(parts) > maxFormatParts {
+		return fmt.Sprintf(strings.Repeat("%s", len(parts)), args...)
+	}
+	return fmt.Sprintf(formats[:2*len(parts)], args...)
+}
+
+// Builder builds the string in a strings.Builder, left to grow as parts
+// are written.
+func Builder(parts []string) string {
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// Buffer builds the string in a bytes.Buffer, left to grow as parts are
+// written, and copies it out with String.
+func Buffer(parts []string) string {
+	var b bytes.Buffer
+	for _, p := range parts {
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// Grow builds the string in a strings.Builder grown to the total length
+// first, so String returns the one buffer it allocated.
+func Grow(parts []string) string {
+	var b strings.Builder
+	b.Grow(totalLen(parts))
+	for _, p := range parts {
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// Append appends the parts to a []byte with capacity for all of them and
+// converts it to a string, which copies it.
+func Append(parts []string) string {
+	b := make([]byte, 0, totalLen(parts))
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return string(b)
+}
+
+// totalLen returns the sum of the parts' lengths.
+func totalLen(parts []string) int {
+	n := 0
+	for _, p := range parts {
+		n += len(p)
+	}
+	return n
+}
+
+// Parts returns n parts of length bytes each, part i repeating the
+// letter 'a'+i%26, to build strings from.
+func Parts(n, length int) []string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = strings.Repeat(string(rune('a'+i%26)), length)
+	}
+	return parts
+}
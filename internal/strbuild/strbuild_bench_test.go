@@ -0,0 +1,36 @@
+package strbuild_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/strbuild"
+)
+
+// Sink variables to prevent compiler from eliminating benchmark loops
+var sinkString string
+
+// partCounts and partLens are the shapes the benchmarks build strings
+// of: a count of parts, each of a length in bytes.
+var (
+	partCounts = []int{2, 8, 64}
+	partLens   = []int{8, 256}
+)
+
+func BenchmarkBuild(b *testing.B) {
+	for _, n := range partCounts {
+		for _, length := range partLens {
+			parts := strbuild.Parts(n, length)
+			for _, bl := range builders {
+				b.Run(fmt.Sprintf("%s/parts=%d/len=%d", bl.name, n, length), func(b *testing.B) {
+					b.ReportAllocs()
+					var s string
+					for i := 0; i < b.N; i++ {
+						s = bl.build(parts)
+					}
+					sinkString = s
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package strbuild_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/strbuild"
+)
+
+// builders are the functions under test.
+var builders = []struct {
+	name  string
+	build func([]string) string
+}{
+	{"Concat", strbuild.Concat},
+	{"Sprintf", strbuild.Sprintf},
+	{"Builder", strbuild.Builder},
+	{"Buffer", strbuild.Buffer},
+	{"Grow", strbuild.Grow},
+	{"Append", strbuild.Append},
+}
+
+func TestBuild(t *testing.T) {
+	for _, b := range builders {
+		for _, n := range []int{0, 1, 3, 2000} {
+			parts := strbuild.Parts(n, 5)
+			want := strings.Join(parts, "")
+			if got := b.build(parts); got != want {
+				t.Errorf("%s: %d parts built %d bytes, want %q...", b.name, n, len(got), want[:min(len(want), 20)])
+			}
+		}
+	}
+}
+
+func TestSprintf_Verbs(t *testing.T) {
+	// Parts are strings, never formats
+	if got := strbuild.Sprintf([]string{"%d", "%%", "x"}); got != "%d%%x" {
+		t.Errorf("Sprintf = %q, want %q", got, "%d%%x")
+	}
+}
+
+func TestGrow_OneAllocation(t *testing.T) {
+	parts := strbuild.Parts(16, 8)
+	if n := testing.AllocsPerRun(100, func() { strbuild.Grow(parts) }); n != 1 {
+		t.Errorf("Grow allocated %v times, want 1", n)
+	}
+}
+
+func TestParts(t *testing.T) {
+	parts := strbuild.Parts(28, 3)
+	if len(parts) != 28 || parts[0] != "aaa" || parts[25] != "zzz" || parts[26] != "aaa" {
+		t.Errorf("Parts(28, 3) = %q", parts)
+	}
+}
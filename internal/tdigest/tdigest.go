@@ -0,0 +1,264 @@
+// Package tdigest provides a merging t-digest (Dunning and Ertl) for
+// latency percentiles that can be combined across passes, runs and
+// machines.
+//
+// A Digest summarizes any number of values in at most a few hundred
+// centroids, each a mean and a count. Centroids near the median may hold
+// many values, and those at the tails only one or a few, so extreme
+// quantiles such as p99.9 stay accurate, even for heavy-tailed latencies,
+// while the digest stays small: within about 1% of the quantile's rank
+// near the median with the default compression of 100, and much closer
+// at the tails. Two digests
+// merge by pooling their centroids and compressing again, with about the
+// same accuracy as one digest of all the values, so each pass, run or
+// machine can keep its own and they can be combined later without ever
+// storing the values themselves.
+//
+// A Digest is not safe for concurrent use.
+package tdigest
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+)
+
+// DefaultCompression is the compression New uses: a digest holds at most
+// about this many centroids, in a few KB.
+const DefaultCompression = 100
+
+// ErrInvalidDigest is returned by UnmarshalJSON for a digest that
+// doesn't hold together: a negative compression, a centroid with no
+// weight, or centroids out of order.
+var ErrInvalidDigest = errors.New("tdigest: invalid digest")
+
+// Centroid is a run of nearby values, by their mean and how many there
+// were.
+type Centroid struct {
+	Mean   float64
+	Weight uint64
+}
+
+// Digest summarizes values for quantile queries. The zero value is not
+// usable; create one with New or NewWithCompression.
+type Digest struct {
+	compression float64
+	centroids   []Centroid // Compressed, sorted by Mean
+	pending     []Centroid // Added since the last compress, in any order
+	count       uint64     // Total weight of centroids and pending
+	min, max    float64
+}
+
+// New creates an empty Digest with DefaultCompression.
+func New() *Digest {
+	return NewWithCompression(DefaultCompression)
+}
+
+// NewWithCompression creates an empty Digest holding at most about
+// compression centroids; more is more accurate. Values below 10 are
+// treated as 10.
+func NewWithCompression(compression float64) *Digest {
+	return &Digest{compression: max(compression, 10), min: math.Inf(1), max: math.Inf(-1)}
+}
+
+// Add records one value.
+func (d *Digest) Add(x float64) {
+	d.add(Centroid{x, 1})
+}
+
+// add records c, compressing once enough has piled up.
+func (d *Digest) add(c Centroid) {
+	d.pending = append(d.pending, c)
+	d.count += c.Weight
+	d.min = min(d.min, c.Mean)
+	d.max = max(d.max, c.Mean)
+	if len(d.pending) >= 5*int(d.compression) {
+		d.compress()
+	}
+}
+
+// Merge adds every value o summarizes to d. o is unchanged.
+func (d *Digest) Merge(o *Digest) {
+	if o == nil || o.count == 0 {
+		return
+	}
+	for _, c := range o.centroids {
+		d.add(c)
+	}
+	for _, c := range o.pending {
+		d.add(c)
+	}
+	// A centroid's mean lies within the values it holds, so o's extremes
+	// may be further out
+	d.min = min(d.min, o.min)
+	d.max = max(d.max, o.max)
+}
+
+// Count returns how many values d summarizes.
+func (d *Digest) Count() uint64 {
+	return d.count
+}
+
+// Min returns the smallest value added, exactly, or 0 if there are none.
+func (d *Digest) Min() float64 {
+	if d.count == 0 {
+		return 0
+	}
+	return d.min
+}
+
+// Max returns the largest value added, exactly, or 0 if there are none.
+func (d *Digest) Max() float64 {
+	if d.count == 0 {
+		return 0
+	}
+	return d.max
+}
+
+// Centroids returns d's centroids, compressed and sorted by mean.
+func (d *Digest) Centroids() []Centroid {
+	d.compress()
+	return slices.Clone(d.centroids)
+}
+
+// normalizer scales the k2 scale function (see k) for n values so that
+// a digest holds about compression centroids whatever n is.
+func (d *Digest) normalizer(n float64) float64 {
+	return d.compression / (4*math.Log(max(n/d.compression, 1)) + 24)
+}
+
+// k is the k2 scale function, for normalizer z: it maps quantile q to a
+// scale on which each centroid may span at most 1. k is steeper the
+// nearer q is to 0 or 1, so centroids shrink in proportion to their
+// distance from the ends, and the outermost hold single values.
+func k(q, z float64) float64 {
+	return z * math.Log(q/(1-q))
+}
+
+// kInv is the inverse of k.
+func kInv(k, z float64) float64 {
+	return 1 / (1 + math.Exp(-k/z))
+}
+
+// compress merges the pending values into the centroids: it sorts them
+// all by mean and merges neighbours while the merged centroid spans at
+// most 1 on the k scale.
+func (d *Digest) compress() {
+	if len(d.pending) == 0 {
+		return
+	}
+	all := append(d.centroids, d.pending...)
+	slices.SortFunc(all, func(a, b Centroid) int { return cmp.Compare(a.Mean, b.Mean) })
+	total := float64(d.count)
+	z := d.normalizer(total)
+
+	out := make([]Centroid, 0, min(len(all), 2*int(d.compression)))
+	cur := all[0]
+	var before float64 // Weight of the centroids before cur
+	limit := kInv(k(0, z)+1, z)
+	for _, c := range all[1:] {
+		if (before+float64(cur.Weight+c.Weight))/total <= limit {
+			w := cur.Weight + c.Weight
+			cur.Mean += (c.Mean - cur.Mean) * float64(c.Weight) / float64(w)
+			cur.Weight = w
+			continue
+		}
+		before += float64(cur.Weight)
+		out = append(out, cur)
+		limit = kInv(k(before/total, z)+1, z)
+		cur = c
+	}
+	d.centroids = append(out, cur)
+	d.pending = d.pending[:0]
+}
+
+// Quantile returns an estimate of the value at quantile q (0..1), or 0
+// if d is empty. Quantiles 0 and 1 are the exact Min and Max; between
+// centroids it interpolates linearly.
+func (d *Digest) Quantile(q float64) float64 {
+	if d.count == 0 {
+		return 0
+	}
+	d.compress()
+	q = min(max(q, 0), 1)
+	cs := d.centroids
+	total := float64(d.count)
+	rank := q * total
+
+	// Each centroid's mean sits at the middle of the ranks it holds
+	first, last := cs[0], cs[len(cs)-1]
+	if rank < float64(first.Weight)/2 {
+		return interpolate(rank, 0, float64(first.Weight)/2, d.min, first.Mean)
+	}
+	if rank > total-float64(last.Weight)/2 {
+		return interpolate(rank, total-float64(last.Weight)/2, total, last.Mean, d.max)
+	}
+	var cum float64
+	for i := 0; i < len(cs)-1; i++ {
+		left := cum + float64(cs[i].Weight)/2
+		right := cum + float64(cs[i].Weight) + float64(cs[i+1].Weight)/2
+		if rank <= right {
+			return interpolate(rank, left, right, cs[i].Mean, cs[i+1].Mean)
+		}
+		cum += float64(cs[i].Weight)
+	}
+	return last.Mean
+}
+
+// interpolate returns the value at x on the line from (x0, y0) to
+// (x1, y1).
+func interpolate(x, x0, x1, y0, y1 float64) float64 {
+	if x1 <= x0 {
+		return y0
+	}
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
+
+// digestJSON is a Digest's JSON form.
+type digestJSON struct {
+	Compression float64      `json:"compression"`
+	Count       uint64       `json:"count"`
+	Min         float64      `json:"min"`
+	Max         float64      `json:"max"`
+	Centroids   [][2]float64 `json:"centroids"` // [mean, weight] pairs
+}
+
+// MarshalJSON writes d compressed, with its centroids as [mean, weight]
+// pairs:
+//
+//	{"compression":100,"count":3,"min":12,"max":40,"centroids":[[12,1],[20,1],[40,1]]}
+func (d *Digest) MarshalJSON() ([]byte, error) {
+	d.compress()
+	j := digestJSON{Compression: d.compression, Count: d.count, Min: d.Min(), Max: d.Max(), Centroids: make([][2]float64, len(d.centroids))}
+	for i, c := range d.centroids {
+		j.Centroids[i] = [2]float64{c.Mean, float64(c.Weight)}
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON reads a digest MarshalJSON wrote.
+func (d *Digest) UnmarshalJSON(data []byte) error {
+	var j digestJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Compression < 0 {
+		return ErrInvalidDigest
+	}
+	nd := NewWithCompression(j.Compression)
+	for _, p := range j.Centroids {
+		c := Centroid{Mean: p[0], Weight: uint64(p[1])}
+		if c.Weight == 0 || (len(nd.centroids) > 0 && c.Mean < nd.centroids[len(nd.centroids)-1].Mean) {
+			return ErrInvalidDigest
+		}
+		nd.centroids = append(nd.centroids, c)
+		nd.count += c.Weight
+	}
+	if nd.count > 0 {
+		nd.min, nd.max = j.Min, j.Max
+	}
+	*d = *nd
+	return nil
+}
@@ -0,0 +1,195 @@
+package tdigest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/tdigest"
+)
+
+// exact returns the value at quantile q of sorted, by nearest rank.
+func exact(sorted []float64, q float64) float64 {
+	i := int(math.Ceil(q*float64(len(sorted)))) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+// latencies returns n log-normal values, shaped like operation latencies
+// in ns: most near 50, with a long tail.
+func latencies(n int, seed uint64) []float64 {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	xs := make([]float64, n)
+	for i := range xs {
+		xs[i] = 50 * math.Exp(rng.NormFloat64()*0.5)
+	}
+	return xs
+}
+
+// checkQuantiles checks d's quantiles against the exact ones of xs: within
+// 1% of the rank, as the package promises, measured as where the
+// estimate falls among the sorted values.
+func checkQuantiles(t *testing.T, d *tdigest.Digest, xs []float64) {
+	t.Helper()
+	sorted := slices.Sorted(slices.Values(xs))
+	for _, q := range []float64{0.01, 0.1, 0.5, 0.9, 0.99, 0.999} {
+		got := d.Quantile(q)
+		rank, _ := slices.BinarySearch(sorted, got)
+		if err := math.Abs(float64(rank)/float64(len(sorted)) - q); err > 0.01 {
+			t.Errorf("Quantile(%v) = %.2f, exactly %.2f: off by %.2f%% of the rank", q, got, exact(sorted, q), err*100)
+		}
+	}
+	if d.Min() != sorted[0] || d.Max() != sorted[len(sorted)-1] {
+		t.Errorf("Min, Max = %v, %v; want exactly %v, %v", d.Min(), d.Max(), sorted[0], sorted[len(sorted)-1])
+	}
+	if d.Quantile(0) != d.Min() || d.Quantile(1) != d.Max() {
+		t.Errorf("Quantile(0), Quantile(1) = %v, %v; want Min and Max", d.Quantile(0), d.Quantile(1))
+	}
+}
+
+func TestDigest_Empty(t *testing.T) {
+	d := tdigest.New()
+	if d.Count() != 0 || d.Quantile(0.5) != 0 || d.Min() != 0 || d.Max() != 0 {
+		t.Errorf("empty digest: Count=%d p50=%v Min=%v Max=%v, want all 0", d.Count(), d.Quantile(0.5), d.Min(), d.Max())
+	}
+}
+
+func TestDigest_Quantile(t *testing.T) {
+	xs := latencies(100_000, 1)
+	d := tdigest.New()
+	for _, x := range xs {
+		d.Add(x)
+	}
+	if d.Count() != uint64(len(xs)) {
+		t.Errorf("Count() = %d, want %d", d.Count(), len(xs))
+	}
+	if n := len(d.Centroids()); n > 2*tdigest.DefaultCompression {
+		t.Errorf("%d centroids, want at most about the compression (%d)", n, tdigest.DefaultCompression)
+	}
+	checkQuantiles(t, d, xs)
+}
+
+func TestDigest_HeavyTail(t *testing.T) {
+	// Mostly near 250ns, with 1% Pareto-tailed stalls: the tail centroids
+	// must stay small enough that p99.9 isn't averaged with the stalls
+	// beyond it, also after merging
+	rng := rand.New(rand.NewPCG(1, 2))
+	xs := make([]float64, 60_000)
+	for i := range xs {
+		xs[i] = 250 + 20*rng.NormFloat64()
+		if rng.Float64() < 0.01 {
+			xs[i] = 250 * math.Pow(rng.Float64(), -1.5)
+		}
+	}
+	d := tdigest.New()
+	for _, x := range xs {
+		d.Add(x)
+	}
+	merged := tdigest.New()
+	merged.Merge(d)
+	merged.Merge(d)
+	sorted := slices.Sorted(slices.Values(xs))
+	for _, q := range []float64{0.99, 0.999} {
+		want := exact(sorted, q)
+		for name, got := range map[string]float64{"single": d.Quantile(q), "merged": merged.Quantile(q)} {
+			if math.Abs(got-want)/want > 0.1 {
+				t.Errorf("%s Quantile(%v) = %.0f, want within 10%% of %.0f", name, q, got, want)
+			}
+		}
+	}
+}
+
+func TestDigest_Small(t *testing.T) {
+	d := tdigest.New()
+	for _, x := range []float64{30, 10, 20} {
+		d.Add(x)
+	}
+	if got := d.Quantile(0.5); got != 20 {
+		t.Errorf("Quantile(0.5) of 10, 20, 30 = %v, want 20", got)
+	}
+	if cs := d.Centroids(); len(cs) != 3 {
+		t.Errorf("3 values in %d centroids, want one each", len(cs))
+	}
+}
+
+func TestDigest_Merge(t *testing.T) {
+	// Passes of different sizes and shapes, as from runs on two machines
+	var all []float64
+	merged := tdigest.New()
+	for i, n := range []int{50_000, 1_000, 20_000} {
+		xs := latencies(n, uint64(i+1))
+		if i == 1 {
+			for j := range xs {
+				xs[j] *= 10 // A slower machine
+			}
+		}
+		d := tdigest.New()
+		for _, x := range xs {
+			d.Add(x)
+		}
+		merged.Merge(d)
+		all = append(all, xs...)
+	}
+	if merged.Count() != uint64(len(all)) {
+		t.Errorf("merged Count() = %d, want %d", merged.Count(), len(all))
+	}
+	checkQuantiles(t, merged, all)
+
+	merged.Merge(nil)
+	merged.Merge(tdigest.New())
+	if merged.Count() != uint64(len(all)) {
+		t.Errorf("merging empty digests changed Count() to %d", merged.Count())
+	}
+}
+
+func TestDigest_JSON(t *testing.T) {
+	xs := latencies(10_000, 7)
+	d := tdigest.New()
+	for _, x := range xs {
+		d.Add(x)
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back tdigest.Digest
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Count() != d.Count() || back.Min() != d.Min() || back.Max() != d.Max() {
+		t.Errorf("round trip: Count, Min, Max = %d, %v, %v; want %d, %v, %v",
+			back.Count(), back.Min(), back.Max(), d.Count(), d.Min(), d.Max())
+	}
+	for _, q := range []float64{0.5, 0.99} {
+		if back.Quantile(q) != d.Quantile(q) {
+			t.Errorf("round trip: Quantile(%v) = %v, want %v", q, back.Quantile(q), d.Quantile(q))
+		}
+	}
+	// A decoded digest keeps merging
+	back.Merge(d)
+	if back.Count() != 2*d.Count() {
+		t.Errorf("decoded digest merged to Count() %d, want %d", back.Count(), 2*d.Count())
+	}
+
+	for _, bad := range []string{
+		`{"compression":100,"count":2,"min":1,"max":2,"centroids":[[2,1],[1,1]]}`,
+		`{"compression":100,"count":1,"min":1,"max":1,"centroids":[[1,0]]}`,
+		`{"compression":-1,"centroids":[]}`,
+	} {
+		var d tdigest.Digest
+		if err := json.Unmarshal([]byte(bad), &d); !errors.Is(err, tdigest.ErrInvalidDigest) {
+			t.Errorf("Unmarshal(%s) error = %v, want ErrInvalidDigest", bad, err)
+		}
+	}
+}
+
+func BenchmarkDigest_Add(b *testing.B) {
+	xs := latencies(4096, 1)
+	d := tdigest.New()
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		d.Add(xs[i%len(xs)])
+	}
+}
@@ -11,6 +11,12 @@ import (
 // Implemented in tsc_amd64.s
 func rdtsc() uint64
 
+// ReadTSC returns the CPU's Time Stamp Counter, for callers outside the
+// package that time code in TSC cycles.
+func ReadTSC() uint64 {
+	return rdtsc()
+}
+
 // CalibrateTSC measures CPU cycles per nanosecond.
 //
 // This performs a ~10ms calibration by comparing TSC ticks against
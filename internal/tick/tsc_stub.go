@@ -19,6 +19,9 @@ func CalibrateTSC() (float64, error) {
 	return 0, ErrTSCNotSupported
 }
 
+// ReadTSC returns 0 on non-amd64 architectures.
+func ReadTSC() uint64 { return 0 }
+
 // NewTSC returns an error on non-amd64 architectures.
 func NewTSC(interval time.Duration, cyclesPerNs float64) (*TSCTicker, error) {
 	return nil, ErrTSCNotSupported
@@ -71,3 +71,11 @@ func TestTSCTicker_CyclesPerNs(t *testing.T) {
 		t.Errorf("expected CyclesPerNs() = 3.0, got %f", ticker.CyclesPerNs())
 	}
 }
+
+func TestReadTSC(t *testing.T) {
+	a := tick.ReadTSC()
+	time.Sleep(time.Millisecond)
+	if b := tick.ReadTSC(); b <= a {
+		t.Errorf("ReadTSC() = %d after %d, want it to advance", b, a)
+	}
+}
@@ -0,0 +1,37 @@
+package timefmt_test
+
+import (
+	"testing"
+	"time"
+)
+
+// Sink variables to prevent compiler from eliminating benchmark loops
+var sinkBytes []byte
+
+// zones are the locations the benchmarks format timestamps in: UTC,
+// which needs no offset, a fixed offset, and time.Local, which has to
+// look up the offset in effect.
+var zones = []struct {
+	name string
+	loc  *time.Location
+}{
+	{"UTC", time.UTC},
+	{"Fixed", time.FixedZone("IST", 5*3600+30*60)},
+	{"Local", time.Local},
+}
+
+func BenchmarkTimestamp(b *testing.B) {
+	for _, z := range zones {
+		tm := time.Date(2025, 3, 9, 14, 5, 7, 123456789, time.UTC).In(z.loc)
+		for _, f := range appenders {
+			b.Run(f.name+"/zone="+z.name, func(b *testing.B) {
+				b.ReportAllocs()
+				buf := make([]byte, 0, 64)
+				for i := 0; i < b.N; i++ {
+					buf = f.append(buf[:0], tm)
+				}
+				sinkBytes = buf
+			})
+		}
+	}
+}
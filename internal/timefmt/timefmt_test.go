@@ -0,0 +1,76 @@
+package timefmt_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/timefmt"
+)
+
+// appender is a function under test.
+type appender struct {
+	name   string
+	append func([]byte, time.Time) []byte
+}
+
+// layouts are the functions that write timefmt.Layout.
+var layouts = []appender{
+	{"Format", timefmt.Format},
+	{"AppendFormat", timefmt.AppendFormat},
+	{"Manual", timefmt.Manual},
+}
+
+// appenders are all the functions under test.
+var appenders = append(layouts[:len(layouts):len(layouts)], appender{"UnixNano", timefmt.UnixNano})
+
+// times covers zones either side of UTC, a zero fraction, short years
+// and years RFC 3339 can't represent.
+var times = []time.Time{
+	time.Date(2025, 3, 9, 14, 5, 7, 123456789, time.UTC),
+	time.Date(2025, 12, 31, 23, 59, 59, 0, time.FixedZone("IST", 5*3600+30*60)),
+	time.Date(1999, 1, 1, 0, 0, 0, 1, time.FixedZone("NST", -(3*3600+30*60))),
+	time.Date(7, 6, 5, 4, 3, 2, 100, time.UTC),
+	time.Date(12345, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(-1, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestLayouts(t *testing.T) {
+	for _, tm := range times {
+		want := tm.Format(timefmt.Layout)
+		for _, l := range layouts {
+			if got := string(l.append([]byte("x "), tm)); got != "x "+want {
+				t.Errorf("%s(%v) = %q, want %q", l.name, tm, got, "x "+want)
+			}
+		}
+	}
+}
+
+func TestManual_RoundTrip(t *testing.T) {
+	tm := times[2]
+	parsed, err := time.Parse(time.RFC3339Nano, string(timefmt.Manual(nil, tm)))
+	if err != nil || !parsed.Equal(tm) {
+		t.Errorf("Manual(%v) parses back as %v, %v", tm, parsed, err)
+	}
+}
+
+func TestUnixNano(t *testing.T) {
+	for _, tm := range times[:4] {
+		got := string(timefmt.UnixNano(nil, tm))
+		if n, err := strconv.ParseInt(got, 10, 64); err != nil || n != tm.UnixNano() {
+			t.Errorf("UnixNano(%v) = %q, want %d", tm, got, tm.UnixNano())
+		}
+	}
+}
+
+// TestAppend_NoAlloc checks that all but Format write into a reused
+// buffer without allocating.
+func TestAppend_NoAlloc(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	tm := times[1]
+	for _, f := range appenders[1:] {
+		if n := testing.AllocsPerRun(100, func() { buf = f.append(buf[:0], tm) }); n != 0 {
+			t.Errorf("%s into a reused buffer allocated %v times, want 0", f.name, n)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package wait
+
+import "sync/atomic"
+
+// Latch is a Waiter on an atomic countdown: each task decrements a
+// counter when it returns, and whichever decrement reaches zero closes a
+// channel Wait receives from.
+//
+// This is the optimized approach. The counter starts at one, a token Wait
+// gives back, so it can't reach zero while Go is still starting tasks.
+// Each task costs one atomic add to start and one to finish, as with
+// WaitGroup, but there is one wakeup in all, through a channel, and
+// nothing else to synchronize with.
+type Latch struct {
+	count atomic.Int64
+	done  chan struct{}
+}
+
+// NewLatch creates a Latch.
+func NewLatch() *Latch {
+	l := &Latch{done: make(chan struct{})}
+	l.count.Store(1)
+	return l
+}
+
+// Go starts f on a new goroutine.
+func (l *Latch) Go(f func()) {
+	l.count.Add(1)
+	go func() {
+		defer l.countDown()
+		f()
+	}()
+}
+
+// Wait blocks until every f has returned.
+func (l *Latch) Wait() {
+	l.countDown()
+	<-l.done
+}
+
+// countDown decrements the count, closing done when it reaches zero.
+func (l *Latch) countDown() {
+	if l.count.Add(-1) == 0 {
+		close(l.done)
+	}
+}
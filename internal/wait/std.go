@@ -0,0 +1,94 @@
+package wait
+
+import (
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// WaitGroup is a Waiter on sync.WaitGroup.
+//
+// This is the standard approach: Go is an Add(1) before starting the
+// goroutine and a deferred Done in it, each an atomic add on the group's
+// counter, and Wait parks on a semaphore the last Done releases.
+type WaitGroup struct {
+	wg sync.WaitGroup
+}
+
+// NewWaitGroup creates a WaitGroup.
+func NewWaitGroup() *WaitGroup {
+	return &WaitGroup{}
+}
+
+// Go starts f on a new goroutine.
+func (w *WaitGroup) Go(f func()) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		f()
+	}()
+}
+
+// Wait blocks until every f has returned.
+func (w *WaitGroup) Wait() {
+	w.wg.Wait()
+}
+
+// ErrGroup is a Waiter on golang.org/x/sync/errgroup.Group.
+//
+// The group is a sync.WaitGroup plus a sync.Once to keep the first error,
+// so it shows what error collection costs when no task fails.
+type ErrGroup struct {
+	g errgroup.Group
+}
+
+// NewErrGroup creates an ErrGroup.
+func NewErrGroup() *ErrGroup {
+	return &ErrGroup{}
+}
+
+// Go starts f on a new goroutine.
+func (e *ErrGroup) Go(f func()) {
+	e.g.Go(func() error {
+		f()
+		return nil
+	})
+}
+
+// Wait blocks until every f has returned.
+func (e *ErrGroup) Wait() {
+	_ = e.g.Wait()
+}
+
+// Chan is a Waiter that gives each task a channel of its own, closed when
+// the task returns, and has Wait receive from each in turn: fan-in by
+// channel close.
+//
+// No counter is shared, but each task costs a channel allocation, and
+// Wait may park and be woken once per task that is still running when it
+// gets to it.
+type Chan struct {
+	done []chan struct{}
+}
+
+// NewChan creates a Chan.
+func NewChan() *Chan {
+	return &Chan{}
+}
+
+// Go starts f on a new goroutine.
+func (c *Chan) Go(f func()) {
+	done := make(chan struct{})
+	c.done = append(c.done, done)
+	go func() {
+		defer close(done)
+		f()
+	}()
+}
+
+// Wait blocks until every f has returned.
+func (c *Chan) Wait() {
+	for _, done := range c.done {
+		<-done
+	}
+}
@@ -0,0 +1,27 @@
+// Package wait provides completion signaling implementations for
+// benchmarking: ways for one goroutine to start N others and wait until
+// all of them are done.
+//
+// This package offers four implementations of the Waiter interface:
+//   - WaitGroup: Standard approach using sync.WaitGroup
+//   - ErrGroup: golang.org/x/sync/errgroup.Group, which adds error
+//     collection on top of a WaitGroup
+//   - Chan: a channel per task, closed when it finishes, received in turn
+//   - Latch: Optimized approach using an atomic countdown that closes one
+//     channel when it reaches zero
+//
+// It is the other half of cancellation: cancel tells the workers to stop,
+// wait tells the caller that they have. Starting the goroutines usually
+// costs more than the signaling, so the differences show per task, and
+// grow with N where each task's signal costs an allocation or a wakeup.
+package wait
+
+// Waiter runs tasks on goroutines of their own and waits for them all.
+//
+// Go starts f on a new goroutine. Wait blocks until every f started by
+// Go has returned. A Waiter is for one batch of tasks: Go and Wait must
+// be called from a single goroutine, every Go before Wait, and not after.
+type Waiter interface {
+	Go(f func())
+	Wait()
+}
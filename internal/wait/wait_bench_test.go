@@ -0,0 +1,33 @@
+package wait_test
+
+import (
+	"fmt"
+	"testing"
+)
+
+// taskCounts are the batch sizes the benchmarks start and wait for.
+var taskCounts = []int{1, 8, 64, 512}
+
+// noop is the task: empty, so the benchmarks measure starting and
+// signaling rather than work.
+func noop() {}
+
+// BenchmarkWait times a batch of N empty tasks, from the first Go to
+// Wait returning, reporting ns per batch and per task.
+func BenchmarkWait(b *testing.B) {
+	for _, n := range taskCounts {
+		for _, c := range constructors {
+			b.Run(fmt.Sprintf("%s/N=%d", c.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					w := c.new()
+					for range n {
+						w.Go(noop)
+					}
+					w.Wait()
+				}
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/task")
+			})
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package wait_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randomizedcoder/some-go-benchmarks/internal/wait"
+)
+
+// constructors creates each Waiter implementation.
+var constructors = []struct {
+	name string
+	new  func() wait.Waiter
+}{
+	{"WaitGroup", func() wait.Waiter { return wait.NewWaitGroup() }},
+	{"ErrGroup", func() wait.Waiter { return wait.NewErrGroup() }},
+	{"Chan", func() wait.Waiter { return wait.NewChan() }},
+	{"Latch", func() wait.Waiter { return wait.NewLatch() }},
+}
+
+func TestWaiter_WaitsForAll(t *testing.T) {
+	for _, c := range constructors {
+		for _, n := range []int{1, 2, 100} {
+			w := c.new()
+			var finished atomic.Int64
+			for i := range n {
+				w.Go(func() {
+					// Later tasks finish first, so Wait can't get lucky
+					time.Sleep(time.Duration(n-i) * 10 * time.Microsecond)
+					finished.Add(1)
+				})
+			}
+			w.Wait()
+			if got := finished.Load(); got != int64(n) {
+				t.Errorf("%s: Wait returned with %d of %d tasks finished", c.name, got, n)
+			}
+		}
+	}
+}
+
+func TestWaiter_NoTasks(t *testing.T) {
+	for _, c := range constructors {
+		done := make(chan struct{})
+		go func() {
+			c.new().Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Errorf("%s: Wait with no tasks didn't return", c.name)
+		}
+	}
+}
+
+// TestLatch_FastTasks checks that tasks finishing before the next Go
+// starts don't bring the Latch to zero early.
+func TestLatch_FastTasks(t *testing.T) {
+	l := wait.NewLatch()
+	var finished atomic.Int64
+	for range 10 {
+		done := make(chan struct{})
+		l.Go(func() {
+			finished.Add(1)
+			close(done)
+		})
+		<-done
+	}
+	l.Go(func() {
+		time.Sleep(time.Millisecond)
+		finished.Add(1)
+	})
+	l.Wait()
+	if got := finished.Load(); got != 11 {
+		t.Errorf("Wait returned with %d of 11 tasks finished", got)
+	}
+}